@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/clients/horizonclient"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historyarchive "github.com/kinecosystem/agora/pkg/transaction/history/archive"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	ingestioncommitter "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/committer"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+var (
+	archiveFrom    uint64
+	archiveMaxSlot uint64
+	archiveMaxAge  time.Duration
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "move old history entries into the archive",
+	Long: `Move old history entries into the archive.
+
+Entries are archived by block partition, once every entry in the partition is
+older than the maximum age. Archived entries are removed from the history
+store, but remain readable through the archive. Archival resumes from the last
+archived partition, so the command can be run periodically.`,
+}
+
+var archiveSolanaCmd = &cobra.Command{
+	Use:   "solana",
+	Short: "archive solana (kin 4) entries",
+	RunE:  archiveSolanaRun,
+	Args:  cobra.NoArgs,
+}
+
+var archiveStellarCmd = &cobra.Command{
+	Use:   "stellar <last>",
+	Short: "archive stellar (kin 2 or kin 3) entries, up to the (final) ledger",
+	RunE:  archiveStellarRun,
+	Args:  cobra.ExactArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveSolanaCmd)
+	archiveCmd.AddCommand(archiveStellarCmd)
+
+	archiveCmd.PersistentFlags().Uint64Var(&archiveFrom, "from", 0, "block to start from, if nothing has been archived yet")
+	archiveCmd.PersistentFlags().DurationVar(&archiveMaxAge, "max-age", 365*24*time.Hour, "minimum age of archived entries")
+
+	archiveSolanaCmd.Flags().Uint64Var(&archiveMaxSlot, "max-slot", 0, "last committed slot (defaults to the dynamodb committer)")
+
+	archiveStellarCmd.Flags().StringVar(&horizonURL, "horizon", "", "horizon url")
+	archiveStellarCmd.Flags().StringVar(&networkPassphrase, "passphrase", "", "network passphrase")
+	archiveStellarCmd.Flags().IntVar(&kinVersion, "kin-version", 3, "kin version of the ledgers (2 or 3)")
+	_ = archiveStellarCmd.MarkFlagRequired("horizon")
+	_ = archiveStellarCmd.MarkFlagRequired("passphrase")
+}
+
+func archiveSolanaRun(_ *cobra.Command, _ []string) error {
+	archiver, err := newArchiver()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := archiveContext()
+	defer cancel()
+
+	// Only committed slots are used to determine whether or not a partition
+	// is complete.
+	maxSlot := archiveMaxSlot
+	if maxSlot == 0 {
+		latest, err := ingestioncommitter.New(dynamoClient).Latest(ctx, ingestion.GetHistoryIngestorName(model.KinVersion_KIN4))
+		if err != nil {
+			return errors.Wrap(err, "failed to get latest committed slot")
+		}
+		if latest == nil {
+			return errors.New("no committed slot")
+		}
+
+		if maxSlot, err = solanaingestor.SlotFromPointer(latest); err != nil {
+			return errors.Wrap(err, "invalid committed pointer")
+		}
+	}
+
+	cutoff := time.Now().Add(-archiveMaxAge)
+	archived, err := archiver.ArchiveSolana(ctx, archiveFrom, maxSlot, cutoff)
+	log.Printf("Archived %d entries older than %s\n", archived, cutoff.Format(time.RFC3339))
+	return err
+}
+
+func archiveStellarRun(_ *cobra.Command, args []string) error {
+	last, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "invalid last ledger")
+	}
+	if archiveFrom > uint64(^uint32(0)) {
+		return errors.New("invalid from ledger")
+	}
+
+	var version model.KinVersion
+	switch kinVersion {
+	case 2:
+		version = model.KinVersion_KIN2
+	case 3:
+		version = model.KinVersion_KIN3
+	default:
+		return errors.Errorf("unsupported kin version: %d", kinVersion)
+	}
+
+	archiver, err := newArchiver()
+	if err != nil {
+		return err
+	}
+
+	client := &horizonclient.Client{
+		HorizonURL: horizonURL,
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+	}
+	loader := stellaringestor.NewLedgerLoader(version, client, networkPassphrase)
+
+	ctx, cancel := archiveContext()
+	defer cancel()
+
+	cutoff := time.Now().Add(-archiveMaxAge)
+	archived, err := archiver.ArchiveStellar(ctx, version, loader, uint32(archiveFrom), uint32(last), cutoff)
+	log.Printf("Archived %d entries older than %s\n", archived, cutoff.Format(time.RFC3339))
+	return err
+}
+
+func newArchiver() (*historyarchive.Archiver, error) {
+	if archiveStore == nil {
+		return nil, errors.New("archive bucket or directory must be specified")
+	}
+
+	deleter, ok := hotRW.(history.Deleter)
+	if !ok {
+		return nil, errors.New("history store does not support deletion")
+	}
+
+	return historyarchive.NewArchiver(hotRW, deleter, archiveStore), nil
+}
+
+// archiveContext returns a context that is cancelled on SIGINT or SIGTERM.
+func archiveContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			log.Println("Stopping archival, archived partitions have been committed")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	committer "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/committer"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+var (
+	solanaEndpoint string
+	kinToken       string
+	chunkSize      uint64
+	concurrency    int
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill <from> <to>",
+	Short: "re-ingest the (inclusive) solana slot range into history",
+	Long: `Re-ingest the (inclusive) solana slot range into history.
+
+The range is split into chunks that are ingested in parallel. The progress of
+each chunk is committed separately from the live ingestion pointer, so an
+interrupted backfill can be resumed by re-running the same command.`,
+	RunE: backfillRun,
+	Args: cobra.ExactArgs(2),
+}
+
+func init() {
+	rootCmd.AddCommand(backfillCmd)
+
+	backfillCmd.Flags().StringVar(&solanaEndpoint, "solana-endpoint", os.Getenv("SOLANA_ENDPOINT"), "solana rpc endpoint")
+	backfillCmd.Flags().StringVar(&kinToken, "token", os.Getenv("KIN_TOKEN"), "kin token mint (base58)")
+	backfillCmd.Flags().Uint64Var(&chunkSize, "chunk-size", 10000, "number of slots per chunk")
+	backfillCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "number of chunks to ingest in parallel")
+}
+
+func backfillRun(_ *cobra.Command, args []string) error {
+	from, to, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	if solanaEndpoint == "" {
+		return errors.New("solana endpoint must be specified")
+	}
+	token, err := base58.Decode(kinToken)
+	if err != nil || len(token) == 0 {
+		return errors.New("invalid kin token")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("Stopping backfill, progress has been committed")
+		cancel()
+	}()
+
+	chunks := solanaingestor.BackfillChunks(from, to, chunkSize)
+	ingestor := solanaingestor.New(
+		ingestion.GetHistoryIngestorName(model.KinVersion_KIN4),
+		solana.New(solanaEndpoint),
+		token,
+	)
+
+	var mu sync.Mutex
+	var completed int
+	progress := func(chunk ingestion.BackfillChunk, block ingestion.Pointer, done bool) {
+		if !done {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		log.Printf("Backfilled chunk ending at %x (%d/%d)\n", chunk.End, completed, len(chunks))
+	}
+
+	log.Printf("Backfilling slots [%d, %d] in %d chunks\n", from, to, len(chunks))
+	if err := ingestion.Backfill(ctx, committer.New(dynamoClient), historyRW, ingestor, chunks, concurrency, progress); err != nil {
+		return err
+	}
+
+	log.Println("Backfill complete")
+	return nil
+}
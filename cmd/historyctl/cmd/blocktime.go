@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/blocktime"
+)
+
+var blockTimesCmd = &cobra.Command{
+	Use:   "backfill-block-times",
+	Short: "add every entry missing from the block time index",
+	Long: `Add every entry missing from the block time index.
+
+Entries written before the block time index existed, or without a block time,
+are excluded from time range queries. This command scans the account history
+for such entries (on both chains), loads the block time of Solana entries that
+are missing one, and rewrites the entries so that they are included in the
+time index.`,
+	RunE: blockTimesRun,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(blockTimesCmd)
+
+	blockTimesCmd.Flags().StringVar(&solanaEndpoint, "solana-endpoint", os.Getenv("SOLANA_ENDPOINT"), "solana rpc endpoint")
+}
+
+func blockTimesRun(_ *cobra.Command, _ []string) error {
+	if solanaEndpoint == "" {
+		return errors.New("solana endpoint must be specified")
+	}
+
+	// Archived entries are immutable, so only the hot store is backfilled.
+	indexer, ok := hotRW.(history.TimeIndexer)
+	if !ok {
+		return errors.New("history store does not support a block time index")
+	}
+	repairer, ok := hotRW.(history.Repairer)
+	if !ok {
+		return errors.New("history store does not support repairs")
+	}
+
+	result, err := blocktime.Backfill(context.Background(), indexer, repairer, solana.New(solanaEndpoint))
+	log.Printf("Updated %d entries (%d skipped)\n", result.Updated, result.Skipped)
+	return err
+}
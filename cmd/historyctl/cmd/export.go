@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/kinecosystem/go/strkey"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/export"
+)
+
+var (
+	format         string
+	output         string
+	checkpointPath string
+	pageSize       int
+	rowsPerPart    int
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export decoded history entries",
+}
+
+var exportBlocksCmd = &cobra.Command{
+	Use:   "blocks <from> <to>",
+	Short: "export the entries in the (inclusive) block range",
+	RunE:  exportBlocksRun,
+	Args:  cobra.ExactArgs(2),
+}
+
+var exportAccountsCmd = &cobra.Command{
+	Use:   "accounts <account>...",
+	Short: "export the entries for the account(s)",
+	RunE:  exportAccountsRun,
+	Args:  cobra.MinimumNArgs(1),
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportBlocksCmd)
+	exportCmd.AddCommand(exportAccountsCmd)
+
+	exportCmd.PersistentFlags().StringVarP(&format, "format", "f", "jsonl", "output format (jsonl, csv or parquet)")
+	exportCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "output file (or file prefix, for parquet)")
+	exportCmd.PersistentFlags().StringVar(&checkpointPath, "checkpoint", "", "checkpoint file (defaults to <output>.checkpoint)")
+	exportCmd.PersistentFlags().IntVar(&pageSize, "page-size", 1000, "number of entries to load per page")
+	exportCmd.PersistentFlags().IntVar(&rowsPerPart, "rows-per-part", 1_000_000, "number of rows to buffer before starting a new part (for parquet)")
+	_ = exportCmd.MarkPersistentFlagRequired("output")
+}
+
+func exportBlocksRun(_ *cobra.Command, args []string) error {
+	from, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid from block")
+	}
+	to, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid to block")
+	}
+	if from > to {
+		return errors.New("from must be less than or equal to to")
+	}
+
+	return runExport(func(e *export.Exporter, cp *export.Checkpoint) error {
+		return e.ExportBlocks(context.Background(), from, to, cp)
+	})
+}
+
+func exportAccountsRun(_ *cobra.Command, args []string) error {
+	accounts := make([]string, 0, len(args))
+	for _, arg := range args {
+		if _, err := strkey.Decode(strkey.VersionByteAccountID, arg); err == nil {
+			accounts = append(accounts, arg)
+			continue
+		}
+
+		k, err := base58.Decode(arg)
+		if err != nil {
+			return errors.Wrapf(err, "invalid account: %s", arg)
+		}
+		account, err := strkey.Encode(strkey.VersionByteAccountID, k)
+		if err != nil {
+			return errors.Wrapf(err, "invalid account: %s", arg)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return runExport(func(e *export.Exporter, cp *export.Checkpoint) error {
+		return e.ExportAccounts(context.Background(), accounts, cp)
+	})
+}
+
+// outputState is the writer state stored alongside a checkpoint, allowing
+// the output to be restored to the checkpointed position.
+type outputState struct {
+	// Offset is the size of the (jsonl or csv) output file.
+	Offset int64 `json:"offset,omitempty"`
+
+	// Part is the next part to be written (for parquet).
+	Part int `json:"part,omitempty"`
+}
+
+func runExport(run func(*export.Exporter, *export.Checkpoint) error) error {
+	if checkpointPath == "" {
+		checkpointPath = output + ".checkpoint"
+	}
+
+	cp, err := export.LoadCheckpoint(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	var state outputState
+	if len(cp.Output) > 0 {
+		if err := json.Unmarshal(cp.Output, &state); err != nil {
+			return errors.Wrap(err, "invalid checkpoint output state")
+		}
+		log.Printf("Resuming export from %s (%+v)\n", checkpointPath, state)
+	}
+
+	var w export.Writer
+	var current func() (outputState, error)
+	switch format {
+	case "jsonl", "csv":
+		f, err := openAt(output, state.Offset)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if format == "jsonl" {
+			w = export.NewJSONLWriter(f)
+		} else {
+			w = export.NewCSVWriter(f, state.Offset == 0)
+		}
+
+		current = func() (outputState, error) {
+			offset, err := f.Seek(0, io.SeekCurrent)
+			return outputState{Offset: offset}, err
+		}
+	case "parquet":
+		pw := &partWriter{next: state.Part}
+		w = export.NewParquetWriter(pw.open, state.Part, rowsPerPart)
+		current = func() (outputState, error) {
+			return outputState{Part: pw.next}, nil
+		}
+	default:
+		return errors.Errorf("unsupported format: %s", format)
+	}
+
+	onCheckpoint := func(cp *export.Checkpoint) error {
+		s, err := current()
+		if err != nil {
+			return errors.Wrap(err, "failed to get output state")
+		}
+		if cp.Output, err = json.Marshal(s); err != nil {
+			return errors.Wrap(err, "failed to marshal output state")
+		}
+
+		return export.SaveCheckpoint(checkpointPath, cp)
+	}
+
+	e := export.NewExporter(historyRW, invoices, w, onCheckpoint)
+	e.SetPageSize(pageSize)
+
+	if err := run(e, cp); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "failed to close writer")
+	}
+
+	log.Println("Export complete:", output)
+	return nil
+}
+
+// openAt opens (or creates) the file at path, discarding any content after
+// offset. Content after the offset was written after the last checkpoint,
+// and will be rewritten.
+func openAt(path string, offset int64) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open output")
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "failed to truncate output")
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "failed to seek output")
+	}
+
+	return f, nil
+}
+
+// partWriter opens sequentially numbered parquet parts, tracking the next
+// part to be written.
+type partWriter struct {
+	next int
+}
+
+func (p *partWriter) open(part int) (io.WriteCloser, error) {
+	f, err := os.Create(fmt.Sprintf("%s.%05d.parquet", output, part))
+	if err != nil {
+		return nil, err
+	}
+
+	p.next = part + 1
+	return f, nil
+}
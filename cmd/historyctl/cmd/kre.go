@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/jackc/pgx/v4/pgxpool"
+	agoraapp "github.com/kinecosystem/agora-common/app"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/option"
+
+	infodb "github.com/kinecosystem/agora/pkg/account/solana/accountinfo/dynamodb"
+	committer "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/committer"
+	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
+	bqsubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/bigquery"
+	filesubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/file"
+	pgsubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/postgres"
+)
+
+var (
+	krePostgresConnString string
+	kreFileDir            string
+	kreFileFormat         string
+	kreBigQueryCreds      string
+	kreBigQueryTables     map[string]string
+
+	krePool *pgxpool.Pool
+	kreBQ   *bigquery.Client
+)
+
+var kreCmd = &cobra.Command{
+	Use:   "kre",
+	Short: "recompute KRE data from history",
+	Long: `Recompute KRE data from history.
+
+Rows are submitted to either a postgres database (--kre-postgres), a
+directory (--kre-dir), or BigQuery (--kre-bigquery), using the same layout as
+the history-collector.`,
+	PersistentPostRunE: krePostRun,
+}
+
+var kreRollupsCmd = &cobra.Command{
+	Use:   "rollups <from> <to>",
+	Short: "recompute the daily rollups for the (exclusive) date range",
+	Long: `Recompute the daily rollups for the (exclusive) date range.
+
+Dates are UTC, formatted as 2006-01-02. Days that are not yet complete in
+history are not submitted. Recomputed rollups replace the existing rollups of
+their date.`,
+	RunE: kreRollupsRun,
+	Args: cobra.ExactArgs(2),
+}
+
+var kreReplayCmd = &cobra.Command{
+	Use:   "replay <from> <to>",
+	Short: "re-submit the KRE rows for the (inclusive) solana slot range",
+	Long: `Re-submit the KRE rows for the (inclusive) solana slot range.
+
+Rows are regenerated from history, and replace the existing rows with the same
+insert id. The range is split into chunks that are replayed in parallel. The
+progress of each chunk is committed separately from the live KRE pointer, so an
+interrupted replay can be resumed by re-running the same command.
+
+The owners of payment destinations are resolved from history, as of the
+payment, rather than from the current state of the accounts.
+
+Replays are only supported for postgres (--kre-postgres). The BigQuery and file
+backends append rows, and replayed slot ranges do not cover whole dates, so
+their partitions cannot be replaced either.
+
+Account states are not modified by a replay.`,
+	RunE: kreReplayRun,
+	Args: cobra.ExactArgs(2),
+}
+
+func init() {
+	rootCmd.AddCommand(kreCmd)
+	kreCmd.AddCommand(kreRollupsCmd)
+	kreCmd.AddCommand(kreReplayCmd)
+
+	kreCmd.PersistentFlags().StringVar(&solanaEndpoint, "solana-endpoint", os.Getenv("SOLANA_ENDPOINT"), "solana rpc endpoint")
+	kreCmd.PersistentFlags().StringVar(&kinToken, "token", os.Getenv("KIN_TOKEN"), "kin token mint (base58)")
+	kreCmd.PersistentFlags().StringVar(&krePostgresConnString, "kre-postgres", os.Getenv("KRE_POSTGRES_CONN_STRING"), "postgres connection string of the KRE tables")
+	kreCmd.PersistentFlags().StringVar(&kreFileDir, "kre-dir", os.Getenv("KRE_FILE_DIR"), "directory to write KRE files to")
+	kreCmd.PersistentFlags().StringVar(&kreFileFormat, "kre-format", "jsonl", "format of KRE files (jsonl or parquet)")
+	kreCmd.PersistentFlags().StringVar(&kreBigQueryCreds, "kre-bigquery", "", "api key or credentials file of the KRE BigQuery tables")
+	kreCmd.PersistentFlags().StringToStringVar(&kreBigQueryTables, "kre-bigquery-tables", defaultKREBigQueryTables(), "BigQuery table of each KRE table (creations, payments, ownership_changes, closures, rollups)")
+
+	kreReplayCmd.Flags().Uint64Var(&chunkSize, "chunk-size", 10000, "number of slots per chunk")
+	kreReplayCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "number of chunks to replay in parallel")
+}
+
+func kreRollupsRun(_ *cobra.Command, args []string) error {
+	from, err := time.Parse("2006-01-02", args[0])
+	if err != nil {
+		return errors.Wrap(err, "invalid from date")
+	}
+	to, err := time.Parse("2006-01-02", args[1])
+	if err != nil {
+		return errors.Wrap(err, "invalid to date")
+	}
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+
+	sc, tc, err := newKRESolanaClients()
+	if err != nil {
+		return err
+	}
+	submitter, err := newKRESubmitter("rollups", pgsubmitter.RollupsTable, kre.RollupsSchema, true)
+	if err != nil {
+		return err
+	}
+
+	// Recompute does not use the commit pointer (or lock) of the aggregator.
+	aggregator := kre.NewAggregator(historyRW, nil, nil, sc, tc, submitter)
+	return aggregator.Recompute(context.Background(), from, to)
+}
+
+func kreReplayRun(_ *cobra.Command, args []string) error {
+	from, to, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	// Replayed rows must replace the existing rows, which only the postgres
+	// backend supports.
+	if kreFileDir != "" || kreBigQueryCreds != "" {
+		return errors.New("replays are only supported with --kre-postgres, as the file and bigquery backends would duplicate rows")
+	}
+
+	sc, tc, err := newKRESolanaClients()
+	if err != nil {
+		return err
+	}
+
+	submitters := make([]kre.Submitter, 4)
+	for i, t := range []struct {
+		name   string
+		table  string
+		schema kre.Schema
+	}{
+		{"creations", pgsubmitter.CreationsTable, kre.CreationsSchema},
+		{"payments", pgsubmitter.PaymentsTable, kre.PaymentsSchema},
+		{"ownership_changes", pgsubmitter.OwnershipChangesTable, kre.OwnershipChangesSchema},
+		{"closures", pgsubmitter.ClosuresTable, kre.ClosuresSchema},
+	} {
+		submitters[i], err = newKRESubmitter(t.name, t.table, t.schema, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("Stopping replay, progress has been committed")
+		cancel()
+	}()
+
+	// The state store is only read, in order to identify closures of known
+	// accounts. Replay does not use the lock of the live loader.
+	loader := kre.NewLoader(
+		historyRW,
+		committer.New(dynamoClient),
+		nil,
+		sc,
+		tc,
+		submitters[0],
+		submitters[1],
+		submitters[2],
+		submitters[3],
+		infodb.NewStore(dynamoClient),
+	)
+
+	chunks := kre.ReplayChunks(from, to, chunkSize)
+
+	var mu sync.Mutex
+	var completed int
+	progress := func(chunk kre.ReplayChunk, _ uint64, done bool) {
+		if !done {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		log.Printf("Replayed chunk [%d, %d] (%d/%d)\n", chunk.From, chunk.To, completed, len(chunks))
+	}
+
+	log.Printf("Replaying slots [%d, %d] in %d chunks\n", from, to, len(chunks))
+	if err := loader.Replay(ctx, chunks, concurrency, progress); err != nil {
+		return err
+	}
+
+	log.Println("Replay complete")
+	return nil
+}
+
+func newKRESolanaClients() (solana.Client, *token.Client, error) {
+	if solanaEndpoint == "" {
+		return nil, nil, errors.New("solana endpoint must be specified")
+	}
+	mint, err := base58.Decode(kinToken)
+	if err != nil || len(mint) == 0 {
+		return nil, nil, errors.New("a valid kin token must be specified")
+	}
+
+	sc := solana.New(solanaEndpoint)
+	return sc, token.NewClient(sc, mint), nil
+}
+
+// newKRESubmitter returns a submitter for the configured KRE backend. name is
+// the subdirectory used for files and the key of the BigQuery table, and table
+// the postgres table.
+//
+// Rows replace existing rows with the same insert id in postgres. If
+// replacePartitions is set, rows replace the partition of their date in the
+// other backends, so all rows of a date must be submitted at once.
+func newKRESubmitter(name, table string, schema kre.Schema, replacePartitions bool) (kre.Submitter, error) {
+	var configured int
+	for _, backend := range []string{krePostgresConnString, kreFileDir, kreBigQueryCreds} {
+		if backend != "" {
+			configured++
+		}
+	}
+
+	switch {
+	case configured > 1:
+		return nil, errors.New("only one of --kre-postgres, --kre-dir and --kre-bigquery may be specified")
+	case krePostgresConnString != "":
+		if krePool == nil {
+			var err error
+			krePool, err = pgxpool.Connect(context.Background(), krePostgresConnString)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to connect to kre postgres")
+			}
+		}
+
+		return pgsubmitter.New(krePool, table, schema, pgsubmitter.WithUpsert()), nil
+	case kreFileDir != "":
+		format, err := filesubmitter.ParseFormat(kreFileFormat)
+		if err != nil {
+			return nil, err
+		}
+
+		var opts []filesubmitter.Option
+		if replacePartitions {
+			opts = append(opts, filesubmitter.WithReplacePartitions())
+		}
+
+		return filesubmitter.New(filepath.Join(kreFileDir, name), schema, format, opts...), nil
+	case kreBigQueryCreds != "":
+		bqTable := kreBigQueryTables[name]
+		if bqTable == "" {
+			return nil, errors.Errorf("no bigquery table specified for %s", name)
+		}
+
+		if kreBQ == nil {
+			authOption := option.WithAPIKey(kreBigQueryCreds)
+			if _, err := url.Parse(kreBigQueryCreds); err == nil {
+				creds, err := agoraapp.LoadFile(kreBigQueryCreds)
+				if err == nil {
+					authOption = option.WithCredentialsJSON(creds)
+				}
+			}
+
+			var err error
+			kreBQ, err = bigquery.NewClient(context.Background(), "kin-bi", authOption)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to initialize bigquery client")
+			}
+		}
+
+		var opts []bqsubmitter.Option
+		if replacePartitions {
+			opts = append(opts, bqsubmitter.WithReplacePartitions())
+		}
+
+		return bqsubmitter.New(kreBQ, bqTable, opts...), nil
+	default:
+		return nil, errors.New("one of --kre-postgres, --kre-dir or --kre-bigquery must be specified")
+	}
+}
+
+// defaultKREBigQueryTables returns the BigQuery tables configured in the
+// environment, using the same variables as the history-collector.
+func defaultKREBigQueryTables() map[string]string {
+	tables := make(map[string]string)
+	for name, env := range map[string]string{
+		"creations":         "BQ_CREATIONS_TABLE",
+		"payments":          "BQ_PAYMENTS_TABLE",
+		"ownership_changes": "BQ_OWNERSHIP_CHANGES_TABLE",
+		"closures":          "BQ_CLOSURES_TABLE",
+		"rollups":           "BQ_ROLLUPS_TABLE",
+	} {
+		if table := os.Getenv(env); table != "" {
+			tables[name] = table
+		}
+	}
+
+	return tables
+}
+
+func krePostRun(cmd *cobra.Command, args []string) error {
+	if krePool != nil {
+		krePool.Close()
+	}
+	if kreBQ != nil {
+		if err := kreBQ.Close(); err != nil {
+			log.Printf("failed to close bigquery client: %v\n", err)
+		}
+	}
+
+	return rootPostRun(cmd, args)
+}
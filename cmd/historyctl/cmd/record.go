@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	ingestionfile "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/file"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+)
+
+var (
+	recordDir    string
+	recordFormat string
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record <from> <to>",
+	Short: "record the (inclusive) solana slot range to a directory for offline replay",
+	Long: `Record the (inclusive) solana slot range to a directory for offline replay.
+
+Each block is written to its own file, containing the history entries that
+ingestion would produce for the block. The directory can then be replayed by
+the file ingestor, without access to a live chain. Re-running the command
+overwrites previously recorded blocks.`,
+	RunE: recordRun,
+	Args: cobra.ExactArgs(2),
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+
+	recordCmd.Flags().StringVar(&solanaEndpoint, "solana-endpoint", os.Getenv("SOLANA_ENDPOINT"), "solana rpc endpoint")
+	recordCmd.Flags().StringVar(&kinToken, "token", os.Getenv("KIN_TOKEN"), "kin token mint (base58)")
+	recordCmd.Flags().StringVarP(&recordDir, "dir", "d", "", "directory to record blocks to")
+	recordCmd.Flags().StringVarP(&recordFormat, "format", "f", "json", "block format (json or pb)")
+}
+
+func recordRun(_ *cobra.Command, args []string) error {
+	from, to, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	if solanaEndpoint == "" {
+		return errors.New("solana endpoint must be specified")
+	}
+	token, err := base58.Decode(kinToken)
+	if err != nil || len(token) == 0 {
+		return errors.New("invalid kin token")
+	}
+	if recordDir == "" {
+		return errors.New("directory must be specified")
+	}
+	format, err := ingestionfile.ParseFormat(recordFormat)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("Stopping recording")
+		cancel()
+	}()
+
+	client := solana.New(solanaEndpoint)
+	recorded, err := ingestionfile.RecordSolana(ctx, client, solanaingestor.NewBlockLoader(client, token), recordDir, format, from, to)
+	log.Printf("Recorded %d blocks\n", recorded)
+	return err
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kinecosystem/agora/pkg/invoice"
+	invoicedb "github.com/kinecosystem/agora/pkg/invoice/dynamodb"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historyarchive "github.com/kinecosystem/agora/pkg/transaction/history/archive"
+	historydb "github.com/kinecosystem/agora/pkg/transaction/history/dynamodb"
+	historypg "github.com/kinecosystem/agora/pkg/transaction/history/postgres"
+)
+
+var (
+	postgresConnString string
+	withInvoices       bool
+	archiveDir         string
+	archiveBucket      string
+
+	dynamoClient *dynamodb.Client
+	pool         *pgxpool.Pool
+	historyRW    history.ReaderWriter
+	hotRW        history.ReaderWriter
+	archiveStore historyarchive.ObjectStore
+	invoices     invoice.Store
+)
+
+var rootCmd = &cobra.Command{
+	Use:                "historyctl",
+	Short:              "Inspect and export transaction history",
+	PersistentPreRunE:  rootPreRun,
+	PersistentPostRunE: rootPostRun,
+}
+
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&postgresConnString, "postgres", "", "postgres connection string of the history store (defaults to dynamodb)")
+	rootCmd.PersistentFlags().BoolVar(&withInvoices, "invoices", true, "join invoices from the (dynamodb) invoice store")
+	rootCmd.PersistentFlags().StringVar(&archiveBucket, "archive-bucket", os.Getenv("HISTORY_ARCHIVE_BUCKET"), "s3 bucket of the history archive, if any")
+	rootCmd.PersistentFlags().StringVar(&archiveDir, "archive-dir", "", "local directory of the history archive (instead of s3)")
+}
+
+func rootPreRun(_ *cobra.Command, _ []string) (err error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to init v2 aws sdk")
+	}
+	dynamoClient = dynamodb.New(cfg)
+
+	if postgresConnString != "" {
+		pool, err = pgxpool.Connect(context.Background(), postgresConnString)
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to postgres")
+		}
+		historyRW = historypg.New(pool)
+	} else {
+		historyRW = historydb.New(dynamoClient)
+	}
+
+	// Reads fall through to the archive, if configured.
+	hotRW = historyRW
+	switch {
+	case archiveDir != "":
+		archiveStore, err = historyarchive.NewFileStore(archiveDir)
+		if err != nil {
+			return errors.Wrap(err, "failed to open archive")
+		}
+	case archiveBucket != "":
+		archiveStore = historyarchive.NewS3Store(s3.New(cfg), archiveBucket, "")
+	}
+	if archiveStore != nil {
+		historyRW = historyarchive.NewReader(hotRW, archiveStore)
+	}
+
+	if withInvoices {
+		invoices = invoicedb.New(dynamoClient)
+	}
+
+	return nil
+}
+
+func rootPostRun(_ *cobra.Command, _ []string) error {
+	if pool != nil {
+		pool.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/clients/horizonclient"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	"github.com/kinecosystem/agora/pkg/transaction/history/verify"
+)
+
+var (
+	repair            bool
+	horizonURL        string
+	networkPassphrase string
+	kinVersion        int
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify the history store against the chain",
+}
+
+var verifySolanaCmd = &cobra.Command{
+	Use:   "solana <from> <to>",
+	Short: "verify the (inclusive) solana slot range",
+	RunE:  verifySolanaRun,
+	Args:  cobra.ExactArgs(2),
+}
+
+var verifyStellarCmd = &cobra.Command{
+	Use:   "stellar <from> <to>",
+	Short: "verify the (inclusive) stellar ledger range",
+	RunE:  verifyStellarRun,
+	Args:  cobra.ExactArgs(2),
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.AddCommand(verifySolanaCmd)
+	verifyCmd.AddCommand(verifyStellarCmd)
+
+	verifyCmd.PersistentFlags().BoolVar(&repair, "repair", false, "repair missing and stale entries")
+
+	verifySolanaCmd.Flags().StringVar(&solanaEndpoint, "solana-endpoint", os.Getenv("SOLANA_ENDPOINT"), "solana rpc endpoint")
+	verifySolanaCmd.Flags().StringVar(&kinToken, "token", os.Getenv("KIN_TOKEN"), "kin token mint (base58)")
+
+	verifyStellarCmd.Flags().StringVar(&horizonURL, "horizon", "", "horizon url")
+	verifyStellarCmd.Flags().StringVar(&networkPassphrase, "passphrase", "", "network passphrase")
+	verifyStellarCmd.Flags().IntVar(&kinVersion, "kin-version", 3, "kin version of the ledgers (2 or 3)")
+	_ = verifyStellarCmd.MarkFlagRequired("horizon")
+	_ = verifyStellarCmd.MarkFlagRequired("passphrase")
+}
+
+func verifySolanaRun(_ *cobra.Command, args []string) error {
+	from, to, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	if solanaEndpoint == "" {
+		return errors.New("solana endpoint must be specified")
+	}
+	token, err := base58.Decode(kinToken)
+	if err != nil || len(token) == 0 {
+		return errors.New("invalid kin token")
+	}
+
+	client := solana.New(solanaEndpoint)
+	v, err := newVerifier()
+	if err != nil {
+		return err
+	}
+
+	report, err := v.VerifySolana(context.Background(), client, solanaingestor.NewBlockLoader(client, token), from, to)
+	printReport(report)
+	return err
+}
+
+func verifyStellarRun(_ *cobra.Command, args []string) error {
+	from, to, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+	if to > uint64(^uint32(0)) {
+		return errors.New("invalid ledger range")
+	}
+
+	var version model.KinVersion
+	switch kinVersion {
+	case 2:
+		version = model.KinVersion_KIN2
+	case 3:
+		version = model.KinVersion_KIN3
+	default:
+		return errors.Errorf("unsupported kin version: %d", kinVersion)
+	}
+
+	client := &horizonclient.Client{
+		HorizonURL: horizonURL,
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+	}
+	loader := stellaringestor.NewLedgerLoader(version, client, networkPassphrase)
+
+	v, err := newVerifier()
+	if err != nil {
+		return err
+	}
+
+	report, err := v.VerifyStellar(context.Background(), loader, uint32(from), uint32(to))
+	printReport(report)
+	return err
+}
+
+func newVerifier() (*verify.Verifier, error) {
+	if !repair {
+		return verify.New(historyRW, nil), nil
+	}
+
+	// Repairs are written to the hot store, bypassing the archive.
+	repairer, ok := hotRW.(history.Repairer)
+	if !ok {
+		return nil, errors.New("history store does not support repairs")
+	}
+
+	return verify.New(historyRW, repairer), nil
+}
+
+func parseRange(args []string) (from, to uint64, err error) {
+	from, err = strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid from")
+	}
+	to, err = strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid to")
+	}
+	if from > to {
+		return 0, 0, errors.New("from must be less than or equal to to")
+	}
+
+	return from, to, nil
+}
+
+func printReport(r *verify.Report) {
+	if r == nil {
+		return
+	}
+
+	for _, issue := range r.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("Verified %d blocks (%d entries): %d issues\n", r.Blocks, r.Entries, len(r.Issues))
+}
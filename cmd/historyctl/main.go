@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/kinecosystem/agora/cmd/historyctl/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
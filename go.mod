@@ -5,12 +5,12 @@ go 1.13
 require (
 	cirello.io/dynamolock v1.3.3
 	cloud.google.com/go/bigquery v1.14.0
-	github.com/aws/aws-sdk-go v1.25.25
+	github.com/aws/aws-sdk-go v1.30.19
 	github.com/aws/aws-sdk-go-v2 v0.17.0
 	github.com/envoyproxy/protoc-gen-validate v0.1.0
 	github.com/go-redis/redis/v7 v7.0.0
 	github.com/go-redis/redis_rate/v8 v8.0.0
-	github.com/golang/protobuf v1.4.3
+	github.com/golang/protobuf v1.5.0
 	github.com/google/uuid v1.1.2
 	github.com/hashicorp/golang-lru v0.5.1
 	github.com/jackc/pgx/v4 v4.9.2
@@ -21,16 +21,24 @@ require (
 	github.com/ory/dockertest v3.3.5+incompatible
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.7.1
+	github.com/segmentio/kafka-go v0.3.5
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v1.1.1
 	github.com/stellar/go v0.0.0-20191211203732-552e507ffa37
-	github.com/stretchr/testify v1.5.1
+	github.com/stretchr/testify v1.7.0
+	github.com/xitongsys/parquet-go v1.5.4
+	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
 	github.com/ybbus/jsonrpc v2.1.2+incompatible
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/exporters/otlp v0.20.0
+	go.opentelemetry.io/otel/sdk v0.20.0
+	go.opentelemetry.io/otel/trace v0.20.0
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	google.golang.org/api v0.36.0
-	google.golang.org/grpc v1.33.2
+	google.golang.org/grpc v1.37.0
+	google.golang.org/protobuf v1.26.0
 )
 
 // This dependency of stellar/go no longer exists; use a forked version of the repo instead.
@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"context"
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound indicates that the destination is not tracked.
+	ErrNotFound = errors.New("destination not found")
+)
+
+// Destination is a tracked destination, along with the transfer
+// statistics that have been recorded for it.
+//
+// The address may either be a token account, or the owner of token
+// accounts, in which case transfers to any of its token accounts are
+// attributed to it.
+type Destination struct {
+	Address ed25519.PublicKey
+	Label   string
+
+	TransferCount uint64
+	QuarkVolume   uint64
+}
+
+// Store stores tracked destinations and their statistics.
+type Store interface {
+	// Add starts tracking the destination with the specified label.
+	//
+	// If the destination is already tracked, only the label is updated.
+	Add(ctx context.Context, address ed25519.PublicKey, label string) error
+
+	// Remove stops tracking the destination, discarding any statistics.
+	//
+	// Removes are idempotent.
+	Remove(ctx context.Context, address ed25519.PublicKey) error
+
+	// Get returns the destination.
+	//
+	// ErrNotFound is returned if the destination is not tracked.
+	Get(ctx context.Context, address ed25519.PublicKey) (*Destination, error)
+
+	// List returns all tracked destinations, ordered by address.
+	List(ctx context.Context) ([]*Destination, error)
+
+	// Record adds the specified number of transfers and quarks to the
+	// destination's statistics.
+	//
+	// ErrNotFound is returned if the destination is not tracked.
+	Record(ctx context.Context, address ed25519.PublicKey, transfers, quarks uint64) error
+}
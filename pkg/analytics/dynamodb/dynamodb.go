@@ -0,0 +1,150 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbiface"
+	dynamodbutil "github.com/kinecosystem/agora-common/aws/dynamodb/util"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/analytics"
+)
+
+type db struct {
+	db dynamodbiface.ClientAPI
+}
+
+// New returns a dynamodb-backed analytics.Store
+func New(client dynamodbiface.ClientAPI) analytics.Store {
+	return &db{
+		db: client,
+	}
+}
+
+// Add implements analytics.Store.Add
+func (d *db) Add(ctx context.Context, address ed25519.PublicKey, label string) error {
+	_, err := d.db.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		TableName:        tableNameStr,
+		UpdateExpression: addUpdateExprStr,
+		Key: map[string]dynamodb.AttributeValue{
+			tableHashKey: {B: address},
+		},
+		ExpressionAttributeNames: map[string]string{
+			"#label": labelAttr,
+		},
+		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+			":label": {S: aws.String(label)},
+		},
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to add destination")
+	}
+
+	return nil
+}
+
+// Remove implements analytics.Store.Remove
+func (d *db) Remove(ctx context.Context, address ed25519.PublicKey) error {
+	_, err := d.db.DeleteItemRequest(&dynamodb.DeleteItemInput{
+		TableName: tableNameStr,
+		Key: map[string]dynamodb.AttributeValue{
+			tableHashKey: {B: address},
+		},
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove destination")
+	}
+
+	return nil
+}
+
+// Get implements analytics.Store.Get
+func (d *db) Get(ctx context.Context, address ed25519.PublicKey) (*analytics.Destination, error) {
+	resp, err := d.db.GetItemRequest(&dynamodb.GetItemInput{
+		TableName: tableNameStr,
+		Key: map[string]dynamodb.AttributeValue{
+			tableHashKey: {B: address},
+		},
+		ConsistentRead: aws.Bool(true),
+	}).Send(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get destination")
+	}
+
+	if len(resp.Item) == 0 {
+		return nil, analytics.ErrNotFound
+	}
+
+	return fromItem(resp.Item)
+}
+
+// List implements analytics.Store.List
+func (d *db) List(ctx context.Context) ([]*analytics.Destination, error) {
+	var destinations []*analytics.Destination
+
+	input := &dynamodb.ScanInput{
+		TableName:      tableNameStr,
+		ConsistentRead: aws.Bool(true),
+	}
+	for {
+		resp, err := d.db.ScanRequest(input).Send(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan destinations")
+		}
+
+		for _, item := range resp.Items {
+			dest, err := fromItem(item)
+			if err != nil {
+				return nil, err
+			}
+
+			destinations = append(destinations, dest)
+		}
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+
+	sort.Slice(destinations, func(i, j int) bool {
+		return bytes.Compare(destinations[i].Address, destinations[j].Address) < 0
+	})
+
+	return destinations, nil
+}
+
+// Record implements analytics.Store.Record
+func (d *db) Record(ctx context.Context, address ed25519.PublicKey, transfers, quarks uint64) error {
+	_, err := d.db.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		TableName:           tableNameStr,
+		UpdateExpression:    recordUpdateExprStr,
+		ConditionExpression: recordConditionStr,
+		Key: map[string]dynamodb.AttributeValue{
+			tableHashKey: {B: address},
+		},
+		ExpressionAttributeNames: map[string]string{
+			"#transfer_count": transferCountAttr,
+			"#quark_volume":   quarkVolumeAttr,
+		},
+		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+			":transfers": {N: aws.String(strconv.FormatUint(transfers, 10))},
+			":quarks":    {N: aws.String(strconv.FormatUint(quarks, 10))},
+		},
+	}).Send(ctx)
+	if err != nil {
+		if dynamodbutil.IsConditionalCheckFailed(err) {
+			return analytics.ErrNotFound
+		}
+
+		return errors.Wrap(err, "failed to record destination stats")
+	}
+
+	return nil
+}
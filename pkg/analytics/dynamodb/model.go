@@ -0,0 +1,71 @@
+package dynamodb
+
+import (
+	"crypto/ed25519"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/analytics"
+)
+
+const (
+	tableName = "tracked-destinations"
+
+	tableHashKey      = "address"
+	labelAttr         = "label"
+	transferCountAttr = "transfer_count"
+	quarkVolumeAttr   = "quark_volume"
+
+	addUpdateExpr    = "SET #label = :label"
+	recordUpdateExpr = "ADD #transfer_count :transfers, #quark_volume :quarks"
+	recordCondition  = "attribute_exists(address)"
+)
+
+var (
+	tableNameStr        = aws.String(tableName)
+	addUpdateExprStr    = aws.String(addUpdateExpr)
+	recordUpdateExprStr = aws.String(recordUpdateExpr)
+	recordConditionStr  = aws.String(recordCondition)
+)
+
+func fromItem(item map[string]dynamodb.AttributeValue) (*analytics.Destination, error) {
+	address, ok := item[tableHashKey]
+	if !ok || len(address.B) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid address")
+	}
+
+	d := &analytics.Destination{
+		Address: address.B,
+	}
+
+	if label, ok := item[labelAttr]; ok && label.S != nil {
+		d.Label = *label.S
+	}
+
+	var err error
+	if d.TransferCount, err = parseUint(item, transferCountAttr); err != nil {
+		return nil, err
+	}
+	if d.QuarkVolume, err = parseUint(item, quarkVolumeAttr); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func parseUint(item map[string]dynamodb.AttributeValue, attr string) (uint64, error) {
+	v, ok := item[attr]
+	if !ok || v.N == nil {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(*v.N, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", attr)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"sort"
+	"sync"
+
+	"github.com/kinecosystem/agora/pkg/analytics"
+)
+
+type store struct {
+	sync.Mutex
+	destinations map[string]analytics.Destination
+}
+
+// New returns an in-memory analytics.Store
+func New() analytics.Store {
+	return &store{
+		destinations: make(map[string]analytics.Destination),
+	}
+}
+
+func (s *store) reset() {
+	s.Lock()
+	s.destinations = make(map[string]analytics.Destination)
+	s.Unlock()
+}
+
+// Add implements analytics.Store.Add
+func (s *store) Add(_ context.Context, address ed25519.PublicKey, label string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	d, ok := s.destinations[string(address)]
+	if !ok {
+		d.Address = append(ed25519.PublicKey{}, address...)
+	}
+	d.Label = label
+
+	s.destinations[string(address)] = d
+	return nil
+}
+
+// Remove implements analytics.Store.Remove
+func (s *store) Remove(_ context.Context, address ed25519.PublicKey) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.destinations, string(address))
+	return nil
+}
+
+// Get implements analytics.Store.Get
+func (s *store) Get(_ context.Context, address ed25519.PublicKey) (*analytics.Destination, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	d, ok := s.destinations[string(address)]
+	if !ok {
+		return nil, analytics.ErrNotFound
+	}
+
+	return &d, nil
+}
+
+// List implements analytics.Store.List
+func (s *store) List(_ context.Context) ([]*analytics.Destination, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	destinations := make([]*analytics.Destination, 0, len(s.destinations))
+	for _, d := range s.destinations {
+		clone := d
+		destinations = append(destinations, &clone)
+	}
+
+	sort.Slice(destinations, func(i, j int) bool {
+		return bytes.Compare(destinations[i].Address, destinations[j].Address) < 0
+	})
+
+	return destinations, nil
+}
+
+// Record implements analytics.Store.Record
+func (s *store) Record(_ context.Context, address ed25519.PublicKey, transfers, quarks uint64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	d, ok := s.destinations[string(address)]
+	if !ok {
+		return analytics.ErrNotFound
+	}
+
+	d.TransferCount += transfers
+	d.QuarkVolume += quarks
+	s.destinations[string(address)] = d
+	return nil
+}
@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/kinecosystem/agora/pkg/analytics/tests"
+)
+
+func TestStore(t *testing.T) {
+	s := New()
+	tests.RunTests(t, s, s.(*store).reset)
+}
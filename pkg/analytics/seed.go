@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+)
+
+// DefaultDestinations are the destinations that were tracked prior to
+// destinations being configurable, keyed by address.
+var DefaultDestinations = map[string]string{
+	"Fapzahf3E91zAvr1yvNw3mYLv7t2DR6EnT8xjY74fT7C": "Rave",
+	"4bRrapNAChmZMLugZacanKfqW5KyHygcWEReBVNiMTUU": "Rave (owner)",
+	"CncYnFygz323VNY6okoiv6ycByLumgHzXSBFzXDDFNEZ": "Peerbet",
+	"BUS5SyrVLhgakivdRcmZE5F69HdR8xJBjHTiF2mqdKpt": "Peerbet (owner)",
+	"2K8XpTqVAheX9cF2niwkTQQBajEwr84TeP34wiYUCoLy": "PauseFor",
+	"3rad7aFPdJS3CkYPSphtDAWCNB8BYpV2yc7o5ZjFQbDb": "PauseFor (owner)",
+	"7cqCpmzfZphbhzctXLJVabxQecFtYp6Bg4vQXo11SiNM": "Poppin",
+	"ejsuFLdZo3YBu4qeuSw9ozbFPwPaUd3Xc2PPuDRpPdS":  "Poppin (owner)",
+}
+
+// Seed tracks the provided destinations (keyed by address) if the store
+// does not track any destinations.
+//
+// Seeding only an empty store ensures that destinations removed by an
+// operator are not tracked again on restart.
+func Seed(ctx context.Context, store Store, destinations map[string]string) error {
+	existing, err := store.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list tracked destinations")
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	for address, label := range destinations {
+		raw, err := base58.Decode(address)
+		if err != nil {
+			return errors.Wrapf(err, "invalid destination address: %s", address)
+		}
+
+		if err := store.Add(ctx, raw, label); err != nil {
+			return errors.Wrapf(err, "failed to track %s", address)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	analyticspb "github.com/kinecosystem/agora/pkg/analytics/proto"
+)
+
+type server struct {
+	log   *logrus.Entry
+	store Store
+}
+
+// NewServer returns an analyticspb.AdminServer backed by the provided store.
+func NewServer(store Store) analyticspb.AdminServer {
+	return &server{
+		log:   logrus.StandardLogger().WithField("type", "analytics/server"),
+		store: store,
+	}
+}
+
+// TrackDestination implements analyticspb.AdminServer.TrackDestination.
+func (s *server) TrackDestination(ctx context.Context, req *analyticspb.TrackDestinationRequest) (*analyticspb.VoidResponse, error) {
+	if err := s.store.Add(ctx, req.Address, req.Label); err != nil {
+		s.log.WithError(err).Warn("failed to track destination")
+		return nil, status.Error(codes.Internal, "failed to track destination")
+	}
+
+	return &analyticspb.VoidResponse{}, nil
+}
+
+// UntrackDestination implements analyticspb.AdminServer.UntrackDestination.
+func (s *server) UntrackDestination(ctx context.Context, req *analyticspb.UntrackDestinationRequest) (*analyticspb.VoidResponse, error) {
+	if err := s.store.Remove(ctx, req.Address); err != nil {
+		s.log.WithError(err).Warn("failed to untrack destination")
+		return nil, status.Error(codes.Internal, "failed to untrack destination")
+	}
+
+	return &analyticspb.VoidResponse{}, nil
+}
+
+// GetDestinationStats implements analyticspb.AdminServer.GetDestinationStats.
+func (s *server) GetDestinationStats(ctx context.Context, req *analyticspb.GetDestinationStatsRequest) (*analyticspb.GetDestinationStatsResponse, error) {
+	var destinations []*Destination
+	switch len(req.Address) {
+	case 0:
+		var err error
+		destinations, err = s.store.List(ctx)
+		if err != nil {
+			s.log.WithError(err).Warn("failed to list destinations")
+			return nil, status.Error(codes.Internal, "failed to list destinations")
+		}
+	case 32:
+		d, err := s.store.Get(ctx, req.Address)
+		if err == ErrNotFound {
+			return &analyticspb.GetDestinationStatsResponse{
+				Result: analyticspb.GetDestinationStatsResponse_NOT_FOUND,
+			}, nil
+		} else if err != nil {
+			s.log.WithError(err).Warn("failed to get destination")
+			return nil, status.Error(codes.Internal, "failed to get destination")
+		}
+
+		destinations = append(destinations, d)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "address must be empty or 32 bytes")
+	}
+
+	resp := &analyticspb.GetDestinationStatsResponse{
+		Destinations: make([]*analyticspb.DestinationStats, len(destinations)),
+	}
+	for i, d := range destinations {
+		resp.Destinations[i] = &analyticspb.DestinationStats{
+			Address:       d.Address,
+			Label:         d.Label,
+			TransferCount: d.TransferCount,
+			QuarkVolume:   d.QuarkVolume,
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,66 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kinecosystem/agora/pkg/analytics"
+	"github.com/kinecosystem/agora/pkg/analytics/memory"
+	analyticspb "github.com/kinecosystem/agora/pkg/analytics/proto"
+	"github.com/kinecosystem/agora/pkg/testutil"
+)
+
+func TestServer(t *testing.T) {
+	store := memory.New()
+	s := analytics.NewServer(store)
+	ctx := context.Background()
+
+	keys := testutil.GenerateSolanaKeys(t, 2)
+	testutil.SortKeys(keys)
+
+	resp, err := s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, analyticspb.GetDestinationStatsResponse_OK, resp.Result)
+	assert.Empty(t, resp.Destinations)
+
+	resp, err = s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{Address: keys[0]})
+	require.NoError(t, err)
+	assert.Equal(t, analyticspb.GetDestinationStatsResponse_NOT_FOUND, resp.Result)
+
+	for i, k := range keys {
+		_, err = s.TrackDestination(ctx, &analyticspb.TrackDestinationRequest{Address: k, Label: "partner"})
+		require.NoError(t, err)
+		require.NoError(t, store.Record(ctx, k, uint64(i+1), uint64(10*(i+1))))
+	}
+
+	resp, err = s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, analyticspb.GetDestinationStatsResponse_OK, resp.Result)
+	require.Len(t, resp.Destinations, 2)
+	for i, d := range resp.Destinations {
+		assert.EqualValues(t, keys[i], d.Address)
+		assert.Equal(t, "partner", d.Label)
+		assert.EqualValues(t, i+1, d.TransferCount)
+		assert.EqualValues(t, 10*(i+1), d.QuarkVolume)
+	}
+
+	resp, err = s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{Address: keys[1]})
+	require.NoError(t, err)
+	require.Len(t, resp.Destinations, 1)
+	assert.EqualValues(t, keys[1], resp.Destinations[0].Address)
+
+	_, err = s.UntrackDestination(ctx, &analyticspb.UntrackDestinationRequest{Address: keys[1]})
+	require.NoError(t, err)
+
+	resp, err = s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{Address: keys[1]})
+	require.NoError(t, err)
+	assert.Equal(t, analyticspb.GetDestinationStatsResponse_NOT_FOUND, resp.Result)
+
+	_, err = s.GetDestinationStats(ctx, &analyticspb.GetDestinationStatsRequest{Address: make([]byte, 16)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/analytics"
+	"github.com/kinecosystem/agora/pkg/testutil"
+)
+
+func RunTests(t *testing.T, s analytics.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s analytics.Store){
+		testRoundTrip,
+		testRecord,
+		testList,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s analytics.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+		address := testutil.GenerateSolanaKeys(t, 1)[0]
+
+		_, err := s.Get(ctx, address)
+		assert.Equal(t, analytics.ErrNotFound, err)
+
+		require.NoError(t, s.Add(ctx, address, "partner"))
+
+		d, err := s.Get(ctx, address)
+		require.NoError(t, err)
+		assert.Equal(t, &analytics.Destination{Address: address, Label: "partner"}, d)
+
+		// Re-adding should only update the label.
+		require.NoError(t, s.Record(ctx, address, 1, 10))
+		require.NoError(t, s.Add(ctx, address, "renamed"))
+
+		d, err = s.Get(ctx, address)
+		require.NoError(t, err)
+		assert.Equal(t, &analytics.Destination{Address: address, Label: "renamed", TransferCount: 1, QuarkVolume: 10}, d)
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, s.Remove(ctx, address))
+		}
+
+		_, err = s.Get(ctx, address)
+		assert.Equal(t, analytics.ErrNotFound, err)
+	})
+}
+
+func testRecord(t *testing.T, s analytics.Store) {
+	t.Run("testRecord", func(t *testing.T) {
+		ctx := context.Background()
+		address := testutil.GenerateSolanaKeys(t, 1)[0]
+
+		assert.Equal(t, analytics.ErrNotFound, s.Record(ctx, address, 1, 10))
+
+		// Recording should not implicitly track a destination.
+		_, err := s.Get(ctx, address)
+		assert.Equal(t, analytics.ErrNotFound, err)
+
+		require.NoError(t, s.Add(ctx, address, "partner"))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.Record(ctx, address, 2, 10))
+		}
+
+		d, err := s.Get(ctx, address)
+		require.NoError(t, err)
+		assert.EqualValues(t, 6, d.TransferCount)
+		assert.EqualValues(t, 30, d.QuarkVolume)
+	})
+}
+
+func testList(t *testing.T, s analytics.Store) {
+	t.Run("testList", func(t *testing.T) {
+		ctx := context.Background()
+
+		destinations, err := s.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, destinations)
+
+		addresses := testutil.GenerateSolanaKeys(t, 5)
+		testutil.SortKeys(addresses)
+		for i, a := range addresses {
+			require.NoError(t, s.Add(ctx, a, "partner"))
+			require.NoError(t, s.Record(ctx, a, uint64(i), uint64(i*10)))
+		}
+
+		destinations, err = s.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, destinations, len(addresses))
+		for i, d := range destinations {
+			assert.EqualValues(t, addresses[i], d.Address)
+			assert.Equal(t, "partner", d.Label)
+			assert.EqualValues(t, i, d.TransferCount)
+			assert.EqualValues(t, i*10, d.QuarkVolume)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package analytics
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+	"time"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/account"
+)
+
+var (
+	transferByDest = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "transfer_by_dest",
+		Help:      "Number of transfers by destination (tracked)",
+	}, []string{"dest"})
+	quarksByDest = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "transfer_quarks_by_dest",
+		Help:      "Number of quarks transferred by destination (tracked)",
+	}, []string{"dest", "label"})
+	droppedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "transfer_tracking_dropped",
+		Help:      "Number of transactions whose transfers were not tracked due to load",
+	})
+)
+
+const (
+	maxConcurrentTracks = 64
+	trackTimeout        = 10 * time.Second
+)
+
+func init() {
+	if err := prometheus.Register(transferByDest); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			transferByDest = e.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			logrus.WithError(err).Error("failed to register transferByDest")
+		}
+	}
+	if err := prometheus.Register(quarksByDest); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			quarksByDest = e.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			logrus.WithError(err).Error("failed to register quarksByDest")
+		}
+	}
+	if err := prometheus.Register(droppedCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			droppedCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			logrus.WithError(err).Error("failed to register droppedCounter")
+		}
+	}
+}
+
+// Transfer is a transfer to a (potentially) tracked destination.
+type Transfer struct {
+	Destination ed25519.PublicKey
+	Amount      uint64
+}
+
+// Tracker records transfers to tracked destinations.
+//
+// The set of tracked destinations is periodically reloaded from the
+// store, so destinations may be added or removed without a restart.
+type Tracker struct {
+	log             *logrus.Entry
+	store           Store
+	mapper          account.Mapper
+	refreshInterval time.Duration
+	sem             chan struct{}
+
+	mu          sync.RWMutex
+	tracked     map[string]string
+	lastRefresh time.Time
+}
+
+// NewTracker returns a new Tracker.
+//
+// Transfers whose destination token account is not tracked are attributed
+// to the owner of the token account (resolved using mapper), if the owner
+// is tracked.
+func NewTracker(store Store, mapper account.Mapper, refreshInterval time.Duration) *Tracker {
+	return &Tracker{
+		log:             logrus.StandardLogger().WithField("type", "analytics/tracker"),
+		store:           store,
+		mapper:          mapper,
+		refreshInterval: refreshInterval,
+		sem:             make(chan struct{}, maxConcurrentTracks),
+	}
+}
+
+// TrackAsync tracks the transfers in the background, so that callers are not
+// delayed by resolving destination owners.
+//
+// Tracking is best effort. If too many transfers are already being tracked,
+// the transfers are dropped.
+func (t *Tracker) TrackAsync(transfers []Transfer) {
+	select {
+	case t.sem <- struct{}{}:
+	default:
+		droppedCounter.Inc()
+		return
+	}
+
+	go func() {
+		defer func() { <-t.sem }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), trackTimeout)
+		defer cancel()
+
+		if err := t.Track(ctx, transfers); err != nil {
+			t.log.WithError(err).Warn("failed to track transfer destinations")
+		}
+	}()
+}
+
+// Track records the transfers that were sent to tracked destinations.
+func (t *Tracker) Track(ctx context.Context, transfers []Transfer) error {
+	tracked, err := t.getTracked(ctx)
+	if err != nil {
+		return err
+	}
+	if len(tracked) == 0 {
+		return nil
+	}
+
+	type stats struct {
+		transfers uint64
+		quarks    uint64
+	}
+	byDest := make(map[string]*stats)
+
+	for _, transfer := range transfers {
+		dest := transfer.Destination
+		if _, ok := tracked[string(dest)]; !ok {
+			owner, err := t.mapper.Get(ctx, dest, solana.CommitmentRecent)
+			if err == account.ErrNotFound {
+				continue
+			} else if err != nil {
+				return errors.Wrap(err, "failed to resolve destination owner")
+			}
+
+			if _, ok := tracked[string(owner)]; !ok {
+				continue
+			}
+			dest = owner
+		}
+
+		s, ok := byDest[string(dest)]
+		if !ok {
+			s = &stats{}
+			byDest[string(dest)] = s
+		}
+		s.transfers++
+		s.quarks += transfer.Amount
+	}
+
+	for dest, s := range byDest {
+		addr := base58.Encode([]byte(dest))
+		transferByDest.WithLabelValues(addr).Add(float64(s.transfers))
+		quarksByDest.WithLabelValues(addr, tracked[dest]).Add(float64(s.quarks))
+
+		// The destination may have been removed since we last refreshed,
+		// which is fine to ignore.
+		err := t.store.Record(ctx, ed25519.PublicKey(dest), s.transfers, s.quarks)
+		if err != nil && err != ErrNotFound {
+			return errors.Wrapf(err, "failed to record stats for %s", addr)
+		}
+	}
+
+	return nil
+}
+
+func (t *Tracker) getTracked(ctx context.Context) (map[string]string, error) {
+	t.mu.RLock()
+	tracked, lastRefresh := t.tracked, t.lastRefresh
+	t.mu.RUnlock()
+
+	if tracked != nil && time.Since(lastRefresh) < t.refreshInterval {
+		return tracked, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Another caller may have refreshed while we were waiting.
+	if t.tracked != nil && time.Since(t.lastRefresh) < t.refreshInterval {
+		return t.tracked, nil
+	}
+
+	destinations, err := t.store.List(ctx)
+	if err != nil {
+		if t.tracked != nil {
+			// Back off until the next interval, rather than hammering
+			// the store on every call.
+			t.log.WithError(err).Warn("failed to refresh tracked destinations, using previous set")
+			t.lastRefresh = time.Now()
+			return t.tracked, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to load tracked destinations")
+	}
+
+	t.tracked = make(map[string]string, len(destinations))
+	for _, d := range destinations {
+		t.tracked[string(d.Address)] = d.Label
+	}
+	t.lastRefresh = time.Now()
+
+	t.log.WithField("count", len(t.tracked)).Debug("refreshed tracked destinations")
+	return t.tracked, nil
+}
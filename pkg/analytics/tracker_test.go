@@ -0,0 +1,123 @@
+package analytics_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/account"
+	mappermemory "github.com/kinecosystem/agora/pkg/account/memory"
+	"github.com/kinecosystem/agora/pkg/analytics"
+	"github.com/kinecosystem/agora/pkg/analytics/memory"
+	"github.com/kinecosystem/agora/pkg/testutil"
+)
+
+type trackerEnv struct {
+	store   analytics.Store
+	mapper  account.Mapper
+	tracker *analytics.Tracker
+}
+
+func setupTracker(refreshInterval time.Duration) (env trackerEnv) {
+	env.store = memory.New()
+	env.mapper = mappermemory.New()
+	env.tracker = analytics.NewTracker(env.store, env.mapper, refreshInterval)
+	return env
+}
+
+func TestTracker_NoDestinations(t *testing.T) {
+	env := setupTracker(time.Minute)
+
+	keys := testutil.GenerateSolanaKeys(t, 1)
+	require.NoError(t, env.tracker.Track(context.Background(), []analytics.Transfer{
+		{Destination: keys[0], Amount: 10},
+	}))
+
+	destinations, err := env.store.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, destinations)
+}
+
+func TestTracker_Track(t *testing.T) {
+	env := setupTracker(time.Minute)
+
+	keys := testutil.GenerateSolanaKeys(t, 5)
+	tokenAccount, owner, ownerTokenAccount, untracked, unmapped := keys[0], keys[1], keys[2], keys[3], keys[4]
+
+	require.NoError(t, env.store.Add(context.Background(), tokenAccount, "token"))
+	require.NoError(t, env.store.Add(context.Background(), owner, "owner"))
+	require.NoError(t, env.mapper.Add(context.Background(), ownerTokenAccount, owner))
+	require.NoError(t, env.mapper.Add(context.Background(), untracked, testutil.GenerateSolanaKeys(t, 1)[0]))
+
+	require.NoError(t, env.tracker.Track(context.Background(), []analytics.Transfer{
+		{Destination: tokenAccount, Amount: 10},
+		{Destination: tokenAccount, Amount: 20},
+		{Destination: ownerTokenAccount, Amount: 5},
+		{Destination: untracked, Amount: 100},
+		{Destination: unmapped, Amount: 100},
+	}))
+
+	d, err := env.store.Get(context.Background(), tokenAccount)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, d.TransferCount)
+	assert.EqualValues(t, 30, d.QuarkVolume)
+
+	d, err = env.store.Get(context.Background(), owner)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, d.TransferCount)
+	assert.EqualValues(t, 5, d.QuarkVolume)
+
+	for _, k := range []ed25519.PublicKey{ownerTokenAccount, untracked, unmapped} {
+		_, err = env.store.Get(context.Background(), k)
+		assert.Equal(t, analytics.ErrNotFound, err)
+	}
+}
+
+func TestTracker_Refresh(t *testing.T) {
+	env := setupTracker(50 * time.Millisecond)
+
+	keys := testutil.GenerateSolanaKeys(t, 1)
+
+	// Prime the tracked set, which should be empty.
+	require.NoError(t, env.tracker.Track(context.Background(), []analytics.Transfer{{Destination: keys[0], Amount: 1}}))
+	require.NoError(t, env.store.Add(context.Background(), keys[0], "new"))
+
+	// Until a refresh occurs, the new destination should not be tracked.
+	require.NoError(t, env.tracker.Track(context.Background(), []analytics.Transfer{{Destination: keys[0], Amount: 1}}))
+	d, err := env.store.Get(context.Background(), keys[0])
+	require.NoError(t, err)
+	assert.Zero(t, d.TransferCount)
+
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, env.tracker.Track(context.Background(), []analytics.Transfer{{Destination: keys[0], Amount: 1}}))
+	d, err = env.store.Get(context.Background(), keys[0])
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, d.TransferCount)
+}
+
+func TestSeed(t *testing.T) {
+	store := memory.New()
+	require.NoError(t, analytics.Seed(context.Background(), store, analytics.DefaultDestinations))
+
+	destinations, err := store.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, destinations, len(analytics.DefaultDestinations))
+	for _, d := range destinations {
+		assert.Equal(t, analytics.DefaultDestinations[base58.Encode(d.Address)], d.Label)
+	}
+
+	// Once destinations are tracked, seeding is a no-op, so removed
+	// destinations stay removed.
+	require.NoError(t, store.Remove(context.Background(), destinations[0].Address))
+	require.NoError(t, analytics.Seed(context.Background(), store, analytics.DefaultDestinations))
+
+	destinations, err = store.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, destinations, len(analytics.DefaultDestinations)-1)
+}
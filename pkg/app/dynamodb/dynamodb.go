@@ -70,3 +70,39 @@ func (d *db) Get(ctx context.Context, appIndex uint16) (*app.Config, error) {
 
 	return fromItem(resp.Item)
 }
+
+// SetEventFilters implements app.ConfigStore.SetEventFilters
+func (d *db) SetEventFilters(ctx context.Context, appIndex uint16, filters []app.EventFilter) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: tableNameStr,
+		Key: map[string]dynamodb.AttributeValue{
+			tableHashKey: {
+				N: aws.String(strconv.Itoa(int(appIndex))),
+			},
+		},
+		ConditionExpression: updateConditionStr,
+		UpdateExpression:    clearEventFiltersUpdateStr,
+	}
+
+	if len(filters) > 0 {
+		av, err := toEventFiltersValue(filters)
+		if err != nil {
+			return err
+		}
+
+		input.UpdateExpression = setEventFiltersUpdateStr
+		input.ExpressionAttributeValues = map[string]dynamodb.AttributeValue{
+			":event_filters": *av,
+		}
+	}
+
+	if _, err := d.db.UpdateItemRequest(input).Send(ctx); err != nil {
+		if dynamodbutil.IsConditionalCheckFailed(err) {
+			return app.ErrNotFound
+		}
+
+		return errors.Wrap(err, "failed to set event filters")
+	}
+
+	return nil
+}
@@ -1,11 +1,13 @@
 package dynamodb
 
 import (
+	"crypto/ed25519"
 	"net/url"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/pkg/errors"
 
 	"github.com/kinecosystem/agora/pkg/app"
@@ -15,12 +17,20 @@ const (
 	tableName    = "app-configs"
 	putCondition = "attribute_not_exists(app_index)"
 
+	updateCondition         = "attribute_exists(app_index)"
+	setEventFiltersUpdate   = "SET event_filters = :event_filters"
+	clearEventFiltersUpdate = "REMOVE event_filters"
+
 	tableHashKey = "app_index"
 )
 
 var (
 	tableNameStr    = aws.String(tableName)
 	putConditionStr = aws.String(putCondition)
+
+	updateConditionStr         = aws.String(updateCondition)
+	setEventFiltersUpdateStr   = aws.String(setEventFiltersUpdate)
+	clearEventFiltersUpdateStr = aws.String(clearEventFiltersUpdate)
 )
 
 type configItem struct {
@@ -29,6 +39,15 @@ type configItem struct {
 	SignTransactionURL string `dynamodbav:"sign_transaction_url,omitempty"`
 	EventsURL          string `dynamodbav:"events_url,omitempty"`
 	WebhookSecret      string `dynamodbav:"webhook_secret,omitempty"`
+
+	EventFilters []eventFilterItem `dynamodbav:"event_filters,omitempty"`
+}
+
+type eventFilterItem struct {
+	TransactionTypes []int16  `dynamodbav:"transaction_types,omitempty"`
+	Status           int      `dynamodbav:"status,omitempty"`
+	MinAmount        uint64   `dynamodbav:"min_amount,omitempty"`
+	Addresses        [][]byte `dynamodbav:"addresses,omitempty"`
 }
 
 func toItem(appIndex uint16, config *app.Config) (map[string]dynamodb.AttributeValue, error) {
@@ -56,10 +75,42 @@ func toItem(appIndex uint16, config *app.Config) (map[string]dynamodb.AttributeV
 	if config.EventsURL != nil {
 		configItem.EventsURL = config.EventsURL.String()
 	}
+	configItem.EventFilters = toEventFilterItems(config.EventFilters)
 
 	return dynamodbattribute.MarshalMap(configItem)
 }
 
+func toEventFiltersValue(filters []app.EventFilter) (*dynamodb.AttributeValue, error) {
+	av, err := dynamodbattribute.Marshal(toEventFilterItems(filters))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event filters")
+	}
+
+	return av, nil
+}
+
+func toEventFilterItems(filters []app.EventFilter) []eventFilterItem {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	items := make([]eventFilterItem, len(filters))
+	for i, f := range filters {
+		items[i] = eventFilterItem{
+			Status:    int(f.Status),
+			MinAmount: f.MinAmount,
+		}
+		for _, t := range f.TransactionTypes {
+			items[i].TransactionTypes = append(items[i].TransactionTypes, int16(t))
+		}
+		for _, a := range f.Addresses {
+			items[i].Addresses = append(items[i].Addresses, a)
+		}
+	}
+
+	return items
+}
+
 func fromItem(item map[string]dynamodb.AttributeValue) (*app.Config, error) {
 	var configItem configItem
 	if err := dynamodbattribute.UnmarshalMap(item, &configItem); err != nil {
@@ -86,5 +137,22 @@ func fromItem(item map[string]dynamodb.AttributeValue) (*app.Config, error) {
 		config.EventsURL = eventsURL
 	}
 
+	for _, item := range configItem.EventFilters {
+		f := app.EventFilter{
+			Status:    app.EventStatus(item.Status),
+			MinAmount: item.MinAmount,
+		}
+		for _, t := range item.TransactionTypes {
+			f.TransactionTypes = append(f.TransactionTypes, kin.TransactionType(t))
+		}
+		for _, a := range item.Addresses {
+			if len(a) != ed25519.PublicKeySize {
+				return nil, errors.Errorf("invalid event filter address length: %d", len(a))
+			}
+			f.Addresses = append(f.Addresses, a)
+		}
+		config.EventFilters = append(config.EventFilters, f)
+	}
+
 	return config, nil
 }
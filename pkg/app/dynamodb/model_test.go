@@ -1,10 +1,12 @@
 package dynamodb
 
 import (
+	"crypto/ed25519"
 	"net/url"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kinecosystem/agora/pkg/app"
@@ -24,6 +26,16 @@ func TestModelConversion_Full(t *testing.T) {
 		AppName:            "kin",
 		SignTransactionURL: signTxURL,
 		EventsURL:          eventsURL,
+		EventFilters: []app.EventFilter{
+			{
+				TransactionTypes: []kin.TransactionType{kin.TransactionTypeNone, kin.TransactionTypeP2P},
+				Status:           app.EventStatusFailed,
+			},
+			{
+				MinAmount: 10,
+				Addresses: []ed25519.PublicKey{make([]byte, ed25519.PublicKeySize)},
+			},
+		},
 	}
 
 	item, err := toItem(1, config)
@@ -32,6 +44,7 @@ func TestModelConversion_Full(t *testing.T) {
 	require.Equal(t, aws.StringValue(item["app_name"].S), config.AppName)
 	require.Equal(t, aws.StringValue(item["sign_transaction_url"].S), signTxURLStr)
 	require.Equal(t, aws.StringValue(item["events_url"].S), eventsURLStr)
+	require.Len(t, item["event_filters"].L, 2)
 
 	convertedConfig, err := fromItem(item)
 	require.NoError(t, err)
@@ -54,6 +67,9 @@ func TestModelConversion_WithEmpty(t *testing.T) {
 	_, ok = item["events_url"]
 	require.False(t, ok)
 
+	_, ok = item["event_filters"]
+	require.False(t, ok)
+
 	convertedConfig, err := fromItem(item)
 	require.NoError(t, err)
 	require.Equal(t, convertedConfig, config)
@@ -64,3 +64,18 @@ func (s *store) Get(ctx context.Context, appIndex uint16) (*app.Config, error) {
 
 	return &config, nil
 }
+
+// SetEventFilters implements app.ConfigStore.SetEventFilters
+func (s *store) SetEventFilters(ctx context.Context, appIndex uint16, filters []app.EventFilter) error {
+	s.Lock()
+	defer s.Unlock()
+
+	config, exists := s.configs[appIndex]
+	if !exists {
+		return app.ErrNotFound
+	}
+
+	config.EventFilters = filters
+	s.configs[appIndex] = config
+	return nil
+}
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"crypto/ed25519"
 	"net/url"
 
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/pkg/errors"
 )
 
@@ -15,4 +17,48 @@ type Config struct {
 	SignTransactionURL *url.URL
 	EventsURL          *url.URL
 	WebhookSecret      string
+
+	// EventFilters restricts the transaction events delivered to the app's
+	// events webhook. An event is delivered if it matches any of the
+	// filters. If there are no filters, every event is delivered.
+	EventFilters []EventFilter
+}
+
+// EventStatus is the status of the transaction in an event.
+type EventStatus int
+
+const (
+	// EventStatusAny matches both successful and failed transactions.
+	EventStatusAny EventStatus = iota
+	// EventStatusSuccessful matches successful transactions.
+	EventStatusSuccessful
+	// EventStatusFailed matches failed transactions.
+	EventStatusFailed
+)
+
+// EventFilter restricts the transaction events delivered to an app.
+//
+// The zero value of each field indicates that the field should not be
+// filtered on.
+type EventFilter struct {
+	// TransactionTypes restricts events to transactions with a Kin memo for
+	// the app of one of the specified types. Transactions attributed to the
+	// app by a text memo have no Kin memo, and are matched by
+	// kin.TransactionTypeNone.
+	TransactionTypes []kin.TransactionType
+
+	// Status restricts events to successful, or failed, transactions.
+	Status EventStatus
+
+	// MinAmount restricts events to transactions with a payment of at
+	// least MinAmount quarks.
+	MinAmount uint64
+
+	// Addresses restricts events to transactions with a payment sent to,
+	// sent from, or authorized by one of the addresses. Solana payments are
+	// sent to token accounts, and so are also matched by the owner of the
+	// destination account.
+	//
+	// If MinAmount is also set, the same payment must satisfy both.
+	Addresses []ed25519.PublicKey
 }
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"crypto/ed25519"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apppb "github.com/kinecosystem/agora/pkg/app/proto"
+)
+
+var (
+	transactionTypesFromProto = map[apppb.EventFilter_TransactionType]kin.TransactionType{
+		apppb.EventFilter_NONE:  kin.TransactionTypeNone,
+		apppb.EventFilter_EARN:  kin.TransactionTypeEarn,
+		apppb.EventFilter_SPEND: kin.TransactionTypeSpend,
+		apppb.EventFilter_P2P:   kin.TransactionTypeP2P,
+	}
+	transactionTypesToProto = map[kin.TransactionType]apppb.EventFilter_TransactionType{
+		kin.TransactionTypeNone:  apppb.EventFilter_NONE,
+		kin.TransactionTypeEarn:  apppb.EventFilter_EARN,
+		kin.TransactionTypeSpend: apppb.EventFilter_SPEND,
+		kin.TransactionTypeP2P:   apppb.EventFilter_P2P,
+	}
+	statusesFromProto = map[apppb.EventFilter_Status]EventStatus{
+		apppb.EventFilter_ANY:        EventStatusAny,
+		apppb.EventFilter_SUCCESSFUL: EventStatusSuccessful,
+		apppb.EventFilter_FAILED:     EventStatusFailed,
+	}
+	statusesToProto = map[EventStatus]apppb.EventFilter_Status{
+		EventStatusAny:        apppb.EventFilter_ANY,
+		EventStatusSuccessful: apppb.EventFilter_SUCCESSFUL,
+		EventStatusFailed:     apppb.EventFilter_FAILED,
+	}
+)
+
+type server struct {
+	log   *logrus.Entry
+	store ConfigStore
+}
+
+// NewServer returns an apppb.AdminServer backed by the provided store.
+func NewServer(store ConfigStore) apppb.AdminServer {
+	return &server{
+		log:   logrus.StandardLogger().WithField("type", "app/server"),
+		store: store,
+	}
+}
+
+// GetEventFilters implements apppb.AdminServer.GetEventFilters.
+func (s *server) GetEventFilters(ctx context.Context, req *apppb.GetEventFiltersRequest) (*apppb.GetEventFiltersResponse, error) {
+	config, err := s.store.Get(ctx, uint16(req.AppIndex))
+	if err == ErrNotFound {
+		return &apppb.GetEventFiltersResponse{Result: apppb.GetEventFiltersResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		s.log.WithError(err).Warn("failed to get app config")
+		return nil, status.Error(codes.Internal, "failed to get app config")
+	}
+
+	resp := &apppb.GetEventFiltersResponse{
+		Filters: make([]*apppb.EventFilter, len(config.EventFilters)),
+	}
+	for i, f := range config.EventFilters {
+		filter := &apppb.EventFilter{
+			Status:    statusesToProto[f.Status],
+			MinAmount: f.MinAmount,
+		}
+		for _, t := range f.TransactionTypes {
+			filter.TransactionTypes = append(filter.TransactionTypes, transactionTypesToProto[t])
+		}
+		for _, a := range f.Addresses {
+			filter.Addresses = append(filter.Addresses, a)
+		}
+		resp.Filters[i] = filter
+	}
+
+	return resp, nil
+}
+
+// SetEventFilters implements apppb.AdminServer.SetEventFilters.
+func (s *server) SetEventFilters(ctx context.Context, req *apppb.SetEventFiltersRequest) (*apppb.SetEventFiltersResponse, error) {
+	log := s.log.WithField("app_index", req.AppIndex)
+
+	var filters []EventFilter
+	for _, f := range req.Filters {
+		filter := EventFilter{
+			Status:    statusesFromProto[f.Status],
+			MinAmount: f.MinAmount,
+		}
+		for _, t := range f.TransactionTypes {
+			filter.TransactionTypes = append(filter.TransactionTypes, transactionTypesFromProto[t])
+		}
+		for _, a := range f.Addresses {
+			filter.Addresses = append(filter.Addresses, ed25519.PublicKey(a))
+		}
+		filters = append(filters, filter)
+	}
+
+	err := s.store.SetEventFilters(ctx, uint16(req.AppIndex), filters)
+	if err == ErrNotFound {
+		return &apppb.SetEventFiltersResponse{Result: apppb.SetEventFiltersResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failed to set event filters")
+		return nil, status.Error(codes.Internal, "failed to set event filters")
+	}
+
+	log.WithField("filters", len(filters)).Info("updated event filters")
+	return &apppb.SetEventFiltersResponse{}, nil
+}
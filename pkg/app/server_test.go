@@ -0,0 +1,86 @@
+package app_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/app"
+	"github.com/kinecosystem/agora/pkg/app/memory"
+	apppb "github.com/kinecosystem/agora/pkg/app/proto"
+	"github.com/kinecosystem/agora/pkg/testutil"
+)
+
+func TestServer(t *testing.T) {
+	store := memory.New()
+	s := app.NewServer(store)
+	ctx := context.Background()
+
+	getResp, err := s.GetEventFilters(ctx, &apppb.GetEventFiltersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.GetEventFiltersResponse_NOT_FOUND, getResp.Result)
+
+	filters := []*apppb.EventFilter{
+		{
+			TransactionTypes: []apppb.EventFilter_TransactionType{apppb.EventFilter_NONE, apppb.EventFilter_SPEND},
+			Status:           apppb.EventFilter_SUCCESSFUL,
+		},
+		{
+			MinAmount: 10,
+			Addresses: [][]byte{testutil.GenerateSolanaKeys(t, 1)[0]},
+		},
+	}
+
+	setResp, err := s.SetEventFilters(ctx, &apppb.SetEventFiltersRequest{AppIndex: 1, Filters: filters})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.SetEventFiltersResponse_NOT_FOUND, setResp.Result)
+
+	require.NoError(t, store.Add(ctx, 1, &app.Config{AppName: "kin"}))
+
+	getResp, err = s.GetEventFilters(ctx, &apppb.GetEventFiltersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.GetEventFiltersResponse_OK, getResp.Result)
+	assert.Empty(t, getResp.Filters)
+
+	setResp, err = s.SetEventFilters(ctx, &apppb.SetEventFiltersRequest{AppIndex: 1, Filters: filters})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.SetEventFiltersResponse_OK, setResp.Result)
+
+	config, err := store.Get(ctx, 1)
+	require.NoError(t, err)
+	expected := []app.EventFilter{
+		{
+			TransactionTypes: []kin.TransactionType{kin.TransactionTypeNone, kin.TransactionTypeSpend},
+			Status:           app.EventStatusSuccessful,
+		},
+		{
+			MinAmount: 10,
+			Addresses: []ed25519.PublicKey{filters[1].Addresses[0]},
+		},
+	}
+	assert.Equal(t, expected, config.EventFilters)
+
+	getResp, err = s.GetEventFilters(ctx, &apppb.GetEventFiltersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.GetEventFiltersResponse_OK, getResp.Result)
+	require.Len(t, getResp.Filters, 2)
+	for i := range filters {
+		assert.Equal(t, filters[i].TransactionTypes, getResp.Filters[i].TransactionTypes)
+		assert.Equal(t, filters[i].Status, getResp.Filters[i].Status)
+		assert.Equal(t, filters[i].MinAmount, getResp.Filters[i].MinAmount)
+		assert.Equal(t, filters[i].Addresses, getResp.Filters[i].Addresses)
+	}
+
+	// Clearing the filters should deliver every event.
+	setResp, err = s.SetEventFilters(ctx, &apppb.SetEventFiltersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Equal(t, apppb.SetEventFiltersResponse_OK, setResp.Result)
+
+	getResp, err = s.GetEventFilters(ctx, &apppb.GetEventFiltersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Empty(t, getResp.Filters)
+}
@@ -21,4 +21,9 @@ type ConfigStore interface {
 	//
 	// Returns ErrNotFound if it could not be found.
 	Get(ctx context.Context, appIndex uint16) (*Config, error)
+
+	// SetEventFilters replaces the event filters of an app's config.
+	//
+	// Returns ErrNotFound if the app index does not exist in the store.
+	SetEventFilters(ctx context.Context, appIndex uint16, filters []EventFilter) error
 }
@@ -2,16 +2,18 @@ package tests
 
 import (
 	"context"
+	"crypto/ed25519"
 	"net/url"
 	"testing"
 
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kinecosystem/agora/pkg/app"
 )
 
 func RunTests(t *testing.T, store app.ConfigStore, teardown func()) {
-	for _, tf := range []func(*testing.T, app.ConfigStore){testRoundTrip, testInvalidParameters} {
+	for _, tf := range []func(*testing.T, app.ConfigStore){testRoundTrip, testInvalidParameters, testEventFilters} {
 		tf(t, store)
 		teardown()
 	}
@@ -59,3 +61,40 @@ func testInvalidParameters(t *testing.T, store app.ConfigStore) {
 		require.Error(t, err)
 	})
 }
+
+func testEventFilters(t *testing.T, store app.ConfigStore) {
+	t.Run("testEventFilters", func(t *testing.T) {
+		filters := []app.EventFilter{
+			{
+				TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn, kin.TransactionTypeSpend},
+				Status:           app.EventStatusSuccessful,
+			},
+			{
+				MinAmount: 10,
+				Addresses: []ed25519.PublicKey{make([]byte, ed25519.PublicKeySize)},
+			},
+		}
+
+		err := store.SetEventFilters(context.Background(), 1, filters)
+		require.Equal(t, app.ErrNotFound, err)
+
+		config := &app.Config{
+			AppName: "kin",
+		}
+		require.NoError(t, store.Add(context.Background(), 1, config))
+
+		require.NoError(t, store.SetEventFilters(context.Background(), 1, filters))
+
+		config.EventFilters = filters
+		actualConfig, err := store.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, config, actualConfig)
+
+		require.NoError(t, store.SetEventFilters(context.Background(), 1, nil))
+
+		config.EventFilters = nil
+		actualConfig, err = store.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, config, actualConfig)
+	})
+}
@@ -0,0 +1,34 @@
+// Package redisutil provides helpers for creating redis clients from the
+// comma separated connection strings used to configure services.
+package redisutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// ParseAddrs parses a comma separated connection string into a set of named
+// shard addresses, suitable for redis.RingOptions.
+func ParseAddrs(connString string) map[string]string {
+	hosts := strings.Split(connString, ",")
+	addrs := make(map[string]string)
+	for i, host := range hosts {
+		addrs[fmt.Sprintf("server%d", i)] = host
+	}
+	return addrs
+}
+
+// NewClient returns a client for a comma separated connection string. A
+// single address results in a single node client, whereas multiple addresses
+// are treated as the seed nodes of a redis cluster.
+//
+// Unlike a redis.Ring, keys are never moved to another shard when a node is
+// unavailable, which is required by stores whose consistency depends on
+// scripts or conditional writes, such as locks and commits.
+func NewClient(connString string) redis.UniversalClient {
+	return redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: strings.Split(connString, ","),
+	})
+}
@@ -0,0 +1,25 @@
+package redisutil
+
+import (
+	"testing"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddrs(t *testing.T) {
+	assert.Equal(t, map[string]string{"server0": "a:6379"}, ParseAddrs("a:6379"))
+	assert.Equal(t, map[string]string{"server0": "a:6379", "server1": "b:6379"}, ParseAddrs("a:6379,b:6379"))
+}
+
+func TestNewClient(t *testing.T) {
+	single := NewClient("a:6379")
+	defer single.Close()
+	_, ok := single.(*redis.Client)
+	assert.True(t, ok)
+
+	cluster := NewClient("a:6379,b:6379")
+	defer cluster.Close()
+	_, ok = cluster.(*redis.ClusterClient)
+	assert.True(t, ok)
+}
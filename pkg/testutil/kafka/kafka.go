@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ory/dockertest"
+	dc "github.com/ory/dockertest/docker"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	containerName    = "bitnami/kafka"
+	containerVersion = "3.5"
+)
+
+var (
+	log = logrus.StandardLogger().WithField("type", "testutil/kafka")
+)
+
+// StartKafka starts a Docker container running a single Kafka broker (in
+// KRaft mode) and returns the broker address for testing purposes.
+func StartKafka(ctx context.Context, pool *dockertest.Pool) (broker string, closeFunc func(), err error) {
+	// The broker advertises the address clients should connect to, so it must
+	// be bound to a known port on the host.
+	port, err := freePort()
+	if err != nil {
+		return "", func() {}, errors.Wrap(err, "failed to find free port")
+	}
+	broker = fmt.Sprintf("localhost:%d", port)
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: containerName,
+		Tag:        containerVersion,
+		Env: []string{
+			"KAFKA_CFG_NODE_ID=0",
+			"KAFKA_CFG_PROCESS_ROLES=controller,broker",
+			"KAFKA_CFG_LISTENERS=PLAINTEXT://:9092,CONTROLLER://:9093",
+			"KAFKA_CFG_ADVERTISED_LISTENERS=PLAINTEXT://" + broker,
+			"KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+			"KAFKA_CFG_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+			"KAFKA_CFG_CONTROLLER_QUORUM_VOTERS=0@localhost:9093",
+		},
+		ExposedPorts: []string{"9092/tcp"},
+		PortBindings: map[dc.Port][]dc.PortBinding{
+			"9092/tcp": {{HostIP: "localhost", HostPort: fmt.Sprintf("%d", port)}},
+		},
+	})
+	if err != nil {
+		return "", func() {}, errors.Wrap(err, "failed to start resource")
+	}
+
+	closeFunc = func() {
+		if err := pool.Purge(resource); err != nil {
+			log.WithError(err).Warn("Failed to clean up Kafka resource")
+		}
+	}
+
+	err = pool.Retry(func() error {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			log.WithError(err).Trace("Kafka health check failed")
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Brokers()
+		return err
+	})
+	if err != nil {
+		closeFunc()
+		return "", func() {}, errors.Wrap(err, "kafka didn't come up in time")
+	}
+
+	return broker, closeFunc, nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
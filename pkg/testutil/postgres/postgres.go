@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ory/dockertest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	containerName    = "postgres"
+	containerVersion = "12"
+
+	user     = "agora"
+	password = "agora"
+	database = "agora"
+)
+
+var (
+	log = logrus.StandardLogger().WithField("type", "testutil/postgres")
+)
+
+// StartPostgres starts a Docker container using the postgres image and returns
+// a connection string for testing purposes.
+func StartPostgres(ctx context.Context, pool *dockertest.Pool) (connString string, closeFunc func(), err error) {
+	resource, err := pool.Run(containerName, containerVersion, []string{
+		"POSTGRES_USER=" + user,
+		"POSTGRES_PASSWORD=" + password,
+		"POSTGRES_DB=" + database,
+	})
+	if err != nil {
+		return "", func() {}, errors.Wrap(err, "failed to start resource")
+	}
+
+	closeFunc = func() {
+		if err := pool.Purge(resource); err != nil {
+			log.WithError(err).Warn("Failed to clean up Postgres resource")
+		}
+	}
+
+	connString = fmt.Sprintf(
+		"postgres://%s:%s@localhost:%s/%s?sslmode=disable",
+		user,
+		password,
+		resource.GetPort("5432/tcp"),
+		database,
+	)
+
+	err = pool.Retry(func() error {
+		conn, err := pgx.Connect(ctx, connString)
+		if err != nil {
+			log.WithError(err).Trace("Postgres health check failed")
+			return err
+		}
+		defer conn.Close(ctx)
+
+		return conn.Ping(ctx)
+	})
+	if err != nil {
+		closeFunc()
+		return "", func() {}, errors.Wrap(err, "postgres didn't come up in time")
+	}
+
+	return connString, closeFunc, nil
+}
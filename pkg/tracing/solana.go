@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"time"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// solanaClient is a solana.Client that records a span for every RPC call.
+//
+// Since solana.Client does not accept a context, spans are parented to the
+// context the client was bound with (see SolanaClientWithContext).
+type solanaClient struct {
+	ctx context.Context
+	sc  solana.Client
+}
+
+// NewSolanaClient returns a solana.Client that records a span for each call
+// made to the underlying client.
+func NewSolanaClient(sc solana.Client) solana.Client {
+	return SolanaClientWithContext(context.Background(), sc)
+}
+
+// SolanaClientWithContext returns a solana.Client whose spans are children of
+// the span contained in ctx.
+func SolanaClientWithContext(ctx context.Context, sc solana.Client) solana.Client {
+	if c, ok := sc.(*solanaClient); ok {
+		sc = c.sc
+	}
+
+	return &solanaClient{
+		ctx: ctx,
+		sc:  sc,
+	}
+}
+
+func (c *solanaClient) start(method string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := StartSpan(c.ctx, "solana.Client/"+method, attrs...)
+	return span
+}
+
+// GetMinimumBalanceForRentExemption implements solana.Client.GetMinimumBalanceForRentExemption.
+func (c *solanaClient) GetMinimumBalanceForRentExemption(size uint64) (lamports uint64, err error) {
+	span := c.start("GetMinimumBalanceForRentExemption")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetMinimumBalanceForRentExemption(size)
+}
+
+// GetSlot implements solana.Client.GetSlot.
+func (c *solanaClient) GetSlot(commitment solana.Commitment) (slot uint64, err error) {
+	span := c.start("GetSlot")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetSlot(commitment)
+}
+
+// GetRecentBlockhash implements solana.Client.GetRecentBlockhash.
+func (c *solanaClient) GetRecentBlockhash() (hash solana.Blockhash, err error) {
+	span := c.start("GetRecentBlockhash")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetRecentBlockhash()
+}
+
+// GetBlockTime implements solana.Client.GetBlockTime.
+func (c *solanaClient) GetBlockTime(block uint64) (t time.Time, err error) {
+	span := c.start("GetBlockTime", attribute.Int64("slot", int64(block)))
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetBlockTime(block)
+}
+
+// GetConfirmedBlock implements solana.Client.GetConfirmedBlock.
+func (c *solanaClient) GetConfirmedBlock(slot uint64) (block *solana.Block, err error) {
+	span := c.start("GetConfirmedBlock", attribute.Int64("slot", int64(slot)))
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetConfirmedBlock(slot)
+}
+
+// GetConfirmedBlocksWithLimit implements solana.Client.GetConfirmedBlocksWithLimit.
+func (c *solanaClient) GetConfirmedBlocksWithLimit(start, limit uint64) (slots []uint64, err error) {
+	span := c.start("GetConfirmedBlocksWithLimit", attribute.Int64("start", int64(start)), attribute.Int64("limit", int64(limit)))
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetConfirmedBlocksWithLimit(start, limit)
+}
+
+// GetConfirmedTransaction implements solana.Client.GetConfirmedTransaction.
+func (c *solanaClient) GetConfirmedTransaction(sig solana.Signature) (txn solana.ConfirmedTransaction, err error) {
+	span := c.start("GetConfirmedTransaction")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetConfirmedTransaction(sig)
+}
+
+// GetBalance implements solana.Client.GetBalance.
+func (c *solanaClient) GetBalance(account ed25519.PublicKey) (balance uint64, err error) {
+	span := c.start("GetBalance")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetBalance(account)
+}
+
+// SimulateTransaction implements solana.Client.SimulateTransaction.
+func (c *solanaClient) SimulateTransaction(txn solana.Transaction) (err error) {
+	span := c.start("SimulateTransaction")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.SimulateTransaction(txn)
+}
+
+// SubmitTransaction implements solana.Client.SubmitTransaction.
+func (c *solanaClient) SubmitTransaction(txn solana.Transaction, commitment solana.Commitment) (sig solana.Signature, stat *solana.SignatureStatus, err error) {
+	span := c.start("SubmitTransaction")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.SubmitTransaction(txn, commitment)
+}
+
+// GetAccountInfo implements solana.Client.GetAccountInfo.
+func (c *solanaClient) GetAccountInfo(account ed25519.PublicKey, commitment solana.Commitment) (info solana.AccountInfo, err error) {
+	span := c.start("GetAccountInfo")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetAccountInfo(account, commitment)
+}
+
+// RequestAirdrop implements solana.Client.RequestAirdrop.
+func (c *solanaClient) RequestAirdrop(account ed25519.PublicKey, lamports uint64, commitment solana.Commitment) (sig solana.Signature, err error) {
+	span := c.start("RequestAirdrop")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.RequestAirdrop(account, lamports, commitment)
+}
+
+// GetConfirmationStatus implements solana.Client.GetConfirmationStatus.
+func (c *solanaClient) GetConfirmationStatus(sig solana.Signature, commitment solana.Commitment) (confirmed bool, err error) {
+	span := c.start("GetConfirmationStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetConfirmationStatus(sig, commitment)
+}
+
+// GetSignatureStatus implements solana.Client.GetSignatureStatus.
+func (c *solanaClient) GetSignatureStatus(sig solana.Signature, commitment solana.Commitment) (stat *solana.SignatureStatus, err error) {
+	span := c.start("GetSignatureStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetSignatureStatus(sig, commitment)
+}
+
+// GetSignatureStatuses implements solana.Client.GetSignatureStatuses.
+func (c *solanaClient) GetSignatureStatuses(sigs []solana.Signature) (stats []*solana.SignatureStatus, err error) {
+	span := c.start("GetSignatureStatuses", attribute.Int("count", len(sigs)))
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetSignatureStatuses(sigs)
+}
+
+// GetTokenAccountsByOwner implements solana.Client.GetTokenAccountsByOwner.
+func (c *solanaClient) GetTokenAccountsByOwner(owner, mint ed25519.PublicKey) (accounts []ed25519.PublicKey, err error) {
+	span := c.start("GetTokenAccountsByOwner")
+	defer func() { EndSpan(span, err) }()
+
+	return c.sc.GetTokenAccountsByOwner(owner, mint)
+}
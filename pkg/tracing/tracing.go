@@ -0,0 +1,89 @@
+// Package tracing provides helpers for instrumenting agora with OpenTelemetry.
+//
+// Spans are created against the global tracer provider. If Init (or Register)
+// is never called, the global provider is a no-op, and instrumentation has no
+// meaningful overhead.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/kinecosystem/agora"
+
+// Init configures the global tracer provider to export spans over OTLP (gRPC)
+// to the specified collector endpoint.
+//
+// The returned function flushes and shuts down the provider, and should be
+// called before the process exits.
+func Init(ctx context.Context, serviceName, endpoint string) (shutdown func(context.Context) error, err error) {
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(
+		otlpgrpc.WithEndpoint(endpoint),
+		otlpgrpc.WithInsecure(),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create otlp exporter")
+	}
+
+	return Register(serviceName, sdktrace.WithBatcher(exporter)), nil
+}
+
+// Register installs a tracer provider configured with the specified options as
+// the global provider, along with the W3C trace context propagator.
+//
+// Register is primarily exposed so that tests can install an in-memory exporter.
+func Register(serviceName string, opts ...sdktrace.TracerProviderOption) (shutdown func(context.Context) error) {
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.ServiceNameKey.String(serviceName))),
+	}, opts...)
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown
+}
+
+// StartSpan starts a span with the specified name, which is a child of any
+// span contained in ctx.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span, marking it as failed if err is non-nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach returns a background context that carries the span (if any) from ctx.
+//
+// It is used when work must outlive the caller's context (for example, after a
+// client has cancelled), while still being attributed to the caller's trace.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// InjectHTTPHeaders propagates the trace context in ctx to the provided headers.
+func InjectHTTPHeaders(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
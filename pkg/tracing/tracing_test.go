@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setup(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := Register("test", sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() {
+		_ = shutdown(context.Background())
+	})
+
+	return exporter
+}
+
+func TestSpans(t *testing.T) {
+	exporter := setup(t)
+
+	ctx, parent := StartSpan(context.Background(), "parent", attribute.String("key", "value"))
+	_, child := StartSpan(ctx, "child")
+	EndSpan(child, errors.New("failed"))
+	EndSpan(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].StatusCode)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+
+	assert.Equal(t, "parent", spans[1].Name)
+	assert.Equal(t, codes.Unset, spans[1].StatusCode)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("key", "value")}, spans[1].Attributes)
+}
+
+func TestDetach(t *testing.T) {
+	exporter := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, parent := StartSpan(ctx, "parent")
+	cancel()
+
+	detached := Detach(ctx)
+	assert.NoError(t, detached.Err())
+
+	_, child := StartSpan(detached, "child")
+	EndSpan(child, nil)
+	EndSpan(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestSolanaClient(t *testing.T) {
+	exporter := setup(t)
+
+	mc := solana.NewMockClient()
+	mc.On("GetSlot", solana.CommitmentMax).Return(uint64(10), nil)
+	mc.On("GetConfirmedBlock", uint64(10)).Return((*solana.Block)(nil), errors.New("failed"))
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	sc := SolanaClientWithContext(ctx, NewSolanaClient(mc))
+
+	slot, err := sc.GetSlot(solana.CommitmentMax)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, slot)
+
+	_, err = sc.GetConfirmedBlock(slot)
+	assert.Error(t, err)
+	EndSpan(parent, nil)
+
+	// Rebinding should not result in nested decorators.
+	assert.Equal(t, mc, sc.(*solanaClient).sc)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "solana.Client/GetSlot", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].StatusCode)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+
+	assert.Equal(t, "solana.Client/GetConfirmedBlock", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].StatusCode)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("slot", 10)}, spans[1].Attributes)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent.SpanID())
+}
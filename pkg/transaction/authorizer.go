@@ -7,6 +7,7 @@ import (
 
 	"github.com/kinecosystem/agora-common/kin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -14,6 +15,8 @@ import (
 
 	"github.com/kinecosystem/agora/pkg/app"
 	"github.com/kinecosystem/agora/pkg/invoice"
+	"github.com/kinecosystem/agora/pkg/tracing"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
 	"github.com/kinecosystem/agora/pkg/version"
 	"github.com/kinecosystem/agora/pkg/webhook"
 	"github.com/kinecosystem/agora/pkg/webhook/signtransaction"
@@ -35,14 +38,18 @@ type authorizer struct {
 
 	webhookClient *webhook.Client
 	limiter       *Limiter
+	velocity      velocity.Checker
 }
 
 // NewAuthorizer returns an authorizer.
+//
+// If velocityChecker is nil, no velocity checks are performed.
 func NewAuthorizer(
 	mapper app.Mapper,
 	configStore app.ConfigStore,
 	webhookClient *webhook.Client,
 	limiter *Limiter,
+	velocityChecker velocity.Checker,
 ) (Authorizer, error) {
 	if err := registerMetrics(); err != nil {
 		return nil, err
@@ -54,6 +61,7 @@ func NewAuthorizer(
 		configStore:   configStore,
 		webhookClient: webhookClient,
 		limiter:       limiter,
+		velocity:      velocityChecker,
 	}, nil
 }
 
@@ -69,6 +77,10 @@ type Transaction struct {
 	OpCount     int
 	InvoiceList *commonpb.InvoiceList
 	SignRequest *signtransaction.RequestBody
+
+	// Transfers contains the transfers in the transaction, which are
+	// used for velocity checks. It may be empty.
+	Transfers []velocity.Transfer
 }
 
 type AuthorizationResult int
@@ -83,10 +95,35 @@ type Authorization struct {
 	Result        int
 	InvoiceErrors []*commonpb.InvoiceError
 	SignResponse  *signtransaction.SuccessResponse
+
+	// Flags contains any velocity thresholds that were exceeded
+	// by an otherwise authorized transaction.
+	Flags []string
+
+	rollback func(context.Context) error
+}
+
+// Rollback undoes any state recorded while authorizing the transaction, such
+// as the transfers recorded for velocity checks.
+//
+// Callers must invoke Rollback if an authorized transaction is not
+// successfully submitted.
+func (a Authorization) Rollback(ctx context.Context) error {
+	if a.rollback == nil {
+		return nil
+	}
+
+	return a.rollback(ctx)
 }
 
 // Authorize implements Authorizer.Authorize.
 func (s *authorizer) Authorize(ctx context.Context, txn Transaction) (a Authorization, err error) {
+	ctx, span := tracing.StartSpan(ctx, "transaction.Authorizer/Authorize", attribute.Int("op_count", txn.OpCount))
+	defer func() {
+		span.SetAttributes(attribute.Int("result", a.Result))
+		tracing.EndSpan(span, err)
+	}()
+
 	log := s.log.WithField("method", "authorize")
 
 	// The only way a transaction can be an earn is if it's using the binary memo
@@ -128,6 +165,8 @@ func (s *authorizer) Authorize(ctx context.Context, txn Transaction) (a Authoriz
 		}
 	}
 
+	span.SetAttributes(attribute.Int("app_index", int(appIndex)))
+
 	allowed, err := s.limiter.Allow(int(appIndex))
 	if err != nil {
 		log.WithError(err).Warn("failed to check rate limit")
@@ -203,6 +242,30 @@ func (s *authorizer) Authorize(ctx context.Context, txn Transaction) (a Authoriz
 		}
 	}
 
+	// Velocity checks are performed last, so that transfers are only recorded
+	// for transactions that would otherwise be submitted.
+	if s.velocity != nil && len(txn.Transfers) > 0 {
+		result, err := s.velocity.Check(ctx, appIndex, txn.ID, txn.Transfers)
+		if err != nil {
+			log.WithError(err).Warn("failed to check velocity")
+		} else {
+			switch result.Action {
+			case velocity.ActionReject:
+				log.WithField("flags", result.Flags).Info("rejecting transaction due to velocity")
+				a.Result = AuthorizationResultRejected
+				a.SignResponse = nil
+				return a, nil
+			case velocity.ActionFlag:
+				log.WithField("flags", result.Flags).Info("flagging transaction due to velocity")
+				a.Flags = result.Flags
+			}
+
+			a.rollback = func(ctx context.Context) error {
+				return s.velocity.Rollback(ctx, result)
+			}
+		}
+	}
+
 	return a, nil
 }
 
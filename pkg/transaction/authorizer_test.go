@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/kinecosystem/agora-common/headers"
@@ -26,6 +27,9 @@ import (
 	appconfigdb "github.com/kinecosystem/agora/pkg/app/memory"
 	appmapper "github.com/kinecosystem/agora/pkg/app/memory/mapper"
 	"github.com/kinecosystem/agora/pkg/rate"
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+	velocitymemory "github.com/kinecosystem/agora/pkg/transaction/velocity/memory"
 	"github.com/kinecosystem/agora/pkg/version"
 	"github.com/kinecosystem/agora/pkg/webhook"
 	"github.com/kinecosystem/agora/pkg/webhook/signtransaction"
@@ -48,6 +52,16 @@ func setup(t *testing.T) (env testEnv) {
 		env.appConfigStore,
 		webhook.NewClient(http.DefaultClient),
 		NewLimiter(func(r int) rate.Limiter { return rate.NewLocalRateLimiter(xrate.Limit(r)) }, 10, 5),
+		velocity.NewChecker(velocitymemory.New(), &velocity.Config{
+			Apps: map[uint16]*velocity.Rules{
+				1: {
+					Destination: []velocity.Threshold{
+						{Window: time.Hour, MaxCount: 2, Action: velocity.ActionFlag},
+						{Window: time.Hour, MaxAmount: 10, Action: velocity.ActionReject},
+					},
+				},
+			},
+		}),
 	)
 	require.NoError(t, err)
 	env.ctx, err = headers.ContextWithHeaders(context.Background())
@@ -458,6 +472,86 @@ func TestAuthorizer_RateLimiter(t *testing.T) {
 	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
 }
 
+func TestAuthorizer_Velocity(t *testing.T) {
+	env := setup(t)
+
+	err := env.appConfigStore.Add(context.Background(), 1, &app.Config{
+		AppName:       "some name",
+		WebhookSecret: generateWebhookKey(t),
+	})
+	require.NoError(t, err)
+
+	dest := testutil.GenerateSolanaKeys(t, 1)[0]
+	owners := testutil.GenerateSolanaKeys(t, 4)
+
+	var results []Authorization
+	for i, amount := range []uint64{2, 2, 2, 5} {
+		txn := generateTransaction(t, 1, nil)
+		txn.ID = []byte{byte(i)}
+		txn.Transfers = []velocity.Transfer{
+			{Owner: owners[i], Destination: dest, Amount: amount},
+		}
+
+		result, err := env.auth.Authorize(env.ctx, txn)
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	assert.Equal(t, AuthorizationResultOK, results[0].Result)
+	assert.Empty(t, results[0].Flags)
+	assert.Equal(t, AuthorizationResultOK, results[1].Result)
+	assert.Empty(t, results[1].Flags)
+	assert.Equal(t, AuthorizationResultOK, results[2].Result)
+	assert.Equal(t, []string{"destination_count:1h0m0s"}, results[2].Flags)
+	assert.Equal(t, AuthorizationResultRejected, results[3].Result)
+
+	// Other apps have no configured thresholds.
+	txn := generateTransaction(t, 0, nil)
+	txn.ID = []byte{4}
+	txn.Transfers = []velocity.Transfer{
+		{Owner: owners[0], Destination: dest, Amount: 100},
+	}
+	result, err := env.auth.Authorize(env.ctx, txn)
+	require.NoError(t, err)
+	assert.Equal(t, AuthorizationResultOK, result.Result)
+	assert.Empty(t, result.Flags)
+}
+
+func TestAuthorizer_VelocityRollback(t *testing.T) {
+	env := setup(t)
+
+	err := env.appConfigStore.Add(context.Background(), 1, &app.Config{
+		AppName:       "some name",
+		WebhookSecret: generateWebhookKey(t),
+	})
+	require.NoError(t, err)
+
+	dest := testutil.GenerateSolanaKeys(t, 1)[0]
+	owners := testutil.GenerateSolanaKeys(t, 3)
+
+	// Transactions that are rolled back (i.e. not submitted) should not
+	// count towards the thresholds of later transactions.
+	for i := 0; i < 3; i++ {
+		txn := generateTransaction(t, 1, nil)
+		txn.ID = []byte{byte(i)}
+		txn.Transfers = []velocity.Transfer{
+			{Owner: owners[i], Destination: dest, Amount: 2},
+		}
+
+		result, err := env.auth.Authorize(env.ctx, txn)
+		require.NoError(t, err)
+		assert.Equal(t, AuthorizationResultOK, result.Result)
+		assert.Empty(t, result.Flags)
+
+		if i > 0 {
+			require.NoError(t, result.Rollback(context.Background()))
+		}
+	}
+
+	// Authorizations without velocity checks have nothing to roll back.
+	assert.NoError(t, Authorization{}.Rollback(context.Background()))
+}
+
 func TestAuthorizer_TextMemo_NoAppID(t *testing.T) {
 	env := setup(t)
 
@@ -0,0 +1,505 @@
+// Package archive provides a cold storage tier for old history entries.
+//
+// Entries are moved out of the (hot) history store into compressed objects,
+// partitioned by block (slot for Solana, ledger for Stellar):
+//
+//	blocks/<version>/<partition>.gz             all entries in the partition
+//	accounts/<account>/<version>/<partition>.gz the entries in the partition related to the account
+//	tx/<shard>/<segment>.gz                     the partitions containing each transaction (see txindex.go)
+//	meta/boundary/<version>                     the first block that has not been archived
+//
+// where <version> is the kin version of the entries, and <partition> is the
+// (zero padded) first block of the partition. Since ordering keys are prefixed
+// by the kin version, the lexicographic order of the objects matches the order
+// of the entries they contain.
+//
+// The block ordered history used by history.Reader.GetTransactions is not moved.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const (
+	// partitionSize is the number of blocks in each partition. It matches the
+	// partitioning of the DynamoDB tx-history table.
+	partitionSize = 10_000
+
+	// pageSize is the number of entries loaded from the history store at a time.
+	pageSize = 1000
+
+	boundaryPrefix = "meta/boundary/"
+)
+
+// versions are the kin versions that may be archived, in ordering key order.
+var versions = []model.KinVersion{model.KinVersion_KIN2, model.KinVersion_KIN3, model.KinVersion_KIN4}
+
+func blockKey(version model.KinVersion, partition uint64) string {
+	return fmt.Sprintf("blocks/%d/%020d.gz", version, partition)
+}
+
+func accountPrefix(account string) string {
+	return fmt.Sprintf("accounts/%s/", account)
+}
+
+func accountKey(account string, version model.KinVersion, partition uint64) string {
+	return fmt.Sprintf("%s%d/%020d.gz", accountPrefix(account), version, partition)
+}
+
+func boundaryKey(version model.KinVersion) string {
+	return fmt.Sprintf("%s%d", boundaryPrefix, version)
+}
+
+// partitionFromKey returns the version and partition of a block or account key.
+func partitionFromKey(key string) (model.KinVersion, uint64, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) < 2 {
+		return 0, 0, errors.Errorf("invalid key: %s", key)
+	}
+
+	version, err := strconv.ParseUint(parts[len(parts)-2], 10, 8)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid version")
+	}
+	partition, err := strconv.ParseUint(strings.TrimSuffix(parts[len(parts)-1], ".gz"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid partition")
+	}
+
+	return model.KinVersion(version), partition, nil
+}
+
+// orderingKeyFromBlock returns the smallest (or largest, if max is set)
+// ordering key of an entry in the block.
+func orderingKeyFromBlock(version model.KinVersion, block uint64, max bool) []byte {
+	if version == model.KinVersion_KIN4 {
+		return model.OrderingKeyFromBlock(block, max)
+	}
+
+	// Stellar ordering keys are paging tokens, which contain the ledger in
+	// the upper 32 bits.
+	var b [9]byte
+	b[0] = byte(version)
+	token := block << 32
+	if max {
+		token |= math.MaxUint32
+	}
+	binary.BigEndian.PutUint64(b[1:], token)
+	return b[:]
+}
+
+// getBlock returns the block (slot or ledger) containing the entry.
+func getBlock(e *model.Entry) uint64 {
+	if sol := e.GetSolana(); sol != nil {
+		return sol.Slot
+	}
+	return e.GetStellar().GetLedger()
+}
+
+// encodeEntries encodes the entries as a gzip compressed sequence of length
+// prefixed (marshalled) entries.
+func encodeEntries(entries []*model.Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	for _, e := range entries {
+		b, err := proto.Marshal(e)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal entry")
+		}
+
+		n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+		if _, err := w.Write(lenBuf[:n]); err != nil {
+			return nil, errors.Wrap(err, "failed to write entry")
+		}
+		if _, err := w.Write(b); err != nil {
+			return nil, errors.Wrap(err, "failed to write entry")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to compress entries")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeEntries(data []byte) ([]*model.Entry, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress entries")
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress entries")
+	}
+
+	var entries []*model.Entry
+	buf := bytes.NewReader(raw)
+	for {
+		n, err := binary.ReadUvarint(buf)
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to read entry length")
+		}
+
+		b := make([]byte, n)
+		if _, err := io.ReadFull(buf, b); err != nil {
+			return nil, errors.Wrap(err, "failed to read entry")
+		}
+
+		e := &model.Entry{}
+		if err := proto.Unmarshal(b, e); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal entry")
+		}
+		entries = append(entries, e)
+	}
+}
+
+// getBoundary returns the first block of the version that has not been archived.
+func getBoundary(ctx context.Context, store ObjectStore, version model.KinVersion) (uint64, error) {
+	data, err := store.Get(ctx, boundaryKey(version))
+	if err == ErrObjectNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Wrap(err, "failed to get archive boundary")
+	}
+
+	boundary, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid archive boundary")
+	}
+
+	return boundary, nil
+}
+
+// Archiver moves old entries from a history store into an archive.
+type Archiver struct {
+	log     *logrus.Entry
+	reader  history.Reader
+	deleter history.Deleter
+	store   ObjectStore
+}
+
+// NewArchiver returns a new Archiver.
+//
+// Entries are read from reader, and removed using deleter once they have
+// been archived. Typically both are the same history store.
+func NewArchiver(reader history.Reader, deleter history.Deleter, store ObjectStore) *Archiver {
+	return &Archiver{
+		log:     logrus.StandardLogger().WithField("type", "transaction/history/archive"),
+		reader:  reader,
+		deleter: deleter,
+		store:   store,
+	}
+}
+
+// ArchiveSolana archives all Solana partitions whose entries have a block time
+// before the cutoff, returning the number of archived entries.
+//
+// Archival resumes from the last archived partition. If nothing has been
+// archived yet, it starts at the partition containing the from slot.
+//
+// A partition is only archived once a later slot (up to maxSlot, which should
+// be the last committed slot) contains an entry with a block time before the
+// cutoff. This ensures that the partition is complete. Empty partitions before
+// such an entry are skipped over.
+func (a *Archiver) ArchiveSolana(ctx context.Context, from, maxSlot uint64, cutoff time.Time) (int, error) {
+	boundary, err := getBoundary(ctx, a.store, model.KinVersion_KIN4)
+	if err != nil {
+		return 0, err
+	}
+	if boundary == 0 {
+		boundary = (from / partitionSize) * partitionSize
+	}
+
+	var archived int
+	for partition := boundary; ; {
+		// Find the partition of the next entry after this one. Every partition
+		// before it is complete, and older than the entry.
+		next := partition + partitionSize
+		if next > maxSlot {
+			return archived, nil
+		}
+
+		entries, err := a.reader.GetTransactions(ctx, next, maxSlot, 1)
+		if err != nil {
+			return archived, errors.Wrap(err, "failed to get transactions")
+		}
+		if len(entries) == 0 {
+			return archived, nil
+		}
+
+		// Entries without a block time are treated as recent.
+		blockTime, err := entries[0].GetBlockTime()
+		if err != nil {
+			return archived, errors.Wrap(err, "failed to get block time")
+		}
+		if blockTime.IsZero() || !blockTime.Before(cutoff) {
+			return archived, nil
+		}
+
+		for end := (getBlock(entries[0]) / partitionSize) * partitionSize; partition < end; partition += partitionSize {
+			select {
+			case <-ctx.Done():
+				return archived, ctx.Err()
+			default:
+			}
+
+			entries, err := a.loadSolanaPartition(ctx, partition)
+			if err != nil {
+				return archived, errors.Wrapf(err, "failed to load partition %d", partition)
+			}
+
+			if err := a.archivePartition(ctx, model.KinVersion_KIN4, partition, entries); err != nil {
+				return archived, errors.Wrapf(err, "failed to archive partition %d", partition)
+			}
+			archived += len(entries)
+		}
+	}
+}
+
+// ArchiveStellar archives all partitions of a Stellar based kin version whose
+// entries have a ledger close time before the cutoff, returning the number of
+// archived entries.
+//
+// Stellar entries are not part of the block ordered history, so the ledgers are
+// enumerated using the loader. Entries are archived from the history store if
+// present; transactions that were never written to the store are skipped.
+//
+// The Stellar based chains are no longer running, so last should be the final
+// ledger of the chain. Archival resumes from the last archived partition, or
+// the partition containing the from ledger if nothing has been archived yet.
+func (a *Archiver) ArchiveStellar(ctx context.Context, version model.KinVersion, loader stellaringestor.LedgerLoader, from, last uint32, cutoff time.Time) (int, error) {
+	if version != model.KinVersion_KIN2 && version != model.KinVersion_KIN3 {
+		return 0, errors.Errorf("unsupported kin version: %d", version)
+	}
+
+	boundary, err := getBoundary(ctx, a.store, version)
+	if err != nil {
+		return 0, err
+	}
+	if boundary == 0 {
+		boundary = (uint64(from) / partitionSize) * partitionSize
+	}
+
+	var archived int
+	for partition := boundary; partition <= uint64(last); partition += partitionSize {
+		select {
+		case <-ctx.Done():
+			return archived, ctx.Err()
+		default:
+		}
+
+		end := partition + partitionSize - 1
+		if end > uint64(last) {
+			end = uint64(last)
+		}
+
+		entries, complete, err := a.loadStellarPartition(ctx, loader, partition, end, cutoff)
+		if err != nil {
+			return archived, errors.Wrapf(err, "failed to load partition %d", partition)
+		}
+		if !complete {
+			return archived, nil
+		}
+
+		if err := a.archivePartition(ctx, version, partition, entries); err != nil {
+			return archived, errors.Wrapf(err, "failed to archive partition %d", partition)
+		}
+		archived += len(entries)
+	}
+
+	return archived, nil
+}
+
+func (a *Archiver) archivePartition(ctx context.Context, version model.KinVersion, partition uint64, entries []*model.Entry) error {
+	// The objects are written before any entries are deleted, and the boundary
+	// is only advanced at the end, so a failed partition can be safely retried.
+	if len(entries) > 0 {
+		if err := a.put(ctx, blockKey(version, partition), entries); err != nil {
+			return err
+		}
+
+		byAccount := make(map[string][]*model.Entry)
+		for _, e := range entries {
+			accounts, err := e.GetAccounts()
+			if err != nil {
+				return errors.Wrap(err, "failed to get accounts")
+			}
+			for _, account := range accounts {
+				byAccount[account] = append(byAccount[account], e)
+			}
+		}
+		for account, accountEntries := range byAccount {
+			if err := a.put(ctx, accountKey(account, version, partition), accountEntries); err != nil {
+				return err
+			}
+		}
+
+		if err := addToTxIndex(ctx, a.store, version, partition, entries); err != nil {
+			return errors.Wrap(err, "failed to update tx index")
+		}
+
+		for _, e := range entries {
+			if err := a.deleter.Delete(ctx, e); err != nil {
+				return errors.Wrap(err, "failed to delete entry")
+			}
+		}
+	}
+
+	boundary := []byte(strconv.FormatUint(partition+partitionSize, 10))
+	if err := a.store.Put(ctx, boundaryKey(version), boundary); err != nil {
+		return errors.Wrap(err, "failed to update archive boundary")
+	}
+
+	a.log.WithFields(logrus.Fields{
+		"version":   version,
+		"partition": partition,
+		"entries":   len(entries),
+	}).Info("archived partition")
+
+	return nil
+}
+
+// loadSolanaPartition returns the (ordered) entries in the partition.
+func (a *Archiver) loadSolanaPartition(ctx context.Context, partition uint64) ([]*model.Entry, error) {
+	end := partition + partitionSize - 1
+	byID := make(map[string]*model.Entry)
+
+	limit := pageSize
+	for start := partition; start <= end; {
+		entries, err := a.reader.GetTransactions(ctx, start, end, limit)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transactions")
+		}
+
+		var added int
+		for _, e := range entries {
+			txID, err := e.GetTxID()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get tx id")
+			}
+
+			// If an entry was written multiple times, the latest write wins.
+			if _, ok := byID[string(txID)]; !ok {
+				added++
+			}
+			byID[string(txID)] = e
+		}
+
+		if len(entries) < limit {
+			break
+		}
+
+		// Pages start at a block, so a block with more entries than the
+		// limit requires a larger page to make progress.
+		if added == 0 {
+			limit *= 2
+			continue
+		}
+
+		start = entries[len(entries)-1].GetSolana().Slot
+		limit = pageSize
+	}
+
+	entries := make([]*model.Entry, 0, len(byID))
+	for _, e := range byID {
+		entries = append(entries, e)
+	}
+
+	return entries, sortEntries(entries)
+}
+
+// loadStellarPartition returns the (ordered) entries of the ledgers [partition, end]
+// that are in the history store. If any of the ledgers closed at or after the
+// cutoff, the partition is not complete, and no entries are returned.
+func (a *Archiver) loadStellarPartition(ctx context.Context, loader stellaringestor.LedgerLoader, partition, end uint64, cutoff time.Time) ([]*model.Entry, bool, error) {
+	var entries []*model.Entry
+	for ledger := partition; ledger <= end; ledger++ {
+		loaded, err := loader.LoadLedger(uint32(ledger))
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to load ledger %d", ledger)
+		}
+
+		for _, e := range loaded {
+			closeTime, err := e.GetBlockTime()
+			if err != nil {
+				return nil, false, errors.Wrap(err, "failed to get ledger close time")
+			}
+			if closeTime.IsZero() || !closeTime.Before(cutoff) {
+				return nil, false, nil
+			}
+
+			txID, err := e.GetTxID()
+			if err != nil {
+				return nil, false, errors.Wrap(err, "failed to get tx id")
+			}
+
+			stored, err := a.reader.GetTransaction(ctx, txID)
+			if err == history.ErrNotFound {
+				continue
+			} else if err != nil {
+				return nil, false, errors.Wrap(err, "failed to get transaction")
+			}
+
+			entries = append(entries, stored)
+		}
+	}
+
+	return entries, true, sortEntries(entries)
+}
+
+func (a *Archiver) put(ctx context.Context, key string, entries []*model.Entry) error {
+	data, err := encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.Put(ctx, key, data); err != nil {
+		return errors.Wrapf(err, "failed to put %s", key)
+	}
+
+	return nil
+}
+
+func sortEntries(entries []*model.Entry) error {
+	var sortErr error
+	sort.Slice(entries, func(i, j int) bool {
+		iKey, err := entries[i].GetOrderingKey()
+		if err != nil {
+			sortErr = err
+		}
+		jKey, err := entries[j].GetOrderingKey()
+		if err != nil {
+			sortErr = err
+		}
+		return bytes.Compare(iKey, jKey) < 0
+	})
+	if sortErr != nil {
+		return errors.Wrap(sortErr, "failed to get ordering key")
+	}
+
+	return nil
+}
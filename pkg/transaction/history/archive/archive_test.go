@@ -0,0 +1,361 @@
+package archive
+
+import (
+	"context"
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/go/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+var base = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type testEnv struct {
+	rw      *historymemory.RW
+	store   ObjectStore
+	sender  ed25519.PrivateKey
+	account string
+	entries []*model.Entry
+}
+
+// setup writes an entry for each slot, with a block time of base + slot
+// seconds. Every entry is sent by the same account.
+func setup(t *testing.T, slots ...uint64) (env testEnv, cleanup func()) {
+	dir, err := ioutil.TempDir("", "history-archive")
+	require.NoError(t, err)
+
+	env.store, err = NewFileStore(dir)
+	require.NoError(t, err)
+
+	env.rw = historymemory.New()
+	env.sender = testutil.GenerateSolanaKeypair(t)
+	env.account = strkey.MustEncode(strkey.VersionByteAccountID, env.sender.Public().(ed25519.PublicKey))
+
+	for _, slot := range slots {
+		e, _ := historytestutil.GenerateSolanaEntry(t, slot, true, env.sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+		e.GetSolana().BlockTime, err = ptypes.TimestampProto(base.Add(time.Duration(slot) * time.Second))
+		require.NoError(t, err)
+
+		require.NoError(t, env.rw.Write(context.Background(), e))
+		env.entries = append(env.entries, e)
+	}
+
+	return env, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	_, err = store.Get(context.Background(), "a/b")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	keys, err := store.List(context.Background(), "a/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, key := range []string{"a/c", "a/b", "ab", "b/a"} {
+		require.NoError(t, store.Put(context.Background(), key, []byte(key)))
+	}
+	require.NoError(t, store.Put(context.Background(), "a/b", []byte("updated")))
+
+	data, err := store.Get(context.Background(), "a/b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+
+	keys, err = store.List(context.Background(), "a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "a/c"}, keys)
+
+	keys, err = store.List(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "a/c", "ab"}, keys)
+
+	for _, key := range []string{"", "/a", "../a"} {
+		assert.Error(t, store.Put(context.Background(), key, nil))
+	}
+
+	require.NoError(t, store.Delete(context.Background(), "a/b"))
+	require.NoError(t, store.Delete(context.Background(), "a/b"))
+	_, err = store.Get(context.Background(), "a/b")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	keys, err = store.List(context.Background(), "a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/c"}, keys)
+}
+
+func TestEncoding_RoundTrip(t *testing.T) {
+	env, cleanup := setup(t, 1, 2, 3)
+	defer cleanup()
+
+	data, err := encodeEntries(env.entries)
+	require.NoError(t, err)
+
+	decoded, err := decodeEntries(data)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(env.entries))
+	for i := range env.entries {
+		assert.True(t, proto.Equal(env.entries[i], decoded[i]))
+	}
+
+	data, err = encodeEntries(nil)
+	require.NoError(t, err)
+	decoded, err = decodeEntries(data)
+	require.NoError(t, err)
+	assert.Empty(t, decoded)
+}
+
+func TestArchiveSolana(t *testing.T) {
+	env, cleanup := setup(t, 100, 200, 10_100, 10_200, 20_100)
+	defer cleanup()
+
+	ctx := context.Background()
+	archiver := NewArchiver(env.rw, env.rw, env.store)
+
+	// Only the first partition is older than the cutoff, and complete.
+	archived, err := archiver.ArchiveSolana(ctx, 100, 30_000, base.Add(10_150*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	boundary, err := getBoundary(ctx, env.store, model.KinVersion_KIN4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10_000, boundary)
+
+	for i, e := range env.entries {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+
+		_, err = env.rw.GetTransaction(ctx, txID)
+		if i < 2 {
+			assert.Equal(t, history.ErrNotFound, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	entries, err := env.rw.GetAccountTransactions(ctx, env.account, &history.ReadOptions{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	keys, err := env.store.List(ctx, "blocks/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{blockKey(model.KinVersion_KIN4, 0)}, keys)
+
+	keys, err = env.store.List(ctx, accountPrefix(env.account))
+	require.NoError(t, err)
+	assert.Equal(t, []string{accountKey(env.account, model.KinVersion_KIN4, 0)}, keys)
+
+	// Re-running should be a no-op.
+	archived, err = archiver.ArchiveSolana(ctx, 100, 30_000, base.Add(10_150*time.Second))
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	// The last partition cannot be archived, as it may not be complete.
+	archived, err = archiver.ArchiveSolana(ctx, 100, 30_000, base.Add(time.Hour*24))
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	boundary, err = getBoundary(ctx, env.store, model.KinVersion_KIN4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 20_000, boundary)
+
+	entries, err = env.rw.GetAccountTransactions(ctx, env.account, &history.ReadOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, proto.Equal(env.entries[4], entries[0]))
+
+	// Block history is untouched.
+	entries, err = env.rw.GetTransactions(ctx, 1, 30_000, 100)
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+func TestArchiveSolana_EmptyPartition(t *testing.T) {
+	env, cleanup := setup(t, 100, 30_100, 40_100)
+	defer cleanup()
+
+	ctx := context.Background()
+	archiver := NewArchiver(env.rw, env.rw, env.store)
+
+	// Empty partitions are archived (as a boundary update only) along with
+	// the partitions before the next entry.
+	archived, err := archiver.ArchiveSolana(ctx, 0, 50_000, base.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	boundary, err := getBoundary(ctx, env.store, model.KinVersion_KIN4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 40_000, boundary)
+
+	keys, err := env.store.List(ctx, "blocks/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{blockKey(model.KinVersion_KIN4, 0), blockKey(model.KinVersion_KIN4, 30_000)}, keys)
+}
+
+func TestArchiveSolana_MaxSlot(t *testing.T) {
+	env, cleanup := setup(t, 100, 10_100, 20_100)
+	defer cleanup()
+
+	ctx := context.Background()
+	archiver := NewArchiver(env.rw, env.rw, env.store)
+
+	// Entries after the max slot (i.e. that have not been committed) are not
+	// used to determine whether or not a partition is complete.
+	archived, err := archiver.ArchiveSolana(ctx, 0, 15_000, base.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	boundary, err := getBoundary(ctx, env.store, model.KinVersion_KIN4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10_000, boundary)
+}
+
+type ledgerLoader map[uint32][]*model.Entry
+
+func (l ledgerLoader) LoadLedger(sequence uint32) ([]*model.Entry, error) {
+	return l[sequence], nil
+}
+
+func TestArchiveStellar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	rw := historymemory.New()
+	loader := make(ledgerLoader)
+
+	_, sender := testutil.GenerateAccountID(t)
+	var entries []*model.Entry
+	for i, ledger := range []uint64{5, 10_005, 10_006, 20_005} {
+		e, _ := historytestutil.GenerateStellarEntry(t, ledger, i, sender, testutil.GenerateAccountIDs(t, 1), nil, nil)
+		e.GetStellar().LedgerCloseTime, err = ptypes.TimestampProto(base.Add(time.Duration(ledger) * time.Second))
+		require.NoError(t, err)
+
+		loader[uint32(ledger)] = append(loader[uint32(ledger)], e)
+		entries = append(entries, e)
+	}
+
+	// Only the entries in the history store are archived.
+	for _, e := range entries[1:] {
+		require.NoError(t, rw.Write(ctx, e))
+	}
+
+	archiver := NewArchiver(rw, rw, store)
+
+	_, err = archiver.ArchiveStellar(ctx, model.KinVersion_KIN4, loader, 0, 20_005, base)
+	assert.Error(t, err)
+
+	// The last partition contains a ledger after the cutoff.
+	archived, err := archiver.ArchiveStellar(ctx, model.KinVersion_KIN3, loader, 0, 20_005, base.Add(20_000*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	boundary, err := getBoundary(ctx, store, model.KinVersion_KIN3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 20_000, boundary)
+
+	// Solana archival is independent.
+	boundary, err = getBoundary(ctx, store, model.KinVersion_KIN4)
+	require.NoError(t, err)
+	assert.Zero(t, boundary)
+
+	keys, err := store.List(ctx, "blocks/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{blockKey(model.KinVersion_KIN3, 10_000)}, keys)
+
+	// The final (partial) partition is archived once the cutoff passes it.
+	archived, err = archiver.ArchiveStellar(ctx, model.KinVersion_KIN3, loader, 0, 20_005, base.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	r := NewReader(rw, store)
+	for _, e := range entries[1:] {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+
+		_, err = rw.GetTransaction(ctx, txID)
+		assert.Equal(t, history.ErrNotFound, err)
+
+		actual, err := r.GetTransaction(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(e, actual))
+	}
+
+	account, err := entries[0].GetAccounts()
+	require.NoError(t, err)
+	archivedEntries, err := r.GetAccountTransactions(ctx, account[0], &history.ReadOptions{})
+	require.NoError(t, err)
+	require.Len(t, archivedEntries, 3)
+	for i, e := range archivedEntries {
+		assert.True(t, proto.Equal(entries[i+1], e))
+	}
+}
+
+func TestTxIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Every entry is in the same shard, so that it's compacted.
+	var entries []*model.Entry
+	for i := 0; i < 20; i++ {
+		e, _ := historytestutil.GenerateSolanaEntry(t, uint64(i)*partitionSize, true, testutil.GenerateSolanaKeypair(t), testutil.GenerateSolanaKeys(t, 1), nil, nil)
+		// The first signature follows the (single byte) signature count.
+		e.GetSolana().Transaction[1] = 0x42
+		entries = append(entries, e)
+
+		require.NoError(t, addToTxIndex(ctx, store, model.KinVersion_KIN4, uint64(i)*partitionSize, []*model.Entry{e}))
+	}
+
+	// 20 = 1*16 + 1*4, so there's a single segment at each of levels 1 and 2.
+	keys, err := store.List(ctx, txShardPrefix(0x42))
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	for i, key := range keys {
+		level, _, err := levelFromSegmentKey(key)
+		require.NoError(t, err)
+		assert.Equal(t, i+1, level)
+	}
+
+	for i, e := range entries {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+
+		version, partition, err := lookupTx(ctx, store, txID)
+		require.NoError(t, err)
+		assert.Equal(t, model.KinVersion_KIN4, version)
+		assert.EqualValues(t, i*partitionSize, partition)
+	}
+
+	_, _, err = lookupTx(ctx, store, make([]byte, 64))
+	assert.Equal(t, ErrObjectNotFound, err)
+}
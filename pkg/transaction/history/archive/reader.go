@@ -0,0 +1,330 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// boundaryRefreshInterval is how often the archive boundary is reloaded.
+const boundaryRefreshInterval = time.Minute
+
+type reader struct {
+	history.ReaderWriter
+	store ObjectStore
+
+	mu              sync.Mutex
+	boundary        []byte
+	boundaryUpdated time.Time
+}
+
+// NewReader returns a history.ReaderWriter that falls through to the archive
+// for entries that have been moved out of rw.
+//
+// Writes, and reads of block ordered history, are always served by rw. Prune,
+// Repair and GetUnindexed are forwarded to rw, if it implements the
+// corresponding interface.
+func NewReader(rw history.ReaderWriter, store ObjectStore) history.ReaderWriter {
+	return &reader{
+		ReaderWriter: rw,
+		store:        store,
+	}
+}
+
+// GetTransaction implements history.Reader.GetTransaction.
+func (r *reader) GetTransaction(ctx context.Context, txHash []byte) (*model.Entry, error) {
+	e, err := r.ReaderWriter.GetTransaction(ctx, txHash)
+	if err != history.ErrNotFound {
+		return e, err
+	}
+
+	version, partition, err := lookupTx(ctx, r.store, txHash)
+	if err == ErrObjectNotFound {
+		return nil, history.ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to lookup archived tx")
+	}
+
+	entries, err := r.load(ctx, blockKey(version, partition))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		txID, err := e.GetTxID()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get tx id")
+		}
+		if bytes.Equal(txID, txHash) {
+			return e, nil
+		}
+	}
+
+	return nil, history.ErrNotFound
+}
+
+// GetAccountTransactions implements history.Reader.GetAccountTransactions.
+//
+// Archived entries all precede the archive boundary (the ordering key of the
+// first unarchived block of the latest archived version). The archive is
+// therefore only consulted if the requested page may include entries before it.
+func (r *reader) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	limit := opts.GetLimit()
+	if limit <= 0 {
+		limit = 100
+	}
+
+	boundary, err := r.getBoundary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hot, err := r.ReaderWriter.GetAccountTransactions(ctx, account, opts)
+	if err != nil || boundary == nil {
+		return hot, err
+	}
+
+	if opts.GetDescending() {
+		if len(hot) >= limit {
+			lastKey, err := hot[len(hot)-1].GetOrderingKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get ordering key")
+			}
+			if bytes.Compare(lastKey, boundary) >= 0 {
+				return hot, nil
+			}
+		}
+	} else if bytes.Compare(opts.GetStart(), boundary) >= 0 {
+		return hot, nil
+	}
+
+	cold, err := history.GetFilteredAccountTransactions(ctx, account, opts, r.getArchivedAccountTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return merge(hot, cold, opts.GetDescending(), limit)
+}
+
+// Prune implements history.Pruner.Prune.
+//
+// Only rw is pruned. history.ErrNotSupported is returned if it does not
+// implement history.Pruner.
+func (r *reader) Prune(ctx context.Context, e *model.Entry) error {
+	pruner, ok := r.ReaderWriter.(history.Pruner)
+	if !ok {
+		return history.ErrNotSupported
+	}
+
+	return pruner.Prune(ctx, e)
+}
+
+// Repair implements history.Repairer.Repair.
+//
+// Only rw is repaired. history.ErrNotSupported is returned if it does not
+// implement history.Repairer.
+func (r *reader) Repair(ctx context.Context, e *model.Entry) error {
+	repairer, ok := r.ReaderWriter.(history.Repairer)
+	if !ok {
+		return history.ErrNotSupported
+	}
+
+	return repairer.Repair(ctx, e)
+}
+
+// GetUnindexed implements history.TimeIndexer.GetUnindexed.
+//
+// Only entries of rw are returned. history.ErrNotSupported is returned if it
+// does not implement history.TimeIndexer.
+func (r *reader) GetUnindexed(ctx context.Context, cursor []byte, limit int) ([]*model.Entry, []byte, error) {
+	indexer, ok := r.ReaderWriter.(history.TimeIndexer)
+	if !ok {
+		return nil, nil, history.ErrNotSupported
+	}
+
+	return indexer.GetUnindexed(ctx, cursor, limit)
+}
+
+// GetLatestForAccount implements history.Reader.GetLatestForAccount.
+func (r *reader) GetLatestForAccount(ctx context.Context, account string) (*model.Entry, error) {
+	e, err := r.ReaderWriter.GetLatestForAccount(ctx, account)
+	if err != history.ErrNotFound {
+		return e, err
+	}
+
+	keys, err := r.store.List(ctx, accountPrefix(account))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list archived account partitions")
+	}
+	if len(keys) == 0 {
+		return nil, history.ErrNotFound
+	}
+
+	entries, err := r.load(ctx, keys[len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, history.ErrNotFound
+	}
+
+	return entries[len(entries)-1], nil
+}
+
+func (r *reader) getArchivedAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	limit := opts.GetLimit()
+	if limit <= 0 {
+		limit = 100
+	}
+
+	keys, err := r.store.List(ctx, accountPrefix(account))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list archived account partitions")
+	}
+
+	if opts.GetDescending() {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	start := opts.GetStart()
+	var results []*model.Entry
+	for _, key := range keys {
+		version, partition, err := partitionFromKey(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid archive key: %s", key)
+		}
+
+		// Skip partitions that are entirely before (or after) the start.
+		if opts.GetDescending() {
+			if bytes.Compare(orderingKeyFromBlock(version, partition, false), start) > 0 {
+				continue
+			}
+		} else if bytes.Compare(orderingKeyFromBlock(version, partition+partitionSize-1, true), start) < 0 {
+			continue
+		}
+
+		entries, err := r.load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if opts.GetDescending() {
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+		}
+
+		for _, e := range entries {
+			orderingKey, err := e.GetOrderingKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get ordering key")
+			}
+
+			cmp := bytes.Compare(orderingKey, start)
+			if (opts.GetDescending() && cmp > 0) || (!opts.GetDescending() && cmp < 0) {
+				continue
+			}
+
+			if inRange, err := opts.InTimeRange(e); err != nil {
+				return nil, errors.Wrap(err, "failed to apply time range")
+			} else if !inRange {
+				continue
+			}
+
+			results = append(results, e)
+			if len(results) >= limit {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// getBoundary returns the ordering key that all archived entries precede, or
+// nil if nothing has been archived.
+func (r *reader) getBoundary(ctx context.Context) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.boundaryUpdated) < boundaryRefreshInterval {
+		return r.boundary, nil
+	}
+
+	var boundary []byte
+	for _, version := range versions {
+		block, err := getBoundary(ctx, r.store, version)
+		if err != nil {
+			return nil, err
+		}
+		if block > 0 {
+			boundary = orderingKeyFromBlock(version, block, false)
+		}
+	}
+
+	r.boundary = boundary
+	r.boundaryUpdated = time.Now()
+	return boundary, nil
+}
+
+func (r *reader) load(ctx context.Context, key string) ([]*model.Entry, error) {
+	data, err := r.store.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", key)
+	}
+
+	return decodeEntries(data)
+}
+
+// merge merges two ordered sets of entries, removing any duplicates.
+func merge(a, b []*model.Entry, descending bool, limit int) ([]*model.Entry, error) {
+	merged := make([]*model.Entry, 0, len(a)+len(b))
+	var lastKey []byte
+	for len(merged) < limit && (len(a) > 0 || len(b) > 0) {
+		var next *model.Entry
+		switch {
+		case len(a) == 0:
+			next, b = b[0], b[1:]
+		case len(b) == 0:
+			next, a = a[0], a[1:]
+		default:
+			aKey, err := a[0].GetOrderingKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get ordering key")
+			}
+			bKey, err := b[0].GetOrderingKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get ordering key")
+			}
+
+			cmp := bytes.Compare(aKey, bKey)
+			if descending {
+				cmp = -cmp
+			}
+			if cmp <= 0 {
+				next, a = a[0], a[1:]
+			} else {
+				next, b = b[0], b[1:]
+			}
+		}
+
+		key, err := next.GetOrderingKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ordering key")
+		}
+		if lastKey != nil && bytes.Equal(key, lastKey) {
+			continue
+		}
+
+		merged = append(merged, next)
+		lastKey = key
+	}
+
+	return merged, nil
+}
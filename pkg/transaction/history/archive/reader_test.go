@@ -0,0 +1,146 @@
+package archive
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kinecosystem/go/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func TestReader_GetTransaction(t *testing.T) {
+	env, cleanup := setup(t, 100, 200, 10_100, 20_100)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := NewArchiver(env.rw, env.rw, env.store).ArchiveSolana(ctx, 0, 100_000, base.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	r := NewReader(env.rw, env.store)
+	for _, e := range env.entries {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+
+		actual, err := r.GetTransaction(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(e, actual))
+	}
+
+	_, err = r.GetTransaction(ctx, make([]byte, 64))
+	assert.Equal(t, history.ErrNotFound, err)
+}
+
+func TestReader_GetAccountTransactions(t *testing.T) {
+	env, cleanup := setup(t, 100, 200, 300, 10_100, 10_200, 20_100, 20_200, 30_100)
+	defer cleanup()
+
+	ctx := context.Background()
+	archived, err := NewArchiver(env.rw, env.rw, env.store).ArchiveSolana(ctx, 0, 100_000, base.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 7, archived)
+
+	r := NewReader(env.rw, env.store)
+	assertEntries := func(t *testing.T, expected []int, actual []*model.Entry) {
+		require.Len(t, actual, len(expected))
+		for i, e := range expected {
+			assert.True(t, proto.Equal(env.entries[e], actual[i]), "expected: %d, index: %d", e, i)
+		}
+	}
+
+	entries, err := r.GetAccountTransactions(ctx, env.account, &history.ReadOptions{})
+	require.NoError(t, err)
+	assertEntries(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, entries)
+
+	latest, err := r.GetLatestForAccount(ctx, env.account)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(env.entries[7], latest))
+
+	// Paging should cross the archive boundary in either direction.
+	for _, descending := range []bool{false, true} {
+		expected := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		opts := &history.ReadOptions{
+			Descending: descending,
+			Limit:      3,
+		}
+		if descending {
+			expected = []int{7, 6, 5, 4, 3, 2, 1, 0}
+			opts.Start = historytestutil.GetOrderingKey(t, env.entries[7])
+		}
+
+		var paged []*model.Entry
+		for {
+			page, err := r.GetAccountTransactions(ctx, env.account, opts)
+			require.NoError(t, err)
+			if len(paged) > 0 && len(page) > 0 {
+				require.True(t, proto.Equal(paged[len(paged)-1], page[0]))
+				page = page[1:]
+			}
+			if len(page) == 0 {
+				break
+			}
+
+			paged = append(paged, page...)
+			opts.Start = historytestutil.GetOrderingKey(t, page[len(page)-1])
+		}
+		assertEntries(t, expected, paged)
+	}
+
+	// Time ranges and filters apply to archived entries.
+	entries, err = r.GetAccountTransactions(ctx, env.account, &history.ReadOptions{
+		StartTime: base.Add(200 * time.Second),
+		EndTime:   base.Add(10_101 * time.Second),
+	})
+	require.NoError(t, err)
+	assertEntries(t, []int{1, 2, 3}, entries)
+
+	entries, err = r.GetAccountTransactions(ctx, env.account, &history.ReadOptions{
+		Filter: &history.Filter{Direction: history.DirectionIncoming},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// An account with only archived history.
+	accounts, err := env.entries[0].GetAccounts()
+	require.NoError(t, err)
+
+	var receiver string
+	for _, a := range accounts {
+		if a != env.account {
+			receiver = a
+		}
+	}
+	require.NotEmpty(t, receiver)
+
+	_, err = env.rw.GetLatestForAccount(ctx, receiver)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	latest, err = r.GetLatestForAccount(ctx, receiver)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(env.entries[0], latest))
+
+	entries, err = r.GetAccountTransactions(ctx, receiver, &history.ReadOptions{Descending: true, Start: historytestutil.GetOrderingKey(t, latest)})
+	require.NoError(t, err)
+	assertEntries(t, []int{0}, entries)
+
+	unknown := strkey.MustEncode(strkey.VersionByteAccountID, make(ed25519.PublicKey, ed25519.PublicKeySize))
+	_, err = r.GetLatestForAccount(ctx, unknown)
+	assert.Equal(t, history.ErrNotFound, err)
+}
+
+func TestReader_NoArchive(t *testing.T) {
+	env, cleanup := setup(t, 100, 200)
+	defer cleanup()
+
+	r := NewReader(env.rw, env.store)
+	entries, err := r.GetAccountTransactions(context.Background(), env.account, &history.ReadOptions{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
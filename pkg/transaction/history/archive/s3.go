@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/s3iface"
+	"github.com/pkg/errors"
+)
+
+type s3Store struct {
+	client s3iface.ClientAPI
+	bucket string
+	prefix string
+}
+
+// NewS3Store returns an ObjectStore backed by an S3 bucket. All keys are
+// stored under the (optional) prefix.
+func NewS3Store(client s3iface.ClientAPI, bucket, prefix string) ObjectStore {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &s3Store{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Put implements ObjectStore.Put.
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+		Body:   bytes.NewReader(data),
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to put object")
+	}
+
+	return nil
+}
+
+// Get implements ObjectStore.Get.
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	}).Send(ctx)
+	if err != nil {
+		if aErr, ok := err.(awserr.Error); ok && aErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrObjectNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read object")
+	}
+
+	return data, nil
+}
+
+// List implements ObjectStore.List.
+func (s *s3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	p := s3.NewListObjectsV2Paginator(s.client.ListObjectsV2Request(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix + prefix),
+	}))
+
+	var keys []string
+	for p.Next(ctx) {
+		for _, obj := range p.CurrentPage().Contents {
+			keys = append(keys, strings.TrimPrefix(aws.StringValue(obj.Key), s.prefix))
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to list objects")
+	}
+
+	// S3 lists keys in UTF-8 binary order, which already matches, but the
+	// ordering is part of the ObjectStore contract.
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete implements ObjectStore.Delete.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	// S3 does not return an error when deleting a missing object.
+	_, err := s.client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete object")
+	}
+
+	return nil
+}
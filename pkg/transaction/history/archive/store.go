@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrObjectNotFound indicates an object does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore is a minimal object storage interface, such as S3 or GCS.
+//
+// Keys are '/' separated paths.
+type ObjectStore interface {
+	// Put stores the object under the specified key, replacing any existing
+	// object.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get returns the object stored under the specified key.
+	//
+	// ErrObjectNotFound is returned if no object exists.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// List returns the (lexicographically ordered) keys of all the objects
+	// whose key starts with the specified prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete removes the object stored under the specified key. Deleting an
+	// object that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore returns an ObjectStore backed by the local filesystem, rooted
+// at the specified directory.
+func NewFileStore(dir string) (ObjectStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create archive directory")
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+// Put implements ObjectStore.Put.
+func (s *fileStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create object directory")
+	}
+
+	// Objects are written to a temporary file and renamed, so readers never
+	// observe a partially written object.
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write object")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write object")
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to write object")
+	}
+
+	return nil
+}
+
+// Get implements ObjectStore.Get.
+func (s *fileStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read object")
+	}
+
+	return data, nil
+}
+
+// List implements ObjectStore.List.
+func (s *fileStore) List(_ context.Context, prefix string) ([]string, error) {
+	// Only walk the deepest directory that contains the prefix.
+	root := s.dir
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		root = filepath.Join(s.dir, filepath.FromSlash(prefix[:i]))
+	}
+
+	var keys []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list objects")
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete implements ObjectStore.Delete.
+func (s *fileStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to delete object")
+	}
+
+	return nil
+}
+
+func (s *fileStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") {
+		return "", errors.Errorf("invalid key: %s", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
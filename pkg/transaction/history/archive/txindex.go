@@ -0,0 +1,292 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// The transaction index maps transaction ids to the partition that contains
+// them. Rather than an object per transaction, the index is sharded by the
+// first byte of the transaction id, and each shard is a set of segments:
+//
+//	tx/<shard>/<level>-<version>-<partition>.gz
+//
+// Archiving a partition adds a level 0 segment to each shard it contains a
+// transaction for. Once a level has txSegmentFanout segments, they are merged
+// into a single segment at the next level. This bounds the number of segments
+// read by a lookup to (txSegmentFanout - 1) per level, while each transaction
+// is only rewritten once per level.
+const txSegmentFanout = 4
+
+type txRef struct {
+	id        []byte
+	version   model.KinVersion
+	partition uint64
+}
+
+func txShardPrefix(shard byte) string {
+	return fmt.Sprintf("tx/%02x/", shard)
+}
+
+func txSegmentKey(shard byte, level int, version model.KinVersion, partition uint64) string {
+	return fmt.Sprintf("%s%02d-%d-%020d.gz", txShardPrefix(shard), level, version, partition)
+}
+
+// levelFromSegmentKey returns the level, and the suffix (version and partition),
+// of the segment.
+func levelFromSegmentKey(key string) (level int, suffix string, err error) {
+	name := key[strings.LastIndex(key, "/")+1:]
+	i := strings.Index(name, "-")
+	if i < 0 {
+		return 0, "", errors.Errorf("invalid tx segment: %s", key)
+	}
+
+	level, err = strconv.Atoi(name[:i])
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "invalid tx segment: %s", key)
+	}
+
+	return level, name[i+1:], nil
+}
+
+func encodeTxRefs(refs []txRef) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	var scratch [binary.MaxVarintLen64]byte
+	for _, ref := range refs {
+		n := binary.PutUvarint(scratch[:], uint64(len(ref.id)))
+		record := append([]byte{}, scratch[:n]...)
+		record = append(record, ref.id...)
+		record = append(record, byte(ref.version))
+		n = binary.PutUvarint(scratch[:], ref.partition)
+		record = append(record, scratch[:n]...)
+
+		if _, err := w.Write(record); err != nil {
+			return nil, errors.Wrap(err, "failed to write tx ref")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to compress tx refs")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeTxRefs(data []byte) ([]txRef, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress tx refs")
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress tx refs")
+	}
+
+	var refs []txRef
+	buf := bytes.NewReader(raw)
+	for {
+		n, err := binary.ReadUvarint(buf)
+		if err == io.EOF {
+			return refs, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to read tx id length")
+		}
+
+		ref := txRef{id: make([]byte, n)}
+		if _, err := io.ReadFull(buf, ref.id); err != nil {
+			return nil, errors.Wrap(err, "failed to read tx id")
+		}
+
+		version, err := buf.ReadByte()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read version")
+		}
+		ref.version = model.KinVersion(version)
+
+		if ref.partition, err = binary.ReadUvarint(buf); err != nil {
+			return nil, errors.Wrap(err, "failed to read partition")
+		}
+
+		refs = append(refs, ref)
+	}
+}
+
+// addToTxIndex adds the entries of the partition to the transaction index.
+//
+// Segments are keyed by the partition, so re-adding a partition replaces its
+// level 0 segments. If those segments were already merged, the merged segment
+// contains duplicate references, which is harmless.
+func addToTxIndex(ctx context.Context, store ObjectStore, version model.KinVersion, partition uint64, entries []*model.Entry) error {
+	byShard := make(map[byte][]txRef)
+	for _, e := range entries {
+		txID, err := e.GetTxID()
+		if err != nil {
+			return errors.Wrap(err, "failed to get tx id")
+		}
+
+		byShard[txID[0]] = append(byShard[txID[0]], txRef{
+			id:        txID,
+			version:   version,
+			partition: partition,
+		})
+	}
+
+	shards := make([]byte, 0, len(byShard))
+	for shard := range byShard {
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+
+	for _, shard := range shards {
+		data, err := encodeTxRefs(byShard[shard])
+		if err != nil {
+			return err
+		}
+
+		if err := store.Put(ctx, txSegmentKey(shard, 0, version, partition), data); err != nil {
+			return errors.Wrap(err, "failed to put tx segment")
+		}
+
+		if err := compactTxShard(ctx, store, shard); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compactTxShard merges the segments of each full level in the shard into a
+// single segment at the next level.
+func compactTxShard(ctx context.Context, store ObjectStore, shard byte) error {
+	keys, err := store.List(ctx, txShardPrefix(shard))
+	if err != nil {
+		return errors.Wrap(err, "failed to list tx segments")
+	}
+
+	byLevel := make(map[int][]string)
+	var maxLevel int
+	for _, key := range keys {
+		level, _, err := levelFromSegmentKey(key)
+		if err != nil {
+			return err
+		}
+
+		byLevel[level] = append(byLevel[level], key)
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+
+	for level := 0; level <= maxLevel; level++ {
+		segments := byLevel[level]
+		if len(segments) < txSegmentFanout {
+			continue
+		}
+
+		var merged []txRef
+		for _, key := range segments {
+			refs, err := loadTxRefs(ctx, store, key)
+			if err != nil {
+				return err
+			}
+			merged = append(merged, refs...)
+		}
+
+		data, err := encodeTxRefs(merged)
+		if err != nil {
+			return err
+		}
+
+		// The merged segment takes the suffix of the latest segment, which
+		// keeps segment keys unique.
+		_, suffix, err := levelFromSegmentKey(segments[len(segments)-1])
+		if err != nil {
+			return err
+		}
+		mergedKey := fmt.Sprintf("%s%02d-%s", txShardPrefix(shard), level+1, suffix)
+
+		// The merged segment is written before the sources are removed, so a
+		// failure only results in duplicate references.
+		if err := store.Put(ctx, mergedKey, data); err != nil {
+			return errors.Wrap(err, "failed to put merged tx segment")
+		}
+		for _, key := range segments {
+			if err := store.Delete(ctx, key); err != nil {
+				return errors.Wrap(err, "failed to delete merged tx segment")
+			}
+		}
+
+		byLevel[level+1] = append(byLevel[level+1], mergedKey)
+		if level+1 > maxLevel {
+			maxLevel = level + 1
+		}
+	}
+
+	return nil
+}
+
+// lookupTx returns the version and partition of the archived transaction.
+//
+// ErrObjectNotFound is returned if the transaction has not been archived.
+func lookupTx(ctx context.Context, store ObjectStore, txID []byte) (model.KinVersion, uint64, error) {
+	if len(txID) == 0 {
+		return 0, 0, ErrObjectNotFound
+	}
+
+	// If a segment is merged while it's being looked up, the merged segment
+	// may not have been listed, so the lookup is retried.
+	for attempt := 0; attempt < 2; attempt++ {
+		keys, err := store.List(ctx, txShardPrefix(txID[0]))
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "failed to list tx segments")
+		}
+
+		var merged bool
+		for _, key := range keys {
+			refs, err := loadTxRefs(ctx, store, key)
+			if err == ErrObjectNotFound {
+				merged = true
+				continue
+			} else if err != nil {
+				return 0, 0, err
+			}
+
+			for _, ref := range refs {
+				if bytes.Equal(ref.id, txID) {
+					return ref.version, ref.partition, nil
+				}
+			}
+		}
+
+		if !merged {
+			break
+		}
+	}
+
+	return 0, 0, ErrObjectNotFound
+}
+
+func loadTxRefs(ctx context.Context, store ObjectStore, key string) ([]txRef, error) {
+	data, err := store.Get(ctx, key)
+	if err == ErrObjectNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", key)
+	}
+
+	return decodeTxRefs(data)
+}
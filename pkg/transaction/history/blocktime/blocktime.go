@@ -0,0 +1,98 @@
+package blocktime
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const pageSize = 1000
+
+// Result contains the outcome of a backfill.
+type Result struct {
+	// Updated is the number of entries added to the block time index.
+	Updated int
+
+	// Skipped is the number of entries whose block time is not yet known
+	// (for example, unconfirmed Solana entries). They are indexed once they
+	// are written with a block time.
+	Skipped int
+}
+
+// Backfill adds every entry in the account history of a store that is
+// missing from its block time index.
+//
+// Stellar entries always contain their ledger close time, and are rewritten
+// as is. Confirmed Solana entries without a block time have it loaded from
+// the client. Entries are rewritten using the repairer, so that every index
+// (including the one used by GetTransaction()) contains the block time.
+//
+// Entries related to multiple accounts may be rewritten more than once, as
+// rewriting is idempotent.
+func Backfill(ctx context.Context, indexer history.TimeIndexer, repairer history.Repairer, client solana.Client) (Result, error) {
+	var result Result
+	var cursor []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		entries, next, err := indexer.GetUnindexed(ctx, cursor, pageSize)
+		if err != nil {
+			return result, errors.Wrap(err, "failed to get unindexed entries")
+		}
+
+		// Block times are cached per page, as entries from the same block
+		// are typically adjacent.
+		times := make(map[uint64]*timestamp.Timestamp)
+
+		for _, e := range entries {
+			if sol := e.GetSolana(); sol != nil && sol.BlockTime == nil {
+				if !sol.Confirmed {
+					result.Skipped++
+					continue
+				}
+
+				ts, ok := times[sol.Slot]
+				if !ok {
+					blockTime, err := client.GetBlockTime(sol.Slot)
+					if err != nil {
+						return result, errors.Wrapf(err, "failed to get block time for slot %d", sol.Slot)
+					}
+
+					if ts, err = ptypes.TimestampProto(blockTime); err != nil {
+						return result, errors.Wrap(err, "failed to marshal block time")
+					}
+					times[sol.Slot] = ts
+				}
+
+				e = proto.Clone(e).(*model.Entry)
+				e.GetSolana().BlockTime = ts
+			} else if t, err := e.GetBlockTime(); err != nil {
+				return result, errors.Wrap(err, "failed to get block time")
+			} else if t.IsZero() {
+				result.Skipped++
+				continue
+			}
+
+			if err := repairer.Repair(ctx, e); err != nil {
+				return result, errors.Wrap(err, "failed to rewrite entry")
+			}
+			result.Updated++
+		}
+
+		if next == nil {
+			return result, nil
+		}
+		cursor = next
+	}
+}
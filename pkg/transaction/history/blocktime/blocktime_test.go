@@ -0,0 +1,93 @@
+package blocktime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/go/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func TestBackfill(t *testing.T) {
+	rw := historymemory.New()
+	client := solana.NewMockClient()
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+
+	// Slots 1 and 2 contain two entries each without a block time, and slot
+	// 3 contains an entry that already has one.
+	base := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	var entries []*model.Entry
+	for _, slot := range []uint64{1, 1, 2, 2} {
+		e, _ := historytestutil.GenerateSolanaEntry(t, slot, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+		entries = append(entries, e)
+	}
+	e, _ := historytestutil.GenerateSolanaEntry(t, 3, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	ts, err := ptypes.TimestampProto(base)
+	require.NoError(t, err)
+	e.GetSolana().BlockTime = ts
+	entries = append(entries, e)
+
+	// Unconfirmed entries have no block time yet, and are left alone.
+	unconfirmed, _ := historytestutil.GenerateSolanaEntry(t, 2, false, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	entries = append(entries, unconfirmed)
+
+	for _, e := range entries {
+		require.NoError(t, rw.Write(context.Background(), e))
+	}
+
+	client.On("GetBlockTime", uint64(1)).Return(base.Add(time.Minute), nil)
+	client.On("GetBlockTime", uint64(2)).Return(base.Add(2*time.Minute), nil)
+
+	result, err := Backfill(context.Background(), rw, rw, client)
+	require.NoError(t, err)
+
+	// Each entry is related to two accounts, so it's scanned twice.
+	assert.Equal(t, 8, result.Updated)
+	assert.Equal(t, 2, result.Skipped)
+
+	// Block times are only fetched once per slot.
+	client.AssertNumberOfCalls(t, "GetBlockTime", 2)
+
+	receiverAddr := strkey.MustEncode(strkey.VersionByteAccountID, receivers[0])
+	inRange, err := rw.GetAccountTransactions(context.Background(), receiverAddr, &history.ReadOptions{
+		StartTime: base.Add(time.Minute),
+		EndTime:   base.Add(3 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, inRange, 4)
+	for i, e := range inRange {
+		expected := base.Add(time.Duration(1+i/2) * time.Minute)
+		actual, err := e.GetBlockTime()
+		require.NoError(t, err)
+		assert.True(t, expected.Equal(actual))
+
+		// The block time is also visible by hash.
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+		byHash, err := rw.GetTransaction(context.Background(), txID)
+		require.NoError(t, err)
+		actual, err = byHash.GetBlockTime()
+		require.NoError(t, err)
+		assert.True(t, expected.Equal(actual))
+	}
+
+	// Only the unconfirmed entry remains unindexed.
+	remaining, next, err := rw.GetUnindexed(context.Background(), nil, 100)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	require.Len(t, remaining, 2)
+	for _, e := range remaining {
+		assert.False(t, e.GetSolana().Confirmed)
+	}
+}
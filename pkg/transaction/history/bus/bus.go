@@ -0,0 +1,158 @@
+// Package bus publishes history entries to a message bus, providing a real
+// time feed of Kin transactions to downstream consumers.
+//
+// Each entry is published as a JSON encoded Message, keyed by the (base58
+// encoded) transaction ID. The Writer returns once the bus has acknowledged
+// the message, so when it is used as a sink of an ingestion pass (see the
+// ingestion/fanout package), a block is only committed once all of its
+// entries have been published. Delivery is therefore at-least-once, and
+// consumers should deduplicate messages by their key.
+//
+// Publishers are provided for Redis Streams (bus/redis) and Kafka (bus/kafka).
+// Core NATS does not persist or acknowledge messages, so it cannot provide
+// at-least-once delivery, and is not supported. NATS JetStream does, but is
+// not yet supported, as its client requires newer versions of the
+// golang.org/x modules than the rest of agora.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/export"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// Publisher publishes messages to a message bus, such as Redis Streams or
+// Kafka.
+type Publisher interface {
+	// Publish publishes the data under the specified key. It returns once the
+	// message has been acknowledged by the bus.
+	Publish(ctx context.Context, key string, data []byte) error
+}
+
+// Message is the published representation of a history entry.
+type Message struct {
+	TxID       string `json:"tx_id"`
+	KinVersion int32  `json:"kin_version"`
+	Block      int64  `json:"block"`
+	BlockTime  string `json:"block_time,omitempty"`
+	Successful bool   `json:"successful"`
+	Error      string `json:"error,omitempty"`
+
+	MemoText string `json:"memo_text,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	MemoType string `json:"memo_type,omitempty"`
+	AppIndex int32  `json:"app_index,omitempty"`
+
+	Payments []*Payment `json:"payments"`
+
+	// Entry is the marshalled model.Entry.
+	Entry []byte `json:"entry"`
+}
+
+// Payment is a single payment contained in a transaction. Accounts are base58
+// encoded, and amounts are in quarks.
+type Payment struct {
+	Index       int32  `json:"index"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Quarks      int64  `json:"quarks"`
+}
+
+// NewMessage returns the Message for an entry.
+func NewMessage(e *model.Entry) (*Message, error) {
+	rows, err := export.RowsFromEntry(e, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode entry")
+	}
+
+	raw, err := proto.Marshal(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal entry")
+	}
+
+	// Transaction level fields are shared by every row. Memos apply to the
+	// payments that follow them, so the memo (and app index) of the
+	// transaction is that of its first payment.
+	r := rows[0]
+	m := &Message{
+		TxID:       r.TxID,
+		KinVersion: r.KinVersion,
+		Block:      r.Block,
+		BlockTime:  r.BlockTime,
+		Successful: r.Successful,
+		Error:      r.Error,
+		MemoText:   r.MemoText,
+		Memo:       r.Memo,
+		MemoType:   r.MemoType,
+		AppIndex:   r.AppIndex,
+		Payments:   make([]*Payment, 0, len(rows)),
+		Entry:      raw,
+	}
+	for _, r := range rows {
+		if r.PaymentIndex < 0 {
+			continue
+		}
+
+		m.Payments = append(m.Payments, &Payment{
+			Index:       r.PaymentIndex,
+			Source:      r.Source,
+			Destination: r.Destination,
+			Quarks:      r.Quarks,
+		})
+	}
+
+	return m, nil
+}
+
+// GetEntry returns the model.Entry contained in the message.
+func (m *Message) GetEntry() (*model.Entry, error) {
+	e := &model.Entry{}
+	if err := proto.Unmarshal(m.Entry, e); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal entry")
+	}
+
+	return e, nil
+}
+
+type writer struct {
+	publisher Publisher
+}
+
+// NewWriter returns a history.Writer that publishes every written entry
+// using the publisher.
+func NewWriter(p Publisher) history.Writer {
+	return &writer{
+		publisher: p,
+	}
+}
+
+// Write implements history.Writer.Write.
+func (w *writer) Write(ctx context.Context, e *model.Entry) error {
+	txID, err := e.GetTxID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get tx id")
+	}
+
+	m, err := NewMessage(e)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	if err := w.publisher.Publish(ctx, base58.Encode(txID), data); err != nil {
+		return errors.Wrap(err, "failed to publish message")
+	}
+
+	return nil
+}
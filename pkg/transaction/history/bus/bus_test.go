@@ -0,0 +1,138 @@
+package bus_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history/bus"
+	busmemory "github.com/kinecosystem/agora/pkg/transaction/history/bus/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/fanout"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func TestNewMessage(t *testing.T) {
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 2)
+
+	e, txID := historytestutil.GenerateSolanaEntry(t, 10, true, sender, receivers, make([]byte, 28), nil)
+	e.GetSolana().TransactionError = []byte("failed")
+
+	m, err := bus.NewMessage(e)
+	require.NoError(t, err)
+
+	assert.Equal(t, base58.Encode(txID), m.TxID)
+	assert.EqualValues(t, 4, m.KinVersion)
+	assert.EqualValues(t, 10, m.Block)
+	assert.False(t, m.Successful)
+	assert.Equal(t, "failed", m.Error)
+	assert.Equal(t, "spend", m.MemoType)
+	assert.EqualValues(t, 1, m.AppIndex)
+
+	require.Len(t, m.Payments, 2)
+	for i, p := range m.Payments {
+		assert.EqualValues(t, i+1, p.Index)
+		assert.Equal(t, base58.Encode(sender.Public().(ed25519.PublicKey)), p.Source)
+		assert.Equal(t, base58.Encode(receivers[i]), p.Destination)
+		assert.EqualValues(t, 10+i+1, p.Quarks)
+	}
+
+	actual, err := m.GetEntry()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(e, actual))
+
+	// Transactions without payments are still published.
+	memo := "hello"
+	e, _ = historytestutil.GenerateSolanaEntry(t, 10, true, sender, nil, nil, &memo)
+	m, err = bus.NewMessage(e)
+	require.NoError(t, err)
+	assert.True(t, m.Successful)
+	assert.Equal(t, "hello", m.MemoText)
+	assert.Empty(t, m.Payments)
+}
+
+func TestWriter(t *testing.T) {
+	p := busmemory.New()
+	w := bus.NewWriter(p)
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 10, true, sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+	require.NoError(t, w.Write(context.Background(), e))
+
+	require.Len(t, p.Messages, 1)
+	assert.Equal(t, base58.Encode(txID), p.Messages[0].Key)
+
+	var m bus.Message
+	require.NoError(t, json.Unmarshal(p.Messages[0].Data, &m))
+	actual, err := m.GetEntry()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(e, actual))
+
+	assert.Error(t, bus.NewWriter(&failingPublisher{}).Write(context.Background(), e))
+}
+
+// TestWriter_FanOut verifies that, as a fan-out sink, every committed entry
+// is published at least once.
+func TestWriter_FanOut(t *testing.T) {
+	primary := historymemory.New()
+	committer := memory.New()
+	p := busmemory.New()
+
+	f := fanout.New(primary, fanout.NewSolanaSource(primary), committer, "ingestor", []fanout.Sink{{Name: "bus", Writer: bus.NewWriter(p)}}, fanout.WithPollInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = f.Run(ctx, &noopLock{})
+	}()
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	expected := make(map[string]struct{})
+	var parent []byte
+	for slot := uint64(1); slot <= 5; slot++ {
+		e, txID := historytestutil.GenerateSolanaEntry(t, slot, true, sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+		require.NoError(t, f.Write(ctx, e))
+		expected[base58.Encode(txID)] = struct{}{}
+
+		block := solanaingestor.PointerFromSlot(slot)
+		require.NoError(t, committer.Commit(ctx, "ingestor", parent, block))
+		parent = block
+	}
+
+	require.Eventually(t, func() bool {
+		latest, err := committer.Latest(ctx, "bus")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(parent, []byte(latest))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	p.Lock()
+	defer p.Unlock()
+	published := make(map[string]struct{})
+	for _, m := range p.Messages {
+		published[m.Key] = struct{}{}
+	}
+	assert.Equal(t, expected, published)
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(_ context.Context, _ string, _ []byte) error {
+	return errors.New("unavailable")
+}
+
+type noopLock struct{}
+
+func (noopLock) Lock(_ context.Context) error { return nil }
+func (noopLock) Unlock() error                { return nil }
@@ -0,0 +1,59 @@
+// Package kafka implements a bus.Publisher backed by a Kafka topic.
+//
+// Messages are written with the message key, so all messages for a
+// transaction are assigned to the same partition. Publish waits for all in-sync
+// replicas to acknowledge the message.
+package kafka
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+// messageWriter is the subset of kafka.Writer used by the Publisher.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Publisher is a bus.Publisher that writes messages to a Kafka topic.
+type Publisher struct {
+	writer messageWriter
+}
+
+// New returns a Publisher that writes messages to the topic, using the
+// specified brokers to discover the cluster.
+func New(brokers []string, topic string) *Publisher {
+	return &Publisher{
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:  brokers,
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+
+			// Messages are published one at a time, and each publish waits
+			// for the message to be acknowledged, so there's nothing to batch.
+			BatchSize:    1,
+			RequiredAcks: -1,
+		}),
+	}
+}
+
+// Publish implements bus.Publisher.Publish.
+func (p *Publisher) Publish(ctx context.Context, key string, data []byte) error {
+	err := p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(key),
+		Value: data,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to write message")
+	}
+
+	return nil
+}
+
+// Close flushes and closes the underlying writer.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,127 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	kafkatest "github.com/kinecosystem/agora/pkg/testutil/kafka"
+)
+
+var (
+	broker string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	broker, cleanUpFunc, err = kafkatest.StartKafka(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting kafka image")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestPublisher_Broker(t *testing.T) {
+	conn, err := kafka.Dial("tcp", broker)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.CreateTopics(kafka.TopicConfig{
+		Topic:             "history",
+		NumPartitions:     1,
+		ReplicationFactor: 1,
+	}))
+
+	p := New([]string{broker}, "history")
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.Publish(ctx, fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("data-%d", i))))
+	}
+
+	leader, err := kafka.DialLeader(ctx, "tcp", broker, "history", 0)
+	require.NoError(t, err)
+	defer leader.Close()
+
+	require.NoError(t, leader.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for i := 0; i < 3; i++ {
+		m, err := leader.ReadMessage(1 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("key-%d", i), string(m.Key))
+		assert.Equal(t, fmt.Sprintf("data-%d", i), string(m.Value))
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	w := &testWriter{}
+	p := &Publisher{writer: w}
+
+	require.NoError(t, p.Publish(context.Background(), "key-0", []byte("data-0")))
+	require.NoError(t, p.Publish(context.Background(), "key-1", []byte("data-1")))
+
+	require.Len(t, w.messages, 2)
+	for i, m := range w.messages {
+		assert.Equal(t, []byte{'k', 'e', 'y', '-', byte('0' + i)}, m.Key)
+		assert.Equal(t, []byte{'d', 'a', 't', 'a', '-', byte('0' + i)}, m.Value)
+	}
+
+	// Unacknowledged messages must fail the publish, so that they can be
+	// retried.
+	w.err = errors.New("not enough replicas")
+	assert.Error(t, p.Publish(context.Background(), "key-2", []byte("data-2")))
+	assert.Len(t, w.messages, 2)
+
+	require.NoError(t, p.Close())
+	assert.True(t, w.closed)
+}
+
+type testWriter struct {
+	sync.Mutex
+	messages []kafka.Message
+	err      error
+	closed   bool
+}
+
+func (w *testWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	w.closed = true
+	return nil
+}
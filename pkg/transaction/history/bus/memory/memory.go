@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a published message.
+type Message struct {
+	Key  string
+	Data []byte
+}
+
+// Publisher is an in memory bus.Publisher, intended for testing and local
+// development.
+type Publisher struct {
+	sync.Mutex
+	Messages []Message
+}
+
+// New returns a new in memory Publisher.
+func New() *Publisher {
+	return &Publisher{}
+}
+
+// Publish implements bus.Publisher.Publish.
+func (p *Publisher) Publish(_ context.Context, key string, data []byte) error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.Messages = append(p.Messages, Message{
+		Key:  key,
+		Data: append([]byte(nil), data...),
+	})
+	return nil
+}
+
+// Reset clears the published messages.
+func (p *Publisher) Reset() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.Messages = nil
+}
@@ -0,0 +1,57 @@
+// Package redis implements a bus.Publisher backed by a Redis Stream.
+//
+// Each message is added to the stream as an entry with the fields:
+//
+//	key  the message key
+//	data the message data
+//
+// Consumers can read the stream using consumer groups (XREADGROUP), which
+// provides at-least-once delivery to each group.
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/bus"
+)
+
+type publisher struct {
+	client redis.Cmdable
+	stream string
+	maxLen int64
+}
+
+// New returns a bus.Publisher that adds messages to the specified stream.
+//
+// If maxLen is greater than zero, the stream is (approximately) trimmed to
+// the most recent maxLen messages.
+func New(client redis.Cmdable, stream string, maxLen int64) bus.Publisher {
+	return &publisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Publish implements bus.Publisher.Publish.
+func (p *publisher) Publish(_ context.Context, key string, data []byte) error {
+	args := &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]interface{}{
+			"key":  key,
+			"data": data,
+		},
+	}
+	if p.maxLen > 0 {
+		args.MaxLenApprox = p.maxLen
+	}
+
+	if err := p.client.XAdd(args).Err(); err != nil {
+		return errors.Wrap(err, "failed to add message to stream")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-redis/redis/v7"
+	redistest "github.com/kinecosystem/agora-common/redis/test"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	redisConnString string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	redisConnString, cleanUpFunc, err = redistest.StartRedis(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting redis connection")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestPublisher(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: redisConnString,
+	})
+	defer client.FlushAll()
+
+	p := New(client, "history", 0)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.Publish(context.Background(), fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("data-%d", i))))
+	}
+
+	messages, err := client.XRange("history", "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	for i, m := range messages {
+		assert.Equal(t, fmt.Sprintf("key-%d", i), m.Values["key"])
+		assert.Equal(t, fmt.Sprintf("data-%d", i), m.Values["data"])
+	}
+}
+
+func TestPublisher_MaxLen(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: redisConnString,
+	})
+	defer client.FlushAll()
+
+	// Trimming is approximate, so we only verify that the stream is bounded.
+	p := New(client, "history", 10)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, p.Publish(context.Background(), "key", []byte("data")))
+	}
+
+	n, err := client.XLen("history").Result()
+	require.NoError(t, err)
+	assert.True(t, n < 1000)
+}
@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+var (
+	hitCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "history_cache_hits",
+		Help:      "Number of history transaction cache hits",
+	})
+	missCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "history_cache_misses",
+		Help:      "Number of history transaction cache misses",
+	})
+)
+
+const defaultNotFoundTTL = 2 * time.Second
+
+type item struct {
+	// entry is nil for invalidations and not found results.
+	entry *model.Entry
+
+	// invalidated indicates the item was written to, and should not be served.
+	invalidated bool
+	created     time.Time
+}
+
+type cache struct {
+	history.ReaderWriter
+
+	// mu guards the check-and-set of results against invalidations.
+	mu          sync.Mutex
+	cache       *lru.Cache
+	ttl         time.Duration
+	notFoundTTL time.Duration
+}
+
+// Option configures a cache.
+type Option func(c *cache)
+
+// WithNotFoundTTL configures how long not found results are cached for.
+//
+// A ttl of 0 disables caching of not found results.
+func WithNotFoundTTL(ttl time.Duration) Option {
+	return func(c *cache) {
+		c.notFoundTTL = ttl
+	}
+}
+
+// New returns a history.ReaderWriter that caches the results of
+// GetTransaction() in a bounded LRU cache.
+//
+// Only finalized entries (confirmed Solana entries, and Stellar entries) are
+// cached. Not found results are cached for a short time (2s, by default), as
+// the entry may be written by another process (i.e. ingestion) at any time.
+//
+// Writes, prunes and repairs through the cache invalidate any cached result
+// for the transaction. Prune, Repair and GetUnindexed are forwarded to rw,
+// if it implements the corresponding interface. Writes by other processes (for example, ingestion in another
+// instance, repairs and block time backfills) are not observed, so cached
+// results only expire after their ttl.
+//
+// All other methods are passed through to rw.
+func New(rw history.ReaderWriter, maxSize int, ttl time.Duration, opts ...Option) (history.ReaderWriter, error) {
+	lruCache, err := lru.New(maxSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create history cache")
+	}
+
+	if err := registerMetrics(); err != nil {
+		return nil, err
+	}
+
+	c := &cache{
+		ReaderWriter: rw,
+		cache:        lruCache,
+		ttl:          ttl,
+		notFoundTTL:  defaultNotFoundTTL,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c, nil
+}
+
+// GetTransaction implements history.Reader.GetTransaction.
+func (c *cache) GetTransaction(ctx context.Context, txHash []byte) (*model.Entry, error) {
+	key := string(txHash)
+	if cached, ok := c.cache.Get(key); ok {
+		it := cached.(*item)
+		switch {
+		case it.invalidated:
+		case it.entry == nil && time.Since(it.created) < c.notFoundTTL:
+			hitCounter.Inc()
+			return nil, history.ErrNotFound
+		case it.entry != nil && time.Since(it.created) < c.ttl:
+			hitCounter.Inc()
+			return proto.Clone(it.entry).(*model.Entry), nil
+		}
+	}
+
+	missCounter.Inc()
+
+	start := time.Now()
+	e, err := c.ReaderWriter.GetTransaction(ctx, txHash)
+	if err == history.ErrNotFound && c.notFoundTTL > 0 {
+		c.add(key, &item{created: start})
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	if isFinalized(e) {
+		c.add(key, &item{entry: proto.Clone(e).(*model.Entry), created: start})
+	}
+
+	return e, nil
+}
+
+// Write implements history.Writer.Write.
+func (c *cache) Write(ctx context.Context, e *model.Entry) error {
+	err := c.ReaderWriter.Write(ctx, e)
+	c.invalidate(e)
+	return err
+}
+
+// Prune implements history.Pruner.Prune.
+//
+// history.ErrNotSupported is returned if the underlying store does not
+// implement history.Pruner.
+func (c *cache) Prune(ctx context.Context, e *model.Entry) error {
+	pruner, ok := c.ReaderWriter.(history.Pruner)
+	if !ok {
+		return history.ErrNotSupported
+	}
+
+	err := pruner.Prune(ctx, e)
+	c.invalidate(e)
+	return err
+}
+
+// Repair implements history.Repairer.Repair.
+//
+// history.ErrNotSupported is returned if the underlying store does not
+// implement history.Repairer.
+func (c *cache) Repair(ctx context.Context, e *model.Entry) error {
+	repairer, ok := c.ReaderWriter.(history.Repairer)
+	if !ok {
+		return history.ErrNotSupported
+	}
+
+	err := repairer.Repair(ctx, e)
+	c.invalidate(e)
+	return err
+}
+
+// GetUnindexed implements history.TimeIndexer.GetUnindexed.
+//
+// history.ErrNotSupported is returned if the underlying store does not
+// implement history.TimeIndexer.
+func (c *cache) GetUnindexed(ctx context.Context, cursor []byte, limit int) ([]*model.Entry, []byte, error) {
+	indexer, ok := c.ReaderWriter.(history.TimeIndexer)
+	if !ok {
+		return nil, nil, history.ErrNotSupported
+	}
+
+	return indexer.GetUnindexed(ctx, cursor, limit)
+}
+
+// invalidate invalidates any cached result for the entry's transaction.
+//
+// Callers invalidate regardless of the result of an update, as a failed
+// update may have been partially applied.
+func (c *cache) invalidate(e *model.Entry) {
+	txID, err := e.GetTxID()
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.cache.Add(string(txID), &item{invalidated: true, created: time.Now()})
+	c.mu.Unlock()
+}
+
+// add caches the result of a read that started at it.created, unless the
+// transaction was written to since then.
+func (c *cache) add(key string, it *item) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.cache.Peek(key); ok {
+		if e := existing.(*item); e.invalidated && !e.created.Before(it.created) {
+			return
+		}
+	}
+
+	c.cache.Add(key, it)
+}
+
+func isFinalized(e *model.Entry) bool {
+	if e.GetStellar() != nil {
+		return true
+	}
+
+	return e.GetSolana().GetConfirmed()
+}
+
+func registerMetrics() error {
+	if err := prometheus.Register(hitCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			hitCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			return errors.Wrap(err, "failed to register history cache hit counter")
+		}
+	}
+	if err := prometheus.Register(missCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			missCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			return errors.Wrap(err, "failed to register history cache miss counter")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+// countingRW counts the number of GetTransaction() calls. If release is set,
+// results are held until it is closed.
+type countingRW struct {
+	*historymemory.RW
+
+	sync.Mutex
+	reads   int
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (rw *countingRW) GetTransaction(ctx context.Context, txHash []byte) (*model.Entry, error) {
+	rw.Lock()
+	rw.reads++
+	read, release := rw.read, rw.release
+	rw.read, rw.release = nil, nil
+	rw.Unlock()
+
+	e, err := rw.RW.GetTransaction(ctx, txHash)
+	if release != nil {
+		close(read)
+		<-release
+	}
+
+	return e, err
+}
+
+func (rw *countingRW) count() int {
+	rw.Lock()
+	defer rw.Unlock()
+	return rw.reads
+}
+
+func TestCache_Finalized(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, time.Minute)
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	confirmed, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	require.NoError(t, c.Write(context.Background(), confirmed))
+
+	_, sender := testutil.GenerateAccountID(t)
+	stellar, stellarID := historytestutil.GenerateStellarEntry(t, 1, 1, sender, testutil.GenerateAccountIDs(t, 1), nil, nil)
+	require.NoError(t, c.Write(context.Background(), stellar))
+
+	for _, tc := range []struct {
+		id    []byte
+		entry *model.Entry
+	}{
+		{txID, confirmed},
+		{stellarID, stellar},
+	} {
+		reads := rw.count()
+		for i := 0; i < 3; i++ {
+			actual, err := c.GetTransaction(context.Background(), tc.id)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tc.entry, actual))
+
+			// Cached entries should not be shared with callers.
+			actual.Version = 0
+		}
+		assert.Equal(t, reads+1, rw.count())
+	}
+}
+
+func TestCache_Unconfirmed(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, time.Minute)
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	unconfirmed, txID := historytestutil.GenerateSolanaEntry(t, 1, false, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	require.NoError(t, c.Write(context.Background(), unconfirmed))
+
+	for i := 0; i < 3; i++ {
+		actual, err := c.GetTransaction(context.Background(), txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(unconfirmed, actual))
+	}
+	assert.Equal(t, 3, rw.count())
+}
+
+func TestCache_NotFound(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, time.Minute, WithNotFoundTTL(100*time.Millisecond))
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTransaction(context.Background(), txID)
+		assert.Equal(t, history.ErrNotFound, err)
+	}
+	assert.Equal(t, 1, rw.count())
+
+	// Entries written by another process are visible once the not found
+	// result expires.
+	require.NoError(t, rw.Write(context.Background(), e))
+	_, err = c.GetTransaction(context.Background(), txID)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	time.Sleep(150 * time.Millisecond)
+	actual, err := c.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(e, actual))
+	assert.Equal(t, 2, rw.count())
+
+	// Entries written through the cache are visible immediately.
+	other, otherID := historytestutil.GenerateSolanaEntry(t, 2, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	_, err = c.GetTransaction(context.Background(), otherID)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	require.NoError(t, c.Write(context.Background(), other))
+	actual, err = c.GetTransaction(context.Background(), otherID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(other, actual))
+}
+
+func TestCache_NotFoundDisabled(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, time.Minute, WithNotFoundTTL(0))
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	_, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTransaction(context.Background(), txID)
+		assert.Equal(t, history.ErrNotFound, err)
+	}
+	assert.Equal(t, 3, rw.count())
+}
+
+func TestCache_Expiry(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	require.NoError(t, rw.Write(context.Background(), e))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTransaction(context.Background(), txID)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, rw.count())
+
+	// Entries rewritten by another process are visible once the cached
+	// entry expires.
+	repaired := proto.Clone(e).(*model.Entry)
+	repaired.GetSolana().BlockTime = ptypes.TimestampNow()
+	require.NoError(t, rw.Repair(context.Background(), repaired))
+
+	time.Sleep(150 * time.Millisecond)
+	actual, err := c.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(repaired, actual))
+	assert.Equal(t, 2, rw.count())
+}
+
+// overwritingRW overwrites existing entries on Write(), as a repair would.
+type overwritingRW struct {
+	*countingRW
+}
+
+func (rw *overwritingRW) Write(ctx context.Context, e *model.Entry) error {
+	return rw.Repair(ctx, e)
+}
+
+func TestCache_ConcurrentWrite(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(&overwritingRW{rw}, 10, time.Minute)
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	require.NoError(t, rw.Write(context.Background(), e))
+
+	rw.Lock()
+	rw.read, rw.release = make(chan struct{}), make(chan struct{})
+	read, release := rw.read, rw.release
+	rw.Unlock()
+
+	// The read observes the store prior to the write, but completes after it.
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.GetTransaction(context.Background(), txID)
+		errCh <- err
+	}()
+
+	<-read
+	updated := proto.Clone(e).(*model.Entry)
+	updated.GetSolana().BlockTime = ptypes.TimestampNow()
+	require.NoError(t, c.Write(context.Background(), updated))
+	close(release)
+	require.NoError(t, <-errCh)
+
+	// The stale result should not have been cached.
+	actual, err := c.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(updated, actual))
+}
+
+func TestCache_Prune(t *testing.T) {
+	rw := &countingRW{RW: historymemory.New()}
+	c, err := New(rw, 10, time.Minute)
+	require.NoError(t, err)
+
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 1, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	require.NoError(t, c.Write(context.Background(), e))
+
+	_, err = c.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+
+	require.NoError(t, c.(history.Pruner).Prune(context.Background(), e))
+	_, err = c.GetTransaction(context.Background(), txID)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	// Repairs through the cache are visible immediately.
+	require.NoError(t, rw.Write(context.Background(), e))
+	_, err = c.GetTransaction(context.Background(), txID)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	repaired := proto.Clone(e).(*model.Entry)
+	repaired.GetSolana().BlockTime = ptypes.TimestampNow()
+	require.NoError(t, c.(history.Repairer).Repair(context.Background(), repaired))
+	actual, err := c.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(repaired, actual))
+
+	// Stores that do not implement the optional interfaces are reported as such.
+	c, err = New(struct{ history.ReaderWriter }{rw}, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, history.ErrNotSupported, c.(history.Pruner).Prune(context.Background(), e))
+	assert.Equal(t, history.ErrNotSupported, c.(history.Repairer).Repair(context.Background(), e))
+	_, _, err = c.(history.TimeIndexer).GetUnindexed(context.Background(), nil, 10)
+	assert.Equal(t, history.ErrNotSupported, err)
+}
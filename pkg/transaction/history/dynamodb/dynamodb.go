@@ -144,11 +144,19 @@ func (db *db) GetTransactions(ctx context.Context, fromBlock, maxBlock uint64, l
 
 // GetAccountTransactions implements history.Reader.GetGetAccountTransactions.
 func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	return history.GetFilteredAccountTransactions(ctx, account, opts, db.getAccountTransactions)
+}
+
+func (db *db) getAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
 	limit := opts.GetLimit()
 	if limit <= 0 {
 		limit = 100
 	}
 
+	if opts.HasTimeRange() {
+		return db.getAccountTransactionsByTime(ctx, account, opts, limit)
+	}
+
 	var condition *string
 	if opts.GetDescending() {
 		condition = getAccountTransactionsDescQueryStr
@@ -156,7 +164,7 @@ func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *
 		condition = getAccountTransactionsAscQueryStr
 	}
 
-	pager := dynamodb.NewQueryPaginator(db.client.QueryRequest(&dynamodb.QueryInput{
+	return db.queryAccountTransactions(ctx, &dynamodb.QueryInput{
 		TableName:              txByAccountTableStr,
 		KeyConditionExpression: condition,
 		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
@@ -165,7 +173,74 @@ func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *
 		},
 		Limit:            aws.Int64(int64(limit)),
 		ScanIndexForward: aws.Bool(!opts.GetDescending()),
-	}))
+	}, opts, limit)
+}
+
+// getAccountTransactionsByTime queries the account's history within the time
+// range of opts from the account-time index.
+//
+// The cursor (opts.Start) is resumed from its position in the index, which
+// requires its block time. If the cursor has no block time (or does not
+// exist), the query starts from the start (or end) of the time range, and
+// items before (or after) the cursor are filtered out.
+func (db *db) getAccountTransactionsByTime(ctx context.Context, account string, opts *history.ReadOptions, limit int) ([]*model.Entry, error) {
+	// Block times are stored with second precision, so the bounds are
+	// rounded up to the next second, with the end bound being exclusive.
+	from := getTimeKey(ceilUnix(opts.GetStartTime()), nil)
+	to := getTimeKey(math.MaxInt64, nil)
+	if t := opts.GetEndTime(); !t.IsZero() {
+		to = getTimeKey(ceilUnix(t), nil)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              txByAccountTableStr,
+		IndexName:              accountTimeIndexStr,
+		KeyConditionExpression: getAccountTimeRangeQueryStr,
+		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+			":account": {S: aws.String(account)},
+		},
+		Limit:            aws.Int64(int64(limit)),
+		ScanIndexForward: aws.Bool(!opts.GetDescending()),
+	}
+
+	if len(opts.Start) > 0 {
+		resp, err := db.client.GetItemRequest(&dynamodb.GetItemInput{
+			TableName: txByAccountTableStr,
+			Key: map[string]dynamodb.AttributeValue{
+				accountKey:      {S: aws.String(account)},
+				orderingSortKey: {B: opts.Start},
+			},
+		}).Send(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get cursor entry")
+		}
+
+		if cursor, ok := resp.Item[timeSortKey]; ok {
+			if opts.GetDescending() && bytes.Compare(cursor.B, to) < 0 {
+				to = cursor.B
+			} else if !opts.GetDescending() && bytes.Compare(cursor.B, from) > 0 {
+				from = cursor.B
+			}
+		}
+
+		input.FilterExpression = aws.String(startFilterAsc)
+		if opts.GetDescending() {
+			input.FilterExpression = aws.String(startFilterDesc)
+		}
+		input.ExpressionAttributeValues[":start"] = dynamodb.AttributeValue{B: opts.Start}
+	}
+
+	if bytes.Compare(from, to) > 0 {
+		return nil, nil
+	}
+	input.ExpressionAttributeValues[":from"] = dynamodb.AttributeValue{B: from}
+	input.ExpressionAttributeValues[":to"] = dynamodb.AttributeValue{B: to}
+
+	return db.queryAccountTransactions(ctx, input, opts, limit)
+}
+
+func (db *db) queryAccountTransactions(ctx context.Context, input *dynamodb.QueryInput, opts *history.ReadOptions, limit int) ([]*model.Entry, error) {
+	pager := dynamodb.NewQueryPaginator(db.client.QueryRequest(input))
 
 	var entries []*model.Entry
 	for pager.Next(ctx) {
@@ -175,6 +250,14 @@ func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *
 				return nil, errors.Wrap(err, "invalid entry")
 			}
 
+			// The index has second precision, so we re-check the range
+			// against the entry itself.
+			if inRange, err := opts.InTimeRange(e); err != nil {
+				return nil, errors.Wrap(err, "failed to apply time range")
+			} else if !inRange {
+				continue
+			}
+
 			entries = append(entries, e)
 
 			// query limit applies per request; we also need to limit
@@ -191,6 +274,55 @@ func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *
 	return entries, nil
 }
 
+// GetUnindexed implements history.TimeIndexer.GetUnindexed.
+//
+// The account index is scanned for items without a time_key, so each call
+// reads (at most) limit items, regardless of how many are returned.
+func (db *db) GetUnindexed(ctx context.Context, cursor []byte, limit int) ([]*model.Entry, []byte, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:        txByAccountTableStr,
+		FilterExpression: unindexedFilterStr,
+		Limit:            aws.Int64(int64(limit)),
+	}
+	if cursor != nil {
+		account, orderingKey, err := history.DecodeAccountCursor(cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		input.ExclusiveStartKey = map[string]dynamodb.AttributeValue{
+			accountKey:      {S: aws.String(account)},
+			orderingSortKey: {B: orderingKey},
+		}
+	}
+
+	resp, err := db.client.ScanRequest(input).Send(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to scan account history")
+	}
+
+	entries := make([]*model.Entry, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		e, err := getEntry(item)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid entry")
+		}
+
+		entries = append(entries, e)
+	}
+
+	if len(resp.LastEvaluatedKey) == 0 {
+		return entries, nil, nil
+	}
+
+	account, orderingKey := resp.LastEvaluatedKey[accountKey], resp.LastEvaluatedKey[orderingSortKey]
+	return entries, history.EncodeAccountCursor(aws.StringValue(account.S), orderingKey.B), nil
+}
+
 // GetLatestForAccount implements history.Reader.GetLatestForAccount.
 func (db *db) GetLatestForAccount(ctx context.Context, account string) (*model.Entry, error) {
 	resp, err := db.client.QueryRequest(&dynamodb.QueryInput{
@@ -215,6 +347,62 @@ func (db *db) GetLatestForAccount(ctx context.Context, account string) (*model.E
 
 // Write implements history.Writer.Write.
 func (db *db) Write(ctx context.Context, entry *model.Entry) error {
+	return db.write(ctx, entry, false)
+}
+
+// Repair implements history.Repairer.Repair.
+func (db *db) Repair(ctx context.Context, entry *model.Entry) error {
+	if entry == nil {
+		return errors.New("missing entry")
+	}
+
+	txHash, err := entry.GetTxID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get tx hash")
+	}
+
+	orderingKey, err := entry.GetOrderingKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to get order key")
+	}
+
+	// If the existing entry is keyed differently, its account and history
+	// items would not be replaced by the write, so they are removed first.
+	previous, err := db.GetTransaction(ctx, txHash)
+	if err != nil && err != history.ErrNotFound {
+		return errors.Wrap(err, "failed to get existing entry")
+	} else if err == nil {
+		previousKey, err := previous.GetOrderingKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing order key")
+		}
+
+		if !bytes.Equal(previousKey, orderingKey) {
+			if err := db.deleteAccountItems(ctx, previous, previousKey); err != nil {
+				return err
+			}
+
+			if sol := previous.GetSolana(); sol != nil && sol.Confirmed {
+				_, err := db.client.DeleteItemRequest(&dynamodb.DeleteItemInput{
+					TableName: txHistoryTableStr,
+					Key: map[string]dynamodb.AttributeValue{
+						historyKey:     {N: aws.String(strconv.FormatUint((sol.Slot/blockPartitionSize)*blockPartitionSize, 10))},
+						historySortKey: {B: previousKey},
+					},
+				}).Send(ctx)
+				if err != nil {
+					return errors.Wrap(err, "failed to delete tx history entry")
+				}
+			}
+		}
+	}
+
+	return db.write(ctx, entry, true)
+}
+
+// write writes the entry to each table. Unless overwrite is set, an existing
+// tx-by-hash entry is not replaced, and must be compatible with the entry.
+func (db *db) write(ctx context.Context, entry *model.Entry, overwrite bool) error {
 	if entry == nil {
 		return errors.New("missing entry")
 	}
@@ -239,14 +427,18 @@ func (db *db) Write(ctx context.Context, entry *model.Entry) error {
 		return errors.Wrap(err, "failed to marshal entry")
 	}
 
-	_, err = db.client.PutItemRequest(&dynamodb.PutItemInput{
-		TableName:           txTableStr,
-		ConditionExpression: writeTxConditionExpressionStr,
+	input := &dynamodb.PutItemInput{
+		TableName: txTableStr,
 		Item: map[string]dynamodb.AttributeValue{
 			txHashKey: {B: txHash},
 			entryAttr: {B: entryBytes},
 		},
-	}).Send(ctx)
+	}
+	if !overwrite {
+		input.ConditionExpression = writeTxConditionExpressionStr
+	}
+
+	_, err = db.client.PutItemRequest(input).Send(ctx)
 	if dynamodbutil.IsConditionalCheckFailed(err) {
 		if err := db.checkDoubleInsertMatch(ctx, txHash, entry); err != nil {
 			return err
@@ -279,15 +471,26 @@ func (db *db) Write(ctx context.Context, entry *model.Entry) error {
 		}
 	}
 
+	blockTime, err := entry.GetBlockTime()
+	if err != nil {
+		return errors.Wrap(err, "failed to get block time")
+	}
+
 	writes := make([]dynamodb.WriteRequest, len(accounts))
 	for i := range accounts {
+		item := map[string]dynamodb.AttributeValue{
+			accountKey:      {S: aws.String(accounts[i])},
+			orderingSortKey: {B: orderingKey},
+			entryAttr:       {B: entryBytes},
+		}
+		if !blockTime.IsZero() {
+			item[blockTimeAttr] = dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(blockTime.Unix(), 10))}
+			item[timeSortKey] = dynamodb.AttributeValue{B: getTimeKey(blockTime.Unix(), orderingKey)}
+		}
+
 		writes[i] = dynamodb.WriteRequest{
 			PutRequest: &dynamodb.PutRequest{
-				Item: map[string]dynamodb.AttributeValue{
-					accountKey:      {S: aws.String(accounts[i])},
-					orderingSortKey: {B: orderingKey},
-					entryAttr:       {B: entryBytes},
-				},
+				Item: item,
 			},
 		}
 	}
@@ -308,6 +511,106 @@ func (db *db) Write(ctx context.Context, entry *model.Entry) error {
 	return nil
 }
 
+// Delete implements history.Deleter.Delete.
+func (db *db) Delete(ctx context.Context, entry *model.Entry) error {
+	if entry == nil {
+		return errors.New("missing entry")
+	}
+
+	txHash, err := entry.GetTxID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get tx hash")
+	}
+
+	orderingKey, err := entry.GetOrderingKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to get order key")
+	}
+
+	// The account entries are removed first, so that a partial failure
+	// leaves the entry discoverable by hash for a retry.
+	if err := db.deleteAccountItems(ctx, entry, orderingKey); err != nil {
+		return err
+	}
+
+	_, err = db.client.DeleteItemRequest(&dynamodb.DeleteItemInput{
+		TableName: txTableStr,
+		Key: map[string]dynamodb.AttributeValue{
+			txHashKey: {B: txHash},
+		},
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete tx entry")
+	}
+
+	return nil
+}
+
+// Prune implements history.Pruner.Prune.
+func (db *db) Prune(ctx context.Context, entry *model.Entry) error {
+	if entry == nil {
+		return errors.New("missing entry")
+	}
+
+	// The history item is removed first, so that a partial failure leaves
+	// the entry discoverable by hash for a retry.
+	if sol := entry.GetSolana(); sol != nil && sol.Confirmed {
+		orderingKey, err := entry.GetOrderingKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to get order key")
+		}
+
+		_, err = db.client.DeleteItemRequest(&dynamodb.DeleteItemInput{
+			TableName: txHistoryTableStr,
+			Key: map[string]dynamodb.AttributeValue{
+				historyKey:     {N: aws.String(strconv.FormatUint((sol.Slot/blockPartitionSize)*blockPartitionSize, 10))},
+				historySortKey: {B: orderingKey},
+			},
+		}).Send(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete tx history entry")
+		}
+	}
+
+	return db.Delete(ctx, entry)
+}
+
+// deleteAccountItems removes the entry's items (keyed by orderingKey) from
+// the account index.
+func (db *db) deleteAccountItems(ctx context.Context, entry *model.Entry, orderingKey []byte) error {
+	accounts, err := entry.GetAccounts()
+	if err != nil {
+		return errors.Wrap(err, "failed to get related accounts")
+	}
+
+	deletes := make([]dynamodb.WriteRequest, len(accounts))
+	for i := range accounts {
+		deletes[i] = dynamodb.WriteRequest{
+			DeleteRequest: &dynamodb.DeleteRequest{
+				Key: map[string]dynamodb.AttributeValue{
+					accountKey:      {S: aws.String(accounts[i])},
+					orderingSortKey: {B: orderingKey},
+				},
+			},
+		}
+	}
+
+	for start := 0; start < len(deletes); start += 25 {
+		end := int(math.Min(float64(start+25), float64(len(deletes))))
+
+		_, err := db.client.BatchWriteItemRequest(&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]dynamodb.WriteRequest{
+				txByAccountTable: deletes[start:end],
+			},
+		}).Send(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete batch of account txns")
+		}
+	}
+
+	return nil
+}
+
 func (db *db) checkDoubleInsertMatch(ctx context.Context, txHash []byte, entry *model.Entry) error {
 	var item map[string]dynamodb.AttributeValue
 
@@ -141,12 +141,34 @@ func setupTestTable(client dynamodbiface.ClientAPI) error {
 			AttributeName: aws.String(orderingSortKey),
 			AttributeType: dynamodb.ScalarAttributeTypeB,
 		},
+		{
+			AttributeName: aws.String(timeSortKey),
+			AttributeType: dynamodb.ScalarAttributeTypeB,
+		},
 	}
 	_, err = client.CreateTableRequest(&dynamodb.CreateTableInput{
 		KeySchema:            keySchema,
 		AttributeDefinitions: attrDefinitions,
 		BillingMode:          dynamodb.BillingModePayPerRequest,
 		TableName:            txByAccountTableStr,
+		GlobalSecondaryIndexes: []dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: accountTimeIndexStr,
+				KeySchema: []dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String(accountKey),
+						KeyType:       dynamodb.KeyTypeHash,
+					},
+					{
+						AttributeName: aws.String(timeSortKey),
+						KeyType:       dynamodb.KeyTypeRange,
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: dynamodb.ProjectionTypeAll,
+				},
+			},
+		},
 	}).Send(context.Background())
 	return err
 }
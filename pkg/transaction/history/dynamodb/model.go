@@ -1,6 +1,9 @@
 package dynamodb
 
 import (
+	"encoding/binary"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/golang/protobuf/proto"
@@ -14,19 +17,33 @@ const (
 	txByAccountTable = "tx-by-account"
 	txHistoryTable   = "tx-history"
 
+	// accountTimeIndex is a global secondary index on tx-by-account, keyed
+	// on (account, time_key). time_key is the big endian block time (in unix
+	// seconds), followed by the ordering key, and is only set for entries
+	// with a block time.
+	accountTimeIndex = "account-time"
+
 	txHashKey       = "tx_hash"
 	accountKey      = "account"
 	orderingSortKey = "ordering_key"
+	timeSortKey     = "time_key"
 	historyKey      = "block_start"
 	historySortKey  = "ordering_key"
 
-	entryAttr = "entry"
+	entryAttr     = "entry"
+	blockTimeAttr = "block_time"
 
 	writeTxConditionExpression      = "attribute_not_exists(tx_hash)"
 	getAccountLatestQuery           = "account = :account"
 	getAccountTransactionsAscQuery  = "account = :account and ordering_key >= :start"
 	getAccountTransactionsDescQuery = "account = :account and ordering_key <= :start"
+	getAccountTimeRangeQuery        = "account = :account and time_key between :from and :to"
 	getTransactionHistoryQuery      = "block_start = :block_start and ordering_key between :from and :max"
+
+	startFilterAsc   = "ordering_key >= :start"
+	startFilterDesc  = "ordering_key <= :start"
+	unindexedFilter  = "attribute_not_exists(time_key)"
+	timeKeyPrefixLen = 8
 )
 
 var (
@@ -37,6 +54,9 @@ var (
 	getAccountLatestQueryStr           = aws.String(getAccountLatestQuery)
 	getAccountTransactionsAscQueryStr  = aws.String(getAccountTransactionsAscQuery)
 	getAccountTransactionsDescQueryStr = aws.String(getAccountTransactionsDescQuery)
+	getAccountTimeRangeQueryStr        = aws.String(getAccountTimeRangeQuery)
+	accountTimeIndexStr                = aws.String(accountTimeIndex)
+	unindexedFilterStr                 = aws.String(unindexedFilter)
 	getTransactionHistoryStr           = aws.String(getTransactionHistoryQuery)
 )
 
@@ -53,3 +73,28 @@ func getEntry(item map[string]dynamodb.AttributeValue) (*model.Entry, error) {
 
 	return entry, nil
 }
+
+// getTimeKey returns the time_key for an entry with the specified block time
+// (in unix seconds) and ordering key.
+//
+// An empty ordering key yields a prefix, which sorts before every time_key
+// with the same block time.
+func getTimeKey(blockTime int64, orderingKey []byte) []byte {
+	key := make([]byte, timeKeyPrefixLen, timeKeyPrefixLen+len(orderingKey))
+	binary.BigEndian.PutUint64(key, uint64(blockTime))
+	return append(key, orderingKey...)
+}
+
+// ceilUnix returns t in unix seconds, rounded up. The zero time is returned
+// as 0.
+func ceilUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	s := t.Unix()
+	if t.Nanosecond() > 0 {
+		s++
+	}
+	return s
+}
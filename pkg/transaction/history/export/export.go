@@ -0,0 +1,316 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	commonpb "github.com/kinecosystem/agora-api/genproto/common/v3"
+
+	"github.com/kinecosystem/agora/pkg/invoice"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const defaultPageSize = 1000
+
+// Checkpoint records the progress of an export, so that it may be resumed.
+type Checkpoint struct {
+	// LastKey is the ordering key of the last exported entry in a block
+	// range export.
+	LastKey []byte `json:"last_key,omitempty"`
+
+	// Accounts contains the ordering key of the last exported entry for
+	// each account in an account export.
+	Accounts map[string][]byte `json:"accounts,omitempty"`
+
+	// Completed contains the accounts that have been fully exported.
+	Completed map[string]bool `json:"completed,omitempty"`
+
+	// Output contains writer specific state (for example, the offset of
+	// the output file) that should be restored alongside the checkpoint.
+	Output json.RawMessage `json:"output,omitempty"`
+}
+
+// LoadCheckpoint loads a checkpoint from the specified path.
+//
+// If no checkpoint exists, an empty checkpoint is returned.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Checkpoint{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read checkpoint")
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal checkpoint")
+	}
+
+	return cp, nil
+}
+
+// SaveCheckpoint atomically saves the checkpoint to the specified path.
+func SaveCheckpoint(path string, cp *Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary checkpoint")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write checkpoint")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to sync checkpoint")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close checkpoint")
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Exporter exports decoded history entries.
+type Exporter struct {
+	log      *logrus.Entry
+	reader   history.Reader
+	invoices invoice.Store
+	writer   Writer
+	pageSize int
+
+	onCheckpoint func(*Checkpoint) error
+}
+
+// NewExporter returns a new Exporter that writes to w.
+//
+// invoices may be nil, in which case invoices are not exported. After
+// each page of entries is written, w is flushed and onCheckpoint (if
+// provided) is called with the updated checkpoint. If w is a BufferedWriter,
+// this only occurs once w is full, and when the export completes.
+func NewExporter(reader history.Reader, invoices invoice.Store, w Writer, onCheckpoint func(*Checkpoint) error) *Exporter {
+	return &Exporter{
+		log:          logrus.StandardLogger().WithField("type", "history/export"),
+		reader:       reader,
+		invoices:     invoices,
+		writer:       w,
+		pageSize:     defaultPageSize,
+		onCheckpoint: onCheckpoint,
+	}
+}
+
+// SetPageSize sets the number of entries loaded (and checkpointed) at a time.
+func (e *Exporter) SetPageSize(size int) {
+	if size > 0 {
+		e.pageSize = size
+	}
+}
+
+// ExportBlocks exports the (committed) entries in the block range [from, to],
+// resuming from cp.
+//
+// Only entries in the global history (i.e. Solana entries) are available
+// by block.
+func (e *Exporter) ExportBlocks(ctx context.Context, from, to uint64, cp *Checkpoint) error {
+	start := from
+	if len(cp.LastKey) > 0 {
+		block, err := model.BlockFromOrderingKey(cp.LastKey)
+		if err != nil {
+			return errors.Wrap(err, "invalid checkpoint")
+		}
+		start = block
+	}
+
+	limit := e.pageSize
+	for start <= to {
+		entries, err := e.reader.GetTransactions(ctx, start, to, limit)
+		if err != nil {
+			return errors.Wrap(err, "failed to get transactions")
+		}
+
+		// Since pages start at a block (rather than an entry), the start
+		// of each page may contain entries we've already exported.
+		fresh, err := after(entries, cp.LastKey)
+		if err != nil {
+			return err
+		}
+
+		if len(fresh) == 0 {
+			if len(entries) < limit {
+				break
+			}
+
+			// The block contains more entries than the page size, so we
+			// need a larger page to make progress.
+			limit *= 2
+			continue
+		}
+
+		if cp.LastKey, err = e.export(ctx, fresh); err != nil {
+			return err
+		}
+		if err := e.checkpoint(cp); err != nil {
+			return err
+		}
+
+		e.log.WithField("block", fresh[len(fresh)-1].GetSolana().GetSlot()).Debug("exported page")
+
+		if len(entries) < limit {
+			break
+		}
+
+		if start, err = model.BlockFromOrderingKey(cp.LastKey); err != nil {
+			return errors.Wrap(err, "invalid ordering key")
+		}
+		limit = e.pageSize
+	}
+
+	return e.flush(cp)
+}
+
+// ExportAccounts exports the entries for each of the accounts (in order),
+// resuming from cp.
+//
+// Accounts are specified in their strkey encoded form.
+func (e *Exporter) ExportAccounts(ctx context.Context, accounts []string, cp *Checkpoint) error {
+	if cp.Accounts == nil {
+		cp.Accounts = make(map[string][]byte)
+	}
+	if cp.Completed == nil {
+		cp.Completed = make(map[string]bool)
+	}
+
+	for _, account := range accounts {
+		if cp.Completed[account] {
+			continue
+		}
+
+		for {
+			lastKey := cp.Accounts[account]
+			entries, err := e.reader.GetAccountTransactions(ctx, account, &history.ReadOptions{
+				Start: lastKey,
+				Limit: e.pageSize,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get transactions for %s", account)
+			}
+
+			// Start is inclusive, so we must drop the previous last entry.
+			fresh, err := after(entries, lastKey)
+			if err != nil {
+				return err
+			}
+
+			if len(fresh) > 0 {
+				if cp.Accounts[account], err = e.export(ctx, fresh); err != nil {
+					return err
+				}
+			}
+
+			if len(entries) < e.pageSize {
+				delete(cp.Accounts, account)
+				cp.Completed[account] = true
+			}
+
+			if err := e.checkpoint(cp); err != nil {
+				return err
+			}
+
+			if cp.Completed[account] {
+				break
+			}
+		}
+
+		e.log.WithField("account", account).Debug("exported account")
+	}
+
+	return e.flush(cp)
+}
+
+// export writes the entries, and returns the ordering key of the last entry.
+func (e *Exporter) export(ctx context.Context, entries []*model.Entry) ([]byte, error) {
+	for _, entry := range entries {
+		txID, err := entry.GetTxID()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get tx id")
+		}
+
+		var il *commonpb.InvoiceList
+		if e.invoices != nil {
+			l, err := e.invoices.Get(ctx, txID)
+			if err != nil && err != invoice.ErrNotFound {
+				return nil, errors.Wrap(err, "failed to get invoice list")
+			}
+			il = l
+		}
+
+		rows, err := RowsFromEntry(entry, il)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode entry")
+		}
+
+		for _, r := range rows {
+			if err := e.writer.Write(r); err != nil {
+				return nil, errors.Wrap(err, "failed to write row")
+			}
+		}
+	}
+
+	return entries[len(entries)-1].GetOrderingKey()
+}
+
+// checkpoint flushes the writer and saves the checkpoint, unless the writer
+// is still buffering rows.
+func (e *Exporter) checkpoint(cp *Checkpoint) error {
+	if b, ok := e.writer.(BufferedWriter); ok && !b.Full() {
+		return nil
+	}
+
+	return e.flush(cp)
+}
+
+func (e *Exporter) flush(cp *Checkpoint) error {
+	if err := e.writer.Flush(); err != nil {
+		return errors.Wrap(err, "failed to flush writer")
+	}
+
+	if e.onCheckpoint == nil {
+		return nil
+	}
+
+	return e.onCheckpoint(cp)
+}
+
+// after returns the entries whose ordering key is after lastKey.
+func after(entries []*model.Entry, lastKey []byte) ([]*model.Entry, error) {
+	if len(lastKey) == 0 {
+		return entries, nil
+	}
+
+	for i, entry := range entries {
+		key, err := entry.GetOrderingKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ordering key")
+		}
+
+		if bytes.Compare(key, lastKey) > 0 {
+			return entries[i:], nil
+		}
+	}
+
+	return nil, nil
+}
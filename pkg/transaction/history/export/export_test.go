@@ -0,0 +1,346 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/kinecosystem/go/strkey"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	commonpb "github.com/kinecosystem/agora-api/genproto/common/v3"
+
+	"github.com/kinecosystem/agora/pkg/invoice"
+	invoicememory "github.com/kinecosystem/agora/pkg/invoice/memory"
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+type testEnv struct {
+	rw       history.ReaderWriter
+	invoices invoice.Store
+
+	// senders contains the sender of each entry, keyed by tx id.
+	senders   map[string]ed25519.PublicKey
+	receivers []ed25519.PublicKey
+	entries   []*model.Entry
+}
+
+func setup(t *testing.T) (env testEnv) {
+	env.rw = historymemory.New()
+	env.invoices = invoicememory.New()
+	env.senders = make(map[string]ed25519.PublicKey)
+	env.receivers = testutil.GenerateSolanaKeys(t, 2)
+
+	// Multiple entries per block ensures that we correctly resume pages
+	// that start partway through a block.
+	for i := 0; i < 20; i++ {
+		var invoiceHash []byte
+		if i == 0 {
+			invoiceHash = make([]byte, 28)
+		}
+
+		// Each entry uses a distinct sender, otherwise entries within the
+		// same block would be identical.
+		sender := testutil.GenerateSolanaKeypair(t)
+		e, txID := historytestutil.GenerateSolanaEntry(t, uint64(1+i/3), true, sender, env.receivers, invoiceHash, nil)
+		require.NoError(t, env.rw.Write(context.Background(), e))
+		env.entries = append(env.entries, e)
+		env.senders[string(txID)] = sender.Public().(ed25519.PublicKey)
+
+		if i == 0 {
+			require.NoError(t, env.invoices.Put(context.Background(), txID, &commonpb.InvoiceList{
+				Invoices: []*commonpb.Invoice{
+					{Items: []*commonpb.Invoice_LineItem{{Title: "first", Amount: 1}}},
+					{Items: []*commonpb.Invoice_LineItem{{Title: "second", Amount: 2}}},
+				},
+			}))
+		}
+	}
+
+	// GetTransactions() orders by ordering key, which may differ from
+	// insertion order within a block.
+	sort.Sort(model.SortableEntries(env.entries))
+
+	return env
+}
+
+type memWriter struct {
+	rows    []*Row
+	flushed int
+}
+
+func (w *memWriter) Write(r *Row) error {
+	w.rows = append(w.rows, r)
+	return nil
+}
+
+func (w *memWriter) Flush() error {
+	w.flushed = len(w.rows)
+	return nil
+}
+
+func (w *memWriter) Close() error {
+	return w.Flush()
+}
+
+func TestExportBlocks(t *testing.T) {
+	env := setup(t)
+
+	w := &memWriter{}
+	e := NewExporter(env.rw, env.invoices, w, nil)
+	e.pageSize = 4
+
+	require.NoError(t, e.ExportBlocks(context.Background(), 0, 100, &Checkpoint{}))
+	assertRows(t, env, env.entries, w.rows)
+	assert.Equal(t, len(w.rows), w.flushed)
+
+	// Range restrictions
+	w = &memWriter{}
+	e = NewExporter(env.rw, nil, w, nil)
+	e.pageSize = 4
+	require.NoError(t, e.ExportBlocks(context.Background(), 2, 3, &Checkpoint{}))
+	assert.Len(t, w.rows, 2*6)
+	for _, r := range w.rows {
+		assert.True(t, r.Block >= 2 && r.Block <= 3)
+		assert.Empty(t, r.Invoice)
+	}
+
+	// Pages smaller than the number of entries in a block.
+	w = &memWriter{}
+	e = NewExporter(env.rw, env.invoices, w, nil)
+	e.pageSize = 1
+	require.NoError(t, e.ExportBlocks(context.Background(), 0, 100, &Checkpoint{}))
+	assertRows(t, env, env.entries, w.rows)
+}
+
+func TestExportBlocks_Resume(t *testing.T) {
+	env := setup(t)
+
+	// Fail on the third checkpoint, simulating a crash. Since the
+	// checkpoint is never persisted, the third page is not recorded.
+	var saved *Checkpoint
+	var checkpoints int
+	onCheckpoint := func(cp *Checkpoint) error {
+		checkpoints++
+		if checkpoints == 3 {
+			return errors.New("crash")
+		}
+
+		b, err := json.Marshal(cp)
+		require.NoError(t, err)
+		saved = &Checkpoint{}
+		require.NoError(t, json.Unmarshal(b, saved))
+		return nil
+	}
+
+	w := &memWriter{}
+	e := NewExporter(env.rw, env.invoices, w, onCheckpoint)
+	e.pageSize = 4
+	require.Error(t, e.ExportBlocks(context.Background(), 0, 100, &Checkpoint{}))
+	require.NotNil(t, saved)
+
+	// Drop the rows that were written after the last successful checkpoint,
+	// as the CLI would by truncating the output.
+	var checkpointed int
+	for i, entry := range env.entries {
+		if bytes.Equal(historytestutil.GetOrderingKey(t, entry), saved.LastKey) {
+			checkpointed = i + 1
+		}
+	}
+	require.True(t, checkpointed > 0)
+	require.True(t, len(w.rows) > 2*checkpointed)
+	rows := w.rows[:2*checkpointed]
+
+	w = &memWriter{}
+	e = NewExporter(env.rw, env.invoices, w, onCheckpoint)
+	e.pageSize = 4
+	require.NoError(t, e.ExportBlocks(context.Background(), 0, 100, saved))
+
+	assertRows(t, env, env.entries, append(rows, w.rows...))
+}
+
+func TestExportAccounts(t *testing.T) {
+	env := setup(t)
+
+	first := strkey.MustEncode(strkey.VersionByteAccountID, env.receivers[0])
+	second := strkey.MustEncode(strkey.VersionByteAccountID, env.receivers[1])
+
+	w := &memWriter{}
+	e := NewExporter(env.rw, env.invoices, w, nil)
+	e.pageSize = 3
+
+	cp := &Checkpoint{}
+	require.NoError(t, e.ExportAccounts(context.Background(), []string{first, second}, cp))
+	assert.Len(t, w.rows, 2*2*len(env.entries))
+	assertRows(t, env, env.entries, w.rows[:2*len(env.entries)])
+	assertRows(t, env, env.entries, w.rows[2*len(env.entries):])
+	assert.True(t, cp.Completed[first])
+	assert.True(t, cp.Completed[second])
+	assert.Empty(t, cp.Accounts)
+
+	// Completed accounts should be skipped when resuming.
+	w = &memWriter{}
+	e = NewExporter(env.rw, env.invoices, w, nil)
+	require.NoError(t, e.ExportAccounts(context.Background(), []string{first, second}, cp))
+	assert.Empty(t, w.rows)
+
+	// Partially exported accounts should resume after the last entry.
+	cp = &Checkpoint{
+		Accounts: map[string][]byte{
+			first: historytestutil.GetOrderingKey(t, env.entries[9]),
+		},
+	}
+	w = &memWriter{}
+	e = NewExporter(env.rw, env.invoices, w, nil)
+	e.pageSize = 3
+	require.NoError(t, e.ExportAccounts(context.Background(), []string{first}, cp))
+	assertRows(t, env, env.entries[10:], w.rows)
+}
+
+func TestWriters(t *testing.T) {
+	env := setup(t)
+
+	var jsonl, csvBuf bytes.Buffer
+	for _, w := range []Writer{
+		NewJSONLWriter(&jsonl),
+		NewCSVWriter(&csvBuf, true),
+	} {
+		e := NewExporter(env.rw, env.invoices, w, nil)
+		e.pageSize = 10
+		require.NoError(t, e.ExportBlocks(context.Background(), 0, 100, &Checkpoint{}))
+		require.NoError(t, w.Close())
+	}
+
+	var rows []*Row
+	dec := json.NewDecoder(&jsonl)
+	for dec.More() {
+		r := &Row{}
+		require.NoError(t, dec.Decode(r))
+		rows = append(rows, r)
+	}
+	assertRows(t, env, env.entries, rows)
+
+	records, err := csv.NewReader(&csvBuf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(rows)+1)
+	assert.Equal(t, Columns, records[0])
+	for i, r := range rows {
+		assert.Equal(t, r.values(), records[i+1])
+	}
+
+	// Parquet rows are buffered into a part until the part is full, or the
+	// export completes. Since pages start at the block of the last exported
+	// entry, 20 entries over 7 blocks span 3 pages, and so at most 3 parts.
+	for _, tc := range []struct {
+		rowsPerPart int
+		parts       int
+	}{
+		{rowsPerPart: 1, parts: 3},
+		{rowsPerPart: len(rows) / 2, parts: 2},
+		{rowsPerPart: 1000, parts: 1},
+	} {
+		var parts []*bytes.Buffer
+		open := func(part int) (io.WriteCloser, error) {
+			require.Equal(t, len(parts), part)
+			parts = append(parts, &bytes.Buffer{})
+			return nopCloser{parts[part]}, nil
+		}
+
+		var checkpoints int
+		onCheckpoint := func(*Checkpoint) error {
+			checkpoints++
+			return nil
+		}
+
+		w := NewParquetWriter(open, 0, tc.rowsPerPart)
+		e := NewExporter(env.rw, env.invoices, w, onCheckpoint)
+		e.pageSize = 10
+		require.NoError(t, e.ExportBlocks(context.Background(), 0, 100, &Checkpoint{}))
+		require.NoError(t, w.Close())
+
+		// Checkpoints only occur once a part is complete (including the
+		// final, possibly empty, flush).
+		require.Equal(t, tc.parts, len(parts))
+		assert.True(t, checkpoints <= tc.parts+1)
+
+		var parquetRows []*Row
+		for _, p := range parts {
+			f, err := buffer.NewBufferFile(p.Bytes())
+			require.NoError(t, err)
+			pr, err := reader.NewParquetReader(f, new(Row), 1)
+			require.NoError(t, err)
+
+			partRows := make([]Row, pr.GetNumRows())
+			require.NoError(t, pr.Read(&partRows))
+			pr.ReadStop()
+
+			for i := range partRows {
+				parquetRows = append(parquetRows, &partRows[i])
+			}
+		}
+		assert.Equal(t, rows, parquetRows)
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func assertRows(t *testing.T, env testEnv, entries []*model.Entry, rows []*Row) {
+	require.Len(t, rows, 2*len(entries))
+
+	for i, e := range entries {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+
+		for p := 0; p < 2; p++ {
+			r := rows[2*i+p]
+			assert.Equal(t, base58.Encode(txID), r.TxID)
+			assert.EqualValues(t, model.KinVersion_KIN4, r.KinVersion)
+			assert.EqualValues(t, e.GetSolana().Slot, r.Block)
+			assert.True(t, r.Successful)
+			assert.Equal(t, base58.Encode(env.senders[string(txID)]), r.Source)
+			assert.Equal(t, base58.Encode(env.receivers[p]), r.Destination)
+			assert.EqualValues(t, e.GetSolana().Slot+uint64(p)+1, r.Quarks)
+
+			invoiceHash := e.GetSolana().Slot == 1 && r.Memo != ""
+			if invoiceHash {
+				assert.Equal(t, "spend", r.MemoType)
+				assert.EqualValues(t, 1, r.AppIndex)
+				assert.NotEmpty(t, r.Invoice)
+			} else {
+				assert.Empty(t, r.MemoType)
+				assert.Empty(t, r.Invoice)
+			}
+		}
+	}
+
+	// Ensure we've exported exactly one invoiced entry (with two invoices).
+	var invoiced int
+	for _, r := range rows {
+		if r.Invoice != "" {
+			invoiced++
+			// The memo is the first instruction, so payments start at index 1.
+			assert.Contains(t, r.Invoice, []string{"first", "second"}[r.PaymentIndex-1])
+		}
+	}
+	if len(entries) == len(env.entries) {
+		assert.Equal(t, 2, invoiced)
+	}
+}
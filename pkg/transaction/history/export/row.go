@@ -0,0 +1,296 @@
+package export
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/memo"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/stellar/go/xdr"
+
+	commonpb "github.com/kinecosystem/agora-api/genproto/common/v3"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// Row is a single exported payment.
+//
+// Transactions without any payments are exported as a single row with a
+// PaymentIndex of -1. Accounts are base58 encoded, and amounts are in quarks.
+type Row struct {
+	TxID       string `json:"tx_id" parquet:"name=tx_id, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	KinVersion int32  `json:"kin_version" parquet:"name=kin_version, type=INT32"`
+	Block      int64  `json:"block" parquet:"name=block, type=INT64"`
+	BlockTime  string `json:"block_time,omitempty" parquet:"name=block_time, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Successful bool   `json:"successful" parquet:"name=successful, type=BOOLEAN"`
+	Error      string `json:"error,omitempty" parquet:"name=error, type=UTF8, encoding=PLAIN_DICTIONARY"`
+
+	MemoText string `json:"memo_text,omitempty" parquet:"name=memo_text, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Memo     string `json:"memo,omitempty" parquet:"name=memo, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	MemoType string `json:"memo_type,omitempty" parquet:"name=memo_type, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	AppIndex int32  `json:"app_index,omitempty" parquet:"name=app_index, type=INT32"`
+
+	PaymentIndex int32  `json:"payment_index" parquet:"name=payment_index, type=INT32"`
+	Source       string `json:"source,omitempty" parquet:"name=source, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Destination  string `json:"destination,omitempty" parquet:"name=destination, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Quarks       int64  `json:"quarks" parquet:"name=quarks, type=INT64"`
+
+	// Invoice is the JSON encoded invoice for the payment, if any.
+	Invoice string `json:"invoice,omitempty" parquet:"name=invoice, type=UTF8, encoding=PLAIN_DICTIONARY"`
+}
+
+// Columns are the names of the Row fields, in order.
+var Columns = []string{
+	"tx_id",
+	"kin_version",
+	"block",
+	"block_time",
+	"successful",
+	"error",
+	"memo_text",
+	"memo",
+	"memo_type",
+	"app_index",
+	"payment_index",
+	"source",
+	"destination",
+	"quarks",
+	"invoice",
+}
+
+func (r *Row) values() []string {
+	return []string{
+		r.TxID,
+		strconv.Itoa(int(r.KinVersion)),
+		strconv.FormatInt(r.Block, 10),
+		r.BlockTime,
+		strconv.FormatBool(r.Successful),
+		r.Error,
+		r.MemoText,
+		r.Memo,
+		r.MemoType,
+		strconv.Itoa(int(r.AppIndex)),
+		strconv.Itoa(int(r.PaymentIndex)),
+		r.Source,
+		r.Destination,
+		strconv.FormatInt(r.Quarks, 10),
+		r.Invoice,
+	}
+}
+
+type memoInfo struct {
+	text     string
+	memo     string
+	memoType string
+	appIndex int32
+}
+
+func (m *memoInfo) apply(r *Row) {
+	if m == nil {
+		return
+	}
+
+	r.MemoText = m.text
+	r.Memo = m.memo
+	r.MemoType = m.memoType
+	r.AppIndex = m.appIndex
+}
+
+func memoFromBytes(raw []byte) *memoInfo {
+	var m kin.Memo
+	if len(raw) == len(m) {
+		copy(m[:], raw)
+		if kin.IsValidMemoStrict(m) {
+			return &memoInfo{
+				memo:     base64.StdEncoding.EncodeToString(raw),
+				memoType: memoTypeString(m.TransactionType()),
+				appIndex: int32(m.AppIndex()),
+			}
+		}
+	}
+
+	return &memoInfo{
+		memo: base64.StdEncoding.EncodeToString(raw),
+	}
+}
+
+func memoTypeString(t kin.TransactionType) string {
+	switch t {
+	case kin.TransactionTypeNone:
+		return "none"
+	case kin.TransactionTypeEarn:
+		return "earn"
+	case kin.TransactionTypeSpend:
+		return "spend"
+	case kin.TransactionTypeP2P:
+		return "p2p"
+	default:
+		return "unknown"
+	}
+}
+
+// RowsFromEntry converts an entry into its exported rows.
+//
+// The invoices in il (if any) are matched to payments by index.
+func RowsFromEntry(e *model.Entry, il *commonpb.InvoiceList) ([]*Row, error) {
+	txID, err := e.GetTxID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tx id")
+	}
+
+	base := Row{
+		KinVersion:   int32(e.Version),
+		PaymentIndex: -1,
+		Successful:   true,
+	}
+
+	var rows []*Row
+	switch k := e.Kind.(type) {
+	case *model.Entry_Stellar:
+		base.TxID = base58.Encode(txID)
+		base.Block = int64(k.Stellar.Ledger)
+		if k.Stellar.LedgerCloseTime != nil {
+			base.BlockTime = formatTime(ptypes.TimestampString(k.Stellar.LedgerCloseTime))
+		}
+
+		var result xdr.TransactionResult
+		if err := result.UnmarshalBinary(k.Stellar.ResultXdr); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal result")
+		}
+		if result.Result.Code != xdr.TransactionResultCodeTxSuccess {
+			base.Successful = false
+			base.Error = result.Result.Code.String()
+		}
+
+		var envelope xdr.TransactionEnvelope
+		if err := envelope.UnmarshalBinary(k.Stellar.EnvelopeXdr); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal envelope")
+		}
+
+		var m *memoInfo
+		switch envelope.Tx.Memo.Type {
+		case xdr.MemoTypeMemoText:
+			m = &memoInfo{text: *envelope.Tx.Memo.Text}
+		case xdr.MemoTypeMemoHash:
+			m = memoFromBytes(envelope.Tx.Memo.Hash[:])
+		}
+		m.apply(&base)
+
+		for i, op := range envelope.Tx.Operations {
+			if op.Body.PaymentOp == nil {
+				continue
+			}
+
+			source := envelope.Tx.SourceAccount
+			if op.SourceAccount != nil {
+				source = *op.SourceAccount
+			}
+
+			amount := int64(op.Body.PaymentOp.Amount)
+			if e.Version == model.KinVersion_KIN2 {
+				// Kin 2 is a 7 decimal asset, whereas quarks are 5 decimals.
+				amount /= 100
+			}
+
+			r := base
+			r.PaymentIndex = int32(i)
+			r.Source = encodeAccountID(source)
+			r.Destination = encodeAccountID(op.Body.PaymentOp.Destination)
+			r.Quarks = amount
+			rows = append(rows, &r)
+		}
+	case *model.Entry_Solana:
+		base.TxID = base58.Encode(txID)
+		base.Block = int64(k.Solana.Slot)
+		if k.Solana.BlockTime != nil {
+			base.BlockTime = formatTime(ptypes.TimestampString(k.Solana.BlockTime))
+		}
+		if len(k.Solana.TransactionError) > 0 {
+			base.Successful = false
+			base.Error = string(k.Solana.TransactionError)
+		}
+
+		var txn solana.Transaction
+		if err := txn.Unmarshal(k.Solana.Transaction); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal transaction")
+		}
+
+		// Memos apply to the transfers that follow them.
+		var m *memoInfo
+		for i := range txn.Message.Instructions {
+			if decompiled, err := memo.DecompileMemo(txn.Message, i); err == nil {
+				if raw, err := base64.StdEncoding.DecodeString(string(decompiled.Data)); err == nil {
+					m = memoFromBytes(raw)
+					if m.memoType == "" {
+						m = &memoInfo{text: string(decompiled.Data)}
+					}
+				} else {
+					m = &memoInfo{text: string(decompiled.Data)}
+				}
+				continue
+			}
+
+			transfer, err := token.DecompileTransferAccount(txn.Message, i)
+			if err != nil {
+				continue
+			}
+
+			r := base
+			m.apply(&r)
+			r.PaymentIndex = int32(i)
+			r.Source = base58.Encode(transfer.Source)
+			r.Destination = base58.Encode(transfer.Destination)
+			r.Quarks = int64(transfer.Amount)
+			rows = append(rows, &r)
+		}
+
+		if len(rows) == 0 {
+			m.apply(&base)
+		}
+	default:
+		return nil, errors.New("unsupported entry type")
+	}
+
+	if len(rows) == 0 {
+		return []*Row{&base}, nil
+	}
+
+	if il != nil {
+		marshaler := &jsonpb.Marshaler{OrigName: true}
+		for i := 0; i < len(il.Invoices) && i < len(rows); i++ {
+			invoice, err := marshaler.MarshalToString(il.Invoices[i])
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal invoice")
+			}
+			rows[i].Invoice = invoice
+		}
+	}
+
+	return rows, nil
+}
+
+func encodeAccountID(id xdr.AccountId) string {
+	v, ok := id.GetEd25519()
+	if !ok {
+		return ""
+	}
+
+	return base58.Encode(v[:])
+}
+
+// formatTime normalizes the (RFC3339) timestamp string to UTC.
+func formatTime(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Writer writes exported rows.
+type Writer interface {
+	// Write writes a row.
+	Write(*Row) error
+
+	// Flush ensures that all previously written rows have been written
+	// to the underlying output.
+	Flush() error
+
+	// Close flushes any remaining rows, and closes the writer.
+	Close() error
+}
+
+// BufferedWriter is implemented by Writers that should only be flushed once
+// enough rows have been buffered. Until then, the Exporter does not flush
+// (or checkpoint) the writer after each page.
+type BufferedWriter interface {
+	Writer
+
+	// Full returns whether or not enough rows have been buffered to flush.
+	Full() bool
+}
+
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLWriter returns a Writer that writes rows as newline delimited JSON.
+func NewJSONLWriter(w io.Writer) Writer {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{
+		buf: buf,
+		enc: json.NewEncoder(buf),
+	}
+}
+
+// Write implements Writer.Write.
+func (w *jsonlWriter) Write(r *Row) error {
+	return w.enc.Encode(r)
+}
+
+// Flush implements Writer.Flush.
+func (w *jsonlWriter) Flush() error {
+	return w.buf.Flush()
+}
+
+// Close implements Writer.Close.
+func (w *jsonlWriter) Close() error {
+	return w.buf.Flush()
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter returns a Writer that writes rows as CSV.
+//
+// If header is true, Columns is written before the first row.
+func NewCSVWriter(w io.Writer, header bool) Writer {
+	return &csvWriter{
+		w:      csv.NewWriter(w),
+		header: header,
+	}
+}
+
+// Write implements Writer.Write.
+func (w *csvWriter) Write(r *Row) error {
+	if w.header {
+		if err := w.w.Write(Columns); err != nil {
+			return err
+		}
+		w.header = false
+	}
+
+	return w.w.Write(r.values())
+}
+
+// Flush implements Writer.Flush.
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Close implements Writer.Close.
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+type parquetWriter struct {
+	open        func(part int) (io.WriteCloser, error)
+	part        int
+	rowsPerPart int
+
+	f    io.WriteCloser
+	pw   *writer.ParquetWriter
+	rows int
+}
+
+// NewParquetWriter returns a BufferedWriter that writes rows as Parquet.
+//
+// Since a Parquet file cannot be read until it has been closed, Flush
+// closes the current file, and subsequent rows are written to a new
+// file (part), opened using open. Parts are numbered sequentially,
+// starting at firstPart. The writer is Full once rowsPerPart rows have
+// been written to the current part.
+func NewParquetWriter(open func(part int) (io.WriteCloser, error), firstPart, rowsPerPart int) BufferedWriter {
+	return &parquetWriter{
+		open:        open,
+		part:        firstPart,
+		rowsPerPart: rowsPerPart,
+	}
+}
+
+// Write implements Writer.Write.
+func (w *parquetWriter) Write(r *Row) error {
+	if w.pw == nil {
+		f, err := w.open(w.part)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open part %d", w.part)
+		}
+
+		pw, err := writer.NewParquetWriterFromWriter(f, new(Row), 1)
+		if err != nil {
+			f.Close()
+			return errors.Wrap(err, "failed to create parquet writer")
+		}
+
+		w.f = f
+		w.pw = pw
+	}
+
+	if err := w.pw.Write(*r); err != nil {
+		return err
+	}
+
+	w.rows++
+	return nil
+}
+
+// Full implements BufferedWriter.Full.
+func (w *parquetWriter) Full() bool {
+	return w.rows >= w.rowsPerPart
+}
+
+// Flush implements Writer.Flush.
+func (w *parquetWriter) Flush() error {
+	if w.pw == nil {
+		return nil
+	}
+
+	if err := w.pw.WriteStop(); err != nil {
+		w.f.Close()
+		return errors.Wrap(err, "failed to finalize parquet file")
+	}
+	if err := w.f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close parquet file")
+	}
+
+	w.f = nil
+	w.pw = nil
+	w.rows = 0
+	w.part++
+	return nil
+}
+
+// Close implements Writer.Close.
+func (w *parquetWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,261 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/go/strkey"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const (
+	// MaxFilterScan is the maximum number of entries examined by a single
+	// filtered read.
+	MaxFilterScan = 10000
+
+	defaultFilterLimit = 100
+)
+
+// ErrFilterScanExceeded is returned by filtered reads that examined
+// MaxFilterScan entries without finding a full page of matching entries.
+//
+// Rather than returning a partial (and possibly empty) page, which callers
+// cannot distinguish from the end of the history, the read fails. Callers
+// should narrow the read, for example with a time range.
+var ErrFilterScanExceeded = errors.New("filter scan limit exceeded")
+
+// Direction is the direction of a payment, relative to an account.
+type Direction int
+
+const (
+	DirectionAny Direction = iota
+	DirectionIncoming
+	DirectionOutgoing
+)
+
+// Filter restricts account history to the entries that match every set
+// field. The zero value of each field indicates that the field should not be
+// filtered on.
+type Filter struct {
+	// AppIndex restricts history to entries with a Kin memo for the app index.
+	AppIndex uint16
+
+	// TransactionTypes restricts history to entries with a Kin memo of one
+	// of the types.
+	TransactionTypes []kin.TransactionType
+
+	// Direction restricts history to entries with a payment in the direction.
+	Direction Direction
+
+	// MinAmount and MaxAmount restrict history to entries with a payment
+	// (in the direction, if any) whose amount, in quarks, is within
+	// [MinAmount, MaxAmount]. A MaxAmount of 0 indicates no upper bound.
+	MinAmount uint64
+	MaxAmount uint64
+
+	// Addresses are the addresses that payments are evaluated relative to,
+	// such as an owner and its token accounts. If empty, the account being
+	// read is used.
+	Addresses []ed25519.PublicKey
+}
+
+func (r *ReadOptions) GetFilter() *Filter {
+	if r == nil {
+		return nil
+	}
+
+	return r.Filter
+}
+
+func (f *Filter) hasPaymentFilter() bool {
+	return f.Direction != DirectionAny || f.MinAmount > 0 || f.MaxAmount > 0
+}
+
+// Matches returns whether or not the entry matches the filter.
+//
+// A nil filter matches every entry.
+func (f *Filter) Matches(e *model.Entry) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	payments, memos, err := e.GetPaymentsAndMemos()
+	if err != nil {
+		return false, err
+	}
+
+	if f.AppIndex > 0 || len(f.TransactionTypes) > 0 {
+		var matched bool
+		for _, m := range memos {
+			if f.AppIndex > 0 && m.AppIndex() != f.AppIndex {
+				continue
+			}
+			if len(f.TransactionTypes) > 0 && !containsType(f.TransactionTypes, m.TransactionType()) {
+				continue
+			}
+
+			matched = true
+			break
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if !f.hasPaymentFilter() {
+		return true, nil
+	}
+
+	for _, p := range payments {
+		incoming := containsAddress(f.Addresses, p.Destination)
+		outgoing := containsAddress(f.Addresses, p.Source) || containsAddress(f.Addresses, p.Owner)
+
+		switch f.Direction {
+		case DirectionIncoming:
+			if !incoming {
+				continue
+			}
+		case DirectionOutgoing:
+			if !outgoing {
+				continue
+			}
+		default:
+			if !incoming && !outgoing {
+				continue
+			}
+		}
+
+		if p.Amount < f.MinAmount {
+			continue
+		}
+		if f.MaxAmount > 0 && p.Amount > f.MaxAmount {
+			continue
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// GetFilteredAccountTransactions returns the entries in the account's history
+// that match the filter of opts, using read to load (unfiltered) pages of the
+// history with the remaining options.
+//
+// It is used by Reader implementations to implement filtering. Pages are read
+// until opts.Limit (or 100, if unset) matching entries are found, or the
+// history is exhausted. If more than MaxFilterScan entries are examined first,
+// ErrFilterScanExceeded is returned.
+func GetFilteredAccountTransactions(
+	ctx context.Context,
+	account string,
+	opts *ReadOptions,
+	read func(ctx context.Context, account string, opts *ReadOptions) ([]*model.Entry, error),
+) ([]*model.Entry, error) {
+	filter := opts.GetFilter()
+	if filter == nil {
+		return read(ctx, account, opts)
+	}
+
+	if len(filter.Addresses) == 0 {
+		raw, err := strkey.Decode(strkey.VersionByteAccountID, account)
+		if err != nil {
+			return nil, err
+		}
+
+		copied := *filter
+		copied.Addresses = []ed25519.PublicKey{raw}
+		filter = &copied
+	}
+
+	limit := opts.GetLimit()
+	if limit <= 0 {
+		limit = defaultFilterLimit
+	}
+
+	pageOpts := &ReadOptions{
+		Descending: opts.GetDescending(),
+		Start:      opts.Start,
+		Limit:      limit,
+		StartTime:  opts.GetStartTime(),
+		EndTime:    opts.GetEndTime(),
+	}
+
+	var results []*model.Entry
+	var scanned int
+	var skip []byte
+	for {
+		entries, err := read(ctx, account, pageOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			// The start of each page is inclusive, so the last entry of the
+			// previous page is skipped.
+			if skip != nil {
+				orderingKey, err := e.GetOrderingKey()
+				if err != nil {
+					return nil, err
+				}
+				if bytes.Equal(orderingKey, skip) {
+					continue
+				}
+			}
+
+			scanned++
+			if matches, err := filter.Matches(e); err != nil {
+				return nil, err
+			} else if !matches {
+				continue
+			}
+
+			results = append(results, e)
+			if len(results) >= limit {
+				return results, nil
+			}
+		}
+
+		if len(entries) < pageOpts.Limit {
+			return results, nil
+		}
+		if scanned >= MaxFilterScan {
+			return nil, ErrFilterScanExceeded
+		}
+
+		if skip, err = entries[len(entries)-1].GetOrderingKey(); err != nil {
+			return nil, err
+		}
+		pageOpts.Start = skip
+		pageOpts.Limit = limit + 1
+	}
+}
+
+func containsType(types []kin.TransactionType, t kin.TransactionType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAddress(addresses []ed25519.PublicKey, address ed25519.PublicKey) bool {
+	if len(address) == 0 {
+		return false
+	}
+
+	for _, a := range addresses {
+		if bytes.Equal(a, address) {
+			return true
+		}
+	}
+
+	return false
+}
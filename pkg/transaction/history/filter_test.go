@@ -0,0 +1,77 @@
+package history_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/kinecosystem/go/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func TestGetFilteredAccountTransactions_ScanLimit(t *testing.T) {
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	senderAddr := strkey.MustEncode(strkey.VersionByteAccountID, sender.Public().(ed25519.PublicKey))
+
+	// Only the last entry has a memo.
+	generated := make([]*model.Entry, history.MaxFilterScan+1)
+	for i := range generated {
+		var invoiceHash []byte
+		if i == len(generated)-1 {
+			invoiceHash = make([]byte, 28)
+		}
+
+		generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, invoiceHash, nil)
+	}
+
+	var reads int
+	read := func(_ context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+		reads++
+		assert.Equal(t, senderAddr, account)
+		assert.Nil(t, opts.Filter)
+
+		var page []*model.Entry
+		for _, e := range generated {
+			if bytes.Compare(historytestutil.GetOrderingKey(t, e), opts.GetStart()) >= 0 {
+				page = append(page, e)
+			}
+			if len(page) == opts.GetLimit() {
+				break
+			}
+		}
+		return page, nil
+	}
+
+	filter := &history.Filter{AppIndex: 1}
+	_, err := history.GetFilteredAccountTransactions(context.Background(), senderAddr, &history.ReadOptions{Filter: filter}, read)
+	assert.Equal(t, history.ErrFilterScanExceeded, err)
+	assert.Equal(t, history.MaxFilterScan/100, reads)
+
+	// Starting closer to the match stays within the limit.
+	entries, err := history.GetFilteredAccountTransactions(context.Background(), senderAddr, &history.ReadOptions{
+		Start:  historytestutil.GetOrderingKey(t, generated[100]),
+		Filter: filter,
+	}, read)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, generated[len(generated)-1], entries[0])
+
+	// Unfiltered reads are passed through.
+	reads = 0
+	entries, err = history.GetFilteredAccountTransactions(context.Background(), senderAddr, &history.ReadOptions{Limit: 10}, func(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+		reads++
+		assert.Equal(t, 10, opts.Limit)
+		return generated[:10], nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, entries, 10)
+	assert.Equal(t, 1, reads)
+}
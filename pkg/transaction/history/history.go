@@ -1,8 +1,10 @@
 package history
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"time"
 
 	model "github.com/kinecosystem/agora/pkg/transaction/history/model"
 )
@@ -10,6 +12,11 @@ import (
 var (
 	ErrNotFound      = errors.New("not found")
 	ErrInvalidUpdate = errors.New("invalid update")
+
+	// ErrNotSupported is returned by decorators of a history store (for
+	// example, caches) that implement an optional interface (for example,
+	// Pruner) if the underlying store does not.
+	ErrNotSupported = errors.New("not supported")
 )
 
 type Writer interface {
@@ -25,6 +32,26 @@ type ReadOptions struct {
 	Descending bool
 	Start      []byte
 	Limit      int
+
+	// StartTime and EndTime, if set, restrict the returned entries to those
+	// whose block time falls within [StartTime, EndTime).
+	//
+	// Entries without a block time (for example, unconfirmed entries) are
+	// excluded if either bound is set. Stores serve time ranges from a
+	// block time index, ordered by block time and then ordering key. Since
+	// block times do not decrease with the ordering key, the order matches
+	// the unbounded one, and Start remains a stable cursor for the next page.
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Filter, if set, restricts the returned entries to those that match.
+	//
+	// Filters are applied before Limit, so up to Limit matching entries are
+	// returned. Since the ordering of entries is unaffected, the ordering key
+	// of the last returned entry remains a stable cursor for the next page.
+	// Stores implement filtering with GetFilteredAccountTransactions, and may
+	// therefore return ErrFilterScanExceeded.
+	Filter *Filter
 }
 
 func (r *ReadOptions) GetDescending() bool {
@@ -51,6 +78,52 @@ func (r *ReadOptions) GetLimit() int {
 	return r.Limit
 }
 
+func (r *ReadOptions) GetStartTime() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+
+	return r.StartTime
+}
+
+func (r *ReadOptions) GetEndTime() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+
+	return r.EndTime
+}
+
+// HasTimeRange returns whether or not a time range was specified.
+func (r *ReadOptions) HasTimeRange() bool {
+	return !r.GetStartTime().IsZero() || !r.GetEndTime().IsZero()
+}
+
+// InTimeRange returns whether or not the entry falls within the specified
+// time range (if any).
+func (r *ReadOptions) InTimeRange(e *model.Entry) (bool, error) {
+	if !r.HasTimeRange() {
+		return true, nil
+	}
+
+	t, err := e.GetBlockTime()
+	if err != nil {
+		return false, err
+	}
+	if t.IsZero() {
+		return false, nil
+	}
+
+	if start := r.GetStartTime(); !start.IsZero() && t.Before(start) {
+		return false, nil
+	}
+	if end := r.GetEndTime(); !end.IsZero() && !t.Before(end) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 type Reader interface {
 	// GetTransaction returns the model.Entry associated with the specified txHash.
 	//
@@ -85,3 +158,116 @@ type ReaderWriter interface {
 	Reader
 	Writer
 }
+
+// GetCompleteBlocks returns the entries of the complete blocks at the start of
+// the (inclusive) block range [fromBlock, maxBlock], using the block ordered
+// history of the reader. next is the first block whose entries were not
+// returned, and is maxBlock+1 once the range has been exhausted.
+//
+// Up to limit entries are loaded at a time. If a page is full, the last block
+// in it may be incomplete, so its entries are not returned. If a page only
+// contains a single block, larger pages are loaded until the block is
+// complete, so more than limit entries may be returned.
+//
+// Only Solana entries are kept in the block ordered history, so blocks are
+// slots.
+func GetCompleteBlocks(ctx context.Context, reader Reader, fromBlock, maxBlock uint64, limit int) (entries []*model.Entry, next uint64, err error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	for {
+		entries, err := reader.GetTransactions(ctx, fromBlock, maxBlock, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(entries) < limit {
+			return entries, maxBlock + 1, nil
+		}
+
+		lastSlot := entries[len(entries)-1].GetSolana().GetSlot()
+
+		n := len(entries)
+		for n > 0 && entries[n-1].GetSolana().GetSlot() == lastSlot {
+			n--
+		}
+		if n == 0 {
+			limit *= 2
+			continue
+		}
+
+		return entries[:n], lastSlot, nil
+	}
+}
+
+// Deleter removes entries from a history store.
+//
+// It is used to move old entries out of the store (for example, into an
+// archive), and should not be used to rewrite history.
+type Deleter interface {
+	// Delete removes the entry from the transaction and account indexes.
+	//
+	// The block ordered history used by GetTransactions is retained. Deleting
+	// an entry that does not exist is not an error.
+	Delete(ctx context.Context, entry *model.Entry) error
+}
+
+// Pruner removes entries from every index of a history store.
+//
+// Unlike Deleter, the entry is also removed from the block ordered history.
+// It is used to remove entries of blocks that were orphaned by a fork.
+type Pruner interface {
+	// Prune removes the entry from every index, including the block ordered
+	// history used by GetTransactions. Pruning an entry that does not exist
+	// is not an error.
+	Prune(ctx context.Context, entry *model.Entry) error
+}
+
+// Repairer overwrites entries in a history store.
+//
+// Unlike Writer, which never replaces the entry for a transaction once it
+// has been written, Repairer replaces it unconditionally. It is used to fix
+// entries that are known to be stale (for example, by verifying them against
+// the chain), and should not be used for ingestion.
+type Repairer interface {
+	// Repair replaces any existing entry for the transaction with the
+	// provided entry, in every index. If the existing entry has a different
+	// ordering key, it is removed from the indexes keyed by ordering key.
+	Repair(ctx context.Context, entry *model.Entry) error
+}
+
+// TimeIndexer is implemented by history stores that index account history
+// by block time.
+//
+// Entries are indexed when they are written with a block time. Entries that
+// were written before the index existed, or without a block time, must be
+// rewritten (for example, with a Repairer) once their block time is known.
+type TimeIndexer interface {
+	// GetUnindexed returns entries in the account history that are missing
+	// from the block time index, starting after the (opaque) cursor. A nil
+	// cursor starts from the beginning.
+	//
+	// The returned cursor is nil once the account history has been fully
+	// scanned. Fewer than limit entries may be returned before then, and an
+	// entry may be returned more than once (once per related account).
+	GetUnindexed(ctx context.Context, cursor []byte, limit int) (entries []*model.Entry, next []byte, err error)
+}
+
+// EncodeAccountCursor encodes a position in the account history (the account,
+// and the ordering key within it) as a TimeIndexer cursor.
+func EncodeAccountCursor(account string, orderingKey []byte) []byte {
+	cursor := make([]byte, 0, len(account)+1+len(orderingKey))
+	cursor = append(cursor, account...)
+	cursor = append(cursor, 0)
+	return append(cursor, orderingKey...)
+}
+
+// DecodeAccountCursor decodes a cursor produced by EncodeAccountCursor.
+func DecodeAccountCursor(cursor []byte) (account string, orderingKey []byte, err error) {
+	i := bytes.IndexByte(cursor, 0)
+	if i < 0 {
+		return "", nil, errors.New("invalid account cursor")
+	}
+
+	return string(cursor[:i]), cursor[i+1:], nil
+}
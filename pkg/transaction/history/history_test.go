@@ -0,0 +1,54 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func TestGetCompleteBlocks(t *testing.T) {
+	rw := historymemory.New()
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+
+	// Slot 2 contains a single entry, slot 3 contains 4 entries, and slot 5
+	// contains 2 entries.
+	var generated []*model.Entry
+	for _, slot := range []uint64{2, 3, 3, 3, 3, 5, 5} {
+		e, _ := historytestutil.GenerateSolanaEntry(t, slot, true, sender, receivers, nil, nil)
+		require.NoError(t, rw.Write(context.Background(), e))
+		generated = append(generated, e)
+	}
+
+	// The incomplete slot 3 is not returned.
+	entries, next, err := history.GetCompleteBlocks(context.Background(), rw, 0, 10, 3)
+	require.NoError(t, err)
+	assert.Equal(t, generated[:1], entries)
+	assert.EqualValues(t, 3, next)
+
+	// A page of a single slot is extended until the slot is complete.
+	entries, next, err = history.GetCompleteBlocks(context.Background(), rw, next, 10, 3)
+	require.NoError(t, err)
+	assert.Equal(t, generated[1:5], entries)
+	assert.EqualValues(t, 5, next)
+
+	// Once the range is exhausted, next is past the end of the range.
+	entries, next, err = history.GetCompleteBlocks(context.Background(), rw, next, 10, 3)
+	require.NoError(t, err)
+	assert.Equal(t, generated[5:], entries)
+	assert.EqualValues(t, 11, next)
+
+	entries, next, err = history.GetCompleteBlocks(context.Background(), rw, 6, 10, 3)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.EqualValues(t, 11, next)
+}
@@ -0,0 +1,178 @@
+package ingestion
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+)
+
+// BackfillChunk is a bounded range of blocks, (Parent, End], to be backfilled.
+type BackfillChunk struct {
+	// Parent points to the block immediately before the first block of the chunk.
+	Parent Pointer
+
+	// End points to the last block of the chunk.
+	End Pointer
+}
+
+// GetBackfillCommitterName returns the committer key used to track the
+// progress of a backfill chunk for the specified ingestor.
+//
+// Backfills use their own keys so that the live ingestion pointer is
+// never modified.
+func GetBackfillCommitterName(ingestor string, chunk BackfillChunk) string {
+	return fmt.Sprintf("%s_backfill_%s_%s", ingestor, hex.EncodeToString(chunk.Parent), hex.EncodeToString(chunk.End))
+}
+
+// BackfillProgress is called after a block in a chunk has been committed,
+// or when a chunk was already backfilled by a previous run. done indicates
+// whether or not the chunk has been completely backfilled.
+type BackfillProgress func(chunk BackfillChunk, block Pointer, done bool)
+
+// Backfill runs the ingestor over each of the bounded chunks, processing
+// up to concurrency chunks in parallel. Backfill blocks until all chunks
+// have been backfilled, or an error occurs.
+//
+// The progress of each chunk is committed using GetBackfillCommitterName,
+// allowing an interrupted backfill to be resumed by calling Backfill with
+// the same chunks. Since history.Writer implementations are idempotent,
+// re-ingesting blocks that already exist in history is safe.
+//
+// Ingestors are unbounded, and may process several blocks concurrently, so
+// blocks after the end of a chunk may be written to w before the ingestor of
+// the chunk is cancelled. These blocks overlap with the next chunk (or the
+// live ingestion), which writes them again. As a result, w should not be a
+// writer that feeds other consumers (such as a fanout.FanOut), as they may
+// observe blocks out of order.
+func Backfill(ctx context.Context, c Committer, w history.Writer, i Ingestor, chunks []BackfillChunk, concurrency int, progress BackfillProgress) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var backfillErr error
+
+	chunkCh := make(chan BackfillChunk)
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for chunk := range chunkCh {
+				if err := backfillChunk(ctx, c, w, i, chunk, progress); err != nil {
+					errOnce.Do(func() {
+						backfillErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, chunk := range chunks {
+		select {
+		case chunkCh <- chunk:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(chunkCh)
+	wg.Wait()
+
+	if backfillErr != nil {
+		return backfillErr
+	}
+
+	return ctx.Err()
+}
+
+func backfillChunk(ctx context.Context, c Committer, w history.Writer, i Ingestor, chunk BackfillChunk, progress BackfillProgress) error {
+	name := GetBackfillCommitterName(i.Name(), chunk)
+	log := logrus.StandardLogger().WithFields(logrus.Fields{
+		"type":     "transaction/history/ingestion",
+		"method":   "Backfill",
+		"ingestor": name,
+	})
+
+	latest, err := c.Latest(ctx, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get latest commit for '%s'", name)
+	}
+	if latest == nil {
+		latest = chunk.Parent
+	}
+	if bytes.Compare(latest, chunk.End) >= 0 {
+		log.Debug("chunk already backfilled")
+		if progress != nil {
+			progress(chunk, latest, true)
+		}
+		return nil
+	}
+
+	// The ingestor is unbounded, so we cancel it once we've passed the
+	// end of the chunk.
+	queueCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	log.WithField("parent", hex.EncodeToString(latest)).Debug("Starting backfill")
+	queue, err := i.Ingest(queueCtx, w, latest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to start ingestor '%s'", name)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultCh, ok := <-queue:
+			if !ok {
+				return errors.Errorf("ingestion queue for '%s' closed before end of chunk", name)
+			}
+
+			var r Result
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case r = <-resultCh:
+			}
+			if r.Err != nil {
+				return errors.Wrap(r.Err, "failed to ingest")
+			}
+
+			// Not every block in a range necessarily exists, so the first block
+			// after the end of the chunk indicates that every block in the chunk
+			// has been processed. In this case, we mark the end of the chunk as
+			// committed.
+			block := r.Block
+			if bytes.Compare(block, chunk.End) > 0 {
+				block = chunk.End
+			}
+
+			if err := c.Commit(ctx, name, latest, block); err != nil {
+				return errors.Wrapf(err, "failed to commit block (%x, %x)", latest, block)
+			}
+			latest = block
+
+			done := bytes.Equal(block, chunk.End)
+			if progress != nil {
+				progress(chunk, block, done)
+			}
+			if done {
+				log.Debug("chunk backfilled")
+				return nil
+			}
+		}
+	}
+}
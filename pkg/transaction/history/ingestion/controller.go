@@ -0,0 +1,531 @@
+package ingestion
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+)
+
+var (
+	// ErrIngestorNotFound indicates that no ingestor with the specified name
+	// is registered with the Controller.
+	ErrIngestorNotFound = errors.New("ingestor not found")
+
+	// ErrInvalidPointer indicates that a pointer is not valid for an ingestor.
+	ErrInvalidPointer = errors.New("invalid pointer")
+
+	// errInterrupted indicates that the current ingestion attempt was
+	// interrupted by a Controller.
+	errInterrupted = errors.New("ingestion interrupted")
+)
+
+// Status is the status of an ingestor registered with a Controller.
+type Status struct {
+	Name string
+
+	// Latest is the latest committed block pointer.
+	Latest Pointer
+
+	// Paused indicates whether or not the ingestion loop has been paused.
+	Paused bool
+
+	// LockHeld indicates whether or not this process holds the ingestion lock.
+	LockHeld bool
+
+	// LockHolder is the owner of the ingestion lock, if known.
+	LockHolder string
+
+	// Lag is the number of blocks between Latest and the latest block of the
+	// blockchain, or -1 if unknown.
+	Lag int64
+
+	// LastCommit is the time of the last commit made by this process, if any.
+	LastCommit time.Time
+}
+
+// defaultPausePollInterval is the default interval at which loops check
+// whether or not they have been paused by another process.
+const defaultPausePollInterval = 10 * time.Second
+
+// Controller provides runtime control over ingestion loops that are run
+// via Controller.Run, or registered via Controller.Register.
+//
+// If the Committer of a loop is a Pauser, pausing and resuming is stored
+// alongside the committed pointer, and so applies to all processes: each
+// process checks the paused state before acquiring the ingestion lock, and
+// periodically while holding it. Otherwise, pausing and resuming only affects
+// the loops in this process; while paused, the ingestion lock is released,
+// and may be acquired by another process.
+//
+// Rewinding updates the committed pointer directly, which is observed by all
+// processes on their next commit.
+type Controller struct {
+	log          *logrus.Entry
+	pollInterval time.Duration
+
+	mu    sync.Mutex
+	loops map[string]*loop
+}
+
+// ControllerOption configures a Controller.
+type ControllerOption func(c *Controller)
+
+// WithPausePollInterval configures the interval at which loops check whether
+// or not they have been paused by another process.
+func WithPausePollInterval(interval time.Duration) ControllerOption {
+	return func(c *Controller) {
+		c.pollInterval = interval
+	}
+}
+
+// NewController returns a new Controller.
+func NewController(opts ...ControllerOption) *Controller {
+	c := &Controller{
+		log:          logrus.StandardLogger().WithField("type", "transaction/history/ingestion/controller"),
+		pollInterval: defaultPausePollInterval,
+		loops:        make(map[string]*loop),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// Run runs an ingestion flow in a blocking fashion (see Run), registering it
+// with the controller for the duration of the call.
+func (c *Controller) Run(ctx context.Context, l DistributedLock, committer Committer, w history.Writer, i Ingestor) error {
+	lp := newLoop(i.Name(), l, committer, c.pollInterval)
+	lp.ingestor = i
+	if reporter, ok := i.(LagReporter); ok {
+		lp.lag = reporter
+	}
+
+	if err := c.add(lp); err != nil {
+		return err
+	}
+	defer c.remove(lp)
+
+	return run(ctx, lp, w)
+}
+
+// Register registers a loop that is run outside of the controller (for
+// example, the sinks of a fanout.FanOut), so that it can be listed, paused,
+// resumed and rewound alongside the ingestors run via Controller.Run. The
+// name is used as the committer key of the loop.
+//
+// The lag reporter is optional. The returned Registration must be closed
+// once the loop stops.
+func (c *Controller) Register(name string, l DistributedLock, committer Committer, lag LagReporter) (*Registration, error) {
+	lp := newLoop(name, l, committer, c.pollInterval)
+	lp.lag = lag
+
+	if err := c.add(lp); err != nil {
+		return nil, err
+	}
+
+	return &Registration{c: c, lp: lp}, nil
+}
+
+// List returns the status of all registered ingestors, ordered by name.
+func (c *Controller) List(ctx context.Context) ([]*Status, error) {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.loops))
+	for name := range c.loops {
+		names = append(names, name)
+	}
+	c.mu.Unlock()
+
+	sort.Strings(names)
+
+	statuses := make([]*Status, 0, len(names))
+	for _, name := range names {
+		s, err := c.Status(ctx, name)
+		if err == ErrIngestorNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+// Status returns the status of the specified ingestor.
+func (c *Controller) Status(ctx context.Context, name string) (*Status, error) {
+	lp, err := c.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := lp.committer.Latest(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get latest commit for '%s'", name)
+	}
+
+	paused, err := lp.isPaused(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lp.mu.Lock()
+	s := &Status{
+		Name:       name,
+		Latest:     latest,
+		Paused:     paused,
+		LockHeld:   lp.lockHeld,
+		Lag:        -1,
+		LastCommit: lp.lastCommit,
+	}
+	lp.mu.Unlock()
+
+	// The lock holder and lag are best effort, as they generally depend on
+	// external systems.
+	log := c.log.WithField("ingestor", name)
+	if holder, ok := lp.lock.(LockHolder); ok {
+		if s.LockHolder, err = holder.Holder(ctx); err != nil {
+			log.WithError(err).Warn("failed to get lock holder")
+		}
+	}
+	if lp.lag != nil {
+		if lag, err := lp.lag.Lag(ctx, latest); err != nil {
+			log.WithError(err).Warn("failed to get ingestion lag")
+		} else {
+			s.Lag = int64(lag)
+		}
+	}
+
+	return s, nil
+}
+
+// Pause pauses the specified ingestor, releasing its lock. Pause is
+// idempotent.
+func (c *Controller) Pause(ctx context.Context, name string) error {
+	lp, err := c.get(name)
+	if err != nil {
+		return err
+	}
+
+	return lp.pause(ctx)
+}
+
+// Resume resumes a paused ingestor. Resume is idempotent.
+func (c *Controller) Resume(ctx context.Context, name string) error {
+	lp, err := c.get(name)
+	if err != nil {
+		return err
+	}
+
+	return lp.resume(ctx)
+}
+
+// Rewind resets the committed pointer of the specified ingestor to block,
+// returning the previously committed pointer. Ingestion is restarted from
+// block once the reset has been applied.
+//
+// ErrInvalidPointer is returned if block is ahead of the committed pointer,
+// as rewinding forward would skip blocks that were never ingested, or if
+// nothing has been committed yet, as there is nothing to rewind.
+//
+// ErrInvalidCommit is returned if a block was committed concurrently, in
+// which case Rewind may be retried.
+func (c *Controller) Rewind(ctx context.Context, name string, block Pointer) (Pointer, error) {
+	lp, err := c.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := lp.committer.Latest(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get latest commit for '%s'", name)
+	}
+
+	// Pointers are opaque, but are ordered bytewise (see Committer).
+	if len(block) == 0 || latest == nil || bytes.Compare(block, latest) > 0 {
+		return nil, ErrInvalidPointer
+	}
+
+	if err := lp.committer.Reset(ctx, name, latest, block); err != nil {
+		return nil, err
+	}
+
+	c.log.WithFields(logrus.Fields{
+		"ingestor": name,
+		"previous": hex.EncodeToString(latest),
+		"block":    hex.EncodeToString(block),
+	}).Info("rewound ingestor")
+
+	lp.interrupt()
+	return latest, nil
+}
+
+func (c *Controller) add(lp *loop) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.loops[lp.name]; ok {
+		return errors.Errorf("ingestor '%s' already registered", lp.name)
+	}
+	c.loops[lp.name] = lp
+
+	return nil
+}
+
+func (c *Controller) remove(lp *loop) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loops[lp.name] == lp {
+		delete(c.loops, lp.name)
+	}
+}
+
+func (c *Controller) get(name string) (*loop, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lp, ok := c.loops[name]
+	if !ok {
+		return nil, ErrIngestorNotFound
+	}
+
+	return lp, nil
+}
+
+// Registration is a loop registered via Controller.Register.
+//
+// The loop is expected to wait until it is resumed before each attempt
+// (see WaitUntilResumed), and to stop the attempt once its context, as
+// returned by StartAttempt, is cancelled by a pause or rewind.
+type Registration struct {
+	c  *Controller
+	lp *loop
+}
+
+// WaitUntilResumed blocks until the loop is not paused, or the context
+// is cancelled.
+func (r *Registration) WaitUntilResumed(ctx context.Context) error {
+	return r.lp.waitUntilResumed(ctx)
+}
+
+// StartAttempt returns a context for an attempt of the loop, which is
+// cancelled when the loop is paused (by any process) or rewound. The
+// returned CancelFunc must be called when the attempt completes.
+func (r *Registration) StartAttempt(ctx context.Context) (context.Context, context.CancelFunc) {
+	attemptCtx, cancel := r.lp.startAttempt(ctx)
+	go r.lp.watchPaused(attemptCtx)
+	return attemptCtx, cancel
+}
+
+// SetLockHeld records whether or not this process holds the lock of the loop.
+func (r *Registration) SetLockHeld(held bool) {
+	r.lp.setLockHeld(held)
+}
+
+// Committed records the time of a commit made by the loop.
+func (r *Registration) Committed(t time.Time) {
+	r.lp.committed(t)
+}
+
+// Close unregisters the loop from the controller.
+func (r *Registration) Close() {
+	r.c.remove(r.lp)
+}
+
+// loop is the runtime state of an ingestion loop.
+type loop struct {
+	log          *logrus.Entry
+	name         string
+	lock         DistributedLock
+	committer    Committer
+	pollInterval time.Duration
+
+	// ingestor is only set for loops run by the Controller, and lag is
+	// optional.
+	ingestor Ingestor
+	lag      LagReporter
+
+	mu         sync.Mutex
+	paused     bool
+	resumed    chan struct{}
+	cancel     context.CancelFunc
+	lockHeld   bool
+	lastCommit time.Time
+}
+
+func newLoop(name string, l DistributedLock, c Committer, pollInterval time.Duration) *loop {
+	return &loop{
+		log: logrus.StandardLogger().WithFields(logrus.Fields{
+			"type":     "transaction/history/ingestion/loop",
+			"ingestor": name,
+		}),
+		name:         name,
+		lock:         l,
+		committer:    c,
+		pollInterval: pollInterval,
+	}
+}
+
+// isPaused returns whether or not the loop is paused. If the committer is a
+// Pauser, the shared state is used, otherwise the local state is used.
+func (l *loop) isPaused(ctx context.Context) (bool, error) {
+	pauser, ok := l.committer.(Pauser)
+	if !ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.paused, nil
+	}
+
+	paused, err := pauser.Paused(ctx, l.name)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get paused state for '%s'", l.name)
+	}
+
+	l.setPaused(paused)
+	return paused, nil
+}
+
+// waitUntilResumed blocks until the loop is not paused, or the context
+// is cancelled.
+//
+// If the paused state cannot be loaded, the loop is considered paused, as
+// another process may have paused it.
+func (l *loop) waitUntilResumed(ctx context.Context) error {
+	for {
+		paused, err := l.isPaused(ctx)
+		if err != nil {
+			l.log.WithError(err).Warn("failed to check if ingestor is paused")
+		} else if !paused {
+			return nil
+		}
+
+		l.mu.Lock()
+		resumed := l.resumed
+		l.mu.Unlock()
+
+		select {
+		case <-resumed:
+		case <-time.After(l.pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watchPaused interrupts the loop if it gets paused by another process,
+// until the context is cancelled. It is a no-op if the committer is not
+// a Pauser.
+func (l *loop) watchPaused(ctx context.Context) {
+	if _, ok := l.committer.(Pauser); !ok {
+		return
+	}
+
+	ticker := time.NewTicker(l.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		paused, err := l.isPaused(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.log.WithError(err).Warn("failed to check if ingestor is paused")
+		} else if paused {
+			l.interrupt()
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startAttempt returns a context for an ingestion attempt, which is cancelled
+// when the loop is interrupted. The returned CancelFunc must be called when
+// the attempt completes.
+func (l *loop) startAttempt(ctx context.Context) (context.Context, context.CancelFunc) {
+	attemptCtx, cancel := context.WithCancel(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.paused {
+		cancel()
+	}
+	l.cancel = cancel
+
+	return attemptCtx, func() {
+		cancel()
+
+		l.mu.Lock()
+		l.cancel = nil
+		l.mu.Unlock()
+	}
+}
+
+func (l *loop) interrupt() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+func (l *loop) pause(ctx context.Context) error {
+	if pauser, ok := l.committer.(Pauser); ok {
+		if err := pauser.SetPaused(ctx, l.name, true); err != nil {
+			return errors.Wrapf(err, "failed to pause '%s'", l.name)
+		}
+	}
+
+	l.setPaused(true)
+	l.interrupt()
+	return nil
+}
+
+func (l *loop) resume(ctx context.Context) error {
+	if pauser, ok := l.committer.(Pauser); ok {
+		if err := pauser.SetPaused(ctx, l.name, false); err != nil {
+			return errors.Wrapf(err, "failed to resume '%s'", l.name)
+		}
+	}
+
+	l.setPaused(false)
+	return nil
+}
+
+func (l *loop) setPaused(paused bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if paused && !l.paused {
+		l.paused = true
+		l.resumed = make(chan struct{})
+	} else if !paused && l.paused {
+		l.paused = false
+		close(l.resumed)
+	}
+}
+
+func (l *loop) setLockHeld(held bool) {
+	l.mu.Lock()
+	l.lockHeld = held
+	l.mu.Unlock()
+}
+
+func (l *loop) committed(t time.Time) {
+	l.mu.Lock()
+	l.lastCommit = t
+	l.mu.Unlock()
+}
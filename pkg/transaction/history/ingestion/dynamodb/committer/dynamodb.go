@@ -11,11 +11,18 @@
 //
 // The commit function uses an atomic compare-and-swap to ensure that the
 // parent matches the stored latest, and that the new latest is 'older' than
-// the previous latest.
+// the previous latest. Resets use the same compare-and-swap, but only allow
+// the latest to move backwards.
+//
+// Each item also stores whether or not the ingestor is paused, in the
+// 'paused' attribute, and the latest fencing token used to commit (see
+// ingestion.WithFencingToken), in the 'fence' attribute.
 package dynamodb
 
 import (
+	"bytes"
 	"context"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -31,12 +38,20 @@ const (
 	ingestorKey      = "ingestor"
 	commitCondition  = "attribute_not_exists(latest) or (latest = :parent and latest < :block)"
 	commitExpression = "SET latest = :block"
+	resetCondition   = "latest = :latest"
+	initCondition    = "attribute_not_exists(latest)"
+	pausedExpression = "SET paused = :paused"
+	fenceCondition   = "(attribute_not_exists(fence) or fence <= :fence)"
+	fenceExpression  = ", fence = :fence"
 )
 
 var (
 	tableNameStr        = aws.String(tableName)
 	commitConditionStr  = aws.String(commitCondition)
 	commitExpressionStr = aws.String(commitExpression)
+	resetConditionStr   = aws.String(resetCondition)
+	initConditionStr    = aws.String(initCondition)
+	pausedExpressionStr = aws.String(pausedExpression)
 )
 
 type committer struct {
@@ -55,7 +70,7 @@ func (c *committer) Commit(ctx context.Context, name string, parent, block inges
 		parent = []byte{0}
 	}
 
-	_, err := c.client.UpdateItemRequest(&dynamodb.UpdateItemInput{
+	input := &dynamodb.UpdateItemInput{
 		TableName:           tableNameStr,
 		ConditionExpression: commitConditionStr,
 		UpdateExpression:    commitExpressionStr,
@@ -66,7 +81,10 @@ func (c *committer) Commit(ctx context.Context, name string, parent, block inges
 			":parent": {B: parent},
 			":block":  {B: block},
 		},
-	}).Send(ctx)
+	}
+	withFence(ctx, input)
+
+	_, err := c.client.UpdateItemRequest(input).Send(ctx)
 	if err != nil {
 		if dynamodbutil.IsConditionalCheckFailed(err) {
 			return ingestion.ErrInvalidCommit
@@ -97,3 +115,90 @@ func (c *committer) Latest(ctx context.Context, name string) (ingestion.Pointer,
 
 	return nil, nil
 }
+
+// Reset implements ingestion.Committer.Reset.
+func (c *committer) Reset(ctx context.Context, name string, latest, block ingestion.Pointer) error {
+	if latest != nil && bytes.Compare(block, latest) > 0 {
+		return ingestion.ErrInvalidCommit
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:        tableNameStr,
+		UpdateExpression: commitExpressionStr,
+		Key: map[string]dynamodb.AttributeValue{
+			ingestorKey: {S: aws.String(name)},
+		},
+		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+			":block": {B: block},
+		},
+	}
+	if latest == nil {
+		input.ConditionExpression = initConditionStr
+	} else {
+		input.ConditionExpression = resetConditionStr
+		input.ExpressionAttributeValues[":latest"] = dynamodb.AttributeValue{B: latest}
+	}
+	withFence(ctx, input)
+
+	_, err := c.client.UpdateItemRequest(input).Send(ctx)
+	if err != nil {
+		if dynamodbutil.IsConditionalCheckFailed(err) {
+			return ingestion.ErrInvalidCommit
+		}
+
+		return errors.Wrap(err, "failed to reset commit")
+	}
+
+	return nil
+}
+
+// withFence adds the fencing token of the context, if any, to the condition
+// and update expressions of the input.
+func withFence(ctx context.Context, input *dynamodb.UpdateItemInput) {
+	token, ok := ingestion.FencingTokenFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	input.ConditionExpression = aws.String("(" + *input.ConditionExpression + ") and " + fenceCondition)
+	input.UpdateExpression = aws.String(*input.UpdateExpression + fenceExpression)
+	input.ExpressionAttributeValues[":fence"] = dynamodb.AttributeValue{N: aws.String(strconv.FormatUint(token, 10))}
+}
+
+// SetPaused implements ingestion.Pauser.SetPaused.
+func (c *committer) SetPaused(ctx context.Context, name string, paused bool) error {
+	_, err := c.client.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		TableName:        tableNameStr,
+		UpdateExpression: pausedExpressionStr,
+		Key: map[string]dynamodb.AttributeValue{
+			ingestorKey: {S: aws.String(name)},
+		},
+		ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+			":paused": {BOOL: aws.Bool(paused)},
+		},
+	}).Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to set paused state")
+	}
+
+	return nil
+}
+
+// Paused implements ingestion.Pauser.Paused.
+func (c *committer) Paused(ctx context.Context, name string) (bool, error) {
+	resp, err := c.client.GetItemRequest(&dynamodb.GetItemInput{
+		TableName: tableNameStr,
+		Key: map[string]dynamodb.AttributeValue{
+			ingestorKey: {S: aws.String(name)},
+		},
+	}).Send(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get paused state")
+	}
+
+	if paused, ok := resp.Item["paused"]; ok && paused.BOOL != nil {
+		return *paused.BOOL, nil
+	}
+
+	return false, nil
+}
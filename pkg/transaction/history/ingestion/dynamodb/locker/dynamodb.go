@@ -2,6 +2,8 @@ package dynamodb
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ func New(db dynamodbiface.DynamoDBAPI, lockKey string, heartbeat time.Duration)
 		tableName,
 		dynamolock.WithLeaseDuration(3*heartbeat),
 		dynamolock.WithHeartbeatPeriod(heartbeat),
+		dynamolock.WithOwnerName(ownerName()),
 	)
 	if err != nil {
 		return nil, err
@@ -52,7 +55,13 @@ func (l *lock) Lock(ctx context.Context) error {
 		return nil
 	}
 
-	lock, err := l.client.AcquireLock(l.key, dynamolock.WithRefreshPeriod(l.refresh))
+	// The lock is deleted on release so that Holder() does not report a
+	// previous owner.
+	lock, err := l.client.AcquireLock(
+		l.key,
+		dynamolock.WithRefreshPeriod(l.refresh),
+		dynamolock.WithDeleteLockOnRelease(),
+	)
 	if err != nil {
 		return err
 	}
@@ -74,3 +83,26 @@ func (l *lock) Unlock() error {
 		return err
 	}
 }
+
+// Holder implements ingestion.LockHolder.Holder.
+//
+// Note: if the owner of the lock stopped without releasing it, it will
+// continue to be reported until another process acquires the lock.
+func (l *lock) Holder(_ context.Context) (string, error) {
+	lock, err := l.client.Get(l.key)
+	if err != nil {
+		return "", err
+	}
+
+	return lock.OwnerName(), nil
+}
+
+// ownerName returns a (unique) lock owner name that identifies the host.
+func ownerName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%x", hostname, time.Now().UnixNano())
+}
@@ -0,0 +1,445 @@
+// Package fanout allows a single ingestion pass to feed multiple sinks.
+//
+// The FanOut is used as the history.Writer of an ingestor. Entries are written
+// to the primary history store, which remains the source of truth. Each sink
+// is then fed independently from a Source, up to the block most recently
+// committed by the ingestor.
+//
+// Every sink has its own commit pointer and retry policy. A slow or failing
+// sink lags behind without stalling ingestion or the other sinks, and catches
+// up from the Source once it recovers. Solana sinks catch up from the block
+// ordered history of the primary store (see NewSolanaSource). History stores
+// do not keep a block ordered history of Stellar entries, so Stellar sinks
+// catch up by loading the ledgers they are missing (see NewStellarSource).
+package fanout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kinecosystem/agora-common/retry"
+	"github.com/kinecosystem/agora-common/retry/backoff"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const (
+	defaultBatchSize    = 1024
+	defaultPollInterval = 5 * time.Second
+)
+
+var (
+	sinkLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "agora",
+		Name:      "history_fanout_sink_lag",
+		Help:      "The number of blocks between a sink and the ingestor it is fed by",
+	}, []string{"sink"})
+	sinkFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "history_fanout_sink_failures",
+		Help:      "The number of times a sink failed to process a batch of entries",
+	}, []string{"sink"})
+)
+
+func init() {
+	if err := registerMetrics(); err != nil {
+		logrus.WithError(err).Error("failed to register fanout metrics")
+	}
+}
+
+// Sink is a consumer of ingested entries.
+type Sink struct {
+	// Name uniquely identifies the sink. It is used as the ingestor name
+	// when committing the sink's progress, and so must not collide with the
+	// name of any ingestor.
+	Name string
+
+	// Writer receives the entries. Entries may be written more than once
+	// (for example, if a batch is retried), so writes must be idempotent.
+	Writer history.Writer
+
+	// Retry is the retry policy used when writing a batch of entries to the
+	// sink. Once exhausted, the sink resumes from its last commit after the
+	// poll interval.
+	//
+	// If none is provided, a batch is attempted up to 5 times, with an
+	// exponential backoff.
+	Retry []retry.Strategy
+
+	// StartFromLatest specifies that a sink without a commit is initialized
+	// to the latest commit of the ingestor, rather than fed from the start
+	// of history.
+	StartFromLatest bool
+}
+
+// Option configures a FanOut.
+type Option func(*FanOut)
+
+// WithBatchSize specifies the maximum number of entries loaded from the
+// source at a time.
+//
+// If none is provided, 1024 is used.
+func WithBatchSize(size int) Option {
+	return func(f *FanOut) {
+		if size > 0 {
+			f.batchSize = size
+		}
+	}
+}
+
+// WithPollInterval specifies how often sinks check for newly committed blocks,
+// in addition to being notified of writes.
+//
+// If none is provided, 5 seconds is used.
+func WithPollInterval(interval time.Duration) Option {
+	return func(f *FanOut) {
+		if interval > 0 {
+			f.pollInterval = interval
+		}
+	}
+}
+
+// WithController registers the sinks with the controller while the FanOut is
+// running, so that they can be listed, paused, resumed and rewound via the
+// controller (and its admin server), using the sink name.
+//
+// Sinks share the lock of the FanOut, and so pausing a sink does not release
+// the lock; the other sinks continue to be fed.
+func WithController(ctrl *ingestion.Controller) Option {
+	return func(f *FanOut) {
+		f.ctrl = ctrl
+	}
+}
+
+// FanOut is a history.ReaderWriter that feeds the entries written by an
+// ingestor to a set of sinks.
+//
+// Reads are served by the primary store, and Prune is forwarded to it (if it
+// implements history.Pruner), so that ingestors can prune orphaned entries.
+// Sinks are not notified of pruned entries, so entries that were fed to a
+// sink before they were pruned remain in the sink.
+type FanOut struct {
+	history.ReaderWriter
+
+	log       *logrus.Entry
+	source    Source
+	committer ingestion.Committer
+	ingestor  string
+	sinks     []*sink
+	ctrl      *ingestion.Controller
+
+	batchSize    int
+	pollInterval time.Duration
+}
+
+type sink struct {
+	Sink
+	notify chan struct{}
+
+	// reg is set while the sink is registered with a controller.
+	reg *ingestion.Registration
+}
+
+// New returns a FanOut that writes to the primary store, and feeds the sinks
+// from the source with the blocks committed by the named ingestor.
+func New(primary history.ReaderWriter, source Source, committer ingestion.Committer, ingestor string, sinks []Sink, opts ...Option) *FanOut {
+	f := &FanOut{
+		ReaderWriter: primary,
+		log:          logrus.StandardLogger().WithField("type", "transaction/history/ingestion/fanout"),
+		source:       source,
+		committer:    committer,
+		ingestor:     ingestor,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+	}
+	for _, o := range opts {
+		o(f)
+	}
+
+	for _, s := range sinks {
+		if len(s.Retry) == 0 {
+			s.Retry = []retry.Strategy{
+				retry.Limit(5),
+				retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 30*time.Second, 0.1),
+			}
+		}
+
+		f.sinks = append(f.sinks, &sink{
+			Sink:   s,
+			notify: make(chan struct{}, 1),
+		})
+	}
+
+	return f
+}
+
+// Write implements history.Writer.Write.
+//
+// The entry is only written to the primary store. Sinks are notified, but
+// only receive the entry once the block containing it has been committed.
+func (f *FanOut) Write(ctx context.Context, e *model.Entry) error {
+	if err := f.ReaderWriter.Write(ctx, e); err != nil {
+		return err
+	}
+
+	for _, s := range f.sinks {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Prune implements history.Pruner.Prune.
+//
+// The entry is only pruned from the primary store. history.ErrNotSupported is
+// returned if the primary store does not implement history.Pruner.
+func (f *FanOut) Prune(ctx context.Context, e *model.Entry) error {
+	pruner, ok := f.ReaderWriter.(history.Pruner)
+	if !ok {
+		return history.ErrNotSupported
+	}
+
+	return pruner.Prune(ctx, e)
+}
+
+// Run feeds the sinks until the context is cancelled. Sinks are only fed
+// once the lock has been acquired.
+func (f *FanOut) Run(ctx context.Context, l ingestion.DistributedLock) error {
+	_, err := retry.Retry(
+		func() error {
+			return l.Lock(ctx)
+		},
+		retry.NonRetriableErrors(context.Canceled),
+		retry.BackoffWithJitter(backoff.Constant(5*time.Second), 5*time.Second, 0.1),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := l.Unlock(); err != nil {
+			f.log.WithError(err).Warn("failed to release lock")
+		}
+	}()
+
+	// Sink commits are fenced in the same way as ingestion commits (see
+	// ingestion.Fencer).
+	if fencer, ok := l.(ingestion.Fencer); ok {
+		if token, held := fencer.Token(); held {
+			ctx = ingestion.WithFencingToken(ctx, token)
+		}
+	}
+
+	if f.ctrl != nil {
+		for i, s := range f.sinks {
+			reg, err := f.ctrl.Register(s.Name, l, f.committer, &sinkLagReporter{f: f, s: s})
+			if err != nil {
+				for _, registered := range f.sinks[:i] {
+					registered.reg.Close()
+					registered.reg = nil
+				}
+				return errors.Wrapf(err, "failed to register sink '%s'", s.Name)
+			}
+
+			reg.SetLockHeld(true)
+			s.reg = reg
+		}
+		defer func() {
+			for _, s := range f.sinks {
+				s.reg.Close()
+				s.reg = nil
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range f.sinks {
+		wg.Add(1)
+		go func(s *sink) {
+			defer wg.Done()
+			f.runSink(ctx, s)
+		}(s)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// runSink feeds the sink until the context is cancelled.
+func (f *FanOut) runSink(ctx context.Context, s *sink) {
+	log := f.log.WithField("sink", s.Name)
+
+	for {
+		attemptCtx, done := ctx, func() {}
+		if s.reg != nil {
+			if err := s.reg.WaitUntilResumed(ctx); err != nil {
+				return
+			}
+
+			// The attempt is cancelled if the sink is paused or rewound.
+			attemptCtx, done = s.reg.StartAttempt(ctx)
+		}
+
+		err := f.catchUp(attemptCtx, s)
+		done()
+
+		if attemptCtx.Err() != nil && ctx.Err() == nil {
+			log.Info("sink interrupted, restarting")
+			continue
+		} else if err != nil && err != context.Canceled {
+			sinkFailures.WithLabelValues(s.Name).Inc()
+			log.WithError(err).Warn("failed to feed sink, will retry")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
+
+// catchUp feeds the sink all entries between its last commit, and the latest
+// commit of the ingestor.
+func (f *FanOut) catchUp(ctx context.Context, s *sink) error {
+	latest, err := f.committer.Latest(ctx, f.ingestor)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest ingestor commit")
+	}
+	maxBlock, err := f.source.Block(latest)
+	if err != nil {
+		return errors.Wrap(err, "invalid ingestor commit")
+	}
+
+	// A new sink is fed from the start of history, unless it starts from the
+	// latest commit. The sink's commit can also be initialized (or reset)
+	// using the committer.
+	committed, err := f.committer.Latest(ctx, s.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest sink commit")
+	}
+	if committed == nil && s.StartFromLatest && latest != nil {
+		ptr := f.source.Pointer(maxBlock)
+		if err := f.committer.Reset(ctx, s.Name, nil, ptr); err != nil {
+			return errors.Wrapf(err, "failed to initialize sink commit (%x)", ptr)
+		}
+
+		f.log.WithField("sink", s.Name).WithField("block", maxBlock).Info("initialized sink from latest commit")
+		return nil
+	}
+	block, err := f.source.Block(committed)
+	if err != nil {
+		return errors.Wrap(err, "invalid sink commit")
+	}
+
+	from := block + 1
+	if committed == nil {
+		from = 0
+	}
+
+	for maxBlock > 0 && from <= maxBlock {
+		sinkLag.WithLabelValues(s.Name).Set(float64(maxBlock - from + 1))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		entries, end, err := f.source.Entries(ctx, from, maxBlock, f.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to load entries")
+		}
+
+		_, err = retry.Retry(
+			func() error {
+				for _, e := range entries {
+					if err := s.Writer.Write(ctx, e); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			append([]retry.Strategy{retry.NonRetriableErrors(context.Canceled)}, s.Retry...)...,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to write to sink")
+		}
+
+		ptr := f.source.Pointer(end)
+		if err := f.committer.Commit(ctx, s.Name, committed, ptr); err != nil {
+			return errors.Wrapf(err, "failed to commit block (%x, %x)", committed, ptr)
+		}
+
+		if s.reg != nil {
+			s.reg.Committed(time.Now())
+		}
+
+		committed = ptr
+		from = end + 1
+	}
+
+	sinkLag.WithLabelValues(s.Name).Set(0)
+	return nil
+}
+
+// sinkLagReporter reports the number of blocks between a sink and the
+// ingestor it is fed by.
+type sinkLagReporter struct {
+	f *FanOut
+	s *sink
+}
+
+// Lag implements ingestion.LagReporter.Lag.
+func (r *sinkLagReporter) Lag(ctx context.Context, latest ingestion.Pointer) (uint64, error) {
+	ingestorLatest, err := r.f.committer.Latest(ctx, r.f.ingestor)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get latest ingestor commit")
+	}
+	maxBlock, err := r.f.source.Block(ingestorLatest)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid ingestor commit")
+	}
+
+	if latest == nil {
+		return maxBlock + 1, nil
+	}
+	block, err := r.f.source.Block(latest)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid sink commit")
+	}
+	if block >= maxBlock {
+		return 0, nil
+	}
+
+	return maxBlock - block, nil
+}
+
+func registerMetrics() error {
+	if err := prometheus.Register(sinkLag); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			sinkLag = e.ExistingCollector.(*prometheus.GaugeVec)
+		} else {
+			return errors.Wrap(err, "failed to register sink lag gauge")
+		}
+	}
+
+	if err := prometheus.Register(sinkFailures); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			sinkFailures = e.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			return errors.Wrap(err, "failed to register sink failures counter")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,422 @@
+package fanout
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kinecosystem/agora-common/retry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+type testEnv struct {
+	primary   *historymemory.RW
+	committer ingestion.Committer
+	healthy   *historymemory.RW
+	flaky     *flakyWriter
+	fanOut    *FanOut
+}
+
+func setup(t *testing.T) (env testEnv) {
+	env.primary = historymemory.New()
+	env.committer = memory.New()
+	env.healthy = historymemory.New()
+	env.flaky = &flakyWriter{RW: historymemory.New()}
+
+	env.fanOut = New(
+		env.primary,
+		NewSolanaSource(env.primary),
+		env.committer,
+		"ingestor",
+		[]Sink{
+			{Name: "healthy", Writer: env.healthy},
+			{Name: "flaky", Writer: env.flaky, Retry: []retry.Strategy{retry.Limit(1)}},
+		},
+		WithBatchSize(3),
+		WithPollInterval(10*time.Millisecond),
+	)
+	return env
+}
+
+// ingest writes n entries for each slot, committing each slot as a block.
+func (env testEnv) ingest(t *testing.T, n int, slots ...uint64) {
+	sender := testutil.GenerateSolanaKeypair(t)
+
+	for _, slot := range slots {
+		for i := 0; i < n; i++ {
+			e, _ := historytestutil.GenerateSolanaEntry(t, slot, true, sender, []ed25519.PublicKey{testutil.GenerateSolanaKeys(t, 1)[0]}, nil, nil)
+			require.NoError(t, env.fanOut.Write(context.Background(), e))
+		}
+
+		latest, err := env.committer.Latest(context.Background(), "ingestor")
+		require.NoError(t, err)
+		require.NoError(t, env.committer.Commit(context.Background(), "ingestor", latest, solanaingestor.PointerFromSlot(slot)))
+	}
+}
+
+func (env testEnv) assertFed(t *testing.T, name string, rw *historymemory.RW, slot uint64) {
+	require.Eventually(t, func() bool {
+		latest, err := env.committer.Latest(context.Background(), name)
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(slot), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	expected, err := env.primary.GetTransactions(context.Background(), 0, slot, 1000)
+	require.NoError(t, err)
+	actual, err := rw.GetTransactions(context.Background(), 0, slot, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestFanOut(t *testing.T) {
+	env := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env.flaky.setFailing(true)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- env.fanOut.Run(ctx, &noopLock{})
+	}()
+
+	env.ingest(t, 2, 1, 2, 3, 5, 8)
+
+	// Slots larger than the batch size are loaded in full.
+	env.ingest(t, 7, 13)
+	env.assertFed(t, "healthy", env.healthy, 13)
+
+	// The failing sink does not hold up the others.
+	env.ingest(t, 1, 21, 34)
+	env.assertFed(t, "healthy", env.healthy, 34)
+
+	latest, err := env.committer.Latest(ctx, "flaky")
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	// Once recovered, the lagging sink catches up from the primary store.
+	env.flaky.setFailing(false)
+	env.assertFed(t, "flaky", env.flaky.RW, 34)
+
+	// Entries in blocks that have not been committed are not fed to the sinks.
+	sender := testutil.GenerateSolanaKeypair(t)
+	e, _ := historytestutil.GenerateSolanaEntry(t, 40, true, sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+	require.NoError(t, env.fanOut.Write(ctx, e))
+	time.Sleep(100 * time.Millisecond)
+
+	for _, rw := range []*historymemory.RW{env.healthy, env.flaky.RW} {
+		entries, err := rw.GetTransactions(ctx, 40, 40, 10)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+
+	cancel()
+	select {
+	case err := <-doneCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed waiting for Run to stop")
+	}
+}
+
+func TestFanOut_ResumeFromCommit(t *testing.T) {
+	env := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The sink has already been fed up to slot 3, so only subsequent
+	// blocks should be written to it.
+	env.ingest(t, 2, 1, 2, 3, 4, 5)
+	require.NoError(t, env.committer.Commit(ctx, "healthy", nil, solanaingestor.PointerFromSlot(3)))
+
+	go func() {
+		_ = env.fanOut.Run(ctx, &noopLock{})
+	}()
+
+	require.Eventually(t, func() bool {
+		latest, err := env.committer.Latest(ctx, "healthy")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(5), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entries, err := env.healthy.GetTransactions(ctx, 0, 10, 100)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	for _, e := range entries {
+		assert.True(t, e.GetSolana().Slot > 3)
+	}
+}
+
+func TestFanOut_StartFromLatest(t *testing.T) {
+	env := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env.ingest(t, 2, 1, 2, 3)
+
+	sink := historymemory.New()
+	f := New(
+		env.primary,
+		NewSolanaSource(env.primary),
+		env.committer,
+		"ingestor",
+		[]Sink{{Name: "latest", Writer: sink, StartFromLatest: true}},
+		WithPollInterval(10*time.Millisecond),
+	)
+	go func() {
+		_ = f.Run(ctx, &noopLock{})
+	}()
+
+	// The existing history is skipped, and only subsequent blocks are fed.
+	require.Eventually(t, func() bool {
+		latest, err := env.committer.Latest(ctx, "latest")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(3), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	env.ingest(t, 2, 4)
+	require.Eventually(t, func() bool {
+		latest, err := env.committer.Latest(ctx, "latest")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(4), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entries, err := sink.GetTransactions(ctx, 0, 10, 100)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, e := range entries {
+		assert.EqualValues(t, 4, e.GetSolana().Slot)
+	}
+}
+
+func TestFanOut_Controller(t *testing.T) {
+	env := setup(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctrl := ingestion.NewController(ingestion.WithPausePollInterval(10 * time.Millisecond))
+	sink := &recordingWriter{}
+	f := New(
+		env.primary,
+		NewSolanaSource(env.primary),
+		env.committer,
+		"ingestor",
+		[]Sink{{Name: "recorded", Writer: sink}},
+		WithPollInterval(10*time.Millisecond),
+		WithController(ctrl),
+	)
+
+	env.ingest(t, 1, 1, 2, 3)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- f.Run(ctx, &noopLock{})
+	}()
+
+	latest := func() ingestion.Pointer {
+		p, err := env.committer.Latest(ctx, "recorded")
+		require.NoError(t, err)
+		return p
+	}
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(3), latest())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	statuses, err := ctrl.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "recorded", statuses[0].Name)
+	assert.Equal(t, solanaingestor.PointerFromSlot(3), statuses[0].Latest)
+	assert.True(t, statuses[0].LockHeld)
+	assert.EqualValues(t, 0, statuses[0].Lag)
+	assert.False(t, statuses[0].LastCommit.IsZero())
+
+	// A paused sink is not fed, and reports its lag.
+	require.NoError(t, ctrl.Pause(ctx, "recorded"))
+	env.ingest(t, 1, 4)
+	time.Sleep(100 * time.Millisecond)
+
+	s, err := ctrl.Status(ctx, "recorded")
+	require.NoError(t, err)
+	assert.True(t, s.Paused)
+	assert.Equal(t, solanaingestor.PointerFromSlot(3), s.Latest)
+	assert.EqualValues(t, 1, s.Lag)
+	assert.Len(t, sink.get(), 3)
+
+	// Once rewound and resumed, the sink is fed again from the rewound block.
+	previous, err := ctrl.Rewind(ctx, "recorded", solanaingestor.PointerFromSlot(1))
+	require.NoError(t, err)
+	assert.Equal(t, solanaingestor.PointerFromSlot(3), previous)
+
+	require.NoError(t, ctrl.Resume(ctx, "recorded"))
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(4), latest())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var slots []uint64
+	for _, e := range sink.get() {
+		slots = append(slots, e.GetSolana().Slot)
+	}
+	assert.Equal(t, []uint64{1, 2, 3, 2, 3, 4}, slots)
+
+	cancel()
+	select {
+	case err := <-doneCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed waiting for Run to stop")
+	}
+
+	// Sinks are unregistered once the FanOut stops.
+	statuses, err = ctrl.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+}
+
+func TestFanOut_Stellar(t *testing.T) {
+	committer := memory.New()
+	loader := make(ledgerLoader)
+	sink := &recordingWriter{}
+
+	f := New(
+		historymemory.New(),
+		NewStellarSource(model.KinVersion_KIN3, loader),
+		committer,
+		"ingestor",
+		[]Sink{{Name: "sink", Writer: sink}},
+		WithBatchSize(3),
+		WithPollInterval(10*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, sender := testutil.GenerateAccountID(t)
+	var expected []*model.Entry
+	for i, ledger := range []uint32{2, 2, 3, 5, 5, 5, 5, 8} {
+		e, _ := historytestutil.GenerateStellarEntry(t, uint64(ledger), i, sender, testutil.GenerateAccountIDs(t, 1), nil, nil)
+		require.NoError(t, f.Write(ctx, e))
+		loader[ledger] = append(loader[ledger], e)
+		expected = append(expected, e)
+	}
+
+	// The sink resumes from its existing commit, and is only fed committed
+	// ledgers.
+	require.NoError(t, committer.Commit(ctx, "sink", nil, stellaringestor.PointerFromSequence(model.KinVersion_KIN3, 2)))
+	require.NoError(t, committer.Commit(ctx, "ingestor", nil, stellaringestor.PointerFromSequence(model.KinVersion_KIN3, 7)))
+
+	go func() {
+		_ = f.Run(ctx, &noopLock{})
+	}()
+
+	require.Eventually(t, func() bool {
+		latest, err := committer.Latest(ctx, "sink")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(stellaringestor.PointerFromSequence(model.KinVersion_KIN3, 7), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, expected[2:7], sink.get())
+}
+
+func TestFanOut_PrimaryFailure(t *testing.T) {
+	primary := &flakyWriter{RW: historymemory.New()}
+	primary.setFailing(true)
+
+	f := New(primary, NewSolanaSource(primary), memory.New(), "ingestor", nil)
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	e, _ := historytestutil.GenerateSolanaEntry(t, 1, true, sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+	assert.Error(t, f.Write(context.Background(), e))
+}
+
+func TestFanOut_Prune(t *testing.T) {
+	env := setup(t)
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	e, txID := historytestutil.GenerateSolanaEntry(t, 1, true, sender, testutil.GenerateSolanaKeys(t, 1), nil, nil)
+	require.NoError(t, env.fanOut.Write(context.Background(), e))
+
+	// Reads are served by the primary.
+	actual, err := env.fanOut.GetTransaction(context.Background(), txID)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(e, actual))
+
+	require.NoError(t, env.fanOut.Prune(context.Background(), e))
+	_, err = env.primary.GetTransaction(context.Background(), txID)
+	assert.Equal(t, history.ErrNotFound, err)
+
+	// Primary stores that do not support pruning are reported as such.
+	f := New(struct{ history.ReaderWriter }{env.primary}, NewSolanaSource(env.primary), env.committer, "ingestor", nil)
+	assert.Equal(t, history.ErrNotSupported, f.Prune(context.Background(), e))
+}
+
+type flakyWriter struct {
+	*historymemory.RW
+
+	mu      sync.Mutex
+	failing bool
+}
+
+func (w *flakyWriter) setFailing(failing bool) {
+	w.mu.Lock()
+	w.failing = failing
+	w.mu.Unlock()
+}
+
+func (w *flakyWriter) Write(ctx context.Context, e *model.Entry) error {
+	w.mu.Lock()
+	failing := w.failing
+	w.mu.Unlock()
+
+	if failing {
+		return errors.New("unavailable")
+	}
+	return w.RW.Write(ctx, e)
+}
+
+type ledgerLoader map[uint32][]*model.Entry
+
+func (l ledgerLoader) LoadLedger(sequence uint32) ([]*model.Entry, error) {
+	return l[sequence], nil
+}
+
+type recordingWriter struct {
+	mu      sync.Mutex
+	entries []*model.Entry
+}
+
+func (w *recordingWriter) Write(_ context.Context, e *model.Entry) error {
+	w.mu.Lock()
+	w.entries = append(w.entries, e)
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *recordingWriter) get() []*model.Entry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]*model.Entry(nil), w.entries...)
+}
+
+type noopLock struct{}
+
+func (noopLock) Lock(_ context.Context) error { return nil }
+func (noopLock) Unlock() error                { return nil }
@@ -0,0 +1,121 @@
+package fanout
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// Source provides the committed entries of an ingestor, in block order, for
+// sinks to catch up from.
+type Source interface {
+	// Block returns the block referenced by a pointer of the ingestor. A nil
+	// pointer references block 0.
+	Block(p ingestion.Pointer) (uint64, error)
+
+	// Pointer returns the pointer that references the block.
+	Pointer(block uint64) ingestion.Pointer
+
+	// Entries returns the entries of the blocks in [from, to], along with the
+	// last block whose entries were all returned. Entries may be returned
+	// beyond the limit in order to return at least one complete block.
+	Entries(ctx context.Context, from, to uint64, limit int) (entries []*model.Entry, end uint64, err error)
+}
+
+type solanaSource struct {
+	reader history.Reader
+}
+
+// NewSolanaSource returns a Source for a Solana ingestor, which loads entries
+// from the block ordered history of the reader.
+func NewSolanaSource(reader history.Reader) Source {
+	return &solanaSource{
+		reader: reader,
+	}
+}
+
+// Block implements Source.Block.
+func (s *solanaSource) Block(p ingestion.Pointer) (uint64, error) {
+	return solanaingestor.SlotFromPointer(p)
+}
+
+// Pointer implements Source.Pointer.
+func (s *solanaSource) Pointer(block uint64) ingestion.Pointer {
+	return solanaingestor.PointerFromSlot(block)
+}
+
+// Entries implements Source.Entries.
+func (s *solanaSource) Entries(ctx context.Context, from, to uint64, limit int) ([]*model.Entry, uint64, error) {
+	entries, next, err := history.GetCompleteBlocks(ctx, s.reader, from, to, limit)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load transactions")
+	}
+
+	return entries, next - 1, nil
+}
+
+type stellarSource struct {
+	version model.KinVersion
+	loader  stellaringestor.LedgerLoader
+}
+
+// NewStellarSource returns a Source for a Stellar ingestor.
+//
+// History stores do not keep a block ordered history of Stellar entries, so
+// entries are loaded one ledger at a time using the loader. As a result, each
+// Stellar sink reads the ledgers from Horizon a second time (after the
+// ingestor). Reading them from the store instead requires a block ordered
+// history of Stellar entries.
+func NewStellarSource(version model.KinVersion, loader stellaringestor.LedgerLoader) Source {
+	return &stellarSource{
+		version: version,
+		loader:  loader,
+	}
+}
+
+// Block implements Source.Block.
+func (s *stellarSource) Block(p ingestion.Pointer) (uint64, error) {
+	seq, err := stellaringestor.SequenceFromPointer(p)
+	return uint64(seq), err
+}
+
+// Pointer implements Source.Pointer.
+func (s *stellarSource) Pointer(block uint64) ingestion.Pointer {
+	return stellaringestor.PointerFromSequence(s.version, uint32(block))
+}
+
+// Entries implements Source.Entries.
+func (s *stellarSource) Entries(ctx context.Context, from, to uint64, limit int) (entries []*model.Entry, end uint64, err error) {
+	if to > math.MaxUint32 {
+		to = math.MaxUint32
+	}
+
+	// Ledger 1 is the genesis ledger, which never contains transactions.
+	if from < 2 {
+		from = 2
+	}
+	end = from - 1
+
+	for seq := from; seq <= to && len(entries) < limit; seq++ {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		ledgerEntries, err := s.loader.LoadLedger(uint32(seq))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, ledgerEntries...)
+		end = seq
+	}
+
+	return entries, end, nil
+}
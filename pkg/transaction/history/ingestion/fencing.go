@@ -0,0 +1,18 @@
+package ingestion
+
+import "context"
+
+type fencingTokenKey struct{}
+
+// WithFencingToken returns a context that carries the fencing token of the
+// lock lease under which commits are made (see Fencer).
+func WithFencingToken(ctx context.Context, token uint64) context.Context {
+	return context.WithValue(ctx, fencingTokenKey{}, token)
+}
+
+// FencingTokenFromContext returns the fencing token carried by the context,
+// if any.
+func FencingTokenFromContext(ctx context.Context) (uint64, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(uint64)
+	return token, ok
+}
@@ -0,0 +1,375 @@
+// Package file provides an ingestion.Ingestor that replays blocks recorded to
+// a local directory, allowing ingestion dependent components to be run
+// against real (recorded) traffic without a live blockchain.
+//
+// Each block is stored in its own file, named by the hex encoded block pointer:
+//
+//	<hex pointer>.json a JSON array of (jsonpb encoded) entries
+//	<hex pointer>.pb   a sequence of length prefixed (marshalled) entries
+//
+// Blocks are replayed in pointer order, where the parent of each block is the
+// previously recorded block. As a result, empty blocks must also be recorded
+// to be replayed.
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// Format is the encoding of a recorded block.
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatProto
+)
+
+// ParseFormat parses a format from its extension name (json or pb).
+func ParseFormat(s string) (Format, error) {
+	for f, ext := range extensions {
+		if s == ext {
+			return f, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown format: %s", s)
+}
+
+var extensions = map[Format]string{
+	FormatJSON:  "json",
+	FormatProto: "pb",
+}
+
+// WriteBlock writes the entries of a block to the directory, replacing any
+// previously recorded entries for the block.
+func WriteBlock(dir string, format Format, block ingestion.Pointer, entries []*model.Entry) error {
+	ext, ok := extensions[format]
+	if !ok {
+		return errors.Errorf("unknown format: %d", format)
+	}
+	if len(block) == 0 {
+		return errors.New("block pointer must be set")
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		if err := encodeJSON(&buf, entries); err != nil {
+			return err
+		}
+	case FormatProto:
+		if err := encodeProto(&buf, entries); err != nil {
+			return err
+		}
+	}
+
+	// Blocks are written to a temporary file and renamed, so an ingestor
+	// never observes a partially written block.
+	tmp, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write block")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write block")
+	}
+
+	path := filepath.Join(dir, hex.EncodeToString(block)+"."+ext)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to write block")
+	}
+
+	return nil
+}
+
+// ReadBlock reads the entries of a recorded block.
+func ReadBlock(dir string, block ingestion.Pointer) ([]*model.Entry, error) {
+	blocks, err := listBlocks(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	i := sort.Search(len(blocks), func(i int) bool {
+		return bytes.Compare(blocks[i].ptr, block) >= 0
+	})
+	if i == len(blocks) || !bytes.Equal(blocks[i].ptr, block) {
+		return nil, errors.Errorf("block not found: %x", block)
+	}
+
+	return blocks[i].read()
+}
+
+type recordedBlock struct {
+	ptr    ingestion.Pointer
+	path   string
+	format Format
+}
+
+func (b recordedBlock) read() ([]*model.Entry, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open block %x", b.ptr)
+	}
+	defer f.Close()
+
+	var entries []*model.Entry
+	switch b.format {
+	case FormatJSON:
+		entries, err = decodeJSON(f)
+	case FormatProto:
+		entries, err = decodeProto(bufio.NewReader(f))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode block %x", b.ptr)
+	}
+
+	return entries, nil
+}
+
+// listBlocks returns the blocks recorded in the directory, in pointer order.
+func listBlocks(dir string) ([]recordedBlock, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read block directory")
+	}
+
+	var blocks []recordedBlock
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		ext := filepath.Ext(f.Name())
+		format, err := ParseFormat(strings.TrimPrefix(ext, "."))
+		if err != nil {
+			continue
+		}
+
+		ptr, err := hex.DecodeString(strings.TrimSuffix(f.Name(), ext))
+		if err != nil || len(ptr) == 0 {
+			continue
+		}
+
+		blocks = append(blocks, recordedBlock{
+			ptr:    ptr,
+			path:   filepath.Join(dir, f.Name()),
+			format: format,
+		})
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return bytes.Compare(blocks[i].ptr, blocks[j].ptr) < 0
+	})
+	for i := 1; i < len(blocks); i++ {
+		if bytes.Equal(blocks[i-1].ptr, blocks[i].ptr) {
+			return nil, errors.Errorf("block %x recorded in multiple formats", blocks[i].ptr)
+		}
+	}
+
+	return blocks, nil
+}
+
+func encodeJSON(w io.Writer, entries []*model.Entry) error {
+	marshaler := &jsonpb.Marshaler{}
+
+	raw := make([]json.RawMessage, len(entries))
+	for i, e := range entries {
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, e); err != nil {
+			return errors.Wrap(err, "failed to marshal entry")
+		}
+		raw[i] = buf.Bytes()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(raw); err != nil {
+		return errors.Wrap(err, "failed to encode entries")
+	}
+
+	return nil
+}
+
+func decodeJSON(r io.Reader) ([]*model.Entry, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode entries")
+	}
+
+	entries := make([]*model.Entry, len(raw))
+	for i := range raw {
+		entries[i] = &model.Entry{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(raw[i]), entries[i]); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal entry")
+		}
+	}
+
+	return entries, nil
+}
+
+func encodeProto(w io.Writer, entries []*model.Entry) error {
+	var lenBuf [binary.MaxVarintLen64]byte
+	for _, e := range entries {
+		b, err := proto.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal entry")
+		}
+
+		n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+		if _, err := w.Write(lenBuf[:n]); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeProto(r *bufio.Reader) ([]*model.Entry, error) {
+	var entries []*model.Entry
+	for {
+		n, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to read entry length")
+		}
+
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, errors.Wrap(err, "failed to read entry")
+		}
+
+		e := &model.Entry{}
+		if err := proto.Unmarshal(b, e); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal entry")
+		}
+		entries = append(entries, e)
+	}
+}
+
+type ingestor struct {
+	log  *logrus.Entry
+	name string
+	dir  string
+}
+
+// New returns an ingestion.Ingestor that replays the blocks recorded in the
+// directory.
+//
+// Once all blocks after the parent have been replayed, the ingestor idles
+// until the context is cancelled.
+func New(name, dir string) ingestion.Ingestor {
+	return &ingestor{
+		log:  logrus.StandardLogger().WithField("type", "transaction/history/ingestion/file"),
+		name: name,
+		dir:  dir,
+	}
+}
+
+// Name implements ingestion.Ingestor.Name.
+func (i *ingestor) Name() string {
+	return i.name
+}
+
+// Lag implements ingestion.LagReporter.Lag.
+func (i *ingestor) Lag(_ context.Context, p ingestion.Pointer) (uint64, error) {
+	blocks, err := listBlocks(i.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(blocks) - firstAfter(blocks, p)), nil
+}
+
+// Ingest implements ingestion.Ingestor.Ingest.
+func (i *ingestor) Ingest(ctx context.Context, w history.Writer, parent ingestion.Pointer) (ingestion.ResultQueue, error) {
+	blocks, err := listBlocks(i.dir)
+	if err != nil {
+		return nil, err
+	}
+	blocks = blocks[firstAfter(blocks, parent):]
+
+	queue := make(chan (<-chan ingestion.Result), 8)
+	go func() {
+		defer close(queue)
+
+		for _, b := range blocks {
+			resultCh := make(chan ingestion.Result, 1)
+			select {
+			case queue <- resultCh:
+			case <-ctx.Done():
+				return
+			}
+
+			result := ingestion.Result{
+				Parent: parent,
+				Block:  b.ptr,
+			}
+			parent = b.ptr
+
+			entries, err := b.read()
+			if err == nil {
+				err = writeEntries(ctx, w, entries)
+			}
+			if err != nil {
+				result.Err = err
+				resultCh <- result
+				close(resultCh)
+				return
+			}
+
+			resultCh <- result
+			close(resultCh)
+		}
+
+		i.log.WithField("blocks", len(blocks)).Info("replay complete")
+		<-ctx.Done()
+	}()
+
+	return queue, nil
+}
+
+// firstAfter returns the index of the first block after p.
+func firstAfter(blocks []recordedBlock, p ingestion.Pointer) int {
+	return sort.Search(len(blocks), func(i int) bool {
+		return bytes.Compare(blocks[i].ptr, p) > 0
+	})
+}
+
+func writeEntries(ctx context.Context, w history.Writer, entries []*model.Entry) error {
+	for _, e := range entries {
+		if err := w.Write(ctx, e); err != nil {
+			return errors.Wrap(err, "failed to write txn")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package file
+
+import (
+	"context"
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+func setup(t *testing.T) (dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "ingestion-file")
+	require.NoError(t, err)
+
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// generateEntries returns n solana entries for the slot.
+func generateEntries(t *testing.T, slot uint64, n int) []*model.Entry {
+	sender := testutil.GenerateSolanaKeypair(t)
+
+	entries := make([]*model.Entry, n)
+	for i := 0; i < n; i++ {
+		entries[i], _ = historytestutil.GenerateSolanaEntry(t, slot, true, sender, []ed25519.PublicKey{testutil.GenerateSolanaKeys(t, 1)[0]}, nil, nil)
+	}
+	return entries
+}
+
+func TestBlock_RoundTrip(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	for _, format := range []Format{FormatJSON, FormatProto} {
+		for slot, n := range []int{0, 1, 3} {
+			block := solanaingestor.PointerFromSlot(uint64(slot))
+			entries := generateEntries(t, uint64(slot), n)
+			require.NoError(t, WriteBlock(dir, format, block, entries))
+
+			actual, err := ReadBlock(dir, block)
+			require.NoError(t, err)
+			require.Len(t, actual, n)
+			for i := range entries {
+				assert.True(t, proto.Equal(entries[i], actual[i]))
+			}
+		}
+
+		// Clear the directory, as a block may only be recorded in one format.
+		require.NoError(t, os.RemoveAll(dir))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+
+	_, err := ReadBlock(dir, solanaingestor.PointerFromSlot(10))
+	assert.Error(t, err)
+	assert.Error(t, WriteBlock(dir, FormatJSON, nil, nil))
+	assert.Error(t, WriteBlock(dir, Format(10), solanaingestor.PointerFromSlot(1), nil))
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	f, err = ParseFormat("pb")
+	require.NoError(t, err)
+	assert.Equal(t, FormatProto, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestIngest(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	// Mixed formats, with gaps and an empty block.
+	slots := []uint64{2, 3, 5, 8, 13}
+	blocks := make(map[uint64][]*model.Entry)
+	for i, slot := range slots {
+		n := 2
+		if slot == 5 {
+			n = 0
+		}
+		blocks[slot] = generateEntries(t, slot, n)
+		require.NoError(t, WriteBlock(dir, Format(i%2), solanaingestor.PointerFromSlot(slot), blocks[slot]))
+	}
+
+	// Unrelated files are ignored.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("recorded blocks"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "zz.json"), []byte("[]"), 0644))
+
+	i := New("test", dir)
+	for _, tc := range []struct {
+		parent ingestion.Pointer
+		slots  []uint64
+	}{
+		{parent: nil, slots: slots},
+		{parent: solanaingestor.PointerFromSlot(3), slots: []uint64{5, 8, 13}},
+		{parent: solanaingestor.PointerFromSlot(6), slots: []uint64{8, 13}},
+		{parent: solanaingestor.PointerFromSlot(13), slots: nil},
+	} {
+		lag, err := i.(ingestion.LagReporter).Lag(context.Background(), tc.parent)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(tc.slots), lag)
+
+		rw := historymemory.New()
+		ctx, cancel := context.WithCancel(context.Background())
+		queue, err := i.Ingest(ctx, rw, tc.parent)
+		require.NoError(t, err)
+
+		parent := tc.parent
+		var expectedEntries []*model.Entry
+		for _, slot := range tc.slots {
+			r := <-(<-queue)
+			require.NoError(t, r.Err)
+			assert.Equal(t, parent, r.Parent)
+			assert.Equal(t, solanaingestor.PointerFromSlot(slot), r.Block)
+
+			parent = r.Block
+			expectedEntries = append(expectedEntries, blocks[slot]...)
+		}
+
+		// The queue remains open until the context is cancelled.
+		select {
+		case <-queue:
+			t.Fatal("unexpected result")
+		case <-time.After(50 * time.Millisecond):
+		}
+		cancel()
+		for range queue {
+		}
+
+		// Entries within a block are ordered by the history store, rather than
+		// the order in which they were written.
+		actual, err := rw.GetTransactions(context.Background(), 0, 100, 1000)
+		require.NoError(t, err)
+		require.Len(t, actual, len(expectedEntries))
+		for _, e := range expectedEntries {
+			txID, err := e.GetTxID()
+			require.NoError(t, err)
+
+			stored, err := rw.GetTransaction(context.Background(), txID)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(e, stored))
+		}
+	}
+}
+
+func TestIngest_Run(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	for slot := uint64(1); slot <= 10; slot++ {
+		require.NoError(t, WriteBlock(dir, FormatProto, solanaingestor.PointerFromSlot(slot), generateEntries(t, slot, 1)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	committer := memory.New()
+	rw := historymemory.New()
+	go func() {
+		_ = ingestion.Run(ctx, &noopLock{}, committer, rw, New("test", dir))
+	}()
+
+	require.Eventually(t, func() bool {
+		latest, err := committer.Latest(ctx, "test")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(solanaingestor.PointerFromSlot(10), latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entries, err := rw.GetTransactions(ctx, 0, 100, 1000)
+	require.NoError(t, err)
+	assert.Len(t, entries, 10)
+}
+
+func TestIngest_Invalid(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	require.NoError(t, WriteBlock(dir, FormatJSON, solanaingestor.PointerFromSlot(1), generateEntries(t, 1, 1)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "040000000000000002.json"), []byte("{"), 0644))
+	require.NoError(t, WriteBlock(dir, FormatJSON, solanaingestor.PointerFromSlot(3), generateEntries(t, 3, 1)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue, err := New("test", dir).Ingest(ctx, historymemory.New(), nil)
+	require.NoError(t, err)
+
+	r := <-(<-queue)
+	require.NoError(t, r.Err)
+	r = <-(<-queue)
+	assert.Error(t, r.Err)
+
+	// The queue is closed after the first failure.
+	_, ok := <-queue
+	assert.False(t, ok)
+
+	// A block recorded in multiple formats is ambiguous.
+	require.NoError(t, WriteBlock(dir, FormatProto, solanaingestor.PointerFromSlot(3), nil))
+	_, err = New("test", dir).Ingest(ctx, historymemory.New(), nil)
+	assert.Error(t, err)
+}
+
+type noopLock struct{}
+
+func (noopLock) Lock(_ context.Context) error { return nil }
+func (noopLock) Unlock() error                { return nil }
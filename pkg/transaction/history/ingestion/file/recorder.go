@@ -0,0 +1,85 @@
+package file
+
+import (
+	"context"
+	"os"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/pkg/errors"
+
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// RecordSolana records the blocks in the (inclusive) slot range [from, to] to
+// the directory, returning the number of blocks recorded.
+//
+// Slots without a block are not recorded. Blocks without any relevant entries
+// are recorded as empty blocks, so that the recorded chain is complete.
+func RecordSolana(ctx context.Context, client solana.Client, loader solanaingestor.BlockLoader, dir string, format Format, from, to uint64) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, errors.Wrap(err, "failed to create block directory")
+	}
+
+	var recorded int
+	for start := from; start <= to; {
+		if err := ctx.Err(); err != nil {
+			return recorded, err
+		}
+
+		slots, err := client.GetConfirmedBlocksWithLimit(start, 1024)
+		if err != nil {
+			return recorded, errors.Wrap(err, "failed to get confirmed blocks")
+		}
+		if len(slots) == 0 {
+			break
+		}
+
+		for _, slot := range slots {
+			if slot > to {
+				return recorded, nil
+			}
+
+			entries, err := loader.LoadBlock(slot)
+			if err != nil {
+				return recorded, errors.Wrapf(err, "failed to load block %d", slot)
+			}
+			if err := WriteBlock(dir, format, solanaingestor.PointerFromSlot(slot), entries); err != nil {
+				return recorded, err
+			}
+
+			recorded++
+			start = slot + 1
+		}
+	}
+
+	return recorded, nil
+}
+
+// RecordStellar records the ledgers in the (inclusive) sequence range
+// [from, to] to the directory, returning the number of ledgers recorded.
+func RecordStellar(ctx context.Context, loader stellaringestor.LedgerLoader, version model.KinVersion, dir string, format Format, from, to uint32) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, errors.Wrap(err, "failed to create block directory")
+	}
+
+	var recorded int
+	for seq := uint64(from); seq <= uint64(to); seq++ {
+		if err := ctx.Err(); err != nil {
+			return recorded, err
+		}
+
+		entries, err := loader.LoadLedger(uint32(seq))
+		if err != nil {
+			return recorded, errors.Wrapf(err, "failed to load ledger %d", seq)
+		}
+		if err := WriteBlock(dir, format, stellaringestor.PointerFromSequence(version, uint32(seq)), entries); err != nil {
+			return recorded, err
+		}
+
+		recorded++
+	}
+
+	return recorded, nil
+}
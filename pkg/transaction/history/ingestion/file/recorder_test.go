@@ -0,0 +1,92 @@
+package file
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+type blockLoader map[uint64][]*model.Entry
+
+func (l blockLoader) LoadBlock(slot uint64) ([]*model.Entry, error) {
+	entries, ok := l[slot]
+	if !ok {
+		return nil, errors.Errorf("unexpected slot: %d", slot)
+	}
+	return entries, nil
+}
+
+type ledgerLoader map[uint32][]*model.Entry
+
+func (l ledgerLoader) LoadLedger(seq uint32) ([]*model.Entry, error) {
+	return l[seq], nil
+}
+
+func TestRecordSolana(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	client := solana.NewMockClient()
+	client.On("GetConfirmedBlocksWithLimit", uint64(10), uint64(1024)).Return([]uint64{10, 12, 15}, nil)
+	client.On("GetConfirmedBlocksWithLimit", uint64(16), uint64(1024)).Return([]uint64{18, 30}, nil)
+
+	loader := blockLoader{
+		10: generateEntries(t, 10, 1),
+		12: nil,
+		15: generateEntries(t, 15, 2),
+		18: generateEntries(t, 18, 1),
+	}
+
+	recorded, err := RecordSolana(context.Background(), client, loader, dir, FormatJSON, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 4, recorded)
+
+	for slot, expected := range loader {
+		actual, err := ReadBlock(dir, solanaingestor.PointerFromSlot(slot))
+		require.NoError(t, err)
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			assert.True(t, proto.Equal(expected[i], actual[i]))
+		}
+	}
+
+	_, err = ReadBlock(dir, solanaingestor.PointerFromSlot(30))
+	assert.Error(t, err)
+
+	// Loader failures are surfaced.
+	delete(loader, 15)
+	_, err = RecordSolana(context.Background(), client, loader, dir, FormatJSON, 10, 20)
+	assert.Error(t, err)
+}
+
+func TestRecordStellar(t *testing.T) {
+	dir, cleanup := setup(t)
+	defer cleanup()
+
+	loader := ledgerLoader{
+		5: generateEntries(t, 5, 2),
+		7: generateEntries(t, 7, 1),
+	}
+
+	recorded, err := RecordStellar(context.Background(), loader, model.KinVersion_KIN3, dir, FormatProto, 5, 8)
+	require.NoError(t, err)
+	assert.Equal(t, 4, recorded)
+
+	for seq := uint32(5); seq <= 8; seq++ {
+		actual, err := ReadBlock(dir, stellaringestor.PointerFromSequence(model.KinVersion_KIN3, seq))
+		require.NoError(t, err)
+		require.Len(t, actual, len(loader[seq]))
+		for i := range loader[seq] {
+			assert.True(t, proto.Equal(loader[seq][i], actual[i]))
+		}
+	}
+}
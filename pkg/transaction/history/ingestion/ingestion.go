@@ -1,6 +1,7 @@
 package ingestion
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
@@ -79,6 +80,43 @@ type DistributedLock interface {
 	Unlock() error
 }
 
+// LockHolder is an optional interface for DistributedLock implementations that
+// can report which process currently holds the lock.
+type LockHolder interface {
+	// Holder returns the owner of the lock, or an empty string if the lock
+	// is not currently held.
+	Holder(ctx context.Context) (string, error)
+}
+
+// Fencer is an optional interface for DistributedLock implementations that
+// provide a fencing token for the current lease. If the lock is a Fencer, the
+// token is provided to the Committer via the context (see WithFencingToken),
+// so that commits from a holder whose lease has expired can be rejected.
+type Fencer interface {
+	// Token returns the fencing token of the current lease. Tokens are
+	// strictly increasing across all holders of the lock. False is returned
+	// if the lock is not held.
+	Token() (uint64, bool)
+}
+
+// LagReporter is an optional interface for Ingestor implementations that can
+// report how far behind the blockchain a pointer is.
+type LagReporter interface {
+	// Lag returns the number of blocks between the specified pointer and the
+	// latest block of the blockchain.
+	Lag(ctx context.Context, p Pointer) (uint64, error)
+}
+
+// ForkResolver is an optional interface for Ingestor implementations that can
+// verify the latest committed block against the blockchain.
+type ForkResolver interface {
+	// Resolve returns the latest block pointer, at or before latest, that is
+	// part of the blockchain. If latest is not part of the blockchain (i.e.
+	// it was orphaned by a fork), the entries of the orphaned blocks are
+	// removed from (or rewritten in) w, if supported.
+	Resolve(ctx context.Context, w history.Writer, latest Pointer) (Pointer, error)
+}
+
 // Committer marks processed blocks as committed, with the following assumptions:
 //
 //   1. The block must be successfully written to history via a history.Writer.
@@ -87,6 +125,11 @@ type DistributedLock interface {
 // Implementations should enforce (2) by ensuring that for each commit C[i], C[i].parent
 // is the same as C[i-1].block (should a previous commit exist), and that C[i].block
 // is greater than C[i].parent (and therefore greater than C[i-1].block).
+//
+// If the context of a Commit or Reset carries a fencing token (see
+// WithFencingToken), implementations must reject it with ErrInvalidCommit if
+// the token is older than the latest token used for the ingestor, and record
+// the token otherwise.
 type Committer interface {
 	// Commit commits the specified block as committed, if 'parent' was the
 	// value of the previously committed block.
@@ -98,6 +141,27 @@ type Committer interface {
 	//
 	// Nil is returned with a nil error if no previous commit exists.
 	Latest(ctx context.Context, ingestor string) (Pointer, error)
+
+	// Reset sets the latest committed block pointer to block, if 'latest' is
+	// the value of the currently committed block. Unlike Commit, block may be
+	// older than the currently committed block, allowing an ingestor to be
+	// rewound. However, block may not be newer than the currently committed
+	// block, as that would skip blocks that were never written.
+	//
+	// ErrInvalidCommit is returned if the currently committed block does not
+	// match 'latest', or if block is newer than 'latest'.
+	Reset(ctx context.Context, ingestor string, latest, block Pointer) error
+}
+
+// Pauser is an optional interface for Committer implementations that can
+// store whether or not an ingestor is paused. Since the state is shared,
+// pausing an ingestor applies to every process ingesting with the Committer.
+type Pauser interface {
+	// SetPaused sets whether or not the specified ingestor is paused.
+	SetPaused(ctx context.Context, ingestor string, paused bool) error
+
+	// Paused returns whether or not the specified ingestor is paused.
+	Paused(ctx context.Context, ingestor string) (bool, error)
 }
 
 // GetHistoryIngestorName returns the history ingestor name for the
@@ -117,8 +181,17 @@ func GetEventsIngestorName(version model.KinVersion) string {
 //
 // The ingestion will only occur when the lock has been acquired. The
 // specified lock can be scoped to the individual ingestor, or for a set
-// of ingestors.
+// of ingestors. If the Committer is a Pauser, ingestion will not occur
+// while the ingestor is paused.
 func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer, i Ingestor) error {
+	lp := newLoop(i.Name(), l, c, defaultPausePollInterval)
+	lp.ingestor = i
+	return run(ctx, lp, w)
+}
+
+func run(ctx context.Context, lp *loop, w history.Writer) error {
+	l, c, i := lp.lock, lp.committer, lp.ingestor
+
 	log := logrus.StandardLogger().WithFields(logrus.Fields{
 		"type":     "transaction/history/ingestion",
 		"method":   "Run",
@@ -134,6 +207,10 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 		default:
 		}
 
+		if err := lp.waitUntilResumed(ctx); err != nil {
+			return err
+		}
+
 		_, err := retry.Retry(
 			func() error {
 				return l.Lock(ctx)
@@ -144,12 +221,38 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 		if err != nil {
 			return err
 		}
+		lp.setLockHeld(true)
+
+		// If the lock provides fencing tokens, commits are made with the
+		// token of the current lease, so that they are rejected once another
+		// process has acquired the lock (i.e. if our lease expired).
+		commitCtx := ctx
+		if fencer, ok := l.(Fencer); ok {
+			if token, held := fencer.Token(); held {
+				commitCtx = WithFencingToken(ctx, token)
+			}
+		}
+
+		// The attempt context is cancelled if the loop is paused (by any
+		// process) or rewound, in which case we release the lock and start over.
+		attemptCtx, interrupt := lp.startAttempt(ctx)
+		go lp.watchPaused(attemptCtx)
+		interrupted := func() error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return errInterrupted
+		}
 
 		// If we continually fail to ingest, we give up the lock and try again
 		// in case there's something specific to our process that's causing an issue,
 		// such as a bad network connection, or invalid/expired permissions.
 		_, err = retry.Retry(
 			func() error {
+				if attemptCtx.Err() != nil {
+					return interrupted()
+				}
+
 				defer metrics.restarts.Inc()
 
 				latest, err := c.Latest(ctx, i.Name())
@@ -157,13 +260,34 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 					return errors.Wrapf(err, "failed to get latest commit for '%s'", i.Name())
 				}
 
+				// If the latest commit was orphaned by a fork, we rewind to the
+				// last consistent block before resuming.
+				if resolver, ok := i.(ForkResolver); ok {
+					resolved, err := resolver.Resolve(attemptCtx, w, latest)
+					if err != nil {
+						return errors.Wrapf(err, "failed to resolve latest commit for '%s'", i.Name())
+					}
+
+					if !bytes.Equal(resolved, latest) {
+						log.WithFields(logrus.Fields{
+							"latest":   hex.EncodeToString(latest),
+							"resolved": hex.EncodeToString(resolved),
+						}).Warn("Latest commit was orphaned, rewinding")
+
+						if err := c.Reset(commitCtx, i.Name(), latest, resolved); err != nil {
+							return err
+						}
+						latest = resolved
+					}
+				}
+
 				// We create a specific queue context that 'defines' the lifetime of the Queue.
 				//
 				// When an error occurs, we want to restart the ingestion process. This creates
 				// a new queue, and so we must ensure that we close the previous one in order to
 				// prevent a memory leak. By tying each queue's lifetime to a context, we can
 				// properly cleanup the underlying resources.
-				queueCtx, cancel := context.WithCancel(ctx)
+				queueCtx, cancel := context.WithCancel(attemptCtx)
 				defer cancel()
 
 				log.WithField("parent", hex.EncodeToString(latest)).Debug("Starting ingestor")
@@ -174,8 +298,8 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 
 				for {
 					select {
-					case <-ctx.Done():
-						return ctx.Err()
+					case <-attemptCtx.Done():
+						return interrupted()
 					case resultCh, ok := <-queue:
 						if !ok {
 							// The queue will only ever be closed if there was a block processing err (in which case
@@ -195,15 +319,16 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 							"block":  hex.EncodeToString(r.Block),
 						}).Trace("Committing block")
 
-						if err := c.Commit(ctx, i.Name(), r.Parent, r.Block); err != nil {
-							// If we get an ErrInvalidCommit, the two likely causes are:
+						if err := c.Commit(commitCtx, i.Name(), r.Parent, r.Block); err != nil {
+							// If we get an ErrInvalidCommit, the likely causes are:
 							//   1. We've lost our lock status, and another process has been processing.
 							//   2. Another node incorrectly believes it has the lock status.
 							//   3. A process manually (or without checking the lock) committed a block.
+							//   4. The ingestor was rewound (see Controller.Rewind).
 							//
 							// There's not much to be done about (3), but (1) and (2) are the same issue,
 							// just from a different perspective. To alleviate them, we'll bail out and
-							// try to acquire the lock again.
+							// try to acquire the lock again, which will also pick up (4).
 							if err == ErrInvalidCommit {
 								return ErrInvalidCommit
 							}
@@ -211,22 +336,28 @@ func Run(ctx context.Context, l DistributedLock, c Committer, w history.Writer,
 							return errors.Wrapf(err, "failed to commit block (%x, %x)", r.Parent, r.Block)
 						}
 
+						lp.committed(time.Now())
 						metrics.lastIngestionTime.Set(float64(time.Now().Unix()))
 						metrics.commits.Inc()
 					}
 				}
 			},
 			retry.Limit(5),
-			retry.NonRetriableErrors(ErrInvalidCommit, context.Canceled),
+			retry.NonRetriableErrors(ErrInvalidCommit, context.Canceled, errInterrupted),
 			retry.Backoff(backoff.BinaryExponential(500*time.Millisecond), 10*time.Second),
 		)
-		if err != nil && err != context.Canceled {
+		interrupt()
+
+		if err == errInterrupted {
+			log.Info("Ingestion interrupted, restarting")
+		} else if err != nil && err != context.Canceled {
 			log.WithError(err).Warn("Failure while ingesting, will retry")
 		}
 
 		if err := l.Unlock(); err != nil {
 			log.WithError(err).Warn("Failed to release lock")
 		}
+		lp.setLockHeld(false)
 	}
 }
 
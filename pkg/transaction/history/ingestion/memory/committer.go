@@ -10,17 +10,21 @@ import (
 
 type committer struct {
 	sync.Mutex
-	ptrs map[string]ingestion.Pointer
+	ptrs   map[string]ingestion.Pointer
+	fences map[string]uint64
+	paused map[string]bool
 }
 
 func New() ingestion.Committer {
 	return &committer{
-		ptrs: make(map[string]ingestion.Pointer),
+		ptrs:   make(map[string]ingestion.Pointer),
+		fences: make(map[string]uint64),
+		paused: make(map[string]bool),
 	}
 }
 
 // Commit implements ingestion.Committer.Commit.
-func (c *committer) Commit(_ context.Context, name string, parent ingestion.Pointer, block ingestion.Pointer) error {
+func (c *committer) Commit(ctx context.Context, name string, parent ingestion.Pointer, block ingestion.Pointer) error {
 	c.Lock()
 	defer c.Unlock()
 
@@ -28,10 +32,13 @@ func (c *committer) Commit(_ context.Context, name string, parent ingestion.Poin
 	copy(cloned, block)
 
 	if p, ok := c.ptrs[name]; ok {
-		if !bytes.Equal(p, parent) || bytes.Compare(parent, block) > 0 {
+		if !bytes.Equal(p, parent) || bytes.Compare(parent, block) >= 0 {
 			return ingestion.ErrInvalidCommit
 		}
 	}
+	if !c.fence(ctx, name) {
+		return ingestion.ErrInvalidCommit
+	}
 
 	c.ptrs[name] = block
 	return nil
@@ -52,3 +59,63 @@ func (c *committer) Latest(ctx context.Context, name string) (ingestion.Pointer,
 
 	return cloned, nil
 }
+
+// Reset implements ingestion.Committer.Reset.
+func (c *committer) Reset(ctx context.Context, name string, latest, block ingestion.Pointer) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if !bytes.Equal(c.ptrs[name], latest) {
+		return ingestion.ErrInvalidCommit
+	}
+	if latest != nil && bytes.Compare(block, latest) > 0 {
+		return ingestion.ErrInvalidCommit
+	}
+	if !c.fence(ctx, name) {
+		return ingestion.ErrInvalidCommit
+	}
+
+	cloned := make(ingestion.Pointer, len(block))
+	copy(cloned, block)
+
+	c.ptrs[name] = cloned
+	return nil
+}
+
+// fence records the fencing token of the context, if any, returning false if
+// the token is older than the latest recorded token. It must be called with
+// the lock held.
+func (c *committer) fence(ctx context.Context, name string) bool {
+	token, ok := ingestion.FencingTokenFromContext(ctx)
+	if !ok {
+		return true
+	}
+	if token < c.fences[name] {
+		return false
+	}
+
+	c.fences[name] = token
+	return true
+}
+
+// SetPaused implements ingestion.Pauser.SetPaused.
+func (c *committer) SetPaused(_ context.Context, name string, paused bool) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if paused {
+		c.paused[name] = true
+	} else {
+		delete(c.paused, name)
+	}
+
+	return nil
+}
+
+// Paused implements ingestion.Pauser.Paused.
+func (c *committer) Paused(_ context.Context, name string) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.paused[name], nil
+}
@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/tests"
+)
+
+func TestCommitter(t *testing.T) {
+	c := New().(*committer)
+	tests.RunCommitterTests(t, c, func() {
+		c.Lock()
+		c.ptrs = make(map[string]ingestion.Pointer)
+		c.fences = make(map[string]uint64)
+		c.paused = make(map[string]bool)
+		c.Unlock()
+	})
+}
@@ -0,0 +1,182 @@
+// Package redis implements a redis backed ingestion.Committer.
+//
+// The latest commit for each ingestor is stored in its own key:
+//
+//	ingestion-commit:{ingestor} latest
+//
+// The latest fencing token used to commit (see ingestion.WithFencingToken) is
+// stored in the same slot:
+//
+//	ingestion-fence:{ingestor} token
+//
+// The paused state of each ingestor is stored alongside it, where the
+// existence of the key indicates the ingestor is paused:
+//
+//	ingestion-paused:{ingestor} 1
+//
+// Commits and resets are applied with an atomic compare-and-swap (via a script)
+// to ensure that the parent matches the stored latest.
+package redis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+)
+
+var (
+	// fenceScript checks that the fencing token ARGV[4] (if non-zero) is not
+	// older than the stored token in KEYS[2]. It is prepended to the commit
+	// and reset scripts, which call recordFence once the update is applied.
+	fenceScript = `
+local fence = tonumber(ARGV[4])
+if fence > 0 and fence < tonumber(redis.call("GET", KEYS[2]) or "0") then
+	return 0
+end
+local function recordFence()
+	if fence > 0 then
+		redis.call("SET", KEYS[2], ARGV[4])
+	end
+end
+`
+
+	// commitScript sets the latest commit to ARGV[2] if no commit exists, or
+	// if the latest commit is ARGV[1] and the block is 'newer' (ARGV[3] == "1").
+	//
+	// Ordering is checked by the caller, as string comparisons in scripts are
+	// not guaranteed to be bytewise.
+	commitScript = redis.NewScript(fenceScript + `
+local latest = redis.call("GET", KEYS[1])
+if latest and (latest ~= ARGV[1] or ARGV[3] ~= "1") then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2])
+recordFence()
+return 1
+`)
+
+	// resetScript sets the latest commit to ARGV[2] if the latest commit is
+	// ARGV[1], or if no commit exists and ARGV[3] == "1".
+	resetScript = redis.NewScript(fenceScript + `
+local latest = redis.call("GET", KEYS[1])
+if ARGV[3] == "1" then
+	if latest then
+		return 0
+	end
+elseif latest ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2])
+recordFence()
+return 1
+`)
+)
+
+type committer struct {
+	client redis.Cmdable
+}
+
+// New returns a redis backed ingestion.Committer.
+func New(client redis.Cmdable) ingestion.Committer {
+	return &committer{
+		client: client,
+	}
+}
+
+// Commit implements ingestion.Committer.Commit.
+func (c *committer) Commit(ctx context.Context, name string, parent, block ingestion.Pointer) error {
+	newer := "0"
+	if bytes.Compare(parent, block) < 0 {
+		newer = "1"
+	}
+
+	committed, err := commitScript.Run(c.client, keys(name), []byte(parent), []byte(block), newer, fence(ctx)).Int64()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+	if committed != 1 {
+		return ingestion.ErrInvalidCommit
+	}
+
+	return nil
+}
+
+// Latest implements ingestion.Committer.Latest.
+func (c *committer) Latest(_ context.Context, name string) (ingestion.Pointer, error) {
+	latest, err := c.client.Get(key(name)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest commit")
+	}
+
+	return latest, nil
+}
+
+// Reset implements ingestion.Committer.Reset.
+func (c *committer) Reset(ctx context.Context, name string, latest, block ingestion.Pointer) error {
+	initial := "0"
+	if latest == nil {
+		initial = "1"
+	} else if bytes.Compare(block, latest) > 0 {
+		return ingestion.ErrInvalidCommit
+	}
+
+	reset, err := resetScript.Run(c.client, keys(name), []byte(latest), []byte(block), initial, fence(ctx)).Int64()
+	if err != nil {
+		return errors.Wrap(err, "failed to reset commit")
+	}
+	if reset != 1 {
+		return ingestion.ErrInvalidCommit
+	}
+
+	return nil
+}
+
+// SetPaused implements ingestion.Pauser.SetPaused.
+func (c *committer) SetPaused(_ context.Context, name string, paused bool) error {
+	var err error
+	if paused {
+		err = c.client.Set(pausedKey(name), "1", 0).Err()
+	} else {
+		err = c.client.Del(pausedKey(name)).Err()
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to set paused state")
+	}
+
+	return nil
+}
+
+// Paused implements ingestion.Pauser.Paused.
+func (c *committer) Paused(_ context.Context, name string) (bool, error) {
+	n, err := c.client.Exists(pausedKey(name)).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get paused state")
+	}
+
+	return n > 0, nil
+}
+
+// fence returns the fencing token of the context, or 0 if there is none.
+func fence(ctx context.Context) uint64 {
+	token, _ := ingestion.FencingTokenFromContext(ctx)
+	return token
+}
+
+func keys(name string) []string {
+	return []string{key(name), fmt.Sprintf("ingestion-fence:{%s}", name)}
+}
+
+func key(name string) string {
+	return fmt.Sprintf("ingestion-commit:{%s}", name)
+}
+
+func pausedKey(name string) string {
+	return fmt.Sprintf("ingestion-paused:{%s}", name)
+}
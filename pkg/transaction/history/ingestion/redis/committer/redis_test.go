@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-redis/redis/v7"
+	redistest "github.com/kinecosystem/agora-common/redis/test"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/tests"
+)
+
+var (
+	redisConnString string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	redisConnString, cleanUpFunc, err = redistest.StartRedis(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting redis connection")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestCommitter(t *testing.T) {
+	client := redis.NewRing(&redis.RingOptions{
+		Addrs: map[string]string{
+			"server1": redisConnString,
+		},
+	})
+
+	tests.RunCommitterTests(t, New(client), func() {
+		client.FlushAll()
+	})
+}
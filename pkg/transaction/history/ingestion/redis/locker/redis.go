@@ -0,0 +1,222 @@
+// Package redis implements a redis backed ingestion.DistributedLock.
+//
+// A lock is a lease stored in a single key, with a value of <owner>/<token>,
+// where token is a fencing token that is incremented on every acquisition:
+//
+//	ingestion-lock:{key}       <owner>/<token>, expiring after the lease duration
+//	ingestion-lock-token:{key} the latest fencing token
+//
+// The lease is extended by the holder while the lock is held. Leases are only
+// extended or released if the stored value matches the holder's, so a holder
+// whose lease expired cannot affect a subsequent holder.
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+)
+
+var (
+	// acquireScript acquires the lock if it is not held, returning the new
+	// fencing token, or 0 if the lock is held.
+	acquireScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], ARGV[1] .. "/" .. token, "PX", ARGV[2])
+return token
+`)
+
+	// refreshScript extends the lease, if it is still held by the caller.
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// releaseScript releases the lock, if it is still held by the caller.
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// Lock is an ingestion.DistributedLock that provides a fencing token for the
+// current lease.
+type Lock interface {
+	ingestion.DistributedLock
+	ingestion.LockHolder
+	ingestion.Fencer
+}
+
+type lock struct {
+	log       *logrus.Entry
+	client    redis.Cmdable
+	key       string
+	tokenKey  string
+	owner     string
+	lease     time.Duration
+	heartbeat time.Duration
+
+	mu    sync.Mutex
+	value string
+	token uint64
+	stop  chan struct{}
+}
+
+// New returns a redis backed Lock for the specified key. While held, the
+// lease is extended every heartbeat, and expires after 3 heartbeats.
+func New(client redis.Cmdable, lockKey string, heartbeat time.Duration) Lock {
+	return &lock{
+		log:       logrus.StandardLogger().WithField("type", "transaction/history/ingestion/redis/locker"),
+		client:    client,
+		key:       fmt.Sprintf("ingestion-lock:{%s}", lockKey),
+		tokenKey:  fmt.Sprintf("ingestion-lock-token:{%s}", lockKey),
+		owner:     ownerName(),
+		lease:     3 * heartbeat,
+		heartbeat: heartbeat,
+	}
+}
+
+// Lock implements ingestion.DistributedLock.Lock.
+func (l *lock) Lock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.value != "" {
+		return nil
+	}
+
+	for {
+		token, err := acquireScript.Run(l.client, []string{l.key, l.tokenKey}, l.owner, l.lease.Milliseconds()).Int64()
+		if err != nil {
+			return errors.Wrap(err, "failed to acquire lock")
+		}
+
+		if token > 0 {
+			l.token = uint64(token)
+			l.value = fmt.Sprintf("%s/%d", l.owner, token)
+			l.stop = make(chan struct{})
+			go l.refresh(l.value, l.stop)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.heartbeat):
+		}
+	}
+}
+
+// Unlock implements ingestion.DistributedLock.Unlock.
+func (l *lock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.value == "" {
+		return nil
+	}
+
+	value := l.value
+	l.release()
+
+	if err := releaseScript.Run(l.client, []string{l.key}, value).Err(); err != nil {
+		return errors.Wrap(err, "failed to release lock")
+	}
+
+	return nil
+}
+
+// Holder implements ingestion.LockHolder.Holder.
+func (l *lock) Holder(_ context.Context) (string, error) {
+	value, err := l.client.Get(l.key).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to get lock")
+	}
+
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		return value[:i], nil
+	}
+	return value, nil
+}
+
+// Token implements ingestion.Fencer.Token.
+func (l *lock) Token() (uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.token, l.value != ""
+}
+
+// refresh extends the lease until stop is closed, or the lease is lost.
+func (l *lock) refresh(value string, stop <-chan struct{}) {
+	ticker := time.NewTicker(l.heartbeat)
+	defer ticker.Stop()
+
+	lastRefresh := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		extended, err := refreshScript.Run(l.client, []string{l.key}, value, l.lease.Milliseconds()).Int64()
+		if err != nil {
+			l.log.WithError(err).Warn("failed to refresh lock")
+
+			// If we've been unable to refresh for the lease duration, the
+			// lease has (likely) expired.
+			if time.Since(lastRefresh) < l.lease {
+				continue
+			}
+		} else if extended == 1 {
+			lastRefresh = time.Now()
+			continue
+		}
+
+		l.log.WithField("key", l.key).Warn("lost lock")
+
+		l.mu.Lock()
+		if l.value == value {
+			l.release()
+		}
+		l.mu.Unlock()
+		return
+	}
+}
+
+// release clears the local lock state. It must be called with mu held.
+func (l *lock) release() {
+	close(l.stop)
+	l.value = ""
+	l.token = 0
+	l.stop = nil
+}
+
+// ownerName returns a (unique) lock owner name that identifies the host.
+func ownerName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%x", hostname, time.Now().UnixNano())
+}
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	redistest "github.com/kinecosystem/agora-common/redis/test"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/tests"
+)
+
+var (
+	redisConnString string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	redisConnString, cleanUpFunc, err = redistest.StartRedis(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting redis connection")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func newClient() *redis.Ring {
+	return redis.NewRing(&redis.RingOptions{
+		Addrs: map[string]string{
+			"server1": redisConnString,
+		},
+	})
+}
+
+func TestLock(t *testing.T) {
+	client := newClient()
+	tests.RunLockTests(t, func(key string) (ingestion.DistributedLock, error) {
+		return New(client, key, 100*time.Millisecond), nil
+	}, func() {
+		client.FlushAll()
+	})
+}
+
+func TestLock_Fencing(t *testing.T) {
+	client := newClient()
+	defer client.FlushAll()
+
+	ctx := context.Background()
+	a := New(client, "fencing", 100*time.Millisecond)
+	b := New(client, "fencing", 100*time.Millisecond)
+
+	_, held := a.Token()
+	assert.False(t, held)
+
+	holder, err := a.Holder(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+
+	require.NoError(t, a.Lock(ctx))
+	require.NoError(t, a.Lock(ctx))
+	first, held := a.Token()
+	assert.True(t, held)
+
+	holder, err = b.Holder(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, a.(*lock).owner, holder)
+
+	// The lease should be extended while held.
+	time.Sleep(500 * time.Millisecond)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Lock(timeoutCtx))
+
+	require.NoError(t, a.Unlock())
+	require.NoError(t, a.Unlock())
+	_, held = a.Token()
+	assert.False(t, held)
+
+	require.NoError(t, b.Lock(ctx))
+	second, held := b.Token()
+	assert.True(t, held)
+	assert.True(t, second > first)
+
+	// If the lease is lost, the stale holder should not affect the new holder.
+	require.NoError(t, client.Del(b.(*lock).key).Err())
+	require.NoError(t, a.Lock(ctx))
+	require.Eventually(t, func() bool {
+		_, held := b.Token()
+		return !held
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, b.Unlock())
+
+	holder, err = b.Holder(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, a.(*lock).owner, holder)
+
+	third, held := a.Token()
+	assert.True(t, held)
+	assert.True(t, third > second)
+}
@@ -0,0 +1,102 @@
+package ingestion
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ingestionpb "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/proto"
+)
+
+type server struct {
+	log  *logrus.Entry
+	ctrl *Controller
+}
+
+// NewServer returns an ingestionpb.AdminServer backed by the provided controller.
+func NewServer(ctrl *Controller) ingestionpb.AdminServer {
+	return &server{
+		log:  logrus.StandardLogger().WithField("type", "transaction/history/ingestion/server"),
+		ctrl: ctrl,
+	}
+}
+
+// ListIngestors implements ingestionpb.AdminServer.ListIngestors.
+func (s *server) ListIngestors(ctx context.Context, _ *ingestionpb.ListIngestorsRequest) (*ingestionpb.ListIngestorsResponse, error) {
+	statuses, err := s.ctrl.List(ctx)
+	if err != nil {
+		s.log.WithError(err).Warn("failed to list ingestors")
+		return nil, status.Error(codes.Internal, "failed to list ingestors")
+	}
+
+	resp := &ingestionpb.ListIngestorsResponse{
+		Ingestors: make([]*ingestionpb.IngestorStatus, len(statuses)),
+	}
+	for i, st := range statuses {
+		resp.Ingestors[i] = &ingestionpb.IngestorStatus{
+			Name:       st.Name,
+			Latest:     st.Latest,
+			LockHeld:   st.LockHeld,
+			LockHolder: st.LockHolder,
+			Lag:        st.Lag,
+		}
+		if st.Paused {
+			resp.Ingestors[i].State = ingestionpb.IngestorStatus_PAUSED
+		}
+		if !st.LastCommit.IsZero() {
+			if resp.Ingestors[i].LastCommitTime, err = ptypes.TimestampProto(st.LastCommit); err != nil {
+				s.log.WithError(err).Warn("failed to marshal last commit time")
+				return nil, status.Error(codes.Internal, "failed to marshal last commit time")
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// Pause implements ingestionpb.AdminServer.Pause.
+func (s *server) Pause(ctx context.Context, req *ingestionpb.PauseRequest) (*ingestionpb.PauseResponse, error) {
+	if err := s.ctrl.Pause(ctx, req.Name); err == ErrIngestorNotFound {
+		return &ingestionpb.PauseResponse{Result: ingestionpb.PauseResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		s.log.WithError(err).Warn("failed to pause ingestor")
+		return nil, status.Error(codes.Internal, "failed to pause ingestor")
+	}
+
+	s.log.WithField("ingestor", req.Name).Info("paused ingestor")
+	return &ingestionpb.PauseResponse{}, nil
+}
+
+// Resume implements ingestionpb.AdminServer.Resume.
+func (s *server) Resume(ctx context.Context, req *ingestionpb.ResumeRequest) (*ingestionpb.ResumeResponse, error) {
+	if err := s.ctrl.Resume(ctx, req.Name); err == ErrIngestorNotFound {
+		return &ingestionpb.ResumeResponse{Result: ingestionpb.ResumeResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		s.log.WithError(err).Warn("failed to resume ingestor")
+		return nil, status.Error(codes.Internal, "failed to resume ingestor")
+	}
+
+	s.log.WithField("ingestor", req.Name).Info("resumed ingestor")
+	return &ingestionpb.ResumeResponse{}, nil
+}
+
+// Rewind implements ingestionpb.AdminServer.Rewind.
+func (s *server) Rewind(ctx context.Context, req *ingestionpb.RewindRequest) (*ingestionpb.RewindResponse, error) {
+	previous, err := s.ctrl.Rewind(ctx, req.Name, req.Block)
+	switch err {
+	case nil:
+		return &ingestionpb.RewindResponse{Previous: previous}, nil
+	case ErrIngestorNotFound:
+		return &ingestionpb.RewindResponse{Result: ingestionpb.RewindResponse_NOT_FOUND}, nil
+	case ErrInvalidPointer:
+		return &ingestionpb.RewindResponse{Result: ingestionpb.RewindResponse_INVALID_POINTER}, nil
+	case ErrInvalidCommit:
+		return nil, status.Error(codes.Aborted, "block committed during rewind, try again")
+	default:
+		s.log.WithError(err).Warn("failed to rewind ingestor")
+		return nil, status.Error(codes.Internal, "failed to rewind ingestor")
+	}
+}
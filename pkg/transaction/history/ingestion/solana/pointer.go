@@ -3,11 +3,18 @@ package solana
 import (
 	"encoding/binary"
 
+	"github.com/kinecosystem/agora-common/solana"
 	"github.com/pkg/errors"
 
 	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 )
 
+// PointerFromSlot returns the ingestion.Pointer for the block at the
+// specified slot.
+func PointerFromSlot(slot uint64) ingestion.Pointer {
+	return pointerFromSlot(slot)
+}
+
 func pointerFromSlot(slot uint64) ingestion.Pointer {
 	ptr := make([]byte, 9)
 	ptr[0] = byte(4)
@@ -15,14 +22,78 @@ func pointerFromSlot(slot uint64) ingestion.Pointer {
 	return ptr
 }
 
+// pointerFromBlock returns the pointer for the specified slot, which also
+// references the latest block at or before the slot (if known). The block
+// is used to verify that the committed chain is still part of the blockchain
+// when ingestion is restarted.
+func pointerFromBlock(slot uint64, block *solana.Block) ingestion.Pointer {
+	if block == nil {
+		return pointerFromSlot(slot)
+	}
+
+	ptr := make([]byte, 17, 17+len(block.Hash))
+	ptr[0] = byte(4)
+	binary.BigEndian.PutUint64(ptr[1:], slot)
+	binary.BigEndian.PutUint64(ptr[9:], block.Slot)
+	return append(ptr, block.Hash...)
+}
+
+// blockFromPointer returns the slot and hash of the block referenced by the
+// pointer. If the pointer does not reference a block, ok is false.
+func blockFromPointer(p ingestion.Pointer) (slot uint64, hash []byte, ok bool) {
+	if len(p) <= 17 {
+		return 0, nil, false
+	}
+
+	return binary.BigEndian.Uint64(p[9:]), p[17:], true
+}
+
+// SlotFromPointer returns the slot of the block referenced by the pointer.
+// A nil pointer refers to slot 0.
+func SlotFromPointer(p ingestion.Pointer) (uint64, error) {
+	return slotFromPointer(p)
+}
+
 func slotFromPointer(p ingestion.Pointer) (slot uint64, err error) {
 	if len(p) == 0 {
 		return 0, nil
 	}
 
-	if len(p) != 9 {
+	if len(p) != 9 && len(p) <= 17 {
 		return 0, errors.Errorf("invalid pointer")
 	}
 
-	return binary.BigEndian.Uint64(p[1:]), nil
+	return binary.BigEndian.Uint64(p[1:9]), nil
+}
+
+// BackfillChunks splits the (inclusive) slot range [from, to] into chunks
+// of at most size slots.
+func BackfillChunks(from, to, size uint64) []ingestion.BackfillChunk {
+	if size == 0 {
+		size = 1
+	}
+
+	var chunks []ingestion.BackfillChunk
+	for start := from; start <= to; start += size {
+		end := start + size - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		var parent ingestion.Pointer
+		if start > 0 {
+			parent = pointerFromSlot(start - 1)
+		}
+
+		chunks = append(chunks, ingestion.BackfillChunk{
+			Parent: parent,
+			End:    pointerFromSlot(end),
+		})
+
+		if end == to {
+			break
+		}
+	}
+
+	return chunks
 }
@@ -3,7 +3,10 @@ package solana
 import (
 	"testing"
 
+	"github.com/kinecosystem/agora-common/solana"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 )
 
 func TestPointer_RoundTrip(t *testing.T) {
@@ -14,4 +17,36 @@ func TestPointer_RoundTrip(t *testing.T) {
 
 	_, err = slotFromPointer([]byte{0})
 	assert.NotNil(t, err)
+
+	_, _, ok := blockFromPointer(pointerFromSlot(expected))
+	assert.False(t, ok)
+
+	block := &solana.Block{Slot: expected - 2, Hash: []byte("hash")}
+	p := pointerFromBlock(expected, block)
+	actual, err = slotFromPointer(p)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	blockSlot, hash, ok := blockFromPointer(p)
+	assert.True(t, ok)
+	assert.Equal(t, block.Slot, blockSlot)
+	assert.Equal(t, block.Hash, hash)
+
+	assert.Equal(t, pointerFromSlot(expected), pointerFromBlock(expected, nil))
+}
+
+func TestBackfillChunks(t *testing.T) {
+	chunks := BackfillChunks(0, 25, 10)
+	assert.Equal(t, []ingestion.BackfillChunk{
+		{Parent: nil, End: pointerFromSlot(9)},
+		{Parent: pointerFromSlot(9), End: pointerFromSlot(19)},
+		{Parent: pointerFromSlot(19), End: pointerFromSlot(25)},
+	}, chunks)
+
+	chunks = BackfillChunks(5, 5, 10)
+	assert.Equal(t, []ingestion.BackfillChunk{
+		{Parent: pointerFromSlot(4), End: pointerFromSlot(5)},
+	}, chunks)
+
+	assert.Empty(t, BackfillChunks(10, 5, 10))
 }
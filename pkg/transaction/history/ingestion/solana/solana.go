@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -14,6 +15,7 @@ import (
 	"github.com/kinecosystem/agora-common/solana/token"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/kinecosystem/agora/pkg/transaction/history"
@@ -21,114 +23,614 @@ import (
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
 )
 
+var (
+	// ErrForkDetected indicates that a block is not a child of the previously
+	// ingested block.
+	ErrForkDetected = errors.New("fork detected")
+
+	// ErrSkippedSlot indicates that the parent of a block was not ingested,
+	// meaning the block list returned by the RPC node was missing data.
+	//
+	// Slots that were legitimately skipped (i.e. no block was produced) do
+	// not result in an error.
+	ErrSkippedSlot = errors.New("skipped slot detected")
+)
+
+const (
+	defaultConcurrency = 16
+	defaultQueueSize   = 16
+
+	// maxForkDepth is the number of slots that are rewound when the latest
+	// committed block is found to have been orphaned. Only confirmed blocks
+	// are ingested, which are rooted well within this depth.
+	maxForkDepth = 512
+
+	// pruneLimit is the number of entries loaded at a time when removing the
+	// entries of orphaned blocks.
+	pruneLimit = 1000
+)
+
 type ingestor struct {
 	log         *logrus.Entry
 	name        string
 	client      solana.Client
 	tokenClient *token.Client
+
+	// fallback is an alternate RPC client that is used to re-load a range
+	// of slots in which data was found to be missing.
+	fallback solana.Client
+
+	concurrency int
+	queueSize   int
+
+	// fallbackMu guards fallbackUntil, which is the last slot (inclusive) that
+	// should be loaded using the fallback client.
+	fallbackMu    sync.Mutex
+	fallbackUntil uint64
+	useFallback   bool
+
+	inconsistencies prometheus.Counter
+	missingBlocks   prometheus.Counter
+	skippedSlots    prometheus.Counter
+}
+
+// Option configures an ingestor.
+type Option func(*ingestor)
+
+// WithConcurrency specifies the maximum number of blocks that are fetched
+// and decoded concurrently.
+//
+// If none is provided, 16 is used.
+func WithConcurrency(concurrency int) Option {
+	return func(i *ingestor) {
+		if concurrency > 0 {
+			i.concurrency = concurrency
+		}
+	}
+}
+
+// WithQueueSize specifies the number of processed blocks that may be
+// buffered ahead of the committer.
+//
+// If none is provided, 16 is used.
+func WithQueueSize(size int) Option {
+	return func(i *ingestor) {
+		if size > 0 {
+			i.queueSize = size
+		}
+	}
+}
+
+// WithFallbackClient specifies an alternate RPC client (typically backed by a
+// different RPC node). If the block list returned by the primary client is
+// found to be missing blocks, the affected range is re-loaded using the
+// fallback client.
+//
+// If none is provided, the range is re-loaded using the primary client.
+func WithFallbackClient(client solana.Client) Option {
+	return func(i *ingestor) {
+		i.fallback = client
+	}
+}
+
+// BlockLoader loads the history entries contained in a block.
+type BlockLoader interface {
+	// LoadBlock returns the (confirmed) entries for the transactions in the
+	// block at the specified slot that are relevant to the configured token.
+	//
+	// If the slot does not contain a block, no entries are returned.
+	LoadBlock(slot uint64) ([]*model.Entry, error)
 }
 
-func New(name string, client solana.Client, t ed25519.PublicKey) ingestion.Ingestor {
+// New returns a new ingestion.Ingestor for the Solana blockchain.
+//
+// Blocks are fetched and decoded concurrently, but are only written to
+// history once they have been verified to be a child of the previous block.
+// If a fork or skipped slot is detected, the result queue fails, allowing
+// ingestion to restart from the last committed (consistent) block.
+func New(name string, client solana.Client, t ed25519.PublicKey, opts ...Option) ingestion.Ingestor {
+	i := newIngestor(name, client, t)
+	for _, o := range opts {
+		o(i)
+	}
+
+	i.inconsistencies = registerCounter(prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "history_solana_inconsistencies",
+		Namespace: "agora",
+		Help:      "Number of forks or skipped slots detected during ingestion",
+		ConstLabels: prometheus.Labels{
+			"ingestor": name,
+		},
+	}))
+	i.missingBlocks = registerCounter(prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "history_solana_missing_blocks",
+		Namespace: "agora",
+		Help:      "Number of times a block was missing from the block list returned by the RPC node",
+		ConstLabels: prometheus.Labels{
+			"ingestor": name,
+		},
+	}))
+	i.skippedSlots = registerCounter(prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "history_solana_skipped_slots",
+		Namespace: "agora",
+		Help:      "Number of slots in which no block was produced",
+		ConstLabels: prometheus.Labels{
+			"ingestor": name,
+		},
+	}))
+
+	return i
+}
+
+// NewBlockLoader returns a BlockLoader that uses the same criteria as the
+// ingestor to determine which transactions are included in history.
+func NewBlockLoader(client solana.Client, t ed25519.PublicKey) BlockLoader {
+	return newIngestor("", client, t)
+}
+
+func newIngestor(name string, client solana.Client, t ed25519.PublicKey) *ingestor {
 	return &ingestor{
 		log:         logrus.StandardLogger().WithField("type", "transaction/history/ingestion/solana"),
 		name:        name,
 		client:      client,
 		tokenClient: token.NewClient(client, t),
+		concurrency: defaultConcurrency,
+		queueSize:   defaultQueueSize,
 	}
 }
 
+func registerCounter(c prometheus.Counter) prometheus.Counter {
+	if err := prometheus.Register(c); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return e.ExistingCollector.(prometheus.Counter)
+		}
+	}
+
+	return c
+}
+
+// setFallback marks the slots up to (and including) the specified slot to be
+// loaded using the fallback client.
+func (i *ingestor) setFallback(slot uint64) {
+	i.fallbackMu.Lock()
+	defer i.fallbackMu.Unlock()
+
+	if !i.useFallback || slot > i.fallbackUntil {
+		i.fallbackUntil = slot
+	}
+	i.useFallback = true
+}
+
+// clientFor returns the client that should be used to load the slot.
+func (i *ingestor) clientFor(slot uint64) solana.Client {
+	if i.fallback == nil {
+		return i.client
+	}
+
+	i.fallbackMu.Lock()
+	defer i.fallbackMu.Unlock()
+
+	if i.useFallback && slot <= i.fallbackUntil {
+		return i.fallback
+	}
+	i.useFallback = false
+	return i.client
+}
+
 // Name implements ingestion.Ingestor.Name.
 func (i *ingestor) Name() string {
 	return i.name
 }
 
+// Lag implements ingestion.LagReporter.Lag.
+//
+// Note: the lag is measured in slots, not all of which contain a block.
+func (i *ingestor) Lag(_ context.Context, p ingestion.Pointer) (uint64, error) {
+	slot, err := slotFromPointer(p)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := i.client.GetSlot(solana.CommitmentMax)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get current slot")
+	}
+
+	if current <= slot {
+		return 0, nil
+	}
+
+	return current - slot, nil
+}
+
+// loadResult is the result of fetching and decoding a block.
+type loadResult struct {
+	slot    uint64
+	block   *solana.Block
+	entries []*model.Entry
+	err     error
+}
+
 // Ingest implements ingestion.Ingestor.Ingest.
+//
+// Ingestion is split into two stages. The first fetches and decodes blocks
+// using a bounded set of workers. The second consumes the loaded blocks in
+// order, verifies that each block is a child of the previous one, and then
+// writes the entries, producing results in block order.
 func (i *ingestor) Ingest(ctx context.Context, w history.Writer, parent ingestion.Pointer) (ingestion.ResultQueue, error) {
 	parentSlot, err := slotFromPointer(parent)
 	if err != nil {
 		return nil, err
 	}
-	start := parentSlot + 1
 
-	// todo(config): allow for a customizable buffer?
-	queue := make(chan (<-chan ingestion.Result), 16)
+	// If the parent references a block, the first block must be its child.
+	// Otherwise (i.e. during backfill), only skipped slots can be detected
+	// until the first block has been loaded.
+	prev, err := i.loadParentBlock(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	pending := make(chan (<-chan loadResult), i.queueSize)
+	queue := make(chan (<-chan ingestion.Result), i.queueSize)
 
 	go func() {
+		defer close(pending)
+		i.fetch(ctx, parentSlot+1, pending)
+	}()
+
+	go func() {
+		defer cancel()
 		defer close(queue)
+		i.sequence(ctx, w, parent, prev, pending, queue)
+	}()
+
+	return queue, nil
+}
+
+// loadParentBlock returns the block referenced by the parent pointer, if any.
+// ErrForkDetected is returned if the block is no longer part of the chain.
+func (i *ingestor) loadParentBlock(parent ingestion.Pointer) (*solana.Block, error) {
+	slot, hash, ok := blockFromPointer(parent)
+	if !ok {
+		return nil, nil
+	}
+
+	block, err := i.client.GetConfirmedBlock(slot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get parent block %d", slot)
+	}
+	if block == nil || !bytes.Equal(block.Hash, hash) {
+		return nil, errors.Wrapf(ErrForkDetected, "parent block %d (%s) is not part of the chain", slot, base58.Encode(hash))
+	}
+
+	return block, nil
+}
+
+// Resolve implements ingestion.ForkResolver.Resolve.
+//
+// If the block referenced by the latest pointer is not part of the chain, the
+// ingestor is rewound by maxForkDepth slots. Entries in the rewound slots that
+// are not part of the chain's block at the same slot are pruned from w, if it
+// implements history.Reader and history.Pruner (and Prune does not return
+// history.ErrNotSupported). The remaining entries are
+// identical to the ones re-ingested.
+func (i *ingestor) Resolve(ctx context.Context, w history.Writer, latest ingestion.Pointer) (ingestion.Pointer, error) {
+	_, forkErr := i.loadParentBlock(latest)
+	if forkErr == nil || !errors.Is(forkErr, ErrForkDetected) {
+		return latest, forkErr
+	}
+
+	slot, err := slotFromPointer(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	i.log.WithError(forkErr).WithField("slot", slot).Warn("committed block was orphaned, rewinding")
+	i.inconsistencies.Inc()
+
+	var start uint64
+	if slot > maxForkDepth {
+		start = slot - maxForkDepth
+	}
 
-		_, err := retry.Retry(
-			func() error {
-				for {
+	// The new pointer references the latest block at or before start, so
+	// that the rewound chain is verified when ingestion resumes.
+	var anchor *solana.Block
+	for s := start; s > 0 && start-s < maxForkDepth; s-- {
+		if anchor, err = i.client.GetConfirmedBlock(s); err != nil {
+			return nil, errors.Wrapf(err, "failed to get block %d", s)
+		}
+		if anchor != nil {
+			break
+		}
+	}
+
+	if err := i.pruneOrphans(ctx, w, start+1, slot); err != nil {
+		return nil, err
+	}
+
+	return pointerFromBlock(start, anchor), nil
+}
+
+// pruneOrphans removes the entries in the (inclusive) slot range that are not
+// part of the chain's block at their slot.
+func (i *ingestor) pruneOrphans(ctx context.Context, w history.Writer, from, to uint64) error {
+	type readPruner interface {
+		history.Reader
+		history.Pruner
+	}
+	rp, ok := w.(readPruner)
+	if !ok {
+		i.log.WithField("from", from).WithField("to", to).Info("writer does not support pruning, orphaned entries are not removed")
+		return nil
+	}
+
+	for start := from; start <= to; {
+		entries, next, err := history.GetCompleteBlocks(ctx, rp, start, to, pruneLimit)
+		if err != nil {
+			return errors.Wrap(err, "failed to get transactions")
+		}
+
+		var slot uint64
+		var canonical map[string]struct{}
+		for _, e := range entries {
+			if e.GetSolana().Slot != slot || canonical == nil {
+				slot = e.GetSolana().Slot
+				if canonical, err = i.getSignatures(slot); err != nil {
+					return err
+				}
+			}
+
+			txID, err := e.GetTxID()
+			if err != nil {
+				return errors.Wrap(err, "failed to get tx id")
+			}
+			if _, ok := canonical[string(txID)]; ok {
+				continue
+			}
+
+			if err := rp.Prune(ctx, e); err == history.ErrNotSupported {
+				i.log.WithField("from", from).WithField("to", to).Info("writer does not support pruning, orphaned entries are not removed")
+				return nil
+			} else if err != nil {
+				return errors.Wrap(err, "failed to prune orphaned entry")
+			}
+			i.log.WithField("slot", slot).WithField("tx", base58.Encode(txID)).Info("pruned orphaned entry")
+		}
+
+		start = next
+	}
+
+	return nil
+}
+
+// getSignatures returns the signatures of the transactions in the block at
+// the specified slot.
+func (i *ingestor) getSignatures(slot uint64) (map[string]struct{}, error) {
+	block, err := i.client.GetConfirmedBlock(slot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block %d", slot)
+	}
+
+	signatures := make(map[string]struct{})
+	if block != nil {
+		for _, txn := range block.Transactions {
+			signatures[string(txn.Transaction.Signature())] = struct{}{}
+		}
+	}
+
+	return signatures, nil
+}
+
+// fetch loads all blocks starting at the specified slot, until the context
+// is cancelled.
+func (i *ingestor) fetch(ctx context.Context, start uint64, pending chan<- (<-chan loadResult)) {
+	sem := make(chan struct{}, i.concurrency)
+
+	_, err := retry.Retry(
+		func() error {
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+
+				// We can request a fairly aggressive range here, as to reduce
+				// the number of requests to the RPC node.
+				//
+				// Note: the number here is currently arbitrary. The real limiter
+				// is the number of concurrent workers (which perform an rpc call
+				// for each block), which impacts the solana RPC node.
+				//
+				// If data was found to be missing in this range, we use the
+				// fallback client until we're past it.
+				client := i.clientFor(start)
+				blocks, err := client.GetConfirmedBlocksWithLimit(start, 1024)
+				if err != nil {
+					i.log.WithError(err).Info("failed to get confirmed blocks")
+					return err
+				}
+
+				i.log.WithField("block_count", len(blocks)).Debug("processing blocks")
+
+				for _, slot := range blocks {
 					select {
+					case sem <- struct{}{}:
 					case <-ctx.Done():
 						return ctx.Err()
-					default:
 					}
 
-					// We can request a fairly aggressive range here, as to reduce
-					// the number of requests to the RPC node.
-					//
-					// Note: the number here is currently arbitrary. The real limiter
-					// is the number of concurrent processors (which perform an rpc call
-					// for each block), which impacts the solana RPC node.
-					blocks, err := i.client.GetConfirmedBlocksWithLimit(start, 1024)
-					if err != nil {
-						i.log.WithError(err).Info("failed to get confirmed blocks")
-						return err
+					loadCh := make(chan loadResult, 1)
+					select {
+					case pending <- loadCh:
+					case <-ctx.Done():
+						<-sem
+						return ctx.Err()
 					}
 
-					i.log.WithField("block_count", len(blocks)).Debug("processing blocks")
+					go func(slot uint64) {
+						defer func() { <-sem }()
 
-					for _, slot := range blocks {
-						blockPtr := pointerFromSlot(slot)
-						resultCh := make(chan ingestion.Result, 1)
+						result := loadResult{slot: slot}
+						result.block, result.entries, result.err = i.loadBlock(client, slot)
+						loadCh <- result
+					}(slot)
 
-						select {
-						case queue <- resultCh:
-						case <-ctx.Done():
-							return ctx.Err()
-						}
+					i.log.WithField("slot", slot).Trace("processing slot")
+					start = slot + 1
+				}
 
-						result := ingestion.Result{
-							Parent: parent,
-							Block:  blockPtr,
-						}
-						parent = blockPtr
+				if len(blocks) == 0 {
+					// todo(config): maybe this should be configurable? currently
+					//               we ensure it's no faster than a second to alleviate load.
+					time.Sleep(time.Duration(math.Max(float64(solana.PollRate), float64(time.Second))))
+				}
+			}
+		},
+		retry.NonRetriableErrors(context.Canceled),
+		retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 30*time.Second, 0.1),
+	)
+	i.log.WithError(err).Info("ingestion stream closed")
+}
 
-						go func(slot uint64) {
-							if err := i.processSlot(slot, w); err != nil {
-								result.Err = err
-							}
+// sequence verifies and writes the loaded blocks in order, producing a
+// result for each. It stops after the first inconsistent block.
+//
+// prev is the block referenced by the parent pointer, if any.
+func (i *ingestor) sequence(ctx context.Context, w history.Writer, parent ingestion.Pointer, prev *solana.Block, pending <-chan (<-chan loadResult), queue chan<- (<-chan ingestion.Result)) {
+	parentSlot, _ := slotFromPointer(parent)
+
+	for loadCh := range pending {
+		var loaded loadResult
+		select {
+		case loaded = <-loadCh:
+		case <-ctx.Done():
+			return
+		}
 
-							resultCh <- result
-							close(resultCh)
-						}(slot)
+		// Empty slots reference the previous block, so that it can be
+		// verified should ingestion be restarted from the slot.
+		latest := prev
+		if loaded.block != nil {
+			latest = loaded.block
+		}
+		blockPtr := pointerFromBlock(loaded.slot, latest)
+		result := ingestion.Result{
+			Parent: parent,
+			Block:  blockPtr,
+			Err:    loaded.err,
+		}
+		if result.Err == nil {
+			result.Err = checkConsistency(parentSlot, prev, loaded.block)
+		}
 
-						i.log.WithField("slot", slot).Trace("processing slot")
-						start = slot + 1
-					}
+		resultCh := make(chan ingestion.Result, 1)
+		select {
+		case queue <- resultCh:
+		case <-ctx.Done():
+			return
+		}
 
-					if len(blocks) == 0 {
-						// todo(config): maybe this should be configurable? currently
-						//               we ensure it's no faster than a second to alleviate load.
-						time.Sleep(time.Duration(math.Max(float64(solana.PollRate), float64(time.Second))))
-					}
-				}
-			},
-			retry.NonRetriableErrors(context.Canceled),
-			retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 30*time.Second, 0.1),
+		if result.Err != nil {
+			switch {
+			case errors.Is(result.Err, ErrSkippedSlot):
+				// The parent of the block exists, but was not returned by the
+				// RPC node. We refuse to commit the block, and re-load the
+				// range (using the fallback client, if any).
+				i.log.WithError(result.Err).WithField("slot", loaded.slot).Error("missing block data detected, rolling back")
+				i.inconsistencies.Inc()
+				i.missingBlocks.Inc()
+				i.setFallback(loaded.slot)
+			case errors.Is(result.Err, ErrForkDetected):
+				i.log.WithError(result.Err).WithField("slot", loaded.slot).Warn("inconsistent chain detected, rolling back")
+				i.inconsistencies.Inc()
+			}
+
+			resultCh <- result
+			close(resultCh)
+			return
+		}
+
+		go func(entries []*model.Entry) {
+			if err := writeEntries(w, entries); err != nil {
+				result.Err = err
+			}
+
+			resultCh <- result
+			close(resultCh)
+		}(loaded.entries)
+
+		// Empty slots are committed, but the next block should still be a
+		// child of the last non-empty block.
+		parent = blockPtr
+		if loaded.block != nil {
+			// Any slots between the block and its parent were legitimately
+			// skipped, as the block is consistent with the parent.
+			if loaded.block.Slot > loaded.block.ParentSlot+1 {
+				i.skippedSlots.Add(float64(loaded.block.Slot - loaded.block.ParentSlot - 1))
+			}
+
+			parentSlot = loaded.slot
+			prev = loaded.block
+		}
+	}
+}
+
+// checkConsistency verifies that block is a child of the previously loaded
+// block (prev). If prev is unknown, only skipped slots after the parent slot
+// can be detected.
+func checkConsistency(parentSlot uint64, prev, block *solana.Block) error {
+	// Not every slot has a block, in which case there is nothing to verify.
+	if block == nil {
+		return nil
+	}
+
+	if block.ParentSlot > parentSlot {
+		return errors.Wrapf(ErrSkippedSlot, "slot %d has parent %d, expected %d", block.Slot, block.ParentSlot, parentSlot)
+	}
+
+	if prev == nil {
+		return nil
+	}
+
+	if block.ParentSlot != prev.Slot || !bytes.Equal(block.PrevHash, prev.Hash) {
+		return errors.Wrapf(
+			ErrForkDetected,
+			"slot %d has parent (%d, %s), expected (%d, %s)",
+			block.Slot, block.ParentSlot, base58.Encode(block.PrevHash), prev.Slot, base58.Encode(prev.Hash),
 		)
-		i.log.WithError(err).Info("ingestion stream closed")
-	}()
+	}
 
-	return queue, nil
+	return nil
 }
 
-func (i *ingestor) processSlot(slot uint64, w history.Writer) error {
-	block, err := i.client.GetConfirmedBlock(slot)
+func writeEntries(w history.Writer, entries []*model.Entry) error {
+	for _, entry := range entries {
+		if err := w.Write(context.Background(), entry); err != nil {
+			return errors.Wrap(err, "failed to write txn")
+		}
+	}
+
+	return nil
+}
+
+// LoadBlock implements BlockLoader.LoadBlock.
+func (i *ingestor) LoadBlock(slot uint64) ([]*model.Entry, error) {
+	_, entries, err := i.loadBlock(i.client, slot)
+	return entries, err
+}
+
+// loadBlock returns the block at the specified slot, along with the entries
+// it contains. If the slot does not contain a block, a nil block is returned.
+func (i *ingestor) loadBlock(client solana.Client, slot uint64) (*solana.Block, []*model.Entry, error) {
+	block, err := client.GetConfirmedBlock(slot)
 	if err != nil {
 		// todo: wtf? why was this return nil...
-		return errors.Wrapf(err, "failed to get confirmed block")
+		return nil, nil, errors.Wrapf(err, "failed to get confirmed block")
 	}
 
 	// Not every slot has a block, so if we get no error, an empty
@@ -136,20 +638,20 @@ func (i *ingestor) processSlot(slot uint64, w history.Writer) error {
 	//
 	// todo(metrics): add meter here. should be close to zero in test/prod
 	if block == nil {
-		return nil
+		return nil, nil, nil
 	}
 
-	blockTime, err := i.client.GetBlockTime(slot)
+	blockTime, err := client.GetBlockTime(slot)
 	if err != nil {
 		// Note: even in the solana.ErrBlockNotAvailable case, we _should_
 		//       always have it available. It being not available indicates the
 		//       underlying RPC node should be fixed.
-		return errors.Wrap(err, "failed to get block time")
+		return nil, nil, errors.Wrap(err, "failed to get block time")
 	}
 
 	ts, err := ptypes.TimestampProto(blockTime)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal block time")
+		return nil, nil, errors.Wrap(err, "failed to marshal block time")
 	}
 
 	type shouldProcessFunc func(solana.BlockTransaction, int) (bool, error)
@@ -159,52 +661,53 @@ func (i *ingestor) processSlot(slot uint64, w history.Writer) error {
 		i.containsSetAuthority,
 	}
 
+	var entries []*model.Entry
 	for _, txn := range block.Transactions {
+		shouldProcess := false
 		for instr := range txn.Transaction.Message.Instructions {
-			shouldProcess := false
 			for _, check := range checks {
 				shouldProcess, err = check(txn, instr)
 				if err != nil {
-					return err
+					return nil, nil, err
 				}
 				if shouldProcess {
 					break
 				}
 			}
 
-			if !shouldProcess {
-				continue
-			}
-
-			var txnErr []byte
-			if txn.Err != nil {
-				raw, err := txn.Err.JSONString()
-				if err != nil {
-					return errors.Wrap(err, "failed to marshal transaction error")
-				}
-				txnErr = []byte(raw)
+			if shouldProcess {
+				break
 			}
+		}
 
-			entry := &model.Entry{
-				Version: model.KinVersion_KIN4,
-				Kind: &model.Entry_Solana{
-					Solana: &model.SolanaEntry{
-						Slot:             slot,
-						Confirmed:        true,
-						BlockTime:        ts,
-						Transaction:      txn.Transaction.Marshal(),
-						TransactionError: txnErr,
-					},
-				},
-			}
+		if !shouldProcess {
+			continue
+		}
 
-			if err := w.Write(context.Background(), entry); err != nil {
-				return errors.Wrap(err, "failed to write txn")
+		var txnErr []byte
+		if txn.Err != nil {
+			raw, err := txn.Err.JSONString()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to marshal transaction error")
 			}
+			txnErr = []byte(raw)
 		}
+
+		entries = append(entries, &model.Entry{
+			Version: model.KinVersion_KIN4,
+			Kind: &model.Entry_Solana{
+				Solana: &model.SolanaEntry{
+					Slot:             slot,
+					Confirmed:        true,
+					BlockTime:        ts,
+					Transaction:      txn.Transaction.Marshal(),
+					TransactionError: txnErr,
+				},
+			},
+		})
 	}
 
-	return nil
+	return block, entries, nil
 }
 
 func (i *ingestor) containsInitialize(txn solana.BlockTransaction, index int) (bool, error) {
@@ -5,19 +5,24 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/sha256"
+	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
 	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
 )
 
 type testEnv struct {
@@ -89,6 +94,29 @@ func TestInitialParams(t *testing.T) {
 	}
 }
 
+func TestLag(t *testing.T) {
+	env := setup(t)
+	env.client.On("GetSlot", solana.CommitmentMax).Return(uint64(100), nil)
+
+	reporter := env.ingestor.(ingestion.LagReporter)
+	for _, tc := range []struct {
+		p   ingestion.Pointer
+		lag uint64
+	}{
+		{p: nil, lag: 100},
+		{p: pointerFromSlot(40), lag: 60},
+		{p: pointerFromSlot(100), lag: 0},
+		{p: pointerFromSlot(120), lag: 0},
+	} {
+		lag, err := reporter.Lag(context.Background(), tc.p)
+		require.NoError(t, err)
+		assert.Equal(t, tc.lag, lag)
+	}
+
+	_, err := reporter.Lag(context.Background(), []byte{1})
+	assert.Error(t, err)
+}
+
 func TestRoundTrip(t *testing.T) {
 	env := setup(t)
 
@@ -385,6 +413,350 @@ func TestTransactionFilter_DeletedAccount(t *testing.T) {
 
 }
 
+func TestConcurrentOrdering(t *testing.T) {
+	env := setup(t)
+	env.ingestor = New("test", env.client, env.token, WithConcurrency(8), WithQueueSize(4))
+
+	tokenAccount := token.Account{
+		Mint: env.token,
+	}
+	accountInfo := solana.AccountInfo{
+		Data:  tokenAccount.Marshal(),
+		Owner: token.ProgramKey,
+	}
+
+	blocks := generateBlocks(t, 50, 1)
+	slots := make([]uint64, len(blocks))
+	for i, b := range blocks {
+		slots[i] = b.Slot
+
+		// Vary the load time so that blocks complete out of order.
+		env.client.On("GetConfirmedBlock", b.Slot).After(time.Duration(rand.Intn(10))*time.Millisecond).Return(b, nil)
+	}
+
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(1), mock.Anything).Return(slots, nil)
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(51), mock.Anything).Return([]uint64{}, nil)
+	env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	env.client.On("GetAccountInfo", mock.Anything, mock.Anything).Return(accountInfo, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue, err := env.ingestor.Ingest(ctx, env.writer, nil)
+	require.NoError(t, err)
+
+	results := collectResults(t, queue, len(blocks))
+	require.Len(t, results, len(blocks))
+	for i, r := range results {
+		require.NoError(t, r.Err)
+		assertSlot(t, uint64(i+1), r.Block)
+		if i > 0 {
+			assert.Equal(t, results[i-1].Block, r.Parent)
+		}
+	}
+
+	assert.Len(t, env.writer.Writes, len(blocks))
+}
+
+func TestInconsistentChain(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		slots    []uint64
+		parent   ingestion.Pointer
+		modify   func(blocks []*solana.Block)
+		expected error
+		valid    int
+	}{
+		{
+			name:  "fork",
+			slots: []uint64{1, 2, 3},
+			modify: func(blocks []*solana.Block) {
+				blocks[2].PrevHash = []byte("fork")
+			},
+			expected: ErrForkDetected,
+			valid:    2,
+		},
+		{
+			name:  "fork (parent slot)",
+			slots: []uint64{1, 2, 3},
+			modify: func(blocks []*solana.Block) {
+				blocks[2].ParentSlot = 1
+			},
+			expected: ErrForkDetected,
+			valid:    2,
+		},
+		{
+			name:     "skipped slot",
+			slots:    []uint64{1, 3},
+			expected: ErrSkippedSlot,
+			valid:    1,
+		},
+		{
+			name:     "skipped slot (initial)",
+			slots:    []uint64{3},
+			parent:   pointerFromSlot(1),
+			expected: ErrSkippedSlot,
+			valid:    0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := setup(t)
+
+			blocks := generateBlocks(t, 3, 0)
+			if tc.modify != nil {
+				tc.modify(blocks)
+			}
+
+			start, err := slotFromPointer(tc.parent)
+			require.NoError(t, err)
+			last := tc.slots[len(tc.slots)-1]
+
+			env.client.On("GetConfirmedBlocksWithLimit", start+1, mock.Anything).Return(tc.slots, nil)
+			env.client.On("GetConfirmedBlocksWithLimit", last+1, mock.Anything).Return([]uint64{}, nil)
+			env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+			for _, slot := range tc.slots {
+				env.client.On("GetConfirmedBlock", slot).Return(blocks[slot-1], nil)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			queue, err := env.ingestor.Ingest(ctx, env.writer, tc.parent)
+			require.NoError(t, err)
+
+			// The queue should be closed after the inconsistent block, so that
+			// ingestion can restart from the last consistent block.
+			results := collectResults(t, queue, len(tc.slots)+1)
+			require.Len(t, results, tc.valid+1)
+			for i := 0; i < tc.valid; i++ {
+				assert.NoError(t, results[i].Err)
+			}
+			assert.True(t, errors.Is(results[tc.valid].Err, tc.expected))
+			assertSlot(t, last, results[tc.valid].Block)
+		})
+	}
+}
+
+func TestSkippedSlots(t *testing.T) {
+	env := setup(t)
+
+	// Slot 2 was skipped by the cluster, so block 3 is a child of block 1.
+	blocks := generateBlocks(t, 3, 0)
+	blocks[2].ParentSlot = 1
+
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(1), mock.Anything).Return([]uint64{1, 3}, nil)
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(4), mock.Anything).Return([]uint64{}, nil)
+	env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	env.client.On("GetConfirmedBlock", uint64(1)).Return(blocks[0], nil)
+	env.client.On("GetConfirmedBlock", uint64(3)).Return(blocks[2], nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue, err := env.ingestor.Ingest(ctx, env.writer, nil)
+	require.NoError(t, err)
+
+	results := collectResults(t, queue, 2)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.NoError(t, r.Err)
+	}
+	assertSlot(t, 3, results[1].Block)
+	assertSlot(t, 1, results[1].Parent)
+}
+
+func TestMissingBlock_Fallback(t *testing.T) {
+	env := setup(t)
+	fallback := solana.NewMockClient()
+	env.ingestor = New("test", env.client, env.token, WithFallbackClient(fallback))
+
+	blocks := generateBlocks(t, 4, 0)
+
+	// The primary RPC node is missing block 2, which the fallback has.
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(1), mock.Anything).Return([]uint64{1, 3, 4}, nil)
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(5), mock.Anything).Return([]uint64{}, nil)
+	env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	fallback.On("GetConfirmedBlocksWithLimit", uint64(2), mock.Anything).Return([]uint64{2, 3, 4}, nil)
+	fallback.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	for _, b := range blocks {
+		env.client.On("GetConfirmedBlock", b.Slot).Return(b, nil)
+		fallback.On("GetConfirmedBlock", b.Slot).Return(b, nil)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue, err := env.ingestor.Ingest(ctx, env.writer, nil)
+	require.NoError(t, err)
+
+	// Block 3 must not be committed, since its parent is missing.
+	results := collectResults(t, queue, 4)
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.True(t, errors.Is(results[1].Err, ErrSkippedSlot))
+	assertSlot(t, 3, results[1].Block)
+	fallback.AssertNotCalled(t, "GetConfirmedBlocksWithLimit", mock.Anything, mock.Anything)
+
+	// Restarting from the last committed block should re-load the range
+	// from the fallback, and then continue with the primary.
+	queue, err = env.ingestor.Ingest(ctx, env.writer, results[0].Block)
+	require.NoError(t, err)
+
+	results = collectResults(t, queue, 3)
+	require.Len(t, results, 3)
+	for i, r := range results {
+		assert.NoError(t, r.Err)
+		assertSlot(t, uint64(i+2), r.Block)
+	}
+	fallback.AssertCalled(t, "GetConfirmedBlocksWithLimit", uint64(2), mock.Anything)
+	fallback.AssertCalled(t, "GetConfirmedBlock", uint64(2))
+}
+
+func TestRestart_VerifiesParent(t *testing.T) {
+	env := setup(t)
+
+	blocks := generateBlocks(t, 4, 0)
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(1), mock.Anything).Return([]uint64{1, 2, 3}, nil)
+	env.client.On("GetConfirmedBlocksWithLimit", uint64(4), mock.Anything).Return([]uint64{}, nil)
+	env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	for _, b := range blocks[:3] {
+		env.client.On("GetConfirmedBlock", b.Slot).Return(b, nil)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue, err := env.ingestor.Ingest(ctx, env.writer, nil)
+	require.NoError(t, err)
+	results := collectResults(t, queue, 3)
+	cancel()
+	require.Len(t, results, 3)
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+	committed := results[2].Block
+
+	// The process restarts, and the committed block (3) was replaced by a fork.
+	restart := func() {
+		env.client = solana.NewMockClient()
+		env.ingestor = New("test", env.client, env.token)
+		env.client.On("GetConfirmedBlocksWithLimit", uint64(4), mock.Anything).Return([]uint64{4}, nil)
+		env.client.On("GetConfirmedBlocksWithLimit", uint64(5), mock.Anything).Return([]uint64{}, nil)
+		env.client.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+	}
+	restart()
+
+	forked := *blocks[2]
+	forked.Hash = []byte("fork")
+	env.client.On("GetConfirmedBlock", uint64(3)).Return(&forked, nil)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err = env.ingestor.Ingest(ctx, env.writer, committed)
+	assert.True(t, errors.Is(err, ErrForkDetected))
+
+	// If block 3 is still part of the chain, the first block after it must be
+	// its child, even though the process restarted.
+	restart()
+	blocks[3].PrevHash = []byte("fork")
+	env.client.On("GetConfirmedBlock", uint64(3)).Return(blocks[2], nil)
+	env.client.On("GetConfirmedBlock", uint64(4)).Return(blocks[3], nil)
+
+	queue, err = env.ingestor.Ingest(ctx, env.writer, committed)
+	require.NoError(t, err)
+	results = collectResults(t, queue, 2)
+	require.Len(t, results, 1)
+	assert.True(t, errors.Is(results[0].Err, ErrForkDetected))
+}
+
+func TestResolve(t *testing.T) {
+	env := setup(t)
+	resolver := env.ingestor.(ingestion.ForkResolver)
+	ctx := context.Background()
+
+	committed := &solana.Block{Slot: 1000, ParentSlot: 999, Hash: []byte("committed")}
+	latest := pointerFromBlock(1000, committed)
+
+	// The committed block is still part of the chain.
+	env.client.On("GetConfirmedBlock", uint64(1000)).Return(committed, nil).Once()
+	resolved, err := resolver.Resolve(ctx, env.writer, latest)
+	require.NoError(t, err)
+	assert.Equal(t, latest, resolved)
+
+	// Legacy pointers cannot be verified.
+	resolved, err = resolver.Resolve(ctx, env.writer, pointerFromSlot(1000))
+	require.NoError(t, err)
+	assert.Equal(t, pointerFromSlot(1000), resolved)
+
+	// Entries in slot 990 are part of the chain, but the entry in slot 995 was
+	// orphaned (slot 995 was skipped by the chain), as was the committed block.
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	kept, _ := historytestutil.GenerateSolanaEntry(t, 990, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	orphaned, _ := historytestutil.GenerateSolanaEntry(t, 995, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	old, _ := historytestutil.GenerateSolanaEntry(t, 400, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+	for _, e := range []*model.Entry{old, kept, orphaned} {
+		require.NoError(t, env.writer.Write(ctx, e))
+	}
+
+	var keptTxn solana.Transaction
+	require.NoError(t, keptTxn.Unmarshal(kept.GetSolana().Transaction))
+
+	// The rewound pointer references the latest block at or before slot 488.
+	anchor := &solana.Block{Slot: 487, ParentSlot: 486, Hash: []byte("anchor")}
+	env.client.On("GetConfirmedBlock", uint64(1000)).Return((*solana.Block)(nil), nil)
+	env.client.On("GetConfirmedBlock", uint64(488)).Return((*solana.Block)(nil), nil)
+	env.client.On("GetConfirmedBlock", uint64(487)).Return(anchor, nil)
+	env.client.On("GetConfirmedBlock", uint64(990)).Return(&solana.Block{
+		Slot:         990,
+		Transactions: []solana.BlockTransaction{{Transaction: keptTxn}},
+	}, nil)
+	env.client.On("GetConfirmedBlock", uint64(995)).Return((*solana.Block)(nil), nil)
+
+	resolved, err = resolver.Resolve(ctx, env.writer, latest)
+	require.NoError(t, err)
+	assert.Equal(t, pointerFromBlock(1000-maxForkDepth, anchor), resolved)
+
+	entries, err := env.writer.GetTransactions(ctx, 0, 1000, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.True(t, proto.Equal(old, entries[0]))
+	assert.True(t, proto.Equal(kept, entries[1]))
+
+	txID, err := orphaned.GetTxID()
+	require.NoError(t, err)
+	_, err = env.writer.GetTransaction(ctx, txID)
+	assert.Equal(t, history.ErrNotFound, err)
+}
+
+func assertSlot(t *testing.T, expected uint64, p ingestion.Pointer) {
+	slot, err := slotFromPointer(p)
+	require.NoError(t, err)
+	assert.Equal(t, expected, slot)
+}
+
+// collectResults reads up to n results from the queue, stopping early if
+// the queue is closed.
+func collectResults(t *testing.T, queue ingestion.ResultQueue, n int) (results []ingestion.Result) {
+	for len(results) < n {
+		select {
+		case r, ok := <-queue:
+			if !ok {
+				return results
+			}
+
+			select {
+			case result := <-r:
+				results = append(results, result)
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for result")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for result (queue)")
+		}
+	}
+
+	return results
+}
+
 func generateBlocks(t *testing.T, num, txnsPerBlock int) []*solana.Block {
 	var blocks []*solana.Block
 
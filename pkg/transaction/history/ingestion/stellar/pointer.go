@@ -10,6 +10,12 @@ import (
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
 )
 
+// PointerFromSequence returns the ingestion.Pointer for the ledger with the
+// specified sequence.
+func PointerFromSequence(v model.KinVersion, seq uint32) ingestion.Pointer {
+	return pointerFromSequence(v, seq)
+}
+
 func pointerFromSequence(v model.KinVersion, seq uint32) ingestion.Pointer {
 	ptr := make([]byte, 5)
 	ptr[0] = byte(v)
@@ -17,6 +23,12 @@ func pointerFromSequence(v model.KinVersion, seq uint32) ingestion.Pointer {
 	return ptr
 }
 
+// SequenceFromPointer returns the sequence of the ledger referenced by the
+// ingestion.Pointer. A nil pointer references sequence 0.
+func SequenceFromPointer(p ingestion.Pointer) (seq uint32, err error) {
+	return sequenceFromPointer(p)
+}
+
 func sequenceFromPointer(p ingestion.Pointer) (seq uint32, err error) {
 	if len(p) == 0 {
 		return 0, nil
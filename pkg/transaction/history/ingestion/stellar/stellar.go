@@ -28,7 +28,23 @@ type ingestor struct {
 	networkPassphrase string
 }
 
+// LedgerLoader loads the history entries contained in a ledger.
+type LedgerLoader interface {
+	// LoadLedger returns the entries for the transactions in the ledger.
+	LoadLedger(sequence uint32) ([]*model.Entry, error)
+}
+
 func New(name string, version model.KinVersion, client horizonclient.ClientInterface, networkPassphrase string) ingestion.Ingestor {
+	return newIngestor(name, version, client, networkPassphrase)
+}
+
+// NewLedgerLoader returns a LedgerLoader that produces the same entries as
+// the ingestor.
+func NewLedgerLoader(version model.KinVersion, client horizonclient.ClientInterface, networkPassphrase string) LedgerLoader {
+	return newIngestor("", version, client, networkPassphrase)
+}
+
+func newIngestor(name string, version model.KinVersion, client horizonclient.ClientInterface, networkPassphrase string) *ingestor {
 	return &ingestor{
 		log: logrus.StandardLogger().WithFields(logrus.Fields{
 			"type":    "transaction/history/ingestion/stellar",
@@ -46,6 +62,25 @@ func (i *ingestor) Name() string {
 	return i.name
 }
 
+// Lag implements ingestion.LagReporter.Lag.
+func (i *ingestor) Lag(_ context.Context, p ingestion.Pointer) (uint64, error) {
+	seq, err := sequenceFromPointer(p)
+	if err != nil {
+		return 0, err
+	}
+
+	root, err := i.client.Root()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get horizon root")
+	}
+
+	if uint32(root.HorizonSequence) <= seq {
+		return 0, nil
+	}
+
+	return uint64(uint32(root.HorizonSequence) - seq), nil
+}
+
 // Ingest implements ingestion.Ingestor.Ingest.
 func (i *ingestor) Ingest(ctx context.Context, w history.Writer, parent ingestion.Pointer) (ingestion.ResultQueue, error) {
 	_, err := cursorFromPointer(parent)
@@ -144,8 +179,42 @@ func (i *ingestor) Ingest(ctx context.Context, w history.Writer, parent ingestio
 }
 
 func (i *ingestor) processLedger(ledger hProtocol.Ledger, w history.Writer) error {
+	entries, err := i.loadLedger(ledger)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := w.Write(context.Background(), entry); err != nil {
+			return errors.Wrap(err, "failed to write txn")
+		}
+	}
+
+	i.log.WithFields(logrus.Fields{
+		"ledger":       ledger.Sequence,
+		"transactions": len(entries),
+	}).Debug("Processed ledger")
+	return nil
+}
+
+// LoadLedger implements LedgerLoader.LoadLedger.
+func (i *ingestor) LoadLedger(sequence uint32) ([]*model.Entry, error) {
+	ledger, err := i.client.LedgerDetail(sequence)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get ledger: %d", sequence)
+	}
+
+	return i.loadLedger(ledger)
+}
+
+func (i *ingestor) loadLedger(ledger hProtocol.Ledger) ([]*model.Entry, error) {
 	var cursor string
-	var totalTxns int
+	var entries []*model.Entry
+
+	closeTime, err := ptypes.TimestampProto(ledger.ClosedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid close time")
+	}
 
 	for {
 		page, err := i.client.Transactions(horizonclient.TransactionRequest{
@@ -156,7 +225,7 @@ func (i *ingestor) processLedger(ledger hProtocol.Ledger, w history.Writer) erro
 			Cursor: cursor,
 		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to get transactions for ledger: %d", ledger.Sequence)
+			return nil, errors.Wrapf(err, "failed to get transactions for ledger: %d", ledger.Sequence)
 		}
 
 		// If there are no more transactions left, we're done paging.
@@ -164,29 +233,23 @@ func (i *ingestor) processLedger(ledger hProtocol.Ledger, w history.Writer) erro
 			break
 		}
 
-		totalTxns += len(page.Embedded.Records)
 		cursor = page.Embedded.Records[len(page.Embedded.Records)-1].PagingToken()
 
-		closeTime, err := ptypes.TimestampProto(ledger.ClosedAt)
-		if err != nil {
-			return errors.Wrap(err, "invalid close time")
-		}
-
 		for _, txn := range page.Embedded.Records {
 			envelopeBytes, err := base64.StdEncoding.DecodeString(txn.EnvelopeXdr)
 			if err != nil {
-				return errors.Wrap(err, "failed to parse envelope xdr")
+				return nil, errors.Wrap(err, "failed to parse envelope xdr")
 			}
 			resultBytes, err := base64.StdEncoding.DecodeString(txn.ResultXdr)
 			if err != nil {
-				return errors.Wrap(err, "failed to parse result xdr")
+				return nil, errors.Wrap(err, "failed to parse result xdr")
 			}
 			pagingToken, err := strconv.ParseUint(txn.PagingToken(), 10, 64)
 			if err != nil {
-				return errors.Wrap(err, "failed to parse paging token")
+				return nil, errors.Wrap(err, "failed to parse paging token")
 			}
 
-			entry := &model.Entry{
+			entries = append(entries, &model.Entry{
 				Version: i.version,
 				Kind: &model.Entry_Stellar{
 					Stellar: &model.StellarEntry{
@@ -198,17 +261,9 @@ func (i *ingestor) processLedger(ledger hProtocol.Ledger, w history.Writer) erro
 						ResultXdr:         resultBytes,
 					},
 				},
-			}
-
-			if err := w.Write(context.Background(), entry); err != nil {
-				return errors.Wrap(err, "failed to write txn")
-			}
+			})
 		}
 	}
 
-	i.log.WithFields(logrus.Fields{
-		"ledger":       ledger.Sequence,
-		"transactions": totalTxns,
-	}).Debug("Processed ledger")
-	return nil
+	return entries, nil
 }
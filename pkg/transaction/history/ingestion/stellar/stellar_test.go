@@ -74,6 +74,29 @@ func TestInitialParams(t *testing.T) {
 	}
 }
 
+func TestLag(t *testing.T) {
+	env := setup(t)
+	env.horizonClient.On("Root").Return(hProtocol.Root{HorizonSequence: 100}, nil)
+
+	reporter := env.ingestor.(ingestion.LagReporter)
+	for _, tc := range []struct {
+		p   ingestion.Pointer
+		lag uint64
+	}{
+		{p: nil, lag: 100},
+		{p: pointerFromSequence(model.KinVersion_KIN3, 40), lag: 60},
+		{p: pointerFromSequence(model.KinVersion_KIN3, 100), lag: 0},
+		{p: pointerFromSequence(model.KinVersion_KIN3, 120), lag: 0},
+	} {
+		lag, err := reporter.Lag(context.Background(), tc.p)
+		require.NoError(t, err)
+		assert.Equal(t, tc.lag, lag)
+	}
+
+	_, err := reporter.Lag(context.Background(), []byte{1})
+	assert.Error(t, err)
+}
+
 func TestRoundTrip(t *testing.T) {
 	env := setup(t)
 
@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+)
+
+func TestBackfill(t *testing.T) {
+	c := memory.New()
+	w := historymemory.New()
+	i := &rangeIngestor{processed: make(map[byte]int)}
+
+	// Only even blocks exist, so chunk ends that fall on odd blocks must be
+	// completed by the first block after the chunk.
+	chunks := []ingestion.BackfillChunk{
+		{Parent: nil, End: []byte{9}},
+		{Parent: []byte{9}, End: []byte{19}},
+		{Parent: []byte{19}, End: []byte{30}},
+	}
+
+	var mu sync.Mutex
+	done := make(map[string]bool)
+	progress := func(chunk ingestion.BackfillChunk, block ingestion.Pointer, finished bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if finished {
+			done[ingestion.GetBackfillCommitterName(i.Name(), chunk)] = true
+		}
+	}
+
+	require.NoError(t, ingestion.Backfill(context.Background(), c, w, i, chunks, 2, progress))
+
+	for _, chunk := range chunks {
+		name := ingestion.GetBackfillCommitterName(i.Name(), chunk)
+		assert.True(t, done[name])
+
+		p, err := c.Latest(context.Background(), name)
+		require.NoError(t, err)
+		assert.Equal(t, chunk.End, p)
+	}
+
+	for b := byte(2); b <= 30; b += 2 {
+		assert.True(t, i.count(b) > 0, "block %d", b)
+	}
+
+	// The live pointer should never be touched.
+	p, err := c.Latest(context.Background(), i.Name())
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	// Completed chunks should not be re-ingested.
+	i.reset()
+	require.NoError(t, ingestion.Backfill(context.Background(), c, w, i, chunks, 2, nil))
+	assert.Zero(t, i.ingestCalls())
+}
+
+func TestBackfill_Resume(t *testing.T) {
+	c := memory.New()
+	w := historymemory.New()
+	i := &rangeIngestor{
+		processed: make(map[byte]int),
+		failAt:    14,
+	}
+
+	chunks := []ingestion.BackfillChunk{
+		{Parent: nil, End: []byte{20}},
+	}
+
+	err := ingestion.Backfill(context.Background(), c, w, i, chunks, 1, nil)
+	require.Error(t, err)
+
+	name := ingestion.GetBackfillCommitterName(i.Name(), chunks[0])
+	p, err := c.Latest(context.Background(), name)
+	require.NoError(t, err)
+	assert.Equal(t, ingestion.Pointer{12}, p)
+
+	// Resuming should start after the last committed block.
+	i.Lock()
+	i.failAt = 0
+	i.Unlock()
+	i.reset()
+	require.NoError(t, ingestion.Backfill(context.Background(), c, w, i, chunks, 1, nil))
+
+	for b := byte(2); b <= 12; b += 2 {
+		assert.Zero(t, i.count(b), "block %d", b)
+	}
+	for b := byte(14); b <= 20; b += 2 {
+		assert.True(t, i.count(b) > 0, "block %d", b)
+	}
+
+	p, err = c.Latest(context.Background(), name)
+	require.NoError(t, err)
+	assert.Equal(t, chunks[0].End, p)
+}
+
+func TestBackfill_PendingResultCancellation(t *testing.T) {
+	c := memory.New()
+	w := historymemory.New()
+
+	// The result of the queued block is never produced.
+	i := &testIngestor{queue: make(chan (<-chan ingestion.Result), 1)}
+	i.queue <- make(chan ingestion.Result)
+
+	chunks := []ingestion.BackfillChunk{
+		{Parent: nil, End: []byte{20}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ingestion.Backfill(ctx, c, w, i, chunks, 1, nil)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("backfill did not stop after cancellation")
+	}
+}
+
+// rangeIngestor produces a result for every even block after the parent.
+type rangeIngestor struct {
+	sync.Mutex
+	processed map[byte]int
+	failAt    byte
+	calls     int
+}
+
+func (i *rangeIngestor) reset() {
+	i.Lock()
+	defer i.Unlock()
+	i.processed = make(map[byte]int)
+	i.calls = 0
+}
+
+func (i *rangeIngestor) ingestCalls() int {
+	i.Lock()
+	defer i.Unlock()
+	return i.calls
+}
+
+func (i *rangeIngestor) count(block byte) int {
+	i.Lock()
+	defer i.Unlock()
+	return i.processed[block]
+}
+
+func (i *rangeIngestor) Name() string {
+	return "range"
+}
+
+func (i *rangeIngestor) Ingest(ctx context.Context, w history.Writer, parent ingestion.Pointer) (ingestion.ResultQueue, error) {
+	queue := make(chan (<-chan ingestion.Result))
+
+	i.Lock()
+	failAt := i.failAt
+	i.calls++
+	i.Unlock()
+
+	go func() {
+		defer close(queue)
+
+		var block byte
+		if len(parent) > 0 {
+			block = parent[0]
+		}
+
+		for block = block + 1; block < 0xff; block++ {
+			if block%2 != 0 {
+				continue
+			}
+
+			result := ingestion.Result{
+				Parent: parent,
+				Block:  ingestion.Pointer{block},
+			}
+			parent = result.Block
+
+			if block == failAt {
+				result.Err = errors.New("failed")
+			} else {
+				i.Lock()
+				i.processed[block]++
+				i.Unlock()
+			}
+
+			resultCh := make(chan ingestion.Result, 1)
+			resultCh <- result
+
+			select {
+			case queue <- resultCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return queue, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func RunCommitterTests(t *testing.T, c ingestion.Committer, teardown func()) {
-	for _, tf := range []func(*testing.T, ingestion.Committer){testCommitter_HappyPath} {
+	for _, tf := range []func(*testing.T, ingestion.Committer){testCommitter_HappyPath, testCommitter_Reset, testCommitter_Fencing, testCommitter_Pauser} {
 		tf(t, c)
 		teardown()
 	}
@@ -48,3 +48,111 @@ func testCommitter_HappyPath(t *testing.T, c ingestion.Committer) {
 		assert.Equal(t, ingestion.ErrInvalidCommit, c.Commit(ctx, "kin2", []byte{9}, []byte{9}))
 	})
 }
+
+func testCommitter_Reset(t *testing.T, c ingestion.Committer) {
+	t.Run("TestCommitter_Reset", func(t *testing.T) {
+		ctx := context.Background()
+
+		// Reset should be able to initialize a commit.
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Reset(ctx, "kin2", []byte{1}, []byte{5}))
+		assert.NoError(t, c.Reset(ctx, "kin2", nil, []byte{5}))
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Reset(ctx, "kin2", nil, []byte{6}))
+
+		for i := byte(6); i < 10; i++ {
+			assert.NoError(t, c.Commit(ctx, "kin2", []byte{i - 1}, []byte{i}))
+		}
+
+		// Reset requires the latest commit to match.
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Reset(ctx, "kin2", []byte{8}, []byte{2}))
+
+		// Resets may not go forward, as blocks would be skipped.
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Reset(ctx, "kin2", []byte{9}, []byte{12}))
+
+		// Unlike commits, resets may go backwards.
+		assert.NoError(t, c.Reset(ctx, "kin2", []byte{9}, []byte{2}))
+		latest, err := c.Latest(ctx, "kin2")
+		assert.NoError(t, err)
+		assert.Equal(t, ingestion.Pointer([]byte{2}), latest)
+
+		// Commits continue from the reset pointer.
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Commit(ctx, "kin2", []byte{9}, []byte{10}))
+		assert.NoError(t, c.Commit(ctx, "kin2", []byte{2}, []byte{3}))
+
+		// Ensure only kin2 ingestor was updated
+		latest, err = c.Latest(ctx, "kin3")
+		assert.NoError(t, err)
+		assert.Nil(t, latest)
+	})
+}
+
+func testCommitter_Fencing(t *testing.T, c ingestion.Committer) {
+	t.Run("TestCommitter_Fencing", func(t *testing.T) {
+		ctx := context.Background()
+		fenced := func(token uint64) context.Context {
+			return ingestion.WithFencingToken(ctx, token)
+		}
+
+		assert.NoError(t, c.Commit(fenced(2), "kin2", nil, []byte{1}))
+		assert.NoError(t, c.Commit(fenced(2), "kin2", []byte{1}, []byte{2}))
+
+		// Commits (and resets) from an older lease are rejected.
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Commit(fenced(1), "kin2", []byte{2}, []byte{3}))
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Reset(fenced(1), "kin2", []byte{2}, []byte{1}))
+
+		latest, err := c.Latest(ctx, "kin2")
+		assert.NoError(t, err)
+		assert.Equal(t, ingestion.Pointer([]byte{2}), latest)
+
+		// Once a newer lease commits, the previous lease is rejected.
+		assert.NoError(t, c.Commit(fenced(3), "kin2", []byte{2}, []byte{3}))
+		assert.Equal(t, ingestion.ErrInvalidCommit, c.Commit(fenced(2), "kin2", []byte{3}, []byte{4}))
+		assert.NoError(t, c.Reset(fenced(3), "kin2", []byte{3}, []byte{2}))
+
+		// Commits without a token are not fenced.
+		assert.NoError(t, c.Commit(ctx, "kin2", []byte{2}, []byte{3}))
+
+		// Tokens are tracked per ingestor.
+		assert.NoError(t, c.Commit(fenced(1), "kin3", nil, []byte{1}))
+	})
+}
+
+func testCommitter_Pauser(t *testing.T, c ingestion.Committer) {
+	t.Run("TestCommitter_Pauser", func(t *testing.T) {
+		pauser, ok := c.(ingestion.Pauser)
+		if !ok {
+			t.Skip("committer does not implement ingestion.Pauser")
+		}
+
+		ctx := context.Background()
+
+		paused, err := pauser.Paused(ctx, "kin2")
+		assert.NoError(t, err)
+		assert.False(t, paused)
+
+		// Pausing is idempotent, and doesn't affect the commits.
+		assert.NoError(t, c.Commit(ctx, "kin2", nil, []byte{1}))
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, pauser.SetPaused(ctx, "kin2", true))
+			paused, err = pauser.Paused(ctx, "kin2")
+			assert.NoError(t, err)
+			assert.True(t, paused)
+		}
+		assert.NoError(t, c.Commit(ctx, "kin2", []byte{1}, []byte{2}))
+
+		latest, err := c.Latest(ctx, "kin2")
+		assert.NoError(t, err)
+		assert.Equal(t, ingestion.Pointer([]byte{2}), latest)
+
+		// Ensure only kin2 ingestor was paused
+		paused, err = pauser.Paused(ctx, "kin3")
+		assert.NoError(t, err)
+		assert.False(t, paused)
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, pauser.SetPaused(ctx, "kin2", false))
+			paused, err = pauser.Paused(ctx, "kin2")
+			assert.NoError(t, err)
+			assert.False(t, paused)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+)
+
+func TestController(t *testing.T) {
+	ctrl := ingestion.NewController()
+	committer := memory.New()
+	lock := &holderLock{}
+	ingestor := &chainIngestor{head: 20}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- ctrl.Run(ctx, lock, committer, historymemory.New(), ingestor)
+	}()
+
+	latest := func() ingestion.Pointer {
+		p, err := committer.Latest(context.Background(), "chain")
+		require.NoError(t, err)
+		return p
+	}
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(ingestion.Pointer{20}, latest())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	statuses, err := ctrl.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "chain", statuses[0].Name)
+	assert.Equal(t, ingestion.Pointer{20}, statuses[0].Latest)
+	assert.False(t, statuses[0].Paused)
+	assert.True(t, statuses[0].LockHeld)
+	assert.Equal(t, "test-node", statuses[0].LockHolder)
+	assert.EqualValues(t, 0, statuses[0].Lag)
+	assert.False(t, statuses[0].LastCommit.IsZero())
+
+	// Registering the same ingestor twice should fail.
+	assert.Error(t, ctrl.Run(ctx, lock, committer, historymemory.New(), ingestor))
+
+	// Pausing releases the lock.
+	require.NoError(t, ctrl.Pause(context.Background(), "chain"))
+	require.NoError(t, ctrl.Pause(context.Background(), "chain"))
+	require.Eventually(t, func() bool {
+		s, err := ctrl.Status(context.Background(), "chain")
+		require.NoError(t, err)
+		return s.Paused && !s.LockHeld && s.LockHolder == ""
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Rewinding while paused should not result in any progress.
+	previous, err := ctrl.Rewind(context.Background(), "chain", ingestion.Pointer{5})
+	require.NoError(t, err)
+	assert.Equal(t, ingestion.Pointer{20}, previous)
+
+	time.Sleep(100 * time.Millisecond)
+	s, err := ctrl.Status(context.Background(), "chain")
+	require.NoError(t, err)
+	assert.Equal(t, ingestion.Pointer{5}, s.Latest)
+	assert.EqualValues(t, 15, s.Lag)
+
+	// Resuming continues from the rewound pointer.
+	require.NoError(t, ctrl.Resume(context.Background(), "chain"))
+	require.NoError(t, ctrl.Resume(context.Background(), "chain"))
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(ingestion.Pointer{20}, latest())
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, ingestor.getStarts(), ingestion.Pointer{5})
+
+	// Rewinding while running restarts ingestion from the rewound pointer.
+	_, err = ctrl.Rewind(context.Background(), "chain", ingestion.Pointer{3})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(ingestion.Pointer{20}, latest())
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, ingestor.getStarts(), ingestion.Pointer{3})
+
+	// Rewinding cannot move the pointer forward.
+	_, err = ctrl.Rewind(context.Background(), "chain", ingestion.Pointer{21})
+	assert.Equal(t, ingestion.ErrInvalidPointer, err)
+	_, err = ctrl.Rewind(context.Background(), "chain", nil)
+	assert.Equal(t, ingestion.ErrInvalidPointer, err)
+
+	// Ingestors without a commit cannot be rewound.
+	reg, err := ctrl.Register("uncommitted", lock, committer, nil)
+	require.NoError(t, err)
+	_, err = ctrl.Register("uncommitted", lock, committer, nil)
+	assert.Error(t, err)
+	_, err = ctrl.Rewind(context.Background(), "uncommitted", ingestion.Pointer{1})
+	assert.Equal(t, ingestion.ErrInvalidPointer, err)
+	p, err := committer.Latest(context.Background(), "uncommitted")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	reg.Close()
+
+	for _, err := range []error{
+		ctrl.Pause(context.Background(), "unknown"),
+		ctrl.Resume(context.Background(), "unknown"),
+		func() error { _, err := ctrl.Rewind(context.Background(), "unknown", ingestion.Pointer{1}); return err }(),
+		func() error { _, err := ctrl.Status(context.Background(), "unknown"); return err }(),
+	} {
+		assert.Equal(t, ingestion.ErrIngestorNotFound, err)
+	}
+
+	cancel()
+	select {
+	case err := <-doneCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed waiting for Run to stop")
+	}
+
+	statuses, err = ctrl.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+}
+
+func TestController_SharedPause(t *testing.T) {
+	// Both controllers share the committer, as separate processes would.
+	committer := memory.New()
+	controllers := []*ingestion.Controller{
+		ingestion.NewController(ingestion.WithPausePollInterval(10 * time.Millisecond)),
+		ingestion.NewController(ingestion.WithPausePollInterval(10 * time.Millisecond)),
+	}
+	locks := []*holderLock{{}, {}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := range controllers {
+		go func(i int) {
+			_ = controllers[i].Run(ctx, locks[i], committer, historymemory.New(), &chainIngestor{head: 20})
+		}(i)
+	}
+
+	latest := func() ingestion.Pointer {
+		p, err := committer.Latest(context.Background(), "chain")
+		require.NoError(t, err)
+		return p
+	}
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(ingestion.Pointer{20}, latest())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Pausing via one controller pauses the loops of both.
+	require.NoError(t, controllers[0].Pause(context.Background(), "chain"))
+	require.Eventually(t, func() bool {
+		for _, ctrl := range controllers {
+			s, err := ctrl.Status(context.Background(), "chain")
+			require.NoError(t, err)
+			if !s.Paused || s.LockHeld {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	previous, err := controllers[0].Rewind(context.Background(), "chain", ingestion.Pointer{5})
+	require.NoError(t, err)
+	assert.Equal(t, ingestion.Pointer{20}, previous)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, ingestion.Pointer{5}, latest())
+
+	// Resuming via the other controller resumes both.
+	require.NoError(t, controllers[1].Resume(context.Background(), "chain"))
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(ingestion.Pointer{20}, latest())
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, ctrl := range controllers {
+		s, err := ctrl.Status(context.Background(), "chain")
+		require.NoError(t, err)
+		assert.False(t, s.Paused)
+	}
+}
+
+// chainIngestor produces a result for every (single byte) block after the
+// parent, up to and including head.
+type chainIngestor struct {
+	head byte
+
+	mu     sync.Mutex
+	starts []ingestion.Pointer
+}
+
+func (i *chainIngestor) Name() string {
+	return "chain"
+}
+
+func (i *chainIngestor) Ingest(ctx context.Context, w history.Writer, parent ingestion.Pointer) (ingestion.ResultQueue, error) {
+	i.mu.Lock()
+	i.starts = append(i.starts, parent)
+	i.mu.Unlock()
+
+	queue := make(chan (<-chan ingestion.Result))
+	go func() {
+		defer close(queue)
+
+		var start byte
+		if len(parent) > 0 {
+			start = parent[0]
+		}
+
+		for b := start + 1; b <= i.head; b++ {
+			resultCh := make(chan ingestion.Result, 1)
+			resultCh <- ingestion.Result{
+				Parent: parent,
+				Block:  ingestion.Pointer{b},
+			}
+			parent = ingestion.Pointer{b}
+
+			select {
+			case queue <- resultCh:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		<-ctx.Done()
+	}()
+
+	return queue, nil
+}
+
+func (i *chainIngestor) Lag(_ context.Context, p ingestion.Pointer) (uint64, error) {
+	if len(p) == 0 {
+		return uint64(i.head), nil
+	}
+
+	return uint64(i.head - p[0]), nil
+}
+
+func (i *chainIngestor) getStarts() []ingestion.Pointer {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]ingestion.Pointer(nil), i.starts...)
+}
+
+type holderLock struct {
+	mu   sync.Mutex
+	held bool
+}
+
+func (l *holderLock) Lock(_ context.Context) error {
+	l.mu.Lock()
+	l.held = true
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *holderLock) Unlock() error {
+	l.mu.Lock()
+	l.held = false
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *holderLock) Holder(_ context.Context) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.held {
+		return "", nil
+	}
+	return "test-node", nil
+}
@@ -215,6 +215,46 @@ func TestRun_CommitErrors(t *testing.T) {
 	assert.EqualValues(t, []byte{0xff}, p)
 }
 
+func TestRun_Fencing(t *testing.T) {
+	env := setup(t)
+
+	lock := &fencedLock{token: 5}
+	c := &fenceRecordingCommitter{Committer: env.committer}
+
+	for i := 0; i < cap(env.ingestor.queue); i++ {
+		resultCh := make(chan ingestion.Result, 1)
+		resultCh <- ingestion.Result{
+			Parent: []byte{byte(i)},
+			Block:  []byte{byte(i + 1)},
+		}
+		env.ingestor.queue <- resultCh
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = ingestion.Run(ctx, lock, c, env.writer, env.ingestor)
+	}()
+
+	require.Eventually(t, func() bool {
+		p, err := env.committer.Latest(context.Background(), "test")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(ingestion.Pointer{0xff}, p)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// All commits should be made with the token of the lease.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.tokens, cap(env.ingestor.queue))
+	for _, token := range c.tokens {
+		assert.EqualValues(t, 5, token)
+	}
+
+	// Commits from an older lease are rejected.
+	staleCtx := ingestion.WithFencingToken(context.Background(), 4)
+	assert.Equal(t, ingestion.ErrInvalidCommit, env.committer.Commit(staleCtx, "test", []byte{0xff}, []byte{0xff, 0x00}))
+}
+
 type testIngestor struct {
 	err   error
 	queue chan (<-chan ingestion.Result)
@@ -249,6 +289,32 @@ func (l *testLock) Unlock() error {
 	return nil
 }
 
+type fencedLock struct {
+	testLock
+	token uint64
+}
+
+func (l *fencedLock) Token() (uint64, bool) {
+	return l.token, true
+}
+
+type fenceRecordingCommitter struct {
+	ingestion.Committer
+
+	mu     sync.Mutex
+	tokens []uint64
+}
+
+func (c *fenceRecordingCommitter) Commit(ctx context.Context, name string, parent, block ingestion.Pointer) error {
+	token, _ := ingestion.FencingTokenFromContext(ctx)
+
+	c.mu.Lock()
+	c.tokens = append(c.tokens, token)
+	c.mu.Unlock()
+
+	return c.Committer.Commit(ctx, name, parent, block)
+}
+
 type testCommitter struct {
 	ingestion.Committer
 	err error
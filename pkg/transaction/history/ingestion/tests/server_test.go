@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
+	ingestionpb "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/proto"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+)
+
+func TestServer(t *testing.T) {
+	ctrl := ingestion.NewController()
+	s := ingestion.NewServer(ctrl)
+	committer := memory.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resp, err := s.ListIngestors(ctx, &ingestionpb.ListIngestorsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Ingestors)
+
+	go func() {
+		_ = ctrl.Run(ctx, &holderLock{}, committer, historymemory.New(), &chainIngestor{head: 10})
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, err = s.ListIngestors(ctx, &ingestionpb.ListIngestorsRequest{})
+		require.NoError(t, err)
+		return len(resp.Ingestors) == 1 && assert.ObjectsAreEqual([]byte{10}, resp.Ingestors[0].Latest)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := resp.Ingestors[0]
+	assert.Equal(t, "chain", status.Name)
+	assert.Equal(t, ingestionpb.IngestorStatus_RUNNING, status.State)
+	assert.True(t, status.LockHeld)
+	assert.Equal(t, "test-node", status.LockHolder)
+	assert.EqualValues(t, 0, status.Lag)
+	assert.NotNil(t, status.LastCommitTime)
+
+	pauseResp, err := s.Pause(ctx, &ingestionpb.PauseRequest{Name: "chain"})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.PauseResponse_OK, pauseResp.Result)
+
+	pauseResp, err = s.Pause(ctx, &ingestionpb.PauseRequest{Name: "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.PauseResponse_NOT_FOUND, pauseResp.Result)
+
+	resp, err = s.ListIngestors(ctx, &ingestionpb.ListIngestorsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Ingestors, 1)
+	assert.Equal(t, ingestionpb.IngestorStatus_PAUSED, resp.Ingestors[0].State)
+
+	rewindResp, err := s.Rewind(ctx, &ingestionpb.RewindRequest{Name: "chain", Block: []byte{4}})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.RewindResponse_OK, rewindResp.Result)
+	assert.Equal(t, []byte{10}, rewindResp.Previous)
+
+	rewindResp, err = s.Rewind(ctx, &ingestionpb.RewindRequest{Name: "chain", Block: []byte{4, 5}})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.RewindResponse_INVALID_POINTER, rewindResp.Result)
+
+	rewindResp, err = s.Rewind(ctx, &ingestionpb.RewindRequest{Name: "unknown", Block: []byte{4}})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.RewindResponse_NOT_FOUND, rewindResp.Result)
+
+	latest, err := committer.Latest(ctx, "chain")
+	require.NoError(t, err)
+	assert.Equal(t, ingestion.Pointer{4}, latest)
+
+	resumeResp, err := s.Resume(ctx, &ingestionpb.ResumeRequest{Name: "chain"})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.ResumeResponse_OK, resumeResp.Result)
+
+	resumeResp, err = s.Resume(ctx, &ingestionpb.ResumeRequest{Name: "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, ingestionpb.ResumeResponse_NOT_FOUND, resumeResp.Result)
+
+	require.Eventually(t, func() bool {
+		latest, err := committer.Latest(ctx, "chain")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqual(ingestion.Pointer{10}, latest)
+	}, 5*time.Second, 10*time.Millisecond)
+}
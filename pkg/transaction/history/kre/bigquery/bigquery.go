@@ -1,22 +1,113 @@
 package bigquery
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
+	"github.com/pkg/errors"
+
 	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
 )
 
+const dateColumn = "date"
+
 type submitter struct {
-	table *bigquery.Table
+	dataset *bigquery.Dataset
+	table   string
+	replace bool
 }
 
-func New(bq *bigquery.Client, table string) kre.Submitter {
-	return &submitter{
-		table: bq.Dataset("solana").Table(table),
+// Option configures a submitter.
+type Option func(s *submitter)
+
+// WithReplacePartitions configures the submitter to replace the date
+// partitions that it submits to, rather than streaming rows into the table.
+//
+// BigQuery only deduplicates streamed rows on a best effort basis, so it
+// should be used for tables whose rows may be recomputed, and that are
+// always submitted a full date at a time, such as kre.RollupsSchema. The
+// table must be partitioned by its date column.
+func WithReplacePartitions() Option {
+	return func(s *submitter) {
+		s.replace = true
+	}
+}
+
+func New(bq *bigquery.Client, table string, opts ...Option) kre.Submitter {
+	s := &submitter{
+		dataset: bq.Dataset("solana"),
+		table:   table,
+	}
+	for _, o := range opts {
+		o(s)
 	}
+
+	return s
 }
 
 func (s *submitter) Submit(ctx context.Context, src interface{}) (err error) {
-	return s.table.Inserter().Put(ctx, src)
+	if !s.replace {
+		return s.dataset.Table(s.table).Inserter().Put(ctx, src)
+	}
+
+	rows, err := kre.GetRows(src)
+	if err != nil {
+		return err
+	}
+
+	partitions := make(map[string][]byte)
+	for _, r := range rows {
+		date, ok := r.Values[dateColumn].(string)
+		if !ok || date == "" {
+			return errors.Errorf("row %s is missing a date", r.InsertID)
+		}
+
+		data, err := json.Marshal(r.Values)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal row %s", r.InsertID)
+		}
+
+		partitions[date] = append(append(partitions[date], data...), '\n')
+	}
+
+	dates := make([]string, 0, len(partitions))
+	for date := range partitions {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if err := s.replacePartition(ctx, date, partitions[date]); err != nil {
+			return errors.Wrapf(err, "failed to replace partition %s", date)
+		}
+	}
+
+	return nil
+}
+
+// replacePartition loads the (newline delimited JSON) rows into the partition
+// of date, truncating its existing rows.
+func (s *submitter) replacePartition(ctx context.Context, date string, data []byte) error {
+	source := bigquery.NewReaderSource(bytes.NewReader(data))
+	source.SourceFormat = bigquery.JSON
+
+	decorated := fmt.Sprintf("%s$%s", s.table, strings.Replace(date, "-", "", -1))
+	loader := s.dataset.Table(decorated).LoaderFrom(source)
+	loader.WriteDisposition = bigquery.WriteTruncate
+
+	job, err := loader.Run(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to start load job")
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to wait for load job")
+	}
+
+	return errors.Wrap(status.Err(), "load job failed")
 }
@@ -0,0 +1,399 @@
+// Package file implements a kre.Submitter that writes rows to local files,
+// allowing KRE reporting to run without BigQuery.
+//
+// Rows are partitioned by their date column, using the Hive style layout:
+//
+//	<dir>/date=<yyyy-mm-dd>/part-<start>-<seq>.<jsonl|parquet>
+//
+// where start is the time at which the submitter was created, and seq is
+// incremented every time a new part is opened. Parts therefore sort in the
+// order they were written.
+//
+// If the submitter replaces partitions (see WithReplacePartitions), each
+// partition instead consists of a single part:
+//
+//	<dir>/date=<yyyy-mm-dd>/part.<jsonl|parquet>
+//
+// In addition to the columns of the schema, every row contains an insert_id
+// column. Rows may be submitted more than once, so consumers should use it
+// to deduplicate rows. Binary columns are base64 encoded.
+package file
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
+)
+
+// Format is the file format that rows are written in.
+type Format int
+
+const (
+	// FormatJSONL writes rows as newline delimited JSON objects.
+	//
+	// Rows are appended to the current part of a partition until it
+	// contains the maximum number of rows, at which point a new part is
+	// opened.
+	FormatJSONL Format = iota
+
+	// FormatParquet writes rows as Parquet.
+	//
+	// Since a Parquet file cannot be appended to, each submission writes
+	// (at least) one new part per partition. Parts are written to a temporary
+	// file and then renamed, so partial parts are never visible.
+	FormatParquet
+)
+
+// ParseFormat parses a Format from its name ("jsonl" or "parquet").
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "jsonl":
+		return FormatJSONL, nil
+	case "parquet":
+		return FormatParquet, nil
+	default:
+		return 0, errors.Errorf("unsupported format: %s", s)
+	}
+}
+
+func (f Format) extension() string {
+	if f == FormatParquet {
+		return ".parquet"
+	}
+	return ".jsonl"
+}
+
+const (
+	insertIDColumn = "insert_id"
+	dateColumn     = "date"
+
+	defaultMaxRowsPerFile = 100000
+)
+
+// Option configures a submitter.
+type Option func(*submitter)
+
+// WithMaxRowsPerFile specifies the maximum number of rows written to a
+// single part.
+//
+// If none is provided, 100000 is used.
+func WithMaxRowsPerFile(n int) Option {
+	return func(s *submitter) {
+		if n > 0 {
+			s.maxRows = n
+		}
+	}
+}
+
+// WithReplacePartitions configures the submitter to replace the contents of
+// each partition that is submitted to, rather than appending to it. The
+// partition is written to a temporary file and then renamed, so it is
+// replaced atomically. The maximum number of rows per part does not apply.
+//
+// It should be used for tables whose rows may be recomputed, and that are
+// always submitted a full date at a time, such as kre.RollupsSchema.
+func WithReplacePartitions() Option {
+	return func(s *submitter) {
+		s.replace = true
+	}
+}
+
+type part struct {
+	name string
+	rows int
+}
+
+type submitter struct {
+	dir     string
+	schema  kre.Schema
+	format  Format
+	maxRows int
+	replace bool
+
+	parquetSchema string
+
+	sync.Mutex
+	start   time.Time
+	seq     int
+	current map[string]*part
+}
+
+// New returns a kre.Submitter that writes rows with the provided schema to
+// partitioned files in dir.
+func New(dir string, schema kre.Schema, format Format, opts ...Option) kre.Submitter {
+	s := &submitter{
+		dir:           dir,
+		schema:        schema,
+		format:        format,
+		maxRows:       defaultMaxRowsPerFile,
+		parquetSchema: parquetSchema(schema),
+		start:         time.Now(),
+		current:       make(map[string]*part),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Submit implements kre.Submitter.Submit.
+func (s *submitter) Submit(_ context.Context, src interface{}) error {
+	rows, err := kre.GetRows(src)
+	if err != nil {
+		return err
+	}
+
+	partitions := make(map[string][][]byte)
+	for _, r := range rows {
+		date, ok := r.Values[dateColumn].(string)
+		if !ok || date == "" {
+			return errors.Errorf("row %s is missing a date", r.InsertID)
+		}
+
+		data, err := s.marshal(r)
+		if err != nil {
+			return err
+		}
+
+		partitions[date] = append(partitions[date], data)
+	}
+
+	dates := make([]string, 0, len(partitions))
+	for date := range partitions {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	s.Lock()
+	defer s.Unlock()
+
+	for _, date := range dates {
+		dir := filepath.Join(s.dir, fmt.Sprintf("date=%s", date))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrap(err, "failed to create partition directory")
+		}
+
+		switch {
+		case s.replace:
+			err = s.replacePartition(dir, partitions[date])
+		case s.format == FormatJSONL:
+			err = s.appendJSONL(dir, date, partitions[date])
+		case s.format == FormatParquet:
+			err = s.writeParquet(dir, partitions[date])
+		default:
+			err = errors.Errorf("unsupported format: %d", s.format)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to write partition %s", date)
+		}
+	}
+
+	return nil
+}
+
+// marshal returns the JSON encoded row, as written to JSONL files (and
+// provided to the Parquet writer).
+func (s *submitter) marshal(r kre.Row) ([]byte, error) {
+	obj := make(map[string]interface{}, len(r.Values)+1)
+	obj[insertIDColumn] = r.InsertID
+	for _, c := range s.schema {
+		v, ok := r.Values[c.Name]
+		if !ok {
+			continue
+		}
+
+		// Binary values are always base64 encoded, since Parquet strings
+		// must be valid UTF-8.
+		if b, ok := v.([]byte); ok {
+			v = base64.StdEncoding.EncodeToString(b)
+		}
+		obj[c.Name] = v
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal row %s", r.InsertID)
+	}
+
+	return data, nil
+}
+
+func (s *submitter) nextPartName() string {
+	s.seq++
+	return fmt.Sprintf("part-%019d-%06d%s", s.start.UnixNano(), s.seq, s.format.extension())
+}
+
+func (s *submitter) appendJSONL(dir, date string, rows [][]byte) error {
+	for len(rows) > 0 {
+		p := s.current[date]
+		if p == nil || p.rows >= s.maxRows {
+			p = &part{name: s.nextPartName()}
+			s.current[date] = p
+		}
+
+		n := s.maxRows - p.rows
+		if n > len(rows) {
+			n = len(rows)
+		}
+
+		var buf []byte
+		for _, r := range rows[:n] {
+			buf = append(buf, r...)
+			buf = append(buf, '\n')
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, p.name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return errors.Wrap(err, "failed to open part")
+		}
+		if _, err := f.Write(buf); err != nil {
+			f.Close()
+			return errors.Wrap(err, "failed to write rows")
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return errors.Wrap(err, "failed to sync part")
+		}
+		if err := f.Close(); err != nil {
+			return errors.Wrap(err, "failed to close part")
+		}
+
+		p.rows += n
+		rows = rows[n:]
+	}
+
+	return nil
+}
+
+func (s *submitter) replacePartition(dir string, rows [][]byte) error {
+	path := filepath.Join(dir, "part"+s.format.extension())
+
+	switch s.format {
+	case FormatJSONL:
+		return writeJSONLPart(path, rows)
+	case FormatParquet:
+		return s.writeParquetPart(path, rows)
+	default:
+		return errors.Errorf("unsupported format: %d", s.format)
+	}
+}
+
+func writeJSONLPart(path string, rows [][]byte) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	var buf []byte
+	for _, r := range rows {
+		buf = append(buf, r...)
+		buf = append(buf, '\n')
+	}
+
+	if _, err := f.Write(buf); err != nil {
+		return errors.Wrap(err, "failed to write rows")
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync part")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close part")
+	}
+
+	return errors.Wrap(os.Rename(f.Name(), path), "failed to rename part")
+}
+
+func (s *submitter) writeParquet(dir string, rows [][]byte) error {
+	for len(rows) > 0 {
+		n := s.maxRows
+		if n > len(rows) {
+			n = len(rows)
+		}
+
+		if err := s.writeParquetPart(filepath.Join(dir, s.nextPartName()), rows[:n]); err != nil {
+			return err
+		}
+
+		rows = rows[n:]
+	}
+
+	return nil
+}
+
+func (s *submitter) writeParquetPart(path string, rows [][]byte) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	pw, err := writer.NewJSONWriterFromWriter(s.parquetSchema, f, 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to create parquet writer")
+	}
+	for _, r := range rows {
+		if err := pw.Write(string(r)); err != nil {
+			return errors.Wrap(err, "failed to write row")
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return errors.Wrap(err, "failed to finalize parquet file")
+	}
+
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync part")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close part")
+	}
+
+	return errors.Wrap(os.Rename(f.Name(), path), "failed to rename part")
+}
+
+// parquetSchema returns the JSON schema used by the Parquet writer.
+//
+// Dates and timestamps are written as strings, in the same format as they
+// are submitted.
+func parquetSchema(schema kre.Schema) string {
+	fields := []string{
+		fmt.Sprintf(`{"Tag": "name=%s, type=UTF8, repetitiontype=REQUIRED"}`, insertIDColumn),
+	}
+	for _, c := range schema {
+		var t string
+		switch c.Type {
+		case kre.ColumnTypeInteger:
+			t = "type=INT64"
+		default:
+			t = "type=UTF8"
+		}
+
+		fields = append(fields, fmt.Sprintf(`{"Tag": "name=%s, %s, repetitiontype=OPTIONAL"}`, c.Name, t))
+	}
+
+	return fmt.Sprintf(`{"Tag": "name=parquet_go_root, repetitiontype=REQUIRED", "Fields": [%s]}`, strings.Join(fields, ", "))
+}
@@ -0,0 +1,248 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
+)
+
+var testSchema = kre.Schema{
+	{Name: "date", Type: kre.ColumnTypeDate},
+	{Name: "tx_id", Type: kre.ColumnTypeString},
+	{Name: "quarks", Type: kre.ColumnTypeInteger},
+	{Name: "memo_binary", Type: kre.ColumnTypeBytes},
+}
+
+type testRow struct {
+	id     int
+	date   string
+	quarks uint64
+	memo   []byte
+}
+
+// Save implements bigquery.ValueSaver.
+func (r *testRow) Save() (map[string]bigquery.Value, string, error) {
+	row := map[string]bigquery.Value{
+		"date":   r.date,
+		"tx_id":  fmt.Sprintf("tx-%d", r.id),
+		"quarks": r.quarks,
+	}
+	if len(r.memo) > 0 {
+		row["memo_binary"] = r.memo
+	}
+
+	return row, fmt.Sprintf("id-%d", r.id), nil
+}
+
+func generateRows(start, n int, date string) []*testRow {
+	rows := make([]*testRow, n)
+	for i := range rows {
+		rows[i] = &testRow{
+			id:     start + i,
+			date:   date,
+			quarks: uint64(start + i),
+		}
+		if i%2 == 0 {
+			rows[i].memo = []byte{0xff, byte(i)}
+		}
+	}
+	return rows
+}
+
+func expectedRows(rows []*testRow) []map[string]interface{} {
+	expected := make([]map[string]interface{}, len(rows))
+	for i, r := range rows {
+		expected[i] = map[string]interface{}{
+			"insert_id": fmt.Sprintf("id-%d", r.id),
+			"date":      r.date,
+			"tx_id":     fmt.Sprintf("tx-%d", r.id),
+			"quarks":    float64(r.quarks),
+		}
+		if len(r.memo) > 0 {
+			expected[i]["memo_binary"] = base64.StdEncoding.EncodeToString(r.memo)
+		}
+	}
+	return expected
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("jsonl")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSONL, f)
+
+	f, err = ParseFormat("parquet")
+	require.NoError(t, err)
+	assert.Equal(t, FormatParquet, f)
+
+	_, err = ParseFormat("csv")
+	assert.Error(t, err)
+}
+
+func TestSubmit_JSONL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kre-file")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := New(dir, testSchema, FormatJSONL, WithMaxRowsPerFile(3))
+
+	first := append(generateRows(0, 2, "2021-01-01"), generateRows(2, 1, "2021-01-02")...)
+	second := generateRows(3, 3, "2021-01-01")
+	require.NoError(t, s.Submit(context.Background(), first))
+	require.NoError(t, s.Submit(context.Background(), second))
+
+	// The first part of 2021-01-01 should have been filled before rotating.
+	parts := listParts(t, filepath.Join(dir, "date=2021-01-01"))
+	require.Len(t, parts, 2)
+	assert.Len(t, readJSONL(t, parts[0]), 3)
+	assert.Len(t, readJSONL(t, parts[1]), 2)
+
+	var actual []map[string]interface{}
+	for _, p := range parts {
+		actual = append(actual, readJSONL(t, p)...)
+	}
+	assert.Equal(t, expectedRows(append(first[:2], second...)), actual)
+
+	parts = listParts(t, filepath.Join(dir, "date=2021-01-02"))
+	require.Len(t, parts, 1)
+	assert.Equal(t, expectedRows(first[2:]), readJSONL(t, parts[0]))
+
+	assert.Error(t, s.Submit(context.Background(), generateRows(10, 1, "")))
+	assert.Error(t, s.Submit(context.Background(), "row"))
+}
+
+func TestSubmit_Parquet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kre-file")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := New(dir, testSchema, FormatParquet, WithMaxRowsPerFile(3))
+
+	first := generateRows(0, 4, "2021-01-01")
+	second := generateRows(4, 1, "2021-01-01")
+	require.NoError(t, s.Submit(context.Background(), first))
+	require.NoError(t, s.Submit(context.Background(), second))
+
+	// Parquet parts are never appended to, so each submission results in
+	// new parts.
+	parts := listParts(t, filepath.Join(dir, "date=2021-01-01"))
+	require.Len(t, parts, 3)
+
+	var actual []map[string]interface{}
+	for _, p := range parts {
+		actual = append(actual, readParquet(t, p)...)
+	}
+	assert.Equal(t, expectedRows(append(first, second...)), actual)
+}
+
+func TestSubmit_ReplacePartitions(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatParquet} {
+		dir, err := ioutil.TempDir("", "kre-file")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		read := readJSONL
+		if format == FormatParquet {
+			read = readParquet
+		}
+
+		s := New(dir, testSchema, format, WithMaxRowsPerFile(1), WithReplacePartitions())
+
+		first := append(generateRows(0, 2, "2021-01-01"), generateRows(2, 1, "2021-01-02")...)
+		require.NoError(t, s.Submit(context.Background(), first))
+
+		// Resubmitting a date replaces its partition, without affecting the
+		// other partitions.
+		second := generateRows(0, 3, "2021-01-01")
+		second[0].quarks = 100
+		require.NoError(t, s.Submit(context.Background(), second))
+
+		parts := listParts(t, filepath.Join(dir, "date=2021-01-01"))
+		require.Len(t, parts, 1)
+		assert.Equal(t, "part"+format.extension(), filepath.Base(parts[0]))
+		assert.Equal(t, expectedRows(second), read(t, parts[0]))
+
+		parts = listParts(t, filepath.Join(dir, "date=2021-01-02"))
+		require.Len(t, parts, 1)
+		assert.Equal(t, expectedRows(first[2:]), read(t, parts[0]))
+	}
+}
+
+func listParts(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	var parts []string
+	for _, info := range infos {
+		parts = append(parts, filepath.Join(dir, info.Name()))
+	}
+	sort.Strings(parts)
+	return parts
+}
+
+func readJSONL(t *testing.T, path string) (rows []map[string]interface{}) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		rows = append(rows, row)
+	}
+	require.NoError(t, scanner.Err())
+
+	return rows
+}
+
+func readParquet(t *testing.T, path string) (rows []map[string]interface{}) {
+	f, err := local.NewLocalFileReader(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	pr, err := reader.NewParquetReader(f, nil, 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+
+	records, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	require.NoError(t, err)
+
+	// The reader returns dynamically created structs (with capitalized field
+	// names), so we round trip them through JSON, omitting null (optional)
+	// columns.
+	for _, r := range records {
+		raw, err := json.Marshal(r)
+		require.NoError(t, err)
+
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &record))
+
+		row := make(map[string]interface{})
+		for _, c := range append(kre.Schema{{Name: insertIDColumn}}, testSchema...) {
+			for k, v := range record {
+				if strings.EqualFold(k, c.Name) && v != nil {
+					row[c.Name] = v
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
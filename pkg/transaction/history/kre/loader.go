@@ -12,6 +12,8 @@ import (
 	"github.com/kinecosystem/agora-common/retry/backoff"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/kinecosystem/go/strkey"
+	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -58,8 +60,10 @@ type Loader struct {
 	sc        solana.Client
 	tc        *token.Client
 
-	creationsSubmitter Submitter
-	paymentsSubmitter  Submitter
+	creationsSubmitter        Submitter
+	paymentsSubmitter         Submitter
+	ownershipChangesSubmitter Submitter
+	closuresSubmitter         Submitter
 
 	stateStore accountinfo.StateStore
 }
@@ -74,6 +78,10 @@ func GetKREIngestorName() string {
 	return "kre_ingestor"
 }
 
+// NewLoader returns a new Loader.
+//
+// The ownership changes and closures submitters are optional. If nil,
+// ownership changes and closures are only applied to the state store.
 func NewLoader(
 	hist history.Reader,
 	committer ingestion.Committer,
@@ -82,18 +90,22 @@ func NewLoader(
 	tc *token.Client,
 	creationsSubmitter Submitter,
 	paymentsSubmitter Submitter,
+	ownershipChangesSubmitter Submitter,
+	closuresSubmitter Submitter,
 	stateStore accountinfo.StateStore,
 ) *Loader {
 	return &Loader{
-		log:                logrus.StandardLogger().WithField("type", "transaction/history/kre"),
-		hist:               hist,
-		committer:          committer,
-		lock:               lock,
-		sc:                 sc,
-		tc:                 tc,
-		creationsSubmitter: creationsSubmitter,
-		paymentsSubmitter:  paymentsSubmitter,
-		stateStore:         stateStore,
+		log:                       logrus.StandardLogger().WithField("type", "transaction/history/kre"),
+		hist:                      hist,
+		committer:                 committer,
+		lock:                      lock,
+		sc:                        sc,
+		tc:                        tc,
+		creationsSubmitter:        creationsSubmitter,
+		paymentsSubmitter:         paymentsSubmitter,
+		ownershipChangesSubmitter: ownershipChangesSubmitter,
+		closuresSubmitter:         closuresSubmitter,
+		stateStore:                stateStore,
 	}
 }
 
@@ -205,107 +217,18 @@ func (l *Loader) process(ctx context.Context) error {
 		}
 
 		//
-		// Process the loaded entries
+		// Process the loaded entries, and ship them off to the reporting backend
 		//
-		var payments []*payment
-		var creations []*creation
-		var ownershipChanges []*ownershipChange
-		var slot uint64
-		for _, entry := range entries {
-			se := entry.GetSolana()
-			if se == nil {
-				continue
-			}
-			slot = se.Slot
-
-			successful := len(se.TransactionError) == 0
-			var txn solana.Transaction
-			if err := txn.Unmarshal(se.Transaction); err != nil {
-				return errors.Wrap(err, "failed to unmarshal transaction")
-			}
-
-			txnPayments, err := l.getPayments(txn, successful)
-			if err != nil {
-				return errors.Wrap(err, "failed to get payments from transaction")
-			}
-			txnCreations, err := l.getCreations(txn, successful)
-			if err != nil {
-				return errors.Wrap(err, "failed to get creations from transaction")
-			}
-
-			// If there aren't any payments or creations in this batch, then we can avoid further
-			// processing.
-			if len(txnPayments) == 0 && len(txnCreations) == 0 {
-				log.WithField("txn", base64.StdEncoding.EncodeToString(se.Transaction)).Debug("No payments or creations to process, skipping")
-				continue
-			}
-
-			memos := l.getMemos(txn)
-			blockTime, err := l.sc.GetBlockTime(se.Slot)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get block time for slot: %d", se.Slot)
-			}
-
-			for _, p := range txnPayments {
-				p.blockTime = blockTime
-				copy(p.txID[:], txn.Signature())
-				p.successful = successful
-				p.subsidizer = txn.Message.Accounts[0]
-
-				// We use the first memo to proceed us in the transaction.
-				for mIdx := len(memos) - 1; mIdx >= 0; mIdx-- {
-					if memos[mIdx].offset < p.offset {
-						p.memoText = memos[mIdx].text
-						p.memo = memos[mIdx].data
-						p.appIndex = memos[mIdx].appIndex
-						break
-					}
-				}
-			}
-
-			for _, c := range txnCreations {
-				c.blockTime = blockTime
-				copy(c.txID[:], txn.Signature())
-				c.successful = successful
-				c.subsidizer = txn.Message.Accounts[0]
-
-				// We use the first memo to proceed us in the transaction.
-				for mIdx := len(memos) - 1; mIdx >= 0; mIdx-- {
-					if memos[mIdx].offset < c.offset {
-						c.memoText = memos[mIdx].text
-						c.memo = memos[mIdx].data
-						c.appIndex = memos[mIdx].appIndex
-						break
-					}
-				}
-			}
-
-			txnOwnershipChanges, err := l.getOwnershipChanges(txn, successful)
-			if err != nil {
-				return errors.Wrap(err, "failed to get ownership changes from transaction")
-			}
-
-			payments = append(payments, txnPayments...)
-			creations = append(creations, txnCreations...)
-			ownershipChanges = append(ownershipChanges, txnOwnershipChanges...)
-		}
-
-		//
-		// Ship the entries off to BigQuery
-		//
-		if len(creations) > 0 {
-			if err := l.creationsSubmitter.Submit(context.Background(), creations); err != nil {
-				return errors.Wrap(err, "failed to insert creations")
-			}
+		b, err := l.load(ctx, entries, false)
+		if err != nil {
+			return err
 		}
-		if len(payments) > 0 {
-			if err := l.paymentsSubmitter.Submit(context.Background(), payments); err != nil {
-				return errors.Wrap(err, "failed to insert payments")
-			}
+		if err := l.submit(b); err != nil {
+			return err
 		}
 
 		// Update affected accounts
-		if err := l.updateAccounts(ctx, slot, creations, payments, ownershipChanges); err != nil {
+		if err := l.updateAccounts(ctx, b.slot, b.creations, b.payments, b.ownershipChanges, b.closures); err != nil {
 			return errors.Wrap(err, "failed to update accounts")
 		}
 
@@ -338,6 +261,192 @@ func (l *Loader) process(ctx context.Context) error {
 	return nil
 }
 
+// batch contains the rows loaded from a set of entries.
+type batch struct {
+	// slot is the slot of the last entry in the batch.
+	slot uint64
+
+	creations        []*creation
+	payments         []*payment
+	ownershipChanges []*ownershipChange
+	closures         []*closure
+}
+
+// load extracts the KRE rows from the entries.
+//
+// If historical is set, the owners of payment destinations are resolved from
+// history (see historicalOwner), rather than from the current state of the
+// accounts. Owners are memoized for the duration of the call, so entries must
+// be provided in block order, without gaps.
+func (l *Loader) load(ctx context.Context, entries []*model.Entry, historical bool) (*batch, error) {
+	log := l.log.WithField("method", "load")
+
+	var owners map[string]resolvedOwner
+	if historical {
+		owners = make(map[string]resolvedOwner)
+	}
+
+	var payments []*payment
+	var creations []*creation
+	var ownershipChanges []*ownershipChange
+	var closures []*closure
+	var slot uint64
+	for _, entry := range entries {
+		se := entry.GetSolana()
+		if se == nil {
+			continue
+		}
+		slot = se.Slot
+
+		successful := len(se.TransactionError) == 0
+		var txn solana.Transaction
+		if err := txn.Unmarshal(se.Transaction); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal transaction")
+		}
+
+		resolve := l.currentOwner
+		if historical {
+			key, err := entry.GetOrderingKey()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compute ordering key")
+			}
+			resolve = l.historicalOwner(key, txn, successful, owners)
+		}
+
+		txnPayments, err := l.getPayments(ctx, txn, successful, resolve)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get payments from transaction")
+		}
+		if historical && successful {
+			l.updateOwners(owners, txn)
+		}
+		txnCreations, err := l.getCreations(txn, successful)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get creations from transaction")
+		}
+
+		// Copy into a fresh slice so that appending doesn't write into the
+		// backing array of creations.
+		created := make([]*creation, 0, len(creations)+len(txnCreations))
+		created = append(created, creations...)
+		created = append(created, txnCreations...)
+
+		txnOwnershipChanges, err := l.getOwnershipChanges(ctx, txn, successful, created)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ownership changes from transaction")
+		}
+		txnClosures, err := l.getClosures(ctx, txn, created)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get closures from transaction")
+		}
+
+		// If there aren't any relevant instructions in this batch, then we can avoid further
+		// processing.
+		if len(txnPayments) == 0 && len(txnCreations) == 0 && len(txnOwnershipChanges) == 0 && len(txnClosures) == 0 {
+			log.WithField("txn", base64.StdEncoding.EncodeToString(se.Transaction)).Debug("No payments, creations, ownership changes or closures to process, skipping")
+			continue
+		}
+
+		memos := l.getMemos(txn)
+		blockTime, err := l.sc.GetBlockTime(se.Slot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get block time for slot: %d", se.Slot)
+		}
+
+		// We use the first memo to proceed us in the transaction.
+		for _, p := range txnPayments {
+			p.blockTime = blockTime
+			copy(p.txID[:], txn.Signature())
+			p.successful = successful
+			p.subsidizer = txn.Message.Accounts[0]
+
+			if m := getMemo(memos, p.offset); m != nil {
+				p.memoText = m.text
+				p.memo = m.data
+				p.appIndex = m.appIndex
+			}
+		}
+
+		for _, c := range txnCreations {
+			c.blockTime = blockTime
+			copy(c.txID[:], txn.Signature())
+			c.successful = successful
+			c.subsidizer = txn.Message.Accounts[0]
+
+			if m := getMemo(memos, c.offset); m != nil {
+				c.memoText = m.text
+				c.memo = m.data
+				c.appIndex = m.appIndex
+			}
+		}
+
+		for _, o := range txnOwnershipChanges {
+			o.blockTime = blockTime
+			copy(o.txID[:], txn.Signature())
+			o.successful = successful
+			o.subsidizer = txn.Message.Accounts[0]
+
+			if m := getMemo(memos, o.offset); m != nil {
+				o.memoText = m.text
+				o.memo = m.data
+				o.appIndex = m.appIndex
+			}
+		}
+
+		for _, c := range txnClosures {
+			c.blockTime = blockTime
+			copy(c.txID[:], txn.Signature())
+			c.successful = successful
+			c.subsidizer = txn.Message.Accounts[0]
+
+			if m := getMemo(memos, c.offset); m != nil {
+				c.memoText = m.text
+				c.memo = m.data
+				c.appIndex = m.appIndex
+			}
+		}
+
+		payments = append(payments, txnPayments...)
+		creations = append(creations, txnCreations...)
+		ownershipChanges = append(ownershipChanges, txnOwnershipChanges...)
+		closures = append(closures, txnClosures...)
+	}
+
+	return &batch{
+		slot:             slot,
+		creations:        creations,
+		payments:         payments,
+		ownershipChanges: ownershipChanges,
+		closures:         closures,
+	}, nil
+}
+
+// submit ships the rows in the batch off to the reporting backend.
+func (l *Loader) submit(b *batch) error {
+	if len(b.creations) > 0 {
+		if err := l.creationsSubmitter.Submit(context.Background(), b.creations); err != nil {
+			return errors.Wrap(err, "failed to insert creations")
+		}
+	}
+	if len(b.payments) > 0 {
+		if err := l.paymentsSubmitter.Submit(context.Background(), b.payments); err != nil {
+			return errors.Wrap(err, "failed to insert payments")
+		}
+	}
+	if len(b.ownershipChanges) > 0 && l.ownershipChangesSubmitter != nil {
+		if err := l.ownershipChangesSubmitter.Submit(context.Background(), b.ownershipChanges); err != nil {
+			return errors.Wrap(err, "failed to insert ownership changes")
+		}
+	}
+	if len(b.closures) > 0 && l.closuresSubmitter != nil {
+		if err := l.closuresSubmitter.Submit(context.Background(), b.closures); err != nil {
+			return errors.Wrap(err, "failed to insert closures")
+		}
+	}
+
+	return nil
+}
+
 func (l *Loader) getCreations(txn solana.Transaction, successful bool) (creations []*creation, err error) {
 	for i := range txn.Message.Instructions {
 		decompiled, err := token.DecompileInitializeAccount(txn.Message, i)
@@ -358,19 +467,15 @@ func (l *Loader) getCreations(txn solana.Transaction, successful bool) (creation
 	return creations, nil
 }
 
-func (l *Loader) getPayments(txn solana.Transaction, successful bool) (payments []*payment, err error) {
+func (l *Loader) getPayments(ctx context.Context, txn solana.Transaction, successful bool, resolve ownerResolver) (payments []*payment, err error) {
 	for i := range txn.Message.Instructions {
 		decompiled, err := token.DecompileTransferAccount(txn.Message, i)
 		if err != nil {
 			continue
 		}
 
-		destAccount, err := l.tc.GetAccount(decompiled.Destination, solana.CommitmentSingle)
-		if err == token.ErrInvalidTokenAccount {
-			// The destination account is either not a token account, or it's not for
-			// our configured mint
-			continue
-		} else if err != nil {
+		destOwner, ok, err := resolve(ctx, decompiled.Destination, i)
+		if err != nil {
 			// If we cannot retrieve the destination account, _and_ there's a transaction failure,
 			// then it is likely (but not guaranteed) that the transaction failed because the
 			// destination does not exist.
@@ -389,13 +494,18 @@ func (l *Loader) getPayments(txn solana.Transaction, successful bool) (payments
 			// Transaction failed, so we don't _really_ care here.
 			continue
 		}
+		if !ok {
+			// The destination account is either not a token account, or it's not for
+			// our configured mint
+			continue
+		}
 
 		payments = append(payments, &payment{
 			offset:      i,
 			source:      decompiled.Source,
 			sourceOwner: decompiled.Owner,
 			dest:        decompiled.Destination,
-			destOwner:   destAccount.Owner,
+			destOwner:   destOwner,
 			quarks:      decompiled.Amount,
 		})
 	}
@@ -403,7 +513,200 @@ func (l *Loader) getPayments(txn solana.Transaction, successful bool) (payments
 	return payments, nil
 }
 
-func (l *Loader) getOwnershipChanges(txn solana.Transaction, successful bool) (changes []*ownershipChange, err error) {
+// ownerResolver returns the owner of a token account referenced by the
+// instruction at offset. ok is false if the account is not a token account
+// for the configured mint.
+type ownerResolver func(ctx context.Context, account ed25519.PublicKey, offset int) (owner ed25519.PublicKey, ok bool, err error)
+
+// currentOwner resolves the owner of a token account from its current state.
+func (l *Loader) currentOwner(_ context.Context, account ed25519.PublicKey, _ int) (ed25519.PublicKey, bool, error) {
+	info, err := l.tc.GetAccount(account, solana.CommitmentSingle)
+	if err == token.ErrInvalidTokenAccount {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return info.Owner, true, nil
+}
+
+const defaultOwnerHistoryPageSize = 100
+
+// historicalOwner returns an ownerResolver that resolves the owner of a token
+// account as of the instruction of the entry with the provided ordering key.
+//
+// The owner is taken from the most recent instruction in the account's history
+// that reveals it: a transfer from the account (which is signed by its owner),
+// a change of its account holder, or its initialization. Since history only
+// contains transactions for the configured mint, such accounts are assumed to
+// be for the configured mint.
+//
+// If history does not reveal the owner, the current state of the account is
+// used instead. Accounts that no longer exist are then ignored, rather than
+// treated as an error.
+//
+// owners memoizes the owners of accounts as of the (successful) entries
+// preceding the entry, and is updated by the caller using updateOwners once
+// the entry has been loaded. txn and successful are those of the entry.
+func (l *Loader) historicalOwner(key []byte, txn solana.Transaction, successful bool, owners map[string]resolvedOwner) ownerResolver {
+	return func(ctx context.Context, account ed25519.PublicKey, offset int) (ed25519.PublicKey, bool, error) {
+		// The instructions of the entry preceding the payment take precedence
+		// over the memoized owners, which do not include the entry.
+		if successful {
+			end := len(txn.Message.Instructions)
+			if offset < end {
+				end = offset
+			}
+			if owner := l.ownerFromInstructions(txn, account, end); owner != nil {
+				return owner, true, nil
+			}
+		}
+
+		if resolved, ok := owners[string(account)]; ok {
+			return resolved.owner, resolved.ok, nil
+		}
+
+		owner, ok, err := l.lookupOwner(ctx, key, account, offset)
+		if err != nil {
+			return nil, false, err
+		}
+
+		owners[string(account)] = resolvedOwner{owner: owner, ok: ok}
+		return owner, ok, nil
+	}
+}
+
+// resolvedOwner is a memoized result of an ownerResolver.
+type resolvedOwner struct {
+	owner ed25519.PublicKey
+	ok    bool
+}
+
+// updateOwners updates the memoized owners of the accounts whose owner is
+// revealed by the instructions of txn (see ownerFromInstructions).
+func (l *Loader) updateOwners(owners map[string]resolvedOwner, txn solana.Transaction) {
+	for i := range txn.Message.Instructions {
+		if transfer, err := token.DecompileTransferAccount(txn.Message, i); err == nil {
+			owners[string(transfer.Source)] = resolvedOwner{owner: transfer.Owner, ok: true}
+			continue
+		}
+
+		if setAuthority, err := token.DecompileSetAuthority(txn.Message, i); err == nil {
+			if setAuthority.Type == token.AuthorityTypeAccountHolder {
+				owners[string(setAuthority.Account)] = resolvedOwner{owner: setAuthority.NewAuthority, ok: true}
+			}
+			continue
+		}
+
+		if initialize, err := token.DecompileInitializeAccount(txn.Message, i); err == nil {
+			if bytes.Equal(initialize.Mint, l.tc.Token()) {
+				owners[string(initialize.Account)] = resolvedOwner{owner: initialize.Owner, ok: true}
+			}
+		}
+	}
+}
+
+// lookupOwner resolves the owner of a token account as of the instruction at
+// offset of the entry with the provided ordering key, by walking back through
+// the account's history.
+func (l *Loader) lookupOwner(ctx context.Context, key []byte, account ed25519.PublicKey, offset int) (ed25519.PublicKey, bool, error) {
+	address, err := strkey.Encode(strkey.VersionByteAccountID, account)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to encode account")
+	}
+
+	start := key
+	for {
+		entries, err := l.hist.GetAccountTransactions(ctx, address, &history.ReadOptions{
+			Descending: true,
+			Start:      start,
+			Limit:      defaultOwnerHistoryPageSize,
+		})
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to get account transactions")
+		}
+
+		full := len(entries) >= defaultOwnerHistoryPageSize
+		for _, e := range entries {
+			entryKey, err := e.GetOrderingKey()
+			if err != nil {
+				return nil, false, errors.Wrap(err, "failed to compute ordering key")
+			}
+
+			// Start is inclusive, so subsequent pages begin with the last
+			// entry of the previous page.
+			if !bytes.Equal(start, key) && bytes.Equal(entryKey, start) {
+				continue
+			}
+			start = entryKey
+
+			se := e.GetSolana()
+			if se == nil || len(se.TransactionError) > 0 {
+				continue
+			}
+
+			var txn solana.Transaction
+			if err := txn.Unmarshal(se.Transaction); err != nil {
+				return nil, false, errors.Wrap(err, "failed to unmarshal transaction")
+			}
+
+			// Only the instructions preceding the payment apply to the
+			// entry being loaded.
+			end := len(txn.Message.Instructions)
+			if bytes.Equal(entryKey, key) && offset < end {
+				end = offset
+			}
+
+			if owner := l.ownerFromInstructions(txn, account, end); owner != nil {
+				return owner, true, nil
+			}
+		}
+
+		if !full {
+			break
+		}
+	}
+
+	owner, ok, err := l.currentOwner(ctx, account, offset)
+	if err == token.ErrAccountNotFound {
+		l.log.WithField("account", base58.Encode(account)).Debug("ignoring payment to unknown account")
+		return nil, false, nil
+	}
+	return owner, ok, err
+}
+
+// ownerFromInstructions returns the owner of account revealed by the last of
+// the first end instructions of txn, if any.
+func (l *Loader) ownerFromInstructions(txn solana.Transaction, account ed25519.PublicKey, end int) ed25519.PublicKey {
+	for i := end - 1; i >= 0; i-- {
+		if transfer, err := token.DecompileTransferAccount(txn.Message, i); err == nil {
+			if bytes.Equal(transfer.Source, account) {
+				return transfer.Owner
+			}
+			continue
+		}
+
+		if setAuthority, err := token.DecompileSetAuthority(txn.Message, i); err == nil {
+			if setAuthority.Type == token.AuthorityTypeAccountHolder && bytes.Equal(setAuthority.Account, account) {
+				return setAuthority.NewAuthority
+			}
+			continue
+		}
+
+		if initialize, err := token.DecompileInitializeAccount(txn.Message, i); err == nil {
+			if bytes.Equal(initialize.Account, account) && bytes.Equal(initialize.Mint, l.tc.Token()) {
+				return initialize.Owner
+			}
+		}
+	}
+
+	return nil
+}
+
+// getOwnershipChanges returns the ownership changes of token accounts for the
+// configured mint. Accounts are resolved the same way as for closures, since
+// an account may have been closed after its ownership changed.
+func (l *Loader) getOwnershipChanges(ctx context.Context, txn solana.Transaction, successful bool, created []*creation) (changes []*ownershipChange, err error) {
 	for i := range txn.Message.Instructions {
 		decompiled, err := token.DecompileSetAuthority(txn.Message, i)
 		if err != nil {
@@ -413,25 +716,87 @@ func (l *Loader) getOwnershipChanges(txn solana.Transaction, successful bool) (c
 			continue
 		}
 
-		_, err = l.tc.GetAccount(decompiled.Account, solana.CommitmentSingle)
-		if err == token.ErrInvalidTokenAccount {
-			continue
-		} else if err != nil {
+		known, err := l.isKnownAccount(ctx, decompiled.Account, created)
+		if err != nil {
 			if successful {
-				return nil, errors.Wrap(err, "failed to get account for non-failed transaction")
+				return nil, errors.Wrap(err, "failed to resolve account for non-failed transaction")
 			}
 			continue
 		}
+		if !known {
+			continue
+		}
 
 		changes = append(changes, &ownershipChange{
-			account:  decompiled.Account,
-			newOwner: decompiled.NewAuthority,
+			offset:        i,
+			account:       decompiled.Account,
+			previousOwner: decompiled.CurrentAuthority,
+			newOwner:      decompiled.NewAuthority,
 		})
 	}
 	return changes, nil
 }
 
-func (l *Loader) updateAccounts(ctx context.Context, slot uint64, creations []*creation, payments []*payment, ownershipChanges []*ownershipChange) error {
+// getClosures returns the closures of token accounts for the configured mint.
+//
+// Since a closed account no longer exists, we can't rely on fetching it to
+// determine its mint. Instead, an account is considered to be for the
+// configured mint if it is known to the state store, was created in the
+// current batch (created), or still exists (i.e. the closure failed).
+func (l *Loader) getClosures(ctx context.Context, txn solana.Transaction, created []*creation) (closures []*closure, err error) {
+	for i := range txn.Message.Instructions {
+		decompiled, err := decompileCloseAccount(txn.Message, i)
+		if err != nil {
+			continue
+		}
+
+		known, err := l.isKnownAccount(ctx, decompiled.account, created)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			continue
+		}
+
+		closures = append(closures, &closure{
+			offset:       i,
+			account:      decompiled.account,
+			accountOwner: decompiled.owner,
+			destination:  decompiled.destination,
+		})
+	}
+
+	return closures, nil
+}
+
+func (l *Loader) isKnownAccount(ctx context.Context, account ed25519.PublicKey, created []*creation) (bool, error) {
+	for _, c := range created {
+		if bytes.Equal(c.account, account) {
+			return true, nil
+		}
+	}
+
+	_, err := l.stateStore.Get(ctx, account)
+	if err == nil {
+		return true, nil
+	} else if err != accountinfo.ErrNotFound {
+		return false, errors.Wrap(err, "failed to get account state from account state store")
+	}
+
+	_, err = l.tc.GetAccount(account, solana.CommitmentSingle)
+	if err == token.ErrInvalidTokenAccount || err == token.ErrAccountNotFound {
+		// Either the account is for a different mint, or it no longer exists,
+		// in which case we have no way of knowing.
+		l.log.WithError(err).WithField("account", base58.Encode(account)).Debug("ignoring unknown account")
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "failed to get account")
+	}
+
+	return true, nil
+}
+
+func (l *Loader) updateAccounts(ctx context.Context, slot uint64, creations []*creation, payments []*payment, ownershipChanges []*ownershipChange, closures []*closure) error {
 	log := l.log.WithField("method", "updateAccounts")
 	newAccounts := getNewAccounts(creations)
 	balanceDiffs := getBalanceDiffs(payments)
@@ -474,6 +839,10 @@ func (l *Loader) updateAccounts(ctx context.Context, slot uint64, creations []*c
 	}
 
 	for _, oc := range ownershipChanges {
+		if !oc.successful {
+			continue
+		}
+
 		if state, ok := updateMap[string(oc.account)]; ok {
 			state.Owner = oc.newOwner
 		}
@@ -497,6 +866,38 @@ func (l *Loader) updateAccounts(ctx context.Context, slot uint64, creations []*c
 		}
 	}
 
+	// Closed accounts no longer exist, so any pending updates are discarded,
+	// and their state is removed.
+	//
+	// todo: this does not handle accounts that are closed and then re-created
+	//       within the same batch.
+	for _, c := range closures {
+		if !c.successful {
+			continue
+		}
+
+		delete(updateMap, string(c.account))
+		delete(fetchRequired, string(c.account))
+
+		storedState, err := l.stateStore.Get(ctx, c.account)
+		if err == accountinfo.ErrNotFound {
+			continue
+		} else if err != nil {
+			log.WithError(err).Warn("failed to get account state from account state store")
+			return errors.Wrap(err, "failed to get account state from account state store")
+		}
+
+		// Don't delete if our slot is behind the stored slot
+		if storedState.Slot >= slot {
+			continue
+		}
+
+		if err := l.stateStore.Delete(ctx, c.account); err != nil {
+			log.WithError(err).Warn("failed to delete account state")
+			return errors.Wrap(err, "failed to delete account state")
+		}
+	}
+
 	for _, state := range updateMap {
 		if state.Balance < 0 {
 			// An incorrect assumption was made above; meter + mark the account for a fetch
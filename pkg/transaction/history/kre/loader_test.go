@@ -32,8 +32,10 @@ type testEnv struct {
 	mint ed25519.PublicKey
 	sc   *solana.MockClient
 
-	creationsSubmitter *submitter
-	paymentsSubmitter  *submitter
+	creationsSubmitter        *submitter
+	paymentsSubmitter         *submitter
+	ownershipChangesSubmitter *submitter
+	closuresSubmitter         *submitter
 
 	infoStore accountinfo.StateStore
 
@@ -48,6 +50,8 @@ func setup(t *testing.T) (env testEnv) {
 	env.sc = solana.NewMockClient()
 	env.creationsSubmitter = newTestSubmitter()
 	env.paymentsSubmitter = newTestSubmitter()
+	env.ownershipChangesSubmitter = newTestSubmitter()
+	env.closuresSubmitter = newTestSubmitter()
 	env.infoStore = memoryaccount.NewStore()
 
 	env.loader = NewLoader(
@@ -58,6 +62,8 @@ func setup(t *testing.T) (env testEnv) {
 		token.NewClient(env.sc, env.mint),
 		env.creationsSubmitter,
 		env.paymentsSubmitter,
+		env.ownershipChangesSubmitter,
+		env.closuresSubmitter,
 		env.infoStore,
 	)
 
@@ -180,8 +186,8 @@ func TestProcess_Existing(t *testing.T) {
 	env.sc.On("GetSlot", mock.Anything).Return(uint64(0), errors.New("")).Once()
 	env.sc.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
 
-	// We expect get account info to get called for ownership change for [0] + the destination [2]
-	env.sc.On("GetAccountInfo", accounts[0], solana.CommitmentSingle).Return(generateAccountInfo(0, env.mint, accounts[3], token.ProgramKey), nil).Once()
+	// We expect get account info to get called for the destination [2], but not for the
+	// ownership change of [0], since it's in the state store
 	env.sc.On("GetAccountInfo", accounts[2], solana.CommitmentSingle).Return(generateAccountInfo(0, env.mint, accounts[3], token.ProgramKey), nil).Once()
 
 	assert.Error(t, env.loader.process(context.Background())) // expect an error from GetSlot returning an error
@@ -219,6 +225,94 @@ func TestProcess_Existing(t *testing.T) {
 	env.sc.AssertExpectations(t)
 }
 
+func TestProcess_OwnershipChangesAndClosures(t *testing.T) {
+	env := setup(t)
+	slot := uint64(2)
+	pointer := model.OrderingKeyFromBlock(slot, false)
+	require.NoError(t, env.committer.Commit(context.Background(), GetKREIngestorName(), nil, pointer))
+
+	accounts := testutil.GenerateSolanaKeys(t, 6)
+
+	require.NoError(t, env.infoStore.Put(context.Background(), &accountinfo.State{
+		Account: accounts[0],
+		Owner:   accounts[1],
+		Balance: 20,
+		Slot:    3,
+	}))
+	require.NoError(t, env.infoStore.Put(context.Background(), &accountinfo.State{
+		Account: accounts[2],
+		Owner:   accounts[3],
+		Balance: 0,
+		Slot:    3,
+	}))
+
+	// memo
+	// ownership of [0] from [1] to [4]
+	// closure of [2] (owned by [3]) to [3]
+	// closure of [5], which is unknown
+	instructions := []solana.Instruction{
+		memo.Instruction("1-test-memo"),
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[1], accounts[4]}, instructionTypeOwnership, 0),
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[3], accounts[3]}, instructionTypeClosure, 0),
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[5], accounts[3], accounts[3]}, instructionTypeClosure, 0),
+	}
+	entry := generateSolanaEntry(t, 4, true, instructions)
+	require.NoError(t, env.rw.Write(context.Background(), entry))
+
+	// fetch slot once initially
+	env.sc.On("GetSlot", mock.Anything).Return(uint64(4), nil).Once()
+	// results in an error being thrown, cutting the tight loop short
+	env.sc.On("GetSlot", mock.Anything).Return(uint64(0), errors.New("")).Once()
+	env.sc.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+
+	// We expect get account info to only get called for the closure of [5], since
+	// [0] and [2] are in the state store
+	env.sc.On("GetAccountInfo", accounts[5], solana.CommitmentSingle).Return(solana.AccountInfo{}, solana.ErrNoAccountInfo).Once()
+
+	assert.Error(t, env.loader.process(context.Background())) // expect an error from GetSlot returning an error
+
+	assert.Empty(t, env.creationsSubmitter.submitted)
+	assert.Empty(t, env.paymentsSubmitter.submitted)
+
+	require.Len(t, env.ownershipChangesSubmitter.submitted, 1)
+	changes := env.ownershipChangesSubmitter.submitted[0].([]*ownershipChange)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 1, changes[0].offset)
+	assert.True(t, changes[0].successful)
+	assert.EqualValues(t, accounts[0], changes[0].account)
+	assert.EqualValues(t, accounts[1], changes[0].previousOwner)
+	assert.EqualValues(t, accounts[4], changes[0].newOwner)
+	require.NotNil(t, changes[0].memoText)
+	assert.Equal(t, "1-test-memo", *changes[0].memoText)
+
+	require.Len(t, env.closuresSubmitter.submitted, 1)
+	closures := env.closuresSubmitter.submitted[0].([]*closure)
+	require.Len(t, closures, 1)
+	assert.Equal(t, 2, closures[0].offset)
+	assert.True(t, closures[0].successful)
+	assert.EqualValues(t, accounts[2], closures[0].account)
+	assert.EqualValues(t, accounts[3], closures[0].accountOwner)
+	assert.EqualValues(t, accounts[3], closures[0].destination)
+	require.NotNil(t, closures[0].memoText)
+	assert.Equal(t, "1-test-memo", *closures[0].memoText)
+
+	// expected state:
+	// [0]: owner=[4], slot=4, balance = 20
+	// [2]: removed
+	state0, err := env.infoStore.Get(context.Background(), accounts[0])
+	require.NoError(t, err)
+	assertState(t, state0, accounts[0], accounts[4], 20, 4)
+
+	_, err = env.infoStore.Get(context.Background(), accounts[2])
+	assert.Equal(t, accountinfo.ErrNotFound, err)
+
+	accounts3, err := env.infoStore.GetAccountsByOwner(context.Background(), accounts[3])
+	require.NoError(t, err)
+	assert.Empty(t, accounts3)
+
+	env.sc.AssertExpectations(t)
+}
+
 type instructionType int
 
 const (
@@ -227,6 +321,7 @@ const (
 	instructionTypePayment
 	instructionTypeOwnership
 	instructionTypeOtherAuthority
+	instructionTypeClosure
 )
 
 func generateInstruction(t *testing.T, mint ed25519.PublicKey, accounts []ed25519.PublicKey, instructionType instructionType, amount uint64) solana.Instruction {
@@ -243,6 +338,9 @@ func generateInstruction(t *testing.T, mint ed25519.PublicKey, accounts []ed2551
 	case instructionTypeOtherAuthority:
 		assert.Equal(t, 3, len(accounts))
 		return token.SetAuthority(accounts[0], accounts[1], accounts[2], token.AuthorityTypeCloseAccount)
+	case instructionTypeClosure:
+		assert.Equal(t, 3, len(accounts))
+		return token.CloseAccount(accounts[0], accounts[1], accounts[2])
 	default:
 		return memo.Instruction("data")
 	}
@@ -1,6 +1,7 @@
 package kre
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/binary"
@@ -11,7 +12,9 @@ import (
 	"github.com/kinecosystem/agora-common/kin"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/memo"
+	"github.com/kinecosystem/agora-common/solana/token"
 	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
 
 	"github.com/kinecosystem/agora/client"
 )
@@ -141,6 +144,7 @@ type memoData struct {
 	text     *string
 	data     []byte
 	appIndex int
+	txType   kin.TransactionType
 }
 
 func (l *Loader) getMemos(txn solana.Transaction) (memos []memoData) {
@@ -156,6 +160,7 @@ func (l *Loader) getMemos(txn solana.Transaction) (memos []memoData) {
 		m := memoData{
 			data:   decompiled.Data,
 			offset: i,
+			txType: kin.TransactionTypeNone,
 		}
 		if strings.HasPrefix(string(m.data), "1-") {
 			str := string(m.data)
@@ -168,6 +173,7 @@ func (l *Loader) getMemos(txn solana.Transaction) (memos []memoData) {
 
 			if kin.IsValidMemoStrict(km) {
 				m.appIndex = int(km.AppIndex())
+				m.txType = km.TransactionType()
 			}
 		}
 
@@ -177,7 +183,163 @@ func (l *Loader) getMemos(txn solana.Transaction) (memos []memoData) {
 	return memos
 }
 
+// getMemo returns the memo that applies to the instruction at the specified
+// offset, which is the last memo that precedes it, if any.
+func getMemo(memos []memoData, offset int) *memoData {
+	for i := len(memos) - 1; i >= 0; i-- {
+		if memos[i].offset < offset {
+			return &memos[i]
+		}
+	}
+
+	return nil
+}
+
 type ownershipChange struct {
-	account  ed25519.PublicKey
-	newOwner ed25519.PublicKey
+	blockTime     time.Time
+	txID          solana.Signature
+	offset        int
+	successful    bool
+	account       ed25519.PublicKey
+	previousOwner ed25519.PublicKey
+	newOwner      ed25519.PublicKey
+	memoText      *string
+	memo          []byte
+	appIndex      int
+	subsidizer    ed25519.PublicKey
+}
+
+// Save implements bigquery.ValueSaver
+func (o *ownershipChange) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	insertID = instructionInsertID(o.txID, o.offset)
+
+	row = map[string]bigquery.Value{
+		"date":               o.blockTime.Format("2006-01-02"),
+		"time":               o.blockTime.Format("2006-01-02 15:04:05 UTC"),
+		"tx_id":              base58.Encode(o.txID[:]),
+		"tx_status":          txStatus(o.successful),
+		"instruction_offset": o.offset,
+		"account":            base58.Encode(o.account),
+		"previous_owner":     base58.Encode(o.previousOwner),
+	}
+
+	// The owner of an account can be removed, although it's not something
+	// that we expect to see in practice.
+	if len(o.newOwner) > 0 {
+		row["new_owner"] = base58.Encode(o.newOwner)
+	}
+	if o.memoText != nil {
+		row["memo_text"] = *o.memoText
+	}
+	if len(o.memo) > 0 {
+		row["memo_binary"] = o.memo
+	}
+	if o.appIndex > 0 {
+		row["app_index"] = o.appIndex
+	}
+	if len(o.subsidizer) > 0 {
+		row["subsidizer"] = base58.Encode(o.subsidizer)
+	}
+
+	return row, insertID, err
+}
+
+type closure struct {
+	blockTime    time.Time
+	txID         solana.Signature
+	offset       int
+	successful   bool
+	account      ed25519.PublicKey
+	accountOwner ed25519.PublicKey
+	destination  ed25519.PublicKey
+	memoText     *string
+	memo         []byte
+	appIndex     int
+	subsidizer   ed25519.PublicKey
+}
+
+// Save implements bigquery.ValueSaver
+func (c *closure) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	insertID = instructionInsertID(c.txID, c.offset)
+
+	row = map[string]bigquery.Value{
+		"date":               c.blockTime.Format("2006-01-02"),
+		"time":               c.blockTime.Format("2006-01-02 15:04:05 UTC"),
+		"tx_id":              base58.Encode(c.txID[:]),
+		"tx_status":          txStatus(c.successful),
+		"instruction_offset": c.offset,
+		"account":            base58.Encode(c.account),
+		"account_owner":      base58.Encode(c.accountOwner),
+		"destination":        base58.Encode(c.destination),
+	}
+
+	if c.memoText != nil {
+		row["memo_text"] = *c.memoText
+	}
+	if len(c.memo) > 0 {
+		row["memo_binary"] = c.memo
+	}
+	if c.appIndex > 0 {
+		row["app_index"] = c.appIndex
+	}
+	if len(c.subsidizer) > 0 {
+		row["subsidizer"] = base58.Encode(c.subsidizer)
+	}
+
+	return row, insertID, err
+}
+
+// instructionInsertID returns the insert id of a row derived from the
+// instruction at the specified offset of a transaction.
+func instructionInsertID(txID solana.Signature, offset int) string {
+	buf := make([]byte, len(txID)+4)
+	copy(buf, txID[:])
+	binary.BigEndian.PutUint32(buf[len(txID):], uint32(offset))
+
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// Reference: https://github.com/kinecosystem/py-kin-base/blob/master/kin_base/stellarxdr/StellarXDR_const.py#L363
+func txStatus(successful bool) string {
+	if successful {
+		return "txSUCCESS"
+	}
+	return "txFAILED"
+}
+
+// commandCloseAccount is the token program's CloseAccount instruction, which
+// is not decompiled by the token package.
+//
+// Reference: https://github.com/solana-labs/solana-program-library/blob/b011698251981b5a12088acba18fad1d41c3719a/token/program/src/instruction.rs#L183-L197
+const commandCloseAccount = 9
+
+type decompiledCloseAccount struct {
+	account     ed25519.PublicKey
+	destination ed25519.PublicKey
+	owner       ed25519.PublicKey
+}
+
+func decompileCloseAccount(m solana.Message, index int) (*decompiledCloseAccount, error) {
+	if index >= len(m.Instructions) {
+		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	}
+
+	i := m.Instructions[index]
+
+	if !bytes.Equal(m.Accounts[i.ProgramIndex], token.ProgramKey) {
+		return nil, solana.ErrIncorrectProgram
+	}
+	if len(i.Data) != 1 || i.Data[0] != commandCloseAccount {
+		return nil, solana.ErrIncorrectInstruction
+	}
+	// Multisig owners are followed by the signer accounts.
+	if len(i.Accounts) < 3 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+
+	return &decompiledCloseAccount{
+		account:     m.Accounts[i.Accounts[0]],
+		destination: m.Accounts[i.Accounts[1]],
+		owner:       m.Accounts[i.Accounts[2]],
+	}, nil
 }
@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
+)
+
+const (
+	// CreationsTable is the table that rows with kre.CreationsSchema are
+	// submitted to.
+	CreationsTable = "kre_creations"
+
+	// PaymentsTable is the table that rows with kre.PaymentsSchema are
+	// submitted to.
+	PaymentsTable = "kre_payments"
+
+	// OwnershipChangesTable is the table that rows with
+	// kre.OwnershipChangesSchema are submitted to.
+	OwnershipChangesTable = "kre_ownership_changes"
+
+	// ClosuresTable is the table that rows with kre.ClosuresSchema are
+	// submitted to.
+	ClosuresTable = "kre_closures"
+
+	// RollupsTable is the table that rows with kre.RollupsSchema are
+	// submitted to.
+	RollupsTable = "kre_daily_rollups"
+)
+
+// Schema contains the statements required to create the tables used by the
+// submitters.
+//
+// Rows are keyed by their insert id, so rows that are submitted more than
+// once are only stored once.
+const Schema = `
+CREATE TABLE IF NOT EXISTS kre_creations (
+	insert_id       TEXT        PRIMARY KEY,
+	date            DATE        NOT NULL,
+	time            TIMESTAMPTZ NOT NULL,
+	tx_id           TEXT        NOT NULL,
+	tx_status       TEXT        NOT NULL,
+	account         TEXT        NOT NULL,
+	account_owner   TEXT        NOT NULL,
+	initial_balance TEXT        NOT NULL,
+	initial_quarks  BIGINT      NOT NULL,
+	memo_text       TEXT,
+	memo_binary     BYTEA,
+	app_index       BIGINT,
+	subsidizer      TEXT
+);
+
+CREATE INDEX IF NOT EXISTS kre_creations_date_idx ON kre_creations (date);
+
+CREATE TABLE IF NOT EXISTS kre_payments (
+	insert_id          TEXT        PRIMARY KEY,
+	date               DATE        NOT NULL,
+	time               TIMESTAMPTZ NOT NULL,
+	tx_id              TEXT        NOT NULL,
+	tx_status          TEXT        NOT NULL,
+	instruction_offset BIGINT      NOT NULL,
+	source             TEXT        NOT NULL,
+	source_owner       TEXT        NOT NULL,
+	destination        TEXT        NOT NULL,
+	destination_owner  TEXT        NOT NULL,
+	amount             TEXT        NOT NULL,
+	quarks             BIGINT      NOT NULL,
+	memo_text          TEXT,
+	memo_binary        BYTEA,
+	app_index          BIGINT,
+	subsidizer         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS kre_payments_date_idx ON kre_payments (date);
+
+CREATE TABLE IF NOT EXISTS kre_ownership_changes (
+	insert_id          TEXT        PRIMARY KEY,
+	date               DATE        NOT NULL,
+	time               TIMESTAMPTZ NOT NULL,
+	tx_id              TEXT        NOT NULL,
+	tx_status          TEXT        NOT NULL,
+	instruction_offset BIGINT      NOT NULL,
+	account            TEXT        NOT NULL,
+	previous_owner     TEXT        NOT NULL,
+	new_owner          TEXT,
+	memo_text          TEXT,
+	memo_binary        BYTEA,
+	app_index          BIGINT,
+	subsidizer         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS kre_ownership_changes_date_idx ON kre_ownership_changes (date);
+
+CREATE TABLE IF NOT EXISTS kre_closures (
+	insert_id          TEXT        PRIMARY KEY,
+	date               DATE        NOT NULL,
+	time               TIMESTAMPTZ NOT NULL,
+	tx_id              TEXT        NOT NULL,
+	tx_status          TEXT        NOT NULL,
+	instruction_offset BIGINT      NOT NULL,
+	account            TEXT        NOT NULL,
+	account_owner      TEXT        NOT NULL,
+	destination        TEXT        NOT NULL,
+	memo_text          TEXT,
+	memo_binary        BYTEA,
+	app_index          BIGINT,
+	subsidizer         TEXT
+);
+
+CREATE INDEX IF NOT EXISTS kre_closures_date_idx ON kre_closures (date);
+
+CREATE TABLE IF NOT EXISTS kre_daily_rollups (
+	insert_id               TEXT   PRIMARY KEY,
+	date                    DATE   NOT NULL,
+	app_index               BIGINT NOT NULL,
+	earn_count              BIGINT NOT NULL,
+	earn_quarks             BIGINT NOT NULL,
+	spend_count             BIGINT NOT NULL,
+	spend_quarks            BIGINT NOT NULL,
+	p2p_count               BIGINT NOT NULL,
+	p2p_quarks              BIGINT NOT NULL,
+	other_count             BIGINT NOT NULL,
+	other_quarks            BIGINT NOT NULL,
+	unique_senders          BIGINT NOT NULL,
+	unique_receivers        BIGINT NOT NULL,
+	new_accounts            BIGINT NOT NULL,
+	subsidized_transactions BIGINT NOT NULL,
+	subsidized_signatures   BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS kre_daily_rollups_date_idx ON kre_daily_rollups (date);
+`
+
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05 MST"
+)
+
+type submitter struct {
+	pool   *pgxpool.Pool
+	schema kre.Schema
+	query  string
+	upsert bool
+}
+
+// Option configures a submitter.
+type Option func(s *submitter)
+
+// WithUpsert configures the submitter to replace rows that have already been
+// submitted, rather than ignoring them. It should be used for tables whose
+// rows may be recomputed, such as kre.RollupsSchema.
+func WithUpsert() Option {
+	return func(s *submitter) {
+		s.upsert = true
+	}
+}
+
+// New returns a kre.Submitter that inserts rows with the provided schema
+// into table.
+//
+// The tables in Schema must already exist.
+func New(pool *pgxpool.Pool, table string, schema kre.Schema, opts ...Option) kre.Submitter {
+	s := &submitter{
+		pool:   pool,
+		schema: schema,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	columns := []string{"insert_id"}
+	params := []string{"$1"}
+	updates := make([]string, 0, len(schema))
+	for i, c := range schema {
+		columns = append(columns, c.Name)
+		params = append(params, fmt.Sprintf("$%d", i+2))
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", c.Name, c.Name))
+	}
+
+	conflict := "DO NOTHING"
+	if s.upsert {
+		conflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	s.query = fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (insert_id) %s",
+		table,
+		strings.Join(columns, ", "),
+		strings.Join(params, ", "),
+		conflict,
+	)
+
+	return s
+}
+
+// Submit implements kre.Submitter.Submit.
+func (s *submitter) Submit(ctx context.Context, src interface{}) (err error) {
+	rows, err := kre.GetRows(src)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, r := range rows {
+		args := []interface{}{r.InsertID}
+		for _, c := range s.schema {
+			v, err := columnValue(c, r.Values[c.Name])
+			if err != nil {
+				return errors.Wrapf(err, "invalid value for %s in row %s", c.Name, r.InsertID)
+			}
+			args = append(args, v)
+		}
+
+		batch.Queue(s.query, args...)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	results := tx.SendBatch(ctx, batch)
+	for range rows {
+		if _, err := results.Exec(); err != nil {
+			_ = results.Close()
+			return errors.Wrap(err, "failed to insert rows")
+		}
+	}
+	if err := results.Close(); err != nil {
+		return errors.Wrap(err, "failed to insert rows")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// columnValue converts a submitted value into the type stored in the column.
+func columnValue(c kre.Column, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch c.Type {
+	case kre.ColumnTypeDate, kre.ColumnTypeTimestamp:
+		str, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("expected string, got %T", v)
+		}
+
+		layout := dateLayout
+		if c.Type == kre.ColumnTypeTimestamp {
+			layout = timestampLayout
+		}
+		return time.Parse(layout, str)
+	case kre.ColumnTypeInteger:
+		switch n := v.(type) {
+		case int:
+			return int64(n), nil
+		case int32:
+			return int64(n), nil
+		case int64:
+			return n, nil
+		case uint32:
+			return int64(n), nil
+		case uint64:
+			return int64(n), nil
+		default:
+			return nil, errors.Errorf("expected integer, got %T", v)
+		}
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	postgrestest "github.com/kinecosystem/agora/pkg/testutil/postgres"
+	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
+)
+
+var testPool *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	connString, cleanUpFunc, err := postgrestest.StartPostgres(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+
+	testPool, err = pgxpool.Connect(context.Background(), connString)
+	if err != nil {
+		log.WithError(err).Error("Error connecting to postgres")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	if _, err := testPool.Exec(context.Background(), Schema); err != nil {
+		log.WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	testPool.Close()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+type testPayment struct {
+	offset int
+	quarks uint64
+	memo   []byte
+}
+
+// Save implements bigquery.ValueSaver.
+func (p *testPayment) Save() (map[string]bigquery.Value, string, error) {
+	blockTime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	row := map[string]bigquery.Value{
+		"date":               blockTime.Format("2006-01-02"),
+		"time":               blockTime.Format("2006-01-02 15:04:05 UTC"),
+		"tx_id":              "tx",
+		"tx_status":          "txSUCCESS",
+		"instruction_offset": p.offset,
+		"source":             "source",
+		"source_owner":       "source_owner",
+		"destination":        "destination",
+		"destination_owner":  "destination_owner",
+		"amount":             "0.00010",
+		"quarks":             p.quarks,
+	}
+	if len(p.memo) > 0 {
+		row["memo_binary"] = p.memo
+		row["app_index"] = 10
+	}
+
+	return row, fmt.Sprintf("tx-%d", p.offset), nil
+}
+
+func TestSubmit(t *testing.T) {
+	s := New(testPool, PaymentsTable, kre.PaymentsSchema)
+
+	payments := []*testPayment{
+		{offset: 0, quarks: 10, memo: []byte{0xff}},
+		{offset: 1, quarks: 20},
+	}
+	require.NoError(t, s.Submit(context.Background(), payments))
+
+	// Resubmitting rows should not result in duplicates.
+	payments = append(payments, &testPayment{offset: 2, quarks: 30})
+	require.NoError(t, s.Submit(context.Background(), payments))
+
+	rows, err := testPool.Query(context.Background(), "SELECT insert_id, date, time, quarks, memo_binary, app_index FROM kre_payments ORDER BY insert_id")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var n int
+	for rows.Next() {
+		var (
+			insertID string
+			date     time.Time
+			ts       time.Time
+			quarks   int64
+			memo     []byte
+			appIndex *int64
+		)
+		require.NoError(t, rows.Scan(&insertID, &date, &ts, &quarks, &memo, &appIndex))
+
+		assert.Equal(t, fmt.Sprintf("tx-%d", n), insertID)
+		assert.Equal(t, "2021-01-02", date.Format("2006-01-02"))
+		assert.True(t, ts.Equal(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)))
+		assert.EqualValues(t, payments[n].quarks, quarks)
+		if n == 0 {
+			assert.Equal(t, []byte{0xff}, memo)
+			require.NotNil(t, appIndex)
+			assert.EqualValues(t, 10, *appIndex)
+		} else {
+			assert.Nil(t, memo)
+			assert.Nil(t, appIndex)
+		}
+		n++
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, 3, n)
+
+	// Rows that are missing required columns are rejected.
+	assert.Error(t, New(testPool, CreationsTable, kre.CreationsSchema).Submit(context.Background(), payments))
+}
+
+type testRollup struct {
+	appIndex  int
+	earnCount int
+}
+
+// Save implements bigquery.ValueSaver.
+func (r *testRollup) Save() (map[string]bigquery.Value, string, error) {
+	row := map[string]bigquery.Value{
+		"date":      "2021-01-02",
+		"app_index": r.appIndex,
+	}
+	for _, c := range kre.RollupsSchema[2:] {
+		row[c.Name] = 0
+	}
+	row["earn_count"] = r.earnCount
+
+	return row, fmt.Sprintf("2021-01-02-%d", r.appIndex), nil
+}
+
+func TestSubmit_Upsert(t *testing.T) {
+	s := New(testPool, RollupsTable, kre.RollupsSchema, WithUpsert())
+
+	require.NoError(t, s.Submit(context.Background(), []*testRollup{{appIndex: 1, earnCount: 1}}))
+
+	// Recomputed rows should replace the existing ones.
+	require.NoError(t, s.Submit(context.Background(), []*testRollup{{appIndex: 1, earnCount: 2}, {appIndex: 2, earnCount: 3}}))
+
+	rows, err := testPool.Query(context.Background(), "SELECT app_index, earn_count FROM kre_daily_rollups ORDER BY app_index")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var actual [][2]int64
+	for rows.Next() {
+		var appIndex, earnCount int64
+		require.NoError(t, rows.Scan(&appIndex, &earnCount))
+		actual = append(actual, [2]int64{appIndex, earnCount})
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, [][2]int64{{1, 2}, {2, 3}}, actual)
+}
@@ -0,0 +1,191 @@
+package kre
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const defaultReplayPageSize = 1024
+
+// ReplayChunk is an (inclusive) slot range to be replayed.
+type ReplayChunk struct {
+	From uint64
+	To   uint64
+}
+
+// ReplayChunks splits the (inclusive) slot range [from, to] into chunks of
+// at most size slots.
+func ReplayChunks(from, to, size uint64) []ReplayChunk {
+	if size == 0 {
+		size = 1
+	}
+
+	var chunks []ReplayChunk
+	for start := from; start <= to; start += size {
+		end := start + size - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		chunks = append(chunks, ReplayChunk{From: start, To: end})
+		if end == to {
+			break
+		}
+	}
+
+	return chunks
+}
+
+// GetReplayCommitterName returns the committer key used to track the progress
+// of a replay chunk.
+//
+// Replays use their own keys so that the live KRE pointer is never modified.
+func GetReplayCommitterName(chunk ReplayChunk) string {
+	return fmt.Sprintf("%s_replay_%d_%d", GetKREIngestorName(), chunk.From, chunk.To)
+}
+
+// ReplayProgress is called after a page of a chunk has been submitted, or
+// when a chunk was already replayed by a previous run. next is the next slot
+// to be replayed, and done indicates whether or not the chunk has been
+// completely replayed.
+type ReplayProgress func(chunk ReplayChunk, next uint64, done bool)
+
+// Replay re-submits the rows for each of the chunks, processing up to
+// concurrency chunks in parallel. Replay blocks until all chunks have been
+// replayed, or an error occurs.
+//
+// Unlike Process, Replay does not modify the live commit pointer or the
+// account state store. Rows use the same (deterministic) insert ids as when
+// they were originally submitted, so submitters must replace existing rows
+// with the same insert id (see postgres.WithUpsert). Submitters that append
+// rows, or replace date partitions (which chunks do not align with), must not
+// be used, as rows would be duplicated or lost.
+//
+// The progress of each chunk is committed using GetReplayCommitterName,
+// allowing an interrupted replay to be resumed by calling Replay with the
+// same chunks.
+//
+// The owners of payment destinations are resolved from history, rather than
+// from the current state of the accounts, which may have changed owners or
+// been closed since.
+//
+// Note: closures are only replayed for accounts that are still known (i.e.
+// in the state store, or created in the same page), since the mint of a
+// closed account cannot otherwise be determined.
+func (l *Loader) Replay(ctx context.Context, chunks []ReplayChunk, concurrency int, progress ReplayProgress) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var replayErr error
+
+	chunkCh := make(chan ReplayChunk)
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for chunk := range chunkCh {
+				if err := l.replayChunk(ctx, chunk, progress); err != nil {
+					errOnce.Do(func() {
+						replayErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, chunk := range chunks {
+		select {
+		case chunkCh <- chunk:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(chunkCh)
+	wg.Wait()
+
+	if replayErr != nil {
+		return replayErr
+	}
+
+	return ctx.Err()
+}
+
+func (l *Loader) replayChunk(ctx context.Context, chunk ReplayChunk, progress ReplayProgress) error {
+	name := GetReplayCommitterName(chunk)
+	log := l.log.WithFields(logrus.Fields{
+		"method":   "Replay",
+		"ingestor": name,
+	})
+
+	latest, err := l.committer.Latest(ctx, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get latest commit for '%s'", name)
+	}
+
+	next := chunk.From
+	if latest != nil {
+		next, err = model.BlockFromOrderingKey(latest)
+		if err != nil {
+			return errors.Wrap(err, "committer contains invalid pointer")
+		}
+	}
+
+	if next > chunk.To {
+		log.Debug("chunk already replayed")
+		if progress != nil {
+			progress(chunk, next, true)
+		}
+		return nil
+	}
+
+	for next <= chunk.To {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		entries, pageEnd, err := history.GetCompleteBlocks(ctx, l.hist, next, chunk.To, defaultReplayPageSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to load transactions")
+		}
+
+		b, err := l.load(ctx, entries, true)
+		if err != nil {
+			return err
+		}
+		if err := l.submit(b); err != nil {
+			return err
+		}
+
+		pointer := model.OrderingKeyFromBlock(pageEnd, false)
+		if err := l.committer.Commit(ctx, name, latest, pointer); err != nil {
+			return errors.Wrap(err, "failed to update commit pointer")
+		}
+		latest = pointer
+		next = pageEnd
+
+		if progress != nil {
+			progress(chunk, next, next > chunk.To)
+		}
+	}
+
+	log.Debug("chunk replayed")
+	return nil
+}
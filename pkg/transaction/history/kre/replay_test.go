@@ -0,0 +1,213 @@
+package kre
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/account/solana/accountinfo"
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+func TestReplayChunks(t *testing.T) {
+	assert.Equal(t, []ReplayChunk{
+		{From: 0, To: 9},
+		{From: 10, To: 19},
+		{From: 20, To: 25},
+	}, ReplayChunks(0, 25, 10))
+	assert.Equal(t, []ReplayChunk{{From: 5, To: 5}}, ReplayChunks(5, 5, 10))
+	assert.Empty(t, ReplayChunks(10, 5, 10))
+}
+
+func TestReplay(t *testing.T) {
+	env := setup(t)
+
+	live := model.OrderingKeyFromBlock(20, false)
+	require.NoError(t, env.committer.Commit(context.Background(), GetKREIngestorName(), nil, live))
+
+	accounts := testutil.GenerateSolanaKeys(t, 4)
+	env.sc.On("GetAccountInfo", accounts[0], mock.Anything).Return(generateAccountInfo(0, env.mint, accounts[1], token.ProgramKey), nil)
+	env.sc.On("GetAccountInfo", accounts[2], mock.Anything).Return(generateAccountInfo(0, env.mint, accounts[3], token.ProgramKey), nil)
+	env.sc.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+
+	slots := []uint64{2, 3, 3, 6, 9, 10}
+	for i, slot := range slots {
+		instructions := []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, uint64(i+1)),
+		}
+		require.NoError(t, env.rw.Write(context.Background(), generateSolanaEntry(t, slot, true, instructions)))
+	}
+
+	var mu sync.Mutex
+	done := make(map[ReplayChunk]bool)
+	progress := func(chunk ReplayChunk, next uint64, chunkDone bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		done[chunk] = done[chunk] || chunkDone
+	}
+
+	chunks := ReplayChunks(1, 10, 3)
+	require.NoError(t, env.loader.Replay(context.Background(), chunks, 2, progress))
+
+	for _, c := range chunks {
+		assert.True(t, done[c])
+
+		latest, err := env.committer.Latest(context.Background(), GetReplayCommitterName(c))
+		require.NoError(t, err)
+		assert.EqualValues(t, model.OrderingKeyFromBlock(c.To+1, false), latest)
+	}
+
+	// Every payment in the range should be submitted exactly once.
+	quarks := make(map[uint64]int)
+	for _, src := range env.paymentsSubmitter.submitted {
+		for _, p := range src.([]*payment) {
+			quarks[p.quarks]++
+		}
+	}
+	assert.Equal(t, map[uint64]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1}, quarks)
+
+	// Neither the live pointer nor the state store should be modified.
+	latest, err := env.committer.Latest(context.Background(), GetKREIngestorName())
+	require.NoError(t, err)
+	assert.EqualValues(t, live, latest)
+
+	_, err = env.infoStore.Get(context.Background(), accounts[0])
+	assert.Equal(t, accountinfo.ErrNotFound, err)
+
+	// Replaying the same chunks again should be a no-op.
+	submitted := len(env.paymentsSubmitter.submitted)
+	done = make(map[ReplayChunk]bool)
+	require.NoError(t, env.loader.Replay(context.Background(), chunks, 2, progress))
+	assert.Len(t, env.paymentsSubmitter.submitted, submitted)
+	for _, c := range chunks {
+		assert.True(t, done[c])
+	}
+}
+
+func TestReplay_HistoricalOwners(t *testing.T) {
+	env := setup(t)
+
+	// [0] is owned by [1]. [2] is initially owned by [3], then by [4], and has
+	// since been closed. [5] has been closed, and has no history revealing its
+	// owner.
+	accounts := testutil.GenerateSolanaKeys(t, 6)
+	env.sc.On("GetAccountInfo", accounts[0], mock.Anything).Return(generateAccountInfo(0, env.mint, accounts[1], token.ProgramKey), nil)
+	env.sc.On("GetAccountInfo", accounts[2], mock.Anything).Return(solana.AccountInfo{}, solana.ErrNoAccountInfo)
+	env.sc.On("GetAccountInfo", accounts[5], mock.Anything).Return(solana.AccountInfo{}, solana.ErrNoAccountInfo)
+	env.sc.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+
+	entries := []*model.Entry{
+		// payment from [2] to [0], revealing [3] as the owner of [2]
+		generateSolanaEntry(t, 2, true, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[0], accounts[3]}, instructionTypePayment, 1),
+		}),
+		// payment from [0] to [2]
+		generateSolanaEntry(t, 3, true, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, 2),
+		}),
+		// payment from [0] to [2], ownership of [2] from [3] to [4], and
+		// another payment from [0] to [2]
+		generateSolanaEntry(t, 4, true, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, 3),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[3], accounts[4]}, instructionTypeOwnership, 0),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, 4),
+		}),
+		// payment from [0] to [5]
+		generateSolanaEntry(t, 5, true, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[5], accounts[1]}, instructionTypePayment, 5),
+		}),
+	}
+	for _, e := range entries {
+		require.NoError(t, env.rw.Write(context.Background(), e))
+	}
+
+	require.NoError(t, env.loader.Replay(context.Background(), ReplayChunks(1, 10, 10), 1, nil))
+
+	var payments []*payment
+	for _, src := range env.paymentsSubmitter.submitted {
+		payments = append(payments, src.([]*payment)...)
+	}
+
+	// The payment to [5] is ignored, rather than failing the replay.
+	require.Len(t, payments, 4)
+	assertPayment(t, payments[0], 0, 1, accounts[2], accounts[3], accounts[0], accounts[1])
+	assertPayment(t, payments[1], 0, 2, accounts[0], accounts[1], accounts[2], accounts[3])
+	assertPayment(t, payments[2], 0, 3, accounts[0], accounts[1], accounts[2], accounts[3])
+	assertPayment(t, payments[3], 2, 4, accounts[0], accounts[1], accounts[2], accounts[4])
+}
+
+// countingReader counts the number of GetAccountTransactions() calls.
+type countingReader struct {
+	history.ReaderWriter
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (r *countingReader) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	r.mu.Lock()
+	r.calls++
+	r.mu.Unlock()
+
+	return r.ReaderWriter.GetAccountTransactions(ctx, account, opts)
+}
+
+func TestReplay_HistoricalOwnersMemoized(t *testing.T) {
+	env := setup(t)
+
+	reader := &countingReader{ReaderWriter: env.rw}
+	env.loader.hist = reader
+
+	// [0] is owned by [1]. [2] is initially owned by [3], then by [4], and has
+	// since been closed.
+	accounts := testutil.GenerateSolanaKeys(t, 5)
+	env.sc.On("GetAccountInfo", accounts[0], mock.Anything).Return(generateAccountInfo(0, env.mint, accounts[1], token.ProgramKey), nil)
+	env.sc.On("GetAccountInfo", accounts[2], mock.Anything).Return(solana.AccountInfo{}, solana.ErrNoAccountInfo)
+	env.sc.On("GetBlockTime", mock.Anything).Return(time.Now(), nil)
+
+	var entries []*model.Entry
+	entries = append(entries, generateSolanaEntry(t, 1, true, []solana.Instruction{
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[0], accounts[3]}, instructionTypePayment, 1),
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, 2),
+	}))
+	for i := 0; i < 5; i++ {
+		entries = append(entries, generateSolanaEntry(t, uint64(2+i), true, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, uint64(3+i)),
+		}))
+	}
+	entries = append(entries, generateSolanaEntry(t, 7, true, []solana.Instruction{
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[3], accounts[4]}, instructionTypeOwnership, 0),
+	}))
+	entries = append(entries, generateSolanaEntry(t, 8, true, []solana.Instruction{
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[1]}, instructionTypePayment, 8),
+	}))
+	for _, e := range entries {
+		require.NoError(t, env.rw.Write(context.Background(), e))
+	}
+
+	require.NoError(t, env.loader.Replay(context.Background(), ReplayChunks(1, 10, 10), 1, nil))
+
+	var payments []*payment
+	for _, src := range env.paymentsSubmitter.submitted {
+		payments = append(payments, src.([]*payment)...)
+	}
+	require.Len(t, payments, 8)
+	for _, p := range payments[1:7] {
+		assert.Equal(t, accounts[3], p.destOwner)
+	}
+	assert.Equal(t, accounts[4], payments[7].destOwner)
+
+	// The owner of [2] is revealed by the first instruction of the page, and
+	// the owner of [0] is only looked up once.
+	assert.Equal(t, 1, reader.calls)
+}
@@ -0,0 +1,547 @@
+package kre
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/retry"
+	"github.com/kinecosystem/agora-common/retry/backoff"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const defaultRollupBatchSize = 1024
+
+func GetRollupIngestorName() string {
+	return "kre_rollup_ingestor"
+}
+
+// rollup contains the aggregated activity of an app on a single (UTC) day.
+//
+// Payments are classified using the transaction type of their memo. Payments
+// without a (typed) memo are counted as other. Only successful transactions
+// are aggregated.
+type rollup struct {
+	date     string
+	appIndex int
+
+	earnCount   int
+	earnQuarks  uint64
+	spendCount  int
+	spendQuarks uint64
+	p2pCount    int
+	p2pQuarks   uint64
+	otherCount  int
+	otherQuarks uint64
+
+	senders     map[string]struct{}
+	receivers   map[string]struct{}
+	newAccounts int
+
+	// A transaction is considered subsidized if its fee payer is not one of
+	// the owners involved in its payments or creations. Each signature is
+	// charged a fee, so subsidizer spend is reported as the number of
+	// signatures, rather than an amount.
+	subsidizedTransactions int
+	subsidizedSignatures   int
+}
+
+func newRollup(date string, appIndex int) *rollup {
+	return &rollup{
+		date:      date,
+		appIndex:  appIndex,
+		senders:   make(map[string]struct{}),
+		receivers: make(map[string]struct{}),
+	}
+}
+
+// Save implements bigquery.ValueSaver
+//
+// The insert id is derived from the day and app index, so a rollup that is
+// recomputed replaces the previous one.
+func (r *rollup) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	insertID = fmt.Sprintf("%s-%d", r.date, r.appIndex)
+
+	row = map[string]bigquery.Value{
+		"date":                    r.date,
+		"app_index":               r.appIndex,
+		"earn_count":              r.earnCount,
+		"earn_quarks":             r.earnQuarks,
+		"spend_count":             r.spendCount,
+		"spend_quarks":            r.spendQuarks,
+		"p2p_count":               r.p2pCount,
+		"p2p_quarks":              r.p2pQuarks,
+		"other_count":             r.otherCount,
+		"other_quarks":            r.otherQuarks,
+		"unique_senders":          len(r.senders),
+		"unique_receivers":        len(r.receivers),
+		"new_accounts":            r.newAccounts,
+		"subsidized_transactions": r.subsidizedTransactions,
+		"subsidized_signatures":   r.subsidizedSignatures,
+	}
+
+	return row, insertID, nil
+}
+
+// day contains the rollups of a single (UTC) day.
+type day struct {
+	date    string
+	rollups map[int]*rollup
+	seen    map[string]struct{}
+}
+
+func newDay(date string) *day {
+	return &day{
+		date:    date,
+		rollups: make(map[int]*rollup),
+		seen:    make(map[string]struct{}),
+	}
+}
+
+func (d *day) get(appIndex int) *rollup {
+	r, ok := d.rollups[appIndex]
+	if !ok {
+		r = newRollup(d.date, appIndex)
+		d.rollups[appIndex] = r
+	}
+
+	return r
+}
+
+// Aggregator maintains daily, per app rollups of KRE activity.
+//
+// Rollups are computed from history, independently of the Loader. Days are
+// only aggregated once they have closed (i.e. the latest block is from a later
+// day), after which the first slot of the following day is committed. The
+// incomplete day is therefore never aggregated, and each day is computed in
+// full, and submitted once (unless it is recomputed).
+type Aggregator struct {
+	log       *logrus.Entry
+	hist      history.Reader
+	committer ingestion.Committer
+	lock      ingestion.DistributedLock
+	sc        solana.Client
+	submitter Submitter
+	batchSize int
+
+	// loader is used to extract the payments, creations and memos from
+	// transactions, in the same way as the rows submitted by the Loader.
+	loader *Loader
+}
+
+// NewAggregator returns a new Aggregator, which submits rollups using the
+// provided submitter.
+func NewAggregator(
+	hist history.Reader,
+	committer ingestion.Committer,
+	lock ingestion.DistributedLock,
+	sc solana.Client,
+	tc *token.Client,
+	submitter Submitter,
+) *Aggregator {
+	log := logrus.StandardLogger().WithField("type", "transaction/history/kre/rollup")
+	return &Aggregator{
+		log:       log,
+		hist:      hist,
+		committer: committer,
+		lock:      lock,
+		sc:        sc,
+		submitter: submitter,
+		batchSize: defaultRollupBatchSize,
+		loader: &Loader{
+			log: log,
+			sc:  sc,
+			tc:  tc,
+		},
+	}
+}
+
+// Process continuously computes rollups for completed days, until the
+// context is cancelled.
+func (a *Aggregator) Process(ctx context.Context, interval time.Duration) error {
+	log := a.log.WithField("method", "Process")
+
+	_, err := retry.Retry(
+		func() error {
+			return a.lock.Lock(ctx)
+		},
+		retry.NonRetriableErrors(context.Canceled),
+		retry.BackoffWithJitter(backoff.Constant(5*time.Second), 5*time.Second, 0.1),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := a.lock.Unlock(); err != nil {
+			log.WithError(err).Warn("Failed to release lock")
+		}
+	}()
+
+	for {
+		if err := a.process(ctx); err == context.Canceled {
+			return err
+		} else if err != nil {
+			log.WithError(err).Warn("failed to process rollups")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (a *Aggregator) process(ctx context.Context) error {
+	latest, err := a.committer.Latest(ctx, GetRollupIngestorName())
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest rollup commit")
+	}
+
+	var from uint64
+	if latest != nil {
+		from, err = model.BlockFromOrderingKey(latest)
+		if err != nil {
+			return errors.Wrap(err, "committer contains invalid pointer")
+		}
+	}
+
+	maxBlock, err := a.sc.GetSlot(solana.CommitmentMax)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest committed block")
+	}
+
+	// Days are only aggregated once they have closed, so that the incomplete
+	// day isn't re-aggregated on every interval.
+	maxTime, err := a.sc.GetBlockTime(maxBlock)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block time for slot: %d", maxBlock)
+	}
+	end := maxTime.UTC().Truncate(24 * time.Hour)
+
+	entries, err := a.hist.GetTransactions(ctx, from, maxBlock, 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to load transactions")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	var bt blockTimes
+	first, err := bt.get(a.sc, entries[0])
+	if err != nil {
+		return err
+	}
+	if !first.Before(end) {
+		return nil
+	}
+
+	_, err = a.aggregate(ctx, from, maxBlock, end, func(d *day, next uint64) error {
+		if err := a.submit(ctx, d); err != nil {
+			return err
+		}
+
+		pointer := model.OrderingKeyFromBlock(next, false)
+		if err := a.committer.Commit(ctx, GetRollupIngestorName(), latest, pointer); err != nil {
+			return errors.Wrap(err, "failed to update commit pointer")
+		}
+		latest = pointer
+
+		a.log.WithField("date", d.date).Info("Submitted rollups")
+		return nil
+	})
+	return err
+}
+
+// Recompute computes (and submits) the rollups for the days in [from, to),
+// without affecting the days processed by Process. from and to are
+// truncated to UTC days.
+//
+// If history does not yet contain the end of the range, the last
+// (incomplete) day is not submitted.
+func (a *Aggregator) Recompute(ctx context.Context, from, to time.Time) error {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+	if !from.Before(to) {
+		return errors.Errorf("from (%s) must be before to (%s)", from, to)
+	}
+
+	maxBlock, err := a.sc.GetSlot(solana.CommitmentMax)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest committed block")
+	}
+
+	start, err := a.firstSlotAfter(ctx, from, maxBlock)
+	if err != nil {
+		return err
+	}
+
+	complete, err := a.aggregate(ctx, start, maxBlock, to, func(d *day, _ uint64) error {
+		if err := a.submit(ctx, d); err != nil {
+			return err
+		}
+
+		a.log.WithField("date", d.date).Info("Recomputed rollups")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !complete {
+		a.log.WithField("to", to).Warn("History does not cover the end of the range, last day not submitted")
+	}
+
+	return nil
+}
+
+func (a *Aggregator) submit(ctx context.Context, d *day) error {
+	if len(d.rollups) == 0 {
+		return nil
+	}
+
+	rollups := make([]*rollup, 0, len(d.rollups))
+	for _, r := range d.rollups {
+		rollups = append(rollups, r)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		return rollups[i].appIndex < rollups[j].appIndex
+	})
+
+	if err := a.submitter.Submit(ctx, rollups); err != nil {
+		return errors.Wrapf(err, "failed to insert rollups for %s", d.date)
+	}
+
+	return nil
+}
+
+// aggregate aggregates history starting at fromBlock (inclusive), calling
+// complete for each day that has been fully aggregated, along with the first
+// slot of the following day.
+//
+// If end is set, aggregation stops at the first entry at (or after) end, and
+// true is returned. Otherwise, it stops once history has been exhausted, and
+// the last (incomplete) day is discarded.
+func (a *Aggregator) aggregate(ctx context.Context, fromBlock, maxBlock uint64, end time.Time, complete func(d *day, next uint64) error) (bool, error) {
+	var current *day
+	var bt blockTimes
+
+	for maxBlock > 0 && fromBlock <= maxBlock {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+
+		entries, next, err := history.GetCompleteBlocks(ctx, a.hist, fromBlock, maxBlock, a.batchSize)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to load transactions")
+		}
+
+		for _, e := range entries {
+			se := e.GetSolana()
+			if se == nil {
+				continue
+			}
+
+			blockTime, err := bt.get(a.sc, e)
+			if err != nil {
+				return false, err
+			}
+			if !end.IsZero() && !blockTime.Before(end) {
+				if current != nil {
+					if err := complete(current, se.Slot); err != nil {
+						return false, err
+					}
+				}
+				return true, nil
+			}
+
+			// Block times are estimates, so in the unlikely case that they
+			// regress, the entry is included in the current day.
+			date := blockTime.UTC().Format("2006-01-02")
+			if current == nil {
+				current = newDay(date)
+			} else if date > current.date {
+				if err := complete(current, se.Slot); err != nil {
+					return false, err
+				}
+				current = newDay(date)
+			}
+
+			if err := a.add(ctx, current, e); err != nil {
+				return false, err
+			}
+		}
+
+		fromBlock = next
+	}
+
+	return false, nil
+}
+
+// add aggregates an entry into its day.
+func (a *Aggregator) add(ctx context.Context, d *day, e *model.Entry) error {
+	se := e.GetSolana()
+	if len(se.TransactionError) > 0 {
+		return nil
+	}
+
+	var txn solana.Transaction
+	if err := txn.Unmarshal(se.Transaction); err != nil {
+		return errors.Wrap(err, "failed to unmarshal transaction")
+	}
+
+	// Guard against double counting, should history contain duplicates.
+	if _, ok := d.seen[string(txn.Signature())]; ok {
+		return nil
+	}
+	d.seen[string(txn.Signature())] = struct{}{}
+
+	payments, err := a.loader.getPayments(ctx, txn, true, a.loader.currentOwner)
+	if err != nil {
+		return errors.Wrap(err, "failed to get payments from transaction")
+	}
+	creations, err := a.loader.getCreations(txn, true)
+	if err != nil {
+		return errors.Wrap(err, "failed to get creations from transaction")
+	}
+	if len(payments) == 0 && len(creations) == 0 {
+		return nil
+	}
+
+	memos := a.loader.getMemos(txn)
+	attribution := func(offset int) (int, kin.TransactionType) {
+		if m := getMemo(memos, offset); m != nil {
+			return m.appIndex, m.txType
+		}
+		return 0, kin.TransactionTypeNone
+	}
+
+	// The transaction itself is attributed to the app of its first relevant
+	// instruction.
+	txApp, txOffset := 0, len(txn.Message.Instructions)
+	owners := make(map[string]struct{})
+
+	for _, p := range payments {
+		appIndex, txType := attribution(p.offset)
+		r := d.get(appIndex)
+
+		switch txType {
+		case kin.TransactionTypeEarn:
+			r.earnCount++
+			r.earnQuarks += p.quarks
+		case kin.TransactionTypeSpend:
+			r.spendCount++
+			r.spendQuarks += p.quarks
+		case kin.TransactionTypeP2P:
+			r.p2pCount++
+			r.p2pQuarks += p.quarks
+		default:
+			r.otherCount++
+			r.otherQuarks += p.quarks
+		}
+
+		r.senders[string(p.sourceOwner)] = struct{}{}
+		r.receivers[string(p.destOwner)] = struct{}{}
+		owners[string(p.sourceOwner)] = struct{}{}
+
+		if p.offset < txOffset {
+			txApp, txOffset = appIndex, p.offset
+		}
+	}
+
+	for _, c := range creations {
+		appIndex, _ := attribution(c.offset)
+		d.get(appIndex).newAccounts++
+		owners[string(c.accountOwner)] = struct{}{}
+
+		if c.offset < txOffset {
+			txApp, txOffset = appIndex, c.offset
+		}
+	}
+
+	feePayer := ed25519.PublicKey(txn.Message.Accounts[0])
+	if _, ok := owners[string(feePayer)]; !ok {
+		r := d.get(txApp)
+		r.subsidizedTransactions++
+		r.subsidizedSignatures += len(txn.Signatures)
+	}
+
+	return nil
+}
+
+// firstSlotAfter returns the first slot in history that contains an entry
+// at (or after) t. Block times are assumed to be non-decreasing, allowing
+// for a binary search.
+func (a *Aggregator) firstSlotAfter(ctx context.Context, t time.Time, maxBlock uint64) (uint64, error) {
+	var bt blockTimes
+
+	lo, hi := uint64(0), maxBlock+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		entries, err := a.hist.GetTransactions(ctx, mid, maxBlock, 1)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to load transactions")
+		}
+		if len(entries) == 0 {
+			hi = mid
+			continue
+		}
+
+		blockTime, err := bt.get(a.sc, entries[0])
+		if err != nil {
+			return 0, err
+		}
+
+		if !blockTime.Before(t) {
+			hi = mid
+		} else {
+			lo = entries[0].GetSolana().Slot + 1
+		}
+	}
+
+	return lo, nil
+}
+
+// blockTimes returns the block time of entries, falling back to the RPC node
+// if the entry does not contain it. The last looked up time is cached, since
+// consecutive entries are generally in the same block.
+type blockTimes struct {
+	slot uint64
+	time time.Time
+}
+
+func (b *blockTimes) get(sc solana.Client, e *model.Entry) (time.Time, error) {
+	t, err := e.GetBlockTime()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get entry block time")
+	}
+	if !t.IsZero() {
+		return t, nil
+	}
+
+	slot := e.GetSolana().Slot
+	if !b.time.IsZero() && b.slot == slot {
+		return b.time, nil
+	}
+
+	t, err = sc.GetBlockTime(slot)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to get block time for slot: %d", slot)
+	}
+
+	b.slot = slot
+	b.time = t
+	return t, nil
+}
@@ -0,0 +1,218 @@
+package kre
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/memo"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+func TestAggregator(t *testing.T) {
+	env := setup(t)
+	rollupSubmitter := newTestSubmitter()
+	aggregator := NewAggregator(env.rw, env.committer, env.lock, env.sc, token.NewClient(env.sc, env.mint), rollupSubmitter)
+
+	// accounts [0], [1], [2] are owned by [3], [4], [5] respectively.
+	accounts := testutil.GenerateSolanaKeys(t, 6)
+	for i := 0; i < 3; i++ {
+		env.sc.On("GetAccountInfo", accounts[i], mock.Anything).Return(generateAccountInfo(0, env.mint, accounts[i+3], token.ProgramKey), nil)
+	}
+	subsidizer := testutil.GenerateSolanaKeypair(t)
+	owner := testutil.GenerateSolanaKeypair(t)
+
+	day1 := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+
+	entries := []*model.Entry{
+		// day 1, app 1: subsidized earns from [0] to [1] and [2], with a
+		// creation of [2].
+		generateRollupEntry(t, 2, day1, subsidizer, nil, []solana.Instruction{
+			generateKinMemo(t, kin.TransactionTypeEarn, 1),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[5]}, instructionTypeCreation, 0),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[1], accounts[3]}, instructionTypePayment, 10),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[0], accounts[2], accounts[3]}, instructionTypePayment, 20),
+		}),
+		// day 1, app 2: p2p from [1] to [0], paid by the sender.
+		generateRollupEntry(t, 3, day1.Add(time.Hour), owner, nil, []solana.Instruction{
+			generateKinMemo(t, kin.TransactionTypeP2P, 2),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[1], accounts[0], owner.Public().(ed25519.PublicKey)}, instructionTypePayment, 5),
+		}),
+		// day 1: failed transactions are ignored.
+		generateRollupEntry(t, 3, day1.Add(time.Hour), subsidizer, []byte("error"), []solana.Instruction{
+			generateKinMemo(t, kin.TransactionTypeSpend, 1),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[1], accounts[0], accounts[4]}, instructionTypePayment, 100),
+		}),
+		// day 2: spend for app 1, and a payment without a memo.
+		generateRollupEntry(t, 5, day2, subsidizer, nil, []solana.Instruction{
+			generateKinMemo(t, kin.TransactionTypeSpend, 1),
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[1], accounts[0], accounts[4]}, instructionTypePayment, 7),
+		}),
+		generateRollupEntry(t, 6, day2, subsidizer, nil, []solana.Instruction{
+			generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[0], accounts[5]}, instructionTypePayment, 3),
+		}),
+	}
+	for _, e := range entries {
+		require.NoError(t, env.rw.Write(context.Background(), e))
+	}
+
+	// Day 2 is not yet complete, so only day 1 should be submitted.
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(6), nil).Once()
+	env.sc.On("GetBlockTime", uint64(6)).Return(day2, nil)
+	require.NoError(t, aggregator.process(context.Background()))
+
+	require.Len(t, rollupSubmitter.submitted, 1)
+	day1Rows := getRollupRows(t, rollupSubmitter.submitted[0])
+	require.Len(t, day1Rows, 2)
+	assertRollup(t, day1Rows[0], "2021-01-01", 1, map[string]int{
+		"earn_count":              2,
+		"earn_quarks":             30,
+		"unique_senders":          1,
+		"unique_receivers":        2,
+		"new_accounts":            1,
+		"subsidized_transactions": 1,
+		"subsidized_signatures":   3, // payer, [3] and the created account
+	})
+	assertRollup(t, day1Rows[1], "2021-01-01", 2, map[string]int{
+		"p2p_count":        1,
+		"p2p_quarks":       5,
+		"unique_senders":   1,
+		"unique_receivers": 1,
+	})
+
+	latest, err := env.committer.Latest(context.Background(), GetRollupIngestorName())
+	require.NoError(t, err)
+	assert.EqualValues(t, model.OrderingKeyFromBlock(5, false), latest)
+
+	// Processing again without new entries should not submit anything.
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(6), nil).Once()
+	require.NoError(t, aggregator.process(context.Background()))
+	require.Len(t, rollupSubmitter.submitted, 1)
+
+	// Day 2 should not be aggregated while it is incomplete, even if history
+	// contains more of it.
+	require.NoError(t, env.rw.Write(context.Background(), generateRollupEntry(t, 7, day2.Add(time.Hour), subsidizer, nil, []solana.Instruction{
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[0], accounts[5]}, instructionTypePayment, 2),
+	})))
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(7), nil).Once()
+	env.sc.On("GetBlockTime", uint64(7)).Return(day2.Add(time.Hour), nil)
+	require.NoError(t, aggregator.process(context.Background()))
+	require.Len(t, rollupSubmitter.submitted, 1)
+
+	latest, err = env.committer.Latest(context.Background(), GetRollupIngestorName())
+	require.NoError(t, err)
+	assert.EqualValues(t, model.OrderingKeyFromBlock(5, false), latest)
+
+	// Once day 3 starts, day 2 is complete.
+	require.NoError(t, env.rw.Write(context.Background(), generateRollupEntry(t, 8, day3, subsidizer, nil, []solana.Instruction{
+		generateInstruction(t, env.mint, []ed25519.PublicKey{accounts[2], accounts[0], accounts[5]}, instructionTypePayment, 1),
+	})))
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(8), nil).Once()
+	env.sc.On("GetBlockTime", uint64(8)).Return(day3, nil)
+	require.NoError(t, aggregator.process(context.Background()))
+
+	require.Len(t, rollupSubmitter.submitted, 2)
+	day2Rows := getRollupRows(t, rollupSubmitter.submitted[1])
+	require.Len(t, day2Rows, 2)
+	assertRollup(t, day2Rows[0], "2021-01-02", 0, map[string]int{
+		"other_count":             2,
+		"other_quarks":            5,
+		"unique_senders":          1,
+		"unique_receivers":        1,
+		"subsidized_transactions": 2,
+		"subsidized_signatures":   4,
+	})
+	assertRollup(t, day2Rows[1], "2021-01-02", 1, map[string]int{
+		"spend_count":             1,
+		"spend_quarks":            7,
+		"unique_senders":          1,
+		"unique_receivers":        1,
+		"subsidized_transactions": 1,
+		"subsidized_signatures":   2,
+	})
+
+	latest, err = env.committer.Latest(context.Background(), GetRollupIngestorName())
+	require.NoError(t, err)
+	assert.EqualValues(t, model.OrderingKeyFromBlock(8, false), latest)
+
+	// Recomputing should yield identical rollups, without affecting the
+	// commit pointer.
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(8), nil).Once()
+	require.NoError(t, aggregator.Recompute(context.Background(), day1, day3))
+
+	require.Len(t, rollupSubmitter.submitted, 4)
+	assert.Equal(t, day1Rows, getRollupRows(t, rollupSubmitter.submitted[2]))
+	assert.Equal(t, day2Rows, getRollupRows(t, rollupSubmitter.submitted[3]))
+
+	// Recomputing a range that history does not cover yet should not submit
+	// the incomplete day.
+	env.sc.On("GetSlot", solana.CommitmentMax).Return(uint64(8), nil).Once()
+	require.NoError(t, aggregator.Recompute(context.Background(), day2, day3.Add(24*time.Hour)))
+
+	require.Len(t, rollupSubmitter.submitted, 5)
+	assert.Equal(t, day2Rows, getRollupRows(t, rollupSubmitter.submitted[4]))
+
+	latest, err = env.committer.Latest(context.Background(), GetRollupIngestorName())
+	require.NoError(t, err)
+	assert.EqualValues(t, model.OrderingKeyFromBlock(8, false), latest)
+
+	assert.Error(t, aggregator.Recompute(context.Background(), day2, day1))
+}
+
+func generateRollupEntry(t *testing.T, slot uint64, blockTime time.Time, payer ed25519.PrivateKey, txErr []byte, instructions []solana.Instruction) *model.Entry {
+	txn := solana.NewTransaction(payer.Public().(ed25519.PublicKey), instructions...)
+
+	require.NoError(t, txn.Sign(payer))
+
+	ts, err := ptypes.TimestampProto(blockTime)
+	require.NoError(t, err)
+
+	return &model.Entry{
+		Version: model.KinVersion_KIN4,
+		Kind: &model.Entry_Solana{
+			Solana: &model.SolanaEntry{
+				Slot:             slot,
+				Confirmed:        true,
+				BlockTime:        ts,
+				Transaction:      txn.Marshal(),
+				TransactionError: txErr,
+			},
+		},
+	}
+}
+
+func generateKinMemo(t *testing.T, txType kin.TransactionType, appIndex uint16) solana.Instruction {
+	m, err := kin.NewMemo(1, txType, appIndex, nil)
+	require.NoError(t, err)
+	return memo.Instruction(base64.StdEncoding.EncodeToString(m[:]))
+}
+
+func getRollupRows(t *testing.T, src interface{}) []Row {
+	rows, err := GetRows(src)
+	require.NoError(t, err)
+	assertSchema(t, RollupsSchema, rows)
+	return rows
+}
+
+func assertRollup(t *testing.T, row Row, date string, appIndex int, expected map[string]int) {
+	assert.Equal(t, date, row.Values["date"])
+	assert.Equal(t, appIndex, row.Values["app_index"])
+	assert.Len(t, row.Values, len(RollupsSchema))
+
+	for _, c := range RollupsSchema[2:] {
+		assert.EqualValues(t, expected[c.Name], row.Values[c.Name], c.Name)
+	}
+}
@@ -1,7 +1,178 @@
 package kre
 
-import "context"
+import (
+	"context"
+	"reflect"
 
+	"cloud.google.com/go/bigquery"
+	"github.com/pkg/errors"
+)
+
+// Submitter submits KRE rows (i.e. creations or payments) to a reporting
+// backend.
+//
+// src is a slice of rows, each of which implements bigquery.ValueSaver.
+// Implementations that are not backed by BigQuery can use GetRows to
+// extract the row values.
 type Submitter interface {
 	Submit(ctx context.Context, src interface{}) (err error)
 }
+
+// Row is a single row submitted to a KRE table.
+type Row struct {
+	// InsertID uniquely identifies the row. Rows may be submitted more than
+	// once (i.e. if the loader is restarted before committing), so backends
+	// should use it to deduplicate rows.
+	InsertID string
+
+	// Values contains the column values of the row, keyed by column name.
+	// Columns without a value are omitted.
+	Values map[string]bigquery.Value
+}
+
+// GetRows returns the rows contained in src, which must be a slice of
+// bigquery.ValueSaver.
+func GetRows(src interface{}) ([]Row, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() != reflect.Slice {
+		return nil, errors.Errorf("src must be a slice, got %T", src)
+	}
+
+	rows := make([]Row, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		saver, ok := v.Index(i).Interface().(bigquery.ValueSaver)
+		if !ok {
+			return nil, errors.Errorf("row %d (%T) does not implement bigquery.ValueSaver", i, v.Index(i).Interface())
+		}
+
+		values, insertID, err := saver.Save()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to save row %d", i)
+		}
+
+		rows[i] = Row{
+			InsertID: insertID,
+			Values:   values,
+		}
+	}
+
+	return rows, nil
+}
+
+// ColumnType is the type of a KRE column.
+type ColumnType int
+
+const (
+	ColumnTypeString ColumnType = iota
+	ColumnTypeInteger
+	ColumnTypeBytes
+
+	// ColumnTypeDate columns contain a date formatted as "2006-01-02".
+	ColumnTypeDate
+
+	// ColumnTypeTimestamp columns contain a UTC time formatted as
+	// "2006-01-02 15:04:05 UTC".
+	ColumnTypeTimestamp
+)
+
+// Column is a column of a KRE table.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Schema is the ordered set of columns of a KRE table.
+type Schema []Column
+
+// CreationsSchema is the schema of the rows submitted by the creations
+// submitter.
+var CreationsSchema = Schema{
+	{Name: "date", Type: ColumnTypeDate},
+	{Name: "time", Type: ColumnTypeTimestamp},
+	{Name: "tx_id", Type: ColumnTypeString},
+	{Name: "tx_status", Type: ColumnTypeString},
+	{Name: "account", Type: ColumnTypeString},
+	{Name: "account_owner", Type: ColumnTypeString},
+	{Name: "initial_balance", Type: ColumnTypeString},
+	{Name: "initial_quarks", Type: ColumnTypeInteger},
+	{Name: "memo_text", Type: ColumnTypeString},
+	{Name: "memo_binary", Type: ColumnTypeBytes},
+	{Name: "app_index", Type: ColumnTypeInteger},
+	{Name: "subsidizer", Type: ColumnTypeString},
+}
+
+// PaymentsSchema is the schema of the rows submitted by the payments
+// submitter.
+var PaymentsSchema = Schema{
+	{Name: "date", Type: ColumnTypeDate},
+	{Name: "time", Type: ColumnTypeTimestamp},
+	{Name: "tx_id", Type: ColumnTypeString},
+	{Name: "tx_status", Type: ColumnTypeString},
+	{Name: "instruction_offset", Type: ColumnTypeInteger},
+	{Name: "source", Type: ColumnTypeString},
+	{Name: "source_owner", Type: ColumnTypeString},
+	{Name: "destination", Type: ColumnTypeString},
+	{Name: "destination_owner", Type: ColumnTypeString},
+	{Name: "amount", Type: ColumnTypeString},
+	{Name: "quarks", Type: ColumnTypeInteger},
+	{Name: "memo_text", Type: ColumnTypeString},
+	{Name: "memo_binary", Type: ColumnTypeBytes},
+	{Name: "app_index", Type: ColumnTypeInteger},
+	{Name: "subsidizer", Type: ColumnTypeString},
+}
+
+// OwnershipChangesSchema is the schema of the rows submitted by the ownership
+// changes submitter.
+var OwnershipChangesSchema = Schema{
+	{Name: "date", Type: ColumnTypeDate},
+	{Name: "time", Type: ColumnTypeTimestamp},
+	{Name: "tx_id", Type: ColumnTypeString},
+	{Name: "tx_status", Type: ColumnTypeString},
+	{Name: "instruction_offset", Type: ColumnTypeInteger},
+	{Name: "account", Type: ColumnTypeString},
+	{Name: "previous_owner", Type: ColumnTypeString},
+	{Name: "new_owner", Type: ColumnTypeString},
+	{Name: "memo_text", Type: ColumnTypeString},
+	{Name: "memo_binary", Type: ColumnTypeBytes},
+	{Name: "app_index", Type: ColumnTypeInteger},
+	{Name: "subsidizer", Type: ColumnTypeString},
+}
+
+// ClosuresSchema is the schema of the rows submitted by the closures
+// submitter.
+var ClosuresSchema = Schema{
+	{Name: "date", Type: ColumnTypeDate},
+	{Name: "time", Type: ColumnTypeTimestamp},
+	{Name: "tx_id", Type: ColumnTypeString},
+	{Name: "tx_status", Type: ColumnTypeString},
+	{Name: "instruction_offset", Type: ColumnTypeInteger},
+	{Name: "account", Type: ColumnTypeString},
+	{Name: "account_owner", Type: ColumnTypeString},
+	{Name: "destination", Type: ColumnTypeString},
+	{Name: "memo_text", Type: ColumnTypeString},
+	{Name: "memo_binary", Type: ColumnTypeBytes},
+	{Name: "app_index", Type: ColumnTypeInteger},
+	{Name: "subsidizer", Type: ColumnTypeString},
+}
+
+// RollupsSchema is the schema of the rows submitted by the rollups submitter.
+//
+// Rollups are keyed by date and app index, and a rollup may be recomputed, so
+// backends should replace previously submitted rows with the same insert id.
+var RollupsSchema = Schema{
+	{Name: "date", Type: ColumnTypeDate},
+	{Name: "app_index", Type: ColumnTypeInteger},
+	{Name: "earn_count", Type: ColumnTypeInteger},
+	{Name: "earn_quarks", Type: ColumnTypeInteger},
+	{Name: "spend_count", Type: ColumnTypeInteger},
+	{Name: "spend_quarks", Type: ColumnTypeInteger},
+	{Name: "p2p_count", Type: ColumnTypeInteger},
+	{Name: "p2p_quarks", Type: ColumnTypeInteger},
+	{Name: "other_count", Type: ColumnTypeInteger},
+	{Name: "other_quarks", Type: ColumnTypeInteger},
+	{Name: "unique_senders", Type: ColumnTypeInteger},
+	{Name: "unique_receivers", Type: ColumnTypeInteger},
+	{Name: "new_accounts", Type: ColumnTypeInteger},
+	{Name: "subsidized_transactions", Type: ColumnTypeInteger},
+	{Name: "subsidized_signatures", Type: ColumnTypeInteger},
+}
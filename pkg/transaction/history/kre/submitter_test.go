@@ -0,0 +1,130 @@
+package kre
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+)
+
+func TestGetRows(t *testing.T) {
+	keys := testutil.GenerateSolanaKeys(t, 5)
+	memoText := "1-test"
+
+	creations := []*creation{
+		{
+			blockTime:    time.Now(),
+			offset:       0,
+			successful:   true,
+			account:      keys[0],
+			accountOwner: keys[1],
+			memoText:     &memoText,
+			memo:         []byte(memoText),
+			appIndex:     10,
+			subsidizer:   keys[4],
+		},
+		{
+			blockTime:    time.Now(),
+			offset:       1,
+			account:      keys[2],
+			accountOwner: keys[3],
+		},
+	}
+	rows, err := GetRows(creations)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.NotEqual(t, rows[0].InsertID, rows[1].InsertID)
+	assertSchema(t, CreationsSchema, rows)
+	assert.Len(t, rows[0].Values, len(CreationsSchema))
+
+	payments := []*payment{
+		{
+			blockTime:   time.Now(),
+			offset:      0,
+			successful:  true,
+			source:      keys[0],
+			sourceOwner: keys[1],
+			dest:        keys[2],
+			destOwner:   keys[3],
+			quarks:      10,
+			memoText:    &memoText,
+			memo:        []byte(memoText),
+			appIndex:    10,
+			subsidizer:  keys[4],
+		},
+	}
+	rows, err = GetRows(payments)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assertSchema(t, PaymentsSchema, rows)
+	assert.Len(t, rows[0].Values, len(PaymentsSchema))
+
+	changes := []*ownershipChange{
+		{
+			blockTime:     time.Now(),
+			offset:        1,
+			successful:    true,
+			account:       keys[0],
+			previousOwner: keys[1],
+			newOwner:      keys[2],
+			memoText:      &memoText,
+			memo:          []byte(memoText),
+			appIndex:      10,
+			subsidizer:    keys[4],
+		},
+		{
+			blockTime:     time.Now(),
+			offset:        2,
+			account:       keys[0],
+			previousOwner: keys[1],
+		},
+	}
+	rows, err = GetRows(changes)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.NotEqual(t, rows[0].InsertID, rows[1].InsertID)
+	assertSchema(t, OwnershipChangesSchema, rows)
+	assert.Len(t, rows[0].Values, len(OwnershipChangesSchema))
+
+	closures := []*closure{
+		{
+			blockTime:    time.Now(),
+			offset:       1,
+			successful:   true,
+			account:      keys[0],
+			accountOwner: keys[1],
+			destination:  keys[1],
+			memoText:     &memoText,
+			memo:         []byte(memoText),
+			appIndex:     10,
+			subsidizer:   keys[4],
+		},
+	}
+	rows, err = GetRows(closures)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assertSchema(t, ClosuresSchema, rows)
+	assert.Len(t, rows[0].Values, len(ClosuresSchema))
+
+	_, err = GetRows(creations[0])
+	assert.Error(t, err)
+	_, err = GetRows([]string{"row"})
+	assert.Error(t, err)
+}
+
+func assertSchema(t *testing.T, schema Schema, rows []Row) {
+	columns := make(map[string]struct{})
+	for _, c := range schema {
+		columns[c.Name] = struct{}{}
+	}
+
+	for _, r := range rows {
+		assert.NotEmpty(t, r.InsertID)
+		for name := range r.Values {
+			assert.Contains(t, columns, name)
+		}
+	}
+}
@@ -185,7 +185,11 @@ func (rw *RW) GetLatestForAccount(_ context.Context, account string) (*model.Ent
 }
 
 // GetAccountTransactions implements history.Writer.GetAccountTransactions.
-func (rw *RW) GetAccountTransactions(_ context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+func (rw *RW) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	return history.GetFilteredAccountTransactions(ctx, account, opts, rw.getAccountTransactions)
+}
+
+func (rw *RW) getAccountTransactions(_ context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
 	rw.Lock()
 	defer rw.Unlock()
 
@@ -223,6 +227,12 @@ func (rw *RW) GetAccountTransactions(_ context.Context, account string, opts *hi
 				continue
 			}
 
+			if inRange, err := opts.InTimeRange(accountHistory[i]); err != nil {
+				return nil, errors.Wrap(err, "failed to apply time range")
+			} else if !inRange {
+				continue
+			}
+
 			results = append(results, proto.Clone(accountHistory[i]).(*model.Entry))
 			if len(results) == limit {
 				break
@@ -230,6 +240,12 @@ func (rw *RW) GetAccountTransactions(_ context.Context, account string, opts *hi
 		}
 	} else {
 		for ; i < len(accountHistory); i++ {
+			if inRange, err := opts.InTimeRange(accountHistory[i]); err != nil {
+				return nil, errors.Wrap(err, "failed to apply time range")
+			} else if !inRange {
+				continue
+			}
+
 			results = append(results, proto.Clone(accountHistory[i]).(*model.Entry))
 			if len(results) == limit {
 				break
@@ -240,6 +256,195 @@ func (rw *RW) GetAccountTransactions(_ context.Context, account string, opts *hi
 	return results, nil
 }
 
+// Delete implements history.Deleter.Delete.
+func (rw *RW) Delete(_ context.Context, e *model.Entry) error {
+	rw.Lock()
+	defer rw.Unlock()
+
+	hash, err := e.GetTxID()
+	if err != nil {
+		return err
+	}
+
+	accounts, err := e.GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	delete(rw.txns, string(hash))
+
+	for _, a := range accounts {
+		var remaining orderedEntries
+		for _, existing := range rw.accountTxns[a] {
+			existingHash, err := existing.GetTxID()
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(existingHash, hash) {
+				remaining = append(remaining, existing)
+			}
+		}
+
+		if len(remaining) == 0 {
+			delete(rw.accountTxns, a)
+		} else {
+			rw.accountTxns[a] = remaining
+		}
+	}
+
+	return nil
+}
+
+// Prune implements history.Pruner.Prune.
+func (rw *RW) Prune(ctx context.Context, e *model.Entry) error {
+	if err := rw.Delete(ctx, e); err != nil {
+		return err
+	}
+
+	rw.Lock()
+	defer rw.Unlock()
+
+	hash, err := e.GetTxID()
+	if err != nil {
+		return err
+	}
+
+	var remaining orderedEntries
+	for _, existing := range rw.txnHistory {
+		existingHash, err := existing.GetTxID()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(existingHash, hash) {
+			remaining = append(remaining, existing)
+		}
+	}
+	rw.txnHistory = remaining
+
+	return nil
+}
+
+// Repair implements history.Repairer.Repair.
+func (rw *RW) Repair(_ context.Context, e *model.Entry) error {
+	rw.Lock()
+	defer rw.Unlock()
+
+	hash, err := e.GetTxID()
+	if err != nil {
+		return err
+	}
+
+	accounts, err := e.GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	without := func(entries orderedEntries) (orderedEntries, error) {
+		var remaining orderedEntries
+		for _, existing := range entries {
+			existingHash, err := existing.GetTxID()
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(existingHash, hash) {
+				remaining = append(remaining, existing)
+			}
+		}
+		return remaining, nil
+	}
+
+	if rw.txnHistory, err = without(rw.txnHistory); err != nil {
+		return err
+	}
+	for a, accountHistory := range rw.accountTxns {
+		remaining, err := without(accountHistory)
+		if err != nil {
+			return err
+		}
+
+		if len(remaining) == 0 {
+			delete(rw.accountTxns, a)
+		} else {
+			rw.accountTxns[a] = remaining
+		}
+	}
+
+	rw.txns[string(hash)] = proto.Clone(e).(*model.Entry)
+
+	if solanaEntry := e.GetSolana(); solanaEntry != nil && solanaEntry.Confirmed {
+		rw.txnHistory = append(rw.txnHistory, proto.Clone(e).(*model.Entry))
+		sort.Sort(rw.txnHistory)
+	}
+
+	for _, a := range accounts {
+		accountHistory := append(rw.accountTxns[a], proto.Clone(e).(*model.Entry))
+		sort.Sort(accountHistory)
+		rw.accountTxns[a] = accountHistory
+	}
+
+	rw.Writes = append(rw.Writes, proto.Clone(e).(*model.Entry))
+
+	return nil
+}
+
+// GetUnindexed implements history.TimeIndexer.GetUnindexed.
+//
+// Entries are indexed by block time as they are read, so any entry without
+// a block time is considered to be unindexed.
+func (rw *RW) GetUnindexed(_ context.Context, cursor []byte, limit int) ([]*model.Entry, []byte, error) {
+	rw.Lock()
+	defer rw.Unlock()
+
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var startAccount string
+	var startKey []byte
+	if cursor != nil {
+		var err error
+		if startAccount, startKey, err = history.DecodeAccountCursor(cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	accounts := make([]string, 0, len(rw.accountTxns))
+	for a := range rw.accountTxns {
+		if a >= startAccount {
+			accounts = append(accounts, a)
+		}
+	}
+	sort.Strings(accounts)
+
+	var results []*model.Entry
+	for _, a := range accounts {
+		for _, e := range rw.accountTxns[a] {
+			orderingKey, err := e.GetOrderingKey()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to get ordering key")
+			}
+			if cursor != nil && a == startAccount && bytes.Compare(orderingKey, startKey) <= 0 {
+				continue
+			}
+
+			blockTime, err := e.GetBlockTime()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to get block time")
+			}
+			if !blockTime.IsZero() {
+				continue
+			}
+
+			results = append(results, proto.Clone(e).(*model.Entry))
+			if len(results) == limit {
+				return results, history.EncodeAccountCursor(a, orderingKey), nil
+			}
+		}
+	}
+
+	return results, nil, nil
+}
+
 // Reset resets the recorded writes.
 func (rw *RW) Reset() {
 	rw.Lock()
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/memo"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/pkg/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Payment is a payment contained in an entry.
+type Payment struct {
+	Source      ed25519.PublicKey
+	Destination ed25519.PublicKey
+
+	// Owner is the owner of the source account, if it is a Solana payment.
+	Owner ed25519.PublicKey
+
+	// Amount is the amount of the payment, in quarks.
+	Amount uint64
+}
+
+// GetPaymentsAndMemos returns the payments, and the valid Kin memos, contained
+// in the entry.
+func (m *Entry) GetPaymentsAndMemos() ([]Payment, []kin.Memo, error) {
+	switch v := m.Kind.(type) {
+	case *Entry_Stellar:
+		var env xdr.TransactionEnvelope
+		if _, err := xdr.Unmarshal(bytes.NewReader(v.Stellar.EnvelopeXdr), &env); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse envelope xdr")
+		}
+
+		payments, memos := GetPaymentsAndMemosFromEnvelope(env, m.Version)
+		return payments, memos, nil
+	case *Entry_Solana:
+		var txn solana.Transaction
+		if err := txn.Unmarshal(v.Solana.Transaction); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse solana transaction")
+		}
+
+		payments, memos := GetPaymentsAndMemosFromTransaction(txn)
+		return payments, memos, nil
+	default:
+		return nil, nil, errors.Errorf("unsupported entry version: %d", m.Version)
+	}
+}
+
+// GetPaymentsAndMemosFromEnvelope returns the payments, and the valid Kin memos,
+// contained within a transaction envelope.
+//
+// Amounts are normalized to quarks, which requires the version of the entry.
+func GetPaymentsAndMemosFromEnvelope(env xdr.TransactionEnvelope, version KinVersion) (payments []Payment, memos []kin.Memo) {
+	if env.Tx.Memo.Hash != nil {
+		var m kin.Memo
+		copy(m[:], env.Tx.Memo.Hash[:])
+		if kin.IsValidMemoStrict(m) {
+			memos = append(memos, m)
+		}
+	}
+
+	for _, op := range env.Tx.Operations {
+		if op.Body.PaymentOp == nil {
+			continue
+		}
+
+		source := env.Tx.SourceAccount
+		if op.SourceAccount != nil {
+			source = *op.SourceAccount
+		}
+
+		amount := uint64(op.Body.PaymentOp.Amount)
+
+		// Kin 2 is a 7 decimal asset, whereas quarks are 5 decimals.
+		if version == KinVersion_KIN2 {
+			amount /= 100
+		}
+
+		payments = append(payments, Payment{
+			Source:      rawFromAccountID(source),
+			Destination: rawFromAccountID(op.Body.PaymentOp.Destination),
+			Amount:      amount,
+		})
+	}
+
+	return payments, memos
+}
+
+// GetPaymentsAndMemosFromTransaction returns the payments, and the valid Kin
+// memos, contained within a solana transaction.
+func GetPaymentsAndMemosFromTransaction(txn solana.Transaction) (payments []Payment, memos []kin.Memo) {
+	for i := range txn.Message.Instructions {
+		if decompiled, err := memo.DecompileMemo(txn.Message, i); err == nil {
+			raw, err := base64.StdEncoding.DecodeString(string(decompiled.Data))
+			if err != nil {
+				continue
+			}
+
+			var m kin.Memo
+			copy(m[:], raw)
+			if kin.IsValidMemoStrict(m) {
+				memos = append(memos, m)
+			}
+			continue
+		}
+
+		if transfer, err := token.DecompileTransferAccount(txn.Message, i); err == nil {
+			payments = append(payments, Payment{
+				Source:      transfer.Source,
+				Destination: transfer.Destination,
+				Owner:       transfer.Owner,
+				Amount:      transfer.Amount,
+			})
+		}
+	}
+
+	return payments, memos
+}
+
+func rawFromAccountID(id xdr.AccountId) ed25519.PublicKey {
+	v, ok := id.GetEd25519()
+	if !ok {
+		return nil
+	}
+
+	raw := make([]byte, ed25519.PublicKeySize)
+	copy(raw, v[:])
+	return raw
+}
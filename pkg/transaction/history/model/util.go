@@ -6,7 +6,10 @@ import (
 	"encoding/binary"
 	"math"
 	"strconv"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stellar/go/network"
@@ -126,6 +129,32 @@ func (m *Entry) GetOrderingKey() ([]byte, error) {
 	}
 }
 
+// GetBlockTime returns the time of the block (or ledger) that contains the
+// entry. The zero time is returned if the time is not known, which is
+// generally the case for unconfirmed entries.
+func (m *Entry) GetBlockTime() (time.Time, error) {
+	var ts *timestamp.Timestamp
+	switch v := m.Kind.(type) {
+	case *Entry_Stellar:
+		ts = v.Stellar.LedgerCloseTime
+	case *Entry_Solana:
+		ts = v.Solana.BlockTime
+	default:
+		return time.Time{}, errors.Errorf("unsupported entry version: %d", m.Version)
+	}
+
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid block time")
+	}
+
+	return t, nil
+}
+
 func OrderingKeyFromCursor(v KinVersion, cursor string) ([]byte, error) {
 	pt, err := strconv.ParseUint(cursor, 10, 64)
 	if err != nil {
@@ -6,7 +6,9 @@ import (
 	"math/rand"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/token"
 	"github.com/kinecosystem/go/strkey"
@@ -147,6 +149,18 @@ func TestSolana(t *testing.T) {
 	assert.EqualValues(t, KinVersion_KIN4, k[0])
 	assert.Equal(t, entry.Kind.(*Entry_Solana).Solana.Slot, binary.BigEndian.Uint64(k[1:]))
 	assert.Equal(t, txID[:8], k[9:])
+
+	// Block time
+	blockTime, err := entry.GetBlockTime()
+	assert.NoError(t, err)
+	assert.True(t, blockTime.IsZero())
+
+	expected := time.Unix(1600000000, 0).UTC()
+	entry.GetSolana().BlockTime, err = ptypes.TimestampProto(expected)
+	require.NoError(t, err)
+	blockTime, err = entry.GetBlockTime()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, blockTime)
 }
 
 func TestAccountFromRaw(t *testing.T) {
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// Schema contains the statements required to create the tables used
+// by the history store.
+//
+// tx_by_hash contains every entry (including those that have not yet been
+// confirmed), tx_by_account contains the entries related to each account,
+// and tx_history contains confirmed (Solana) entries in block order.
+//
+// tx_by_account.block_time is only set for entries that have a block time,
+// and is used to serve time range queries from tx_by_account_time_idx. The
+// ALTER and DROP statements migrate existing deployments. Rows written before
+// the column existed must be backfilled (see history.TimeIndexer).
+const Schema = `
+CREATE TABLE IF NOT EXISTS tx_by_hash (
+	tx_hash BYTEA PRIMARY KEY,
+	entry   BYTEA NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tx_by_account (
+	account      TEXT  NOT NULL,
+	ordering_key BYTEA NOT NULL,
+	tx_hash      BYTEA NOT NULL,
+	entry        BYTEA NOT NULL,
+	block_time   TIMESTAMPTZ,
+
+	PRIMARY KEY (account, ordering_key)
+);
+
+ALTER TABLE tx_by_account ADD COLUMN IF NOT EXISTS block_time TIMESTAMPTZ;
+DROP INDEX IF EXISTS tx_by_account_block_time_idx;
+CREATE INDEX IF NOT EXISTS tx_by_account_time_idx ON tx_by_account (account, block_time, ordering_key) WHERE block_time IS NOT NULL;
+CREATE INDEX IF NOT EXISTS tx_by_account_unindexed_idx ON tx_by_account (account, ordering_key) WHERE block_time IS NULL;
+
+CREATE TABLE IF NOT EXISTS tx_history (
+	ordering_key BYTEA  PRIMARY KEY,
+	slot         BIGINT NOT NULL,
+	tx_hash      BYTEA  NOT NULL,
+	entry        BYTEA  NOT NULL
+);
+`
+
+const (
+	insertTxQuery = `INSERT INTO tx_by_hash (tx_hash, entry) VALUES ($1, $2) ON CONFLICT (tx_hash) DO NOTHING`
+	getTxQuery    = `SELECT entry FROM tx_by_hash WHERE tx_hash = $1`
+	deleteTxQuery = `DELETE FROM tx_by_hash WHERE tx_hash = $1`
+	upsertTxQuery = `
+		INSERT INTO tx_by_hash (tx_hash, entry) VALUES ($1, $2)
+		ON CONFLICT (tx_hash) DO UPDATE SET entry = EXCLUDED.entry`
+	getTxForUpdateQuery = `SELECT entry FROM tx_by_hash WHERE tx_hash = $1 FOR UPDATE`
+
+	putHistoryQuery = `
+		INSERT INTO tx_history (ordering_key, slot, tx_hash, entry) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ordering_key) DO UPDATE SET slot = EXCLUDED.slot, tx_hash = EXCLUDED.tx_hash, entry = EXCLUDED.entry`
+	deleteHistoryQuery = `DELETE FROM tx_history WHERE ordering_key = $1`
+	getHistoryQuery    = `
+		SELECT entry FROM tx_history
+		WHERE ordering_key BETWEEN $1 AND $2
+		ORDER BY ordering_key ASC
+		LIMIT $3`
+
+	putAccountTxQuery = `
+		INSERT INTO tx_by_account (account, ordering_key, tx_hash, entry, block_time) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account, ordering_key) DO UPDATE SET tx_hash = EXCLUDED.tx_hash, entry = EXCLUDED.entry, block_time = EXCLUDED.block_time`
+
+	getAccountTxsAscQuery = `
+		SELECT entry FROM tx_by_account
+		WHERE account = $1 AND ordering_key >= $2
+		ORDER BY ordering_key ASC
+		LIMIT $3`
+	getAccountTxsDescQuery = `
+		SELECT entry FROM tx_by_account
+		WHERE account = $1 AND ordering_key <= $2
+		ORDER BY ordering_key DESC
+		LIMIT $3`
+
+	// The time range queries are served by tx_by_account_time_idx. $4 and $5
+	// are the (optional) [start, end) block time bounds.
+	//
+	// The cursor ($2) is resumed from its position in the index, which is
+	// its block time. If the cursor row has no block time, the scan starts
+	// from the start (or end) of the range instead, and the ordering key
+	// bound excludes everything before (or after) the cursor.
+	getAccountTxsByTimeAscQuery = `
+		SELECT entry FROM tx_by_account
+		WHERE account = $1 AND ordering_key >= $2
+			AND block_time >= COALESCE($4::timestamptz, '-infinity')
+			AND block_time < COALESCE($5::timestamptz, 'infinity')
+			AND (block_time, ordering_key) >= (
+				COALESCE((SELECT c.block_time FROM tx_by_account c WHERE c.account = $1 AND c.ordering_key = $2), '-infinity'),
+				$2
+			)
+		ORDER BY block_time ASC, ordering_key ASC
+		LIMIT $3`
+	getAccountTxsByTimeDescQuery = `
+		SELECT entry FROM tx_by_account
+		WHERE account = $1 AND ordering_key <= $2
+			AND block_time >= COALESCE($4::timestamptz, '-infinity')
+			AND block_time < COALESCE($5::timestamptz, 'infinity')
+			AND (block_time, ordering_key) <= (
+				COALESCE((SELECT c.block_time FROM tx_by_account c WHERE c.account = $1 AND c.ordering_key = $2), 'infinity'),
+				$2
+			)
+		ORDER BY block_time DESC, ordering_key DESC
+		LIMIT $3`
+
+	// getUnindexedQuery scans the rows without a block time, after the
+	// ($1, $2) cursor.
+	getUnindexedQuery = `
+		SELECT account, ordering_key, entry FROM tx_by_account
+		WHERE block_time IS NULL AND (account, ordering_key) > ($1, $2)
+		ORDER BY account ASC, ordering_key ASC
+		LIMIT $3`
+
+	getAccountLatestQuery = `
+		SELECT entry FROM tx_by_account
+		WHERE account = $1
+		ORDER BY ordering_key DESC
+		LIMIT 1`
+	deleteAccountTxsQuery = `DELETE FROM tx_by_account WHERE account = ANY($1) AND ordering_key = $2`
+)
+
+func getEntry(raw []byte) (*model.Entry, error) {
+	entry := &model.Entry{}
+	if err := proto.Unmarshal(raw, entry); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal entry")
+	}
+
+	return entry, nil
+}
+
+func getEntries(rows pgx.Rows) ([]*model.Entry, error) {
+	defer rows.Close()
+
+	var entries []*model.Entry
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, errors.Wrap(err, "failed to scan entry")
+		}
+
+		e, err := getEntry(raw)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid entry")
+		}
+
+		entries = append(entries, e)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return entries, nil
+}
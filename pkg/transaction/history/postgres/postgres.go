@@ -0,0 +1,429 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+type db struct {
+	pool *pgxpool.Pool
+}
+
+// New returns a history.ReaderWriter backed by PostgreSQL.
+//
+// The tables in Schema must already exist.
+func New(pool *pgxpool.Pool) history.ReaderWriter {
+	return &db{
+		pool: pool,
+	}
+}
+
+// GetTransaction implements history.Reader.GetTransaction.
+func (db *db) GetTransaction(ctx context.Context, txHash []byte) (*model.Entry, error) {
+	var raw []byte
+	err := db.pool.QueryRow(ctx, getTxQuery, txHash).Scan(&raw)
+	if err == pgx.ErrNoRows {
+		return nil, history.ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get tx")
+	}
+
+	return getEntry(raw)
+}
+
+// GetTransactions implements history.Reader.GetTransactions.
+func (db *db) GetTransactions(ctx context.Context, fromBlock, maxBlock uint64, limit int) ([]*model.Entry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if maxBlock == 0 {
+		return nil, errors.New("maxBlock must be non-zero")
+	}
+	if maxBlock < fromBlock {
+		return nil, errors.Errorf("fromBlock (%d) must be <= maxBlock (%d)", fromBlock, maxBlock)
+	}
+
+	rows, err := db.pool.Query(
+		ctx,
+		getHistoryQuery,
+		model.OrderingKeyFromBlock(fromBlock, false),
+		model.OrderingKeyFromBlock(maxBlock, true),
+		limit,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query transactions")
+	}
+
+	entries, err := getEntries(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query transactions")
+	}
+
+	return entries, nil
+}
+
+// GetAccountTransactions implements history.Reader.GetAccountTransactions.
+func (db *db) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	return history.GetFilteredAccountTransactions(ctx, account, opts, db.getAccountTransactions)
+}
+
+func (db *db) getAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	limit := opts.GetLimit()
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var rows pgx.Rows
+	var err error
+	if opts.HasTimeRange() {
+		query := getAccountTxsByTimeAscQuery
+		if opts.GetDescending() {
+			query = getAccountTxsByTimeDescQuery
+		}
+
+		var startTime, endTime *time.Time
+		if t := opts.GetStartTime(); !t.IsZero() {
+			startTime = &t
+		}
+		if t := opts.GetEndTime(); !t.IsZero() {
+			endTime = &t
+		}
+
+		rows, err = db.pool.Query(ctx, query, account, opts.GetStart(), limit, startTime, endTime)
+	} else {
+		query := getAccountTxsAscQuery
+		if opts.GetDescending() {
+			query = getAccountTxsDescQuery
+		}
+
+		rows, err = db.pool.Query(ctx, query, account, opts.GetStart(), limit)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query account history")
+	}
+
+	entries, err := getEntries(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query account history")
+	}
+
+	return entries, nil
+}
+
+// GetLatestForAccount implements history.Reader.GetLatestForAccount.
+func (db *db) GetLatestForAccount(ctx context.Context, account string) (*model.Entry, error) {
+	var raw []byte
+	err := db.pool.QueryRow(ctx, getAccountLatestQuery, account).Scan(&raw)
+	if err == pgx.ErrNoRows {
+		return nil, history.ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest entry")
+	}
+
+	return getEntry(raw)
+}
+
+// GetUnindexed implements history.TimeIndexer.GetUnindexed.
+func (db *db) GetUnindexed(ctx context.Context, cursor []byte, limit int) ([]*model.Entry, []byte, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var account string
+	orderingKey := []byte{}
+	if cursor != nil {
+		var err error
+		if account, orderingKey, err = history.DecodeAccountCursor(cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rows, err := db.pool.Query(ctx, getUnindexedQuery, account, orderingKey, limit)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to query unindexed entries")
+	}
+	defer rows.Close()
+
+	var entries []*model.Entry
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&account, &orderingKey, &raw); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to scan entry")
+		}
+
+		e, err := getEntry(raw)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid entry")
+		}
+
+		entries = append(entries, e)
+	}
+	if rows.Err() != nil {
+		return nil, nil, errors.Wrap(rows.Err(), "failed to query unindexed entries")
+	}
+
+	if len(entries) < limit {
+		return entries, nil, nil
+	}
+
+	return entries, history.EncodeAccountCursor(account, orderingKey), nil
+}
+
+// Write implements history.Writer.Write.
+//
+// Similar to the DynamoDB implementation, the entry in tx_by_hash is
+// only written once. Subsequent writes are validated against it, but
+// do not replace it.
+func (db *db) Write(ctx context.Context, entry *model.Entry) error {
+	return db.write(ctx, entry, false)
+}
+
+// Repair implements history.Repairer.Repair.
+func (db *db) Repair(ctx context.Context, entry *model.Entry) error {
+	return db.write(ctx, entry, true)
+}
+
+func (db *db) write(ctx context.Context, entry *model.Entry, overwrite bool) (err error) {
+	if entry == nil {
+		return errors.New("missing entry")
+	}
+
+	txHash, err := entry.GetTxID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get tx hash")
+	}
+
+	accounts, err := entry.GetAccounts()
+	if err != nil {
+		return errors.Wrap(err, "failed to get related accounts")
+	}
+
+	orderingKey, err := entry.GetOrderingKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to get order key")
+	}
+
+	entryBytes, err := proto.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal entry")
+	}
+
+	var blockTime *time.Time
+	if t, err := entry.GetBlockTime(); err != nil {
+		return errors.Wrap(err, "failed to get block time")
+	} else if !t.IsZero() {
+		blockTime = &t
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if overwrite {
+		if err := repairTx(ctx, tx, txHash, orderingKey, entryBytes); err != nil {
+			return err
+		}
+	} else if tag, err := tx.Exec(ctx, insertTxQuery, txHash, entryBytes); err != nil {
+		return errors.Wrap(err, "failed to insert tx entry")
+	} else if tag.RowsAffected() == 0 {
+		// ON CONFLICT DO NOTHING waits for any concurrent insert to complete,
+		// so the existing entry is visible at this point.
+		var raw []byte
+		if err := tx.QueryRow(ctx, getTxQuery, txHash).Scan(&raw); err != nil {
+			return errors.Wrap(err, "failed to check double insert match")
+		}
+
+		previous, err := getEntry(raw)
+		if err != nil {
+			return err
+		}
+
+		if err := checkDoubleInsertMatch(previous, entry); err != nil {
+			return err
+		}
+	}
+
+	// As with the DynamoDB implementation, only rooted/committed solana
+	// entries are written to history.
+	if solanaEntry := entry.GetSolana(); solanaEntry != nil && solanaEntry.Confirmed {
+		_, err := tx.Exec(ctx, putHistoryQuery, orderingKey, int64(solanaEntry.Slot), txHash, entryBytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to insert tx history entry")
+		}
+	}
+
+	batch := &pgx.Batch{}
+	for _, account := range accounts {
+		batch.Queue(putAccountTxQuery, account, orderingKey, txHash, entryBytes, blockTime)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range accounts {
+		if _, err := results.Exec(); err != nil {
+			_ = results.Close()
+			return errors.Wrap(err, "failed to insert account txns")
+		}
+	}
+	if err := results.Close(); err != nil {
+		return errors.Wrap(err, "failed to insert account txns")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// Delete implements history.Deleter.Delete.
+func (db *db) Delete(ctx context.Context, entry *model.Entry) error {
+	return db.delete(ctx, entry, false)
+}
+
+// Prune implements history.Pruner.Prune.
+func (db *db) Prune(ctx context.Context, entry *model.Entry) error {
+	return db.delete(ctx, entry, true)
+}
+
+// delete removes the entry from the transaction and account indexes, and, if
+// prune is set, from the block ordered history.
+func (db *db) delete(ctx context.Context, entry *model.Entry, prune bool) (err error) {
+	if entry == nil {
+		return errors.New("missing entry")
+	}
+
+	txHash, err := entry.GetTxID()
+	if err != nil {
+		return errors.Wrap(err, "failed to get tx hash")
+	}
+
+	accounts, err := entry.GetAccounts()
+	if err != nil {
+		return errors.Wrap(err, "failed to get related accounts")
+	}
+
+	orderingKey, err := entry.GetOrderingKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to get order key")
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, deleteAccountTxsQuery, accounts, orderingKey); err != nil {
+		return errors.Wrap(err, "failed to delete account txns")
+	}
+	if _, err := tx.Exec(ctx, deleteTxQuery, txHash); err != nil {
+		return errors.Wrap(err, "failed to delete tx entry")
+	}
+	if prune {
+		if _, err := tx.Exec(ctx, deleteHistoryQuery, orderingKey); err != nil {
+			return errors.Wrap(err, "failed to delete tx history entry")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// repairTx replaces the tx_by_hash entry. If the existing entry is keyed
+// differently, its tx_history and tx_by_account rows would not be replaced
+// by the subsequent writes, so they are removed.
+func repairTx(ctx context.Context, tx pgx.Tx, txHash, orderingKey, entryBytes []byte) error {
+	var raw []byte
+	err := tx.QueryRow(ctx, getTxForUpdateQuery, txHash).Scan(&raw)
+	if err != nil && err != pgx.ErrNoRows {
+		return errors.Wrap(err, "failed to get existing entry")
+	} else if err == nil {
+		previous, err := getEntry(raw)
+		if err != nil {
+			return err
+		}
+
+		previousKey, err := previous.GetOrderingKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to get existing order key")
+		}
+
+		if !bytes.Equal(previousKey, orderingKey) {
+			previousAccounts, err := previous.GetAccounts()
+			if err != nil {
+				return errors.Wrap(err, "failed to get existing related accounts")
+			}
+
+			if _, err := tx.Exec(ctx, deleteAccountTxsQuery, previousAccounts, previousKey); err != nil {
+				return errors.Wrap(err, "failed to delete existing account txns")
+			}
+			if _, err := tx.Exec(ctx, deleteHistoryQuery, previousKey); err != nil {
+				return errors.Wrap(err, "failed to delete existing tx history entry")
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, upsertTxQuery, txHash, entryBytes); err != nil {
+		return errors.Wrap(err, "failed to upsert tx entry")
+	}
+
+	return nil
+}
+
+func checkDoubleInsertMatch(previous, entry *model.Entry) error {
+	if previous.Version <= model.KinVersion_KIN3 {
+		if proto.Equal(previous, entry) {
+			return nil
+		}
+
+		return errors.Wrap(history.ErrInvalidUpdate, "double insert with different entries detected")
+	}
+
+	prevSol := previous.GetSolana()
+	sol := entry.GetSolana()
+
+	// See the DynamoDB implementation for why we only guard against
+	// the slot being reset.
+	if prevSol.Slot > 0 && sol.Slot == 0 {
+		return errors.Wrapf(history.ErrInvalidUpdate, "double insert with different entries detected (slot, old: %d, new: 0)", prevSol.Slot)
+	}
+
+	// A confirmed block cannot be unconfirmed
+	if prevSol.Confirmed && !sol.Confirmed {
+		return errors.Wrap(history.ErrInvalidUpdate, "double insert with different entries detected (confirmation status)")
+	}
+
+	if !bytes.Equal(prevSol.Transaction, sol.Transaction) {
+		return errors.Wrap(history.ErrInvalidUpdate, "double insert with different entries detected (transaction)")
+	}
+
+	// In theory an error can occur after submission.
+	// Therefore, we only check equality if there's an error already set.
+	if len(prevSol.TransactionError) > 0 && !bytes.Equal(prevSol.TransactionError, sol.TransactionError) {
+		return errors.Wrap(history.ErrInvalidUpdate, "double insert with different entries detected (transaction error)")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+
+	postgrestest "github.com/kinecosystem/agora/pkg/testutil/postgres"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	"github.com/kinecosystem/agora/pkg/transaction/history/tests"
+)
+
+var (
+	testRW   history.ReaderWriter
+	teardown func()
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	testPool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	connString, cleanUpFunc, err := postgrestest.StartPostgres(context.Background(), testPool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+
+	pool, err := pgxpool.Connect(context.Background(), connString)
+	if err != nil {
+		log.WithError(err).Error("Error connecting to postgres")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	if _, err := pool.Exec(context.Background(), Schema); err != nil {
+		log.WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	testRW = New(pool)
+	teardown = func() {
+		if pc := recover(); pc != nil {
+			cleanUpFunc()
+			panic(pc)
+		}
+
+		if _, err := pool.Exec(context.Background(), "TRUNCATE tx_by_hash, tx_by_account, tx_history"); err != nil {
+			log.WithError(err).Error("Error resetting test tables")
+			cleanUpFunc()
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	pool.Close()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestStore(t *testing.T) {
+	tests.RunTests(t, testRW, teardown)
+}
@@ -5,8 +5,11 @@ import (
 	"crypto/ed25519"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/kinecosystem/go/strkey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,9 +24,15 @@ func RunTests(t *testing.T, rw history.ReaderWriter, teardown func()) {
 	for _, tf := range []func(t *testing.T, rw history.ReaderWriter){
 		testRoundTrip_Stellar,
 		testGetAccountTransactions,
+		testGetAccountTransactions_Filter,
+		testGetAccountTransactions_TimeRange,
 		testHistory,
 		testDoubleInsert_Stellar,
 		testDoubleInsert_Solana,
+		testDelete,
+		testPrune,
+		testRepair,
+		testGetUnindexed,
 	} {
 		tf(t, rw)
 		teardown()
@@ -255,6 +264,223 @@ func testGetAccountTransactions(t *testing.T, rw history.ReaderWriter) {
 	})
 }
 
+func testGetAccountTransactions_Filter(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestGetAccountTransactions_Filter", func(t *testing.T) {
+		ctx := context.Background()
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 1)
+
+		senderAddr := strkey.MustEncode(strkey.VersionByteAccountID, sender.Public().(ed25519.PublicKey))
+		receiverAddr := strkey.MustEncode(strkey.VersionByteAccountID, receivers[0])
+
+		// Every even entry has a (spend) memo for app index 1.
+		//
+		// Entry i transfers i+2 quarks from sender to the receiver.
+		generated := make([]*model.Entry, 20)
+		for i := 0; i < len(generated); i++ {
+			var invoiceHash []byte
+			if i%2 == 0 {
+				invoiceHash = make([]byte, 28)
+			}
+
+			generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, invoiceHash, nil)
+			require.NoError(t, rw.Write(ctx, generated[i]))
+		}
+
+		assertEntries := func(t *testing.T, expected []int, actual []*model.Entry) {
+			require.Len(t, actual, len(expected))
+			for i, e := range expected {
+				assert.True(t, proto.Equal(generated[e], actual[i]), "expected: %d, index: %d", e, i)
+			}
+		}
+
+		// An empty filter should match everything
+		entries, err := rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: &history.Filter{}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 20)
+
+		var evens []int
+		for i := 0; i < 20; i += 2 {
+			evens = append(evens, i)
+		}
+
+		for _, f := range []*history.Filter{
+			{AppIndex: 1},
+			{TransactionTypes: []kin.TransactionType{kin.TransactionTypeSpend}},
+			{AppIndex: 1, TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn, kin.TransactionTypeSpend}},
+		} {
+			entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: f})
+			require.NoError(t, err)
+			assertEntries(t, evens, entries)
+		}
+
+		for _, f := range []*history.Filter{
+			{AppIndex: 2},
+			{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}},
+			{Direction: history.DirectionIncoming},
+		} {
+			entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: f})
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		}
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: &history.Filter{Direction: history.DirectionOutgoing}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 20)
+
+		entries, err = rw.GetAccountTransactions(ctx, receiverAddr, &history.ReadOptions{Filter: &history.Filter{Direction: history.DirectionIncoming}})
+		require.NoError(t, err)
+		assert.Len(t, entries, 20)
+
+		// Amounts
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: &history.Filter{MinAmount: 16}})
+		require.NoError(t, err)
+		assertEntries(t, []int{14, 15, 16, 17, 18, 19}, entries)
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: &history.Filter{MaxAmount: 6}})
+		require.NoError(t, err)
+		assertEntries(t, []int{0, 1, 2, 3, 4}, entries)
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{Filter: &history.Filter{MinAmount: 10, MaxAmount: 12, Direction: history.DirectionOutgoing}})
+		require.NoError(t, err)
+		assertEntries(t, []int{8, 9, 10}, entries)
+
+		// Paging through filtered results should yield each matching entry exactly
+		// once, using the ordering key of the last entry as the next start.
+		//
+		// Since start is inclusive, every page after the first repeats the last
+		// entry of the previous page.
+		for _, descending := range []bool{false, true} {
+			opts := &history.ReadOptions{
+				Descending: descending,
+				Limit:      4,
+				Filter:     &history.Filter{AppIndex: 1},
+			}
+			expected := evens
+			if descending {
+				opts.Start = historytestutil.GetOrderingKey(t, generated[len(generated)-1])
+				expected = nil
+				for i := len(evens) - 1; i >= 0; i-- {
+					expected = append(expected, evens[i])
+				}
+			}
+
+			var paged []*model.Entry
+			for {
+				page, err := rw.GetAccountTransactions(ctx, senderAddr, opts)
+				require.NoError(t, err)
+				if len(paged) > 0 && len(page) > 0 {
+					require.True(t, proto.Equal(paged[len(paged)-1], page[0]))
+					page = page[1:]
+				}
+				if len(page) == 0 {
+					break
+				}
+
+				paged = append(paged, page...)
+				opts.Start = historytestutil.GetOrderingKey(t, page[len(page)-1])
+			}
+			assertEntries(t, expected, paged)
+		}
+
+		// Filters are applied from the start (inclusive).
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			Start:  historytestutil.GetOrderingKey(t, generated[5]),
+			Filter: &history.Filter{MaxAmount: 12},
+		})
+		require.NoError(t, err)
+		assertEntries(t, []int{5, 6, 7, 8, 9, 10}, entries)
+	})
+}
+
+func testGetAccountTransactions_TimeRange(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestGetAccountTransactions_TimeRange", func(t *testing.T) {
+		ctx := context.Background()
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 1)
+		senderAddr := strkey.MustEncode(strkey.VersionByteAccountID, sender.Public().(ed25519.PublicKey))
+
+		// Entry i has a block time of base + i hours. The last entry has no
+		// block time, and should never be returned by a time range query.
+		base := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		generated := make([]*model.Entry, 11)
+		for i := 0; i < len(generated); i++ {
+			generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, nil, nil)
+			if i < len(generated)-1 {
+				blockTime, err := ptypes.TimestampProto(base.Add(time.Duration(i) * time.Hour))
+				require.NoError(t, err)
+				generated[i].GetSolana().BlockTime = blockTime
+			}
+
+			require.NoError(t, rw.Write(ctx, generated[i]))
+		}
+
+		assertEntries := func(t *testing.T, expected []int, actual []*model.Entry) {
+			require.Len(t, actual, len(expected))
+			for i, e := range expected {
+				assert.True(t, proto.Equal(generated[e], actual[i]), "expected: %d, index: %d", e, i)
+			}
+		}
+
+		entries, err := rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{})
+		require.NoError(t, err)
+		assert.Len(t, entries, 11)
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			StartTime: base.Add(2 * time.Hour),
+			EndTime:   base.Add(5 * time.Hour),
+		})
+		require.NoError(t, err)
+		assertEntries(t, []int{2, 3, 4}, entries)
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			StartTime: base.Add(7 * time.Hour),
+		})
+		require.NoError(t, err)
+		assertEntries(t, []int{7, 8, 9}, entries)
+
+		// Sub-second bounds should still be respected.
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			EndTime: base.Add(2*time.Hour + time.Millisecond),
+		})
+		require.NoError(t, err)
+		assertEntries(t, []int{0, 1, 2}, entries)
+
+		entries, err = rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			StartTime: base.Add(time.Millisecond),
+			EndTime:   base.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		// Time ranges compose with paging and filters.
+		opts := &history.ReadOptions{
+			Descending: true,
+			Limit:      2,
+			Start:      historytestutil.GetOrderingKey(t, generated[len(generated)-1]),
+			StartTime:  base.Add(3 * time.Hour),
+			EndTime:    base.Add(8 * time.Hour),
+			Filter:     &history.Filter{MinAmount: 5},
+		}
+		var paged []*model.Entry
+		for {
+			page, err := rw.GetAccountTransactions(ctx, senderAddr, opts)
+			require.NoError(t, err)
+			if len(paged) > 0 && len(page) > 0 {
+				require.True(t, proto.Equal(paged[len(paged)-1], page[0]))
+				page = page[1:]
+			}
+			if len(page) == 0 {
+				break
+			}
+
+			paged = append(paged, page...)
+			opts.Start = historytestutil.GetOrderingKey(t, page[len(page)-1])
+		}
+		assertEntries(t, []int{7, 6, 5, 4, 3}, paged)
+	})
+}
+
 func testHistory(t *testing.T, rw history.ReaderWriter) {
 	t.Run("TestHistory", func(t *testing.T) {
 		ctx := context.Background()
@@ -309,3 +535,264 @@ func testHistory(t *testing.T, rw history.ReaderWriter) {
 		}
 	})
 }
+
+func testDelete(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestDelete", func(t *testing.T) {
+		deleter, ok := rw.(history.Deleter)
+		if !ok {
+			t.Skip("store does not implement history.Deleter")
+		}
+
+		ctx := context.Background()
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 2)
+		senderAddr := strkey.MustEncode(strkey.VersionByteAccountID, sender.Public().(ed25519.PublicKey))
+
+		generated := make([]*model.Entry, 3)
+		for i := 0; i < len(generated); i++ {
+			generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, nil, nil)
+			require.NoError(t, rw.Write(ctx, generated[i]))
+		}
+
+		require.NoError(t, deleter.Delete(ctx, generated[1]))
+
+		txID, err := generated[1].GetTxID()
+		require.NoError(t, err)
+		_, err = rw.GetTransaction(ctx, txID)
+		assert.Equal(t, history.ErrNotFound, err)
+
+		accounts, err := generated[1].GetAccounts()
+		require.NoError(t, err)
+		for _, account := range accounts {
+			entries, err := rw.GetAccountTransactions(ctx, account, &history.ReadOptions{})
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.True(t, proto.Equal(generated[0], entries[0]))
+			assert.True(t, proto.Equal(generated[2], entries[1]))
+		}
+
+		// Block history is retained.
+		entries, err := rw.GetTransactions(ctx, 1, 3, 10)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+
+		// Deleting a missing entry is not an error.
+		require.NoError(t, deleter.Delete(ctx, generated[1]))
+
+		latest, err := rw.GetLatestForAccount(ctx, senderAddr)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(generated[2], latest))
+	})
+}
+
+func testPrune(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestPrune", func(t *testing.T) {
+		pruner, ok := rw.(history.Pruner)
+		if !ok {
+			t.Skip("store does not implement history.Pruner")
+		}
+
+		ctx := context.Background()
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 2)
+
+		generated := make([]*model.Entry, 3)
+		for i := 0; i < len(generated); i++ {
+			generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, nil, nil)
+			require.NoError(t, rw.Write(ctx, generated[i]))
+		}
+
+		require.NoError(t, pruner.Prune(ctx, generated[1]))
+
+		txID, err := generated[1].GetTxID()
+		require.NoError(t, err)
+		_, err = rw.GetTransaction(ctx, txID)
+		assert.Equal(t, history.ErrNotFound, err)
+
+		accounts, err := generated[1].GetAccounts()
+		require.NoError(t, err)
+		for _, account := range accounts {
+			entries, err := rw.GetAccountTransactions(ctx, account, &history.ReadOptions{})
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.True(t, proto.Equal(generated[0], entries[0]))
+			assert.True(t, proto.Equal(generated[2], entries[1]))
+		}
+
+		// Unlike Delete, the entry is removed from the block history.
+		entries, err := rw.GetTransactions(ctx, 1, 3, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.True(t, proto.Equal(generated[0], entries[0]))
+		assert.True(t, proto.Equal(generated[2], entries[1]))
+
+		// Pruning a missing entry is not an error.
+		require.NoError(t, pruner.Prune(ctx, generated[1]))
+
+		// The entry can be written again (for example, in a different block).
+		moved, _ := historytestutil.GenerateSolanaEntry(t, 4, true, sender, receivers, nil, nil)
+		moved.GetSolana().Transaction = generated[1].GetSolana().Transaction
+		require.NoError(t, rw.Write(ctx, moved))
+
+		actual, err := rw.GetTransaction(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(moved, actual))
+	})
+}
+
+func testRepair(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestRepair", func(t *testing.T) {
+		repairer, ok := rw.(history.Repairer)
+		if !ok {
+			t.Skip("store does not implement history.Repairer")
+		}
+
+		ctx := context.Background()
+
+		assertRepaired := func(t *testing.T, repaired *model.Entry) {
+			txID, err := repaired.GetTxID()
+			require.NoError(t, err)
+			actual, err := rw.GetTransaction(ctx, txID)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(repaired, actual))
+
+			accounts, err := repaired.GetAccounts()
+			require.NoError(t, err)
+			for _, account := range accounts {
+				entries, err := rw.GetAccountTransactions(ctx, account, &history.ReadOptions{})
+				require.NoError(t, err)
+				require.Len(t, entries, 1)
+				assert.True(t, proto.Equal(repaired, entries[0]))
+			}
+		}
+
+		// A stale KIN3 entry can never be replaced by a write, as the
+		// entries must be identical.
+		accounts := testutil.GenerateAccountIDs(t, 3)
+		stellarEntry, _ := historytestutil.GenerateStellarEntry(t, 1, 1, accounts[0], accounts[1:], nil, nil)
+		stellarEntry.GetStellar().LedgerCloseTime = nil
+		require.NoError(t, rw.Write(ctx, stellarEntry))
+
+		repaired := proto.Clone(stellarEntry).(*model.Entry)
+		repaired.GetStellar().LedgerCloseTime = ptypes.TimestampNow()
+		repaired.GetStellar().PagingToken++
+		assert.True(t, errors.Is(rw.Write(ctx, repaired), history.ErrInvalidUpdate))
+
+		require.NoError(t, repairer.Repair(ctx, repaired))
+		assertRepaired(t, repaired)
+
+		// Solana entries with a different slot are moved in the block history.
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 1)
+		solanaEntry, _ := historytestutil.GenerateSolanaEntry(t, 10, true, sender, receivers, nil, nil)
+		require.NoError(t, rw.Write(ctx, solanaEntry))
+
+		repaired = proto.Clone(solanaEntry).(*model.Entry)
+		repaired.GetSolana().Slot = 20
+		require.NoError(t, repairer.Repair(ctx, repaired))
+		assertRepaired(t, repaired)
+
+		entries, err := rw.GetTransactions(ctx, 10, 10, 10)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		entries, err = rw.GetTransactions(ctx, 20, 20, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, proto.Equal(repaired, entries[0]))
+
+		// Repairing is idempotent, and does not require an existing entry.
+		require.NoError(t, repairer.Repair(ctx, repaired))
+		assertRepaired(t, repaired)
+
+		missing, _ := historytestutil.GenerateSolanaEntry(t, 30, true, sender, receivers, nil, nil)
+		require.NoError(t, repairer.Repair(ctx, missing))
+		txID, err := missing.GetTxID()
+		require.NoError(t, err)
+		actual, err := rw.GetTransaction(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(missing, actual))
+	})
+}
+
+func testGetUnindexed(t *testing.T, rw history.ReaderWriter) {
+	t.Run("TestGetUnindexed", func(t *testing.T) {
+		indexer, ok := rw.(history.TimeIndexer)
+		if !ok {
+			t.Skip("store does not implement history.TimeIndexer")
+		}
+		repairer, ok := rw.(history.Repairer)
+		if !ok {
+			t.Skip("store does not implement history.Repairer")
+		}
+
+		ctx := context.Background()
+		sender := testutil.GenerateSolanaKeypair(t)
+		receivers := testutil.GenerateSolanaKeys(t, 1)
+		senderAddr := strkey.MustEncode(strkey.VersionByteAccountID, sender.Public().(ed25519.PublicKey))
+
+		// Every odd entry has a block time.
+		base := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		generated := make([]*model.Entry, 6)
+		unindexed := make(map[string]*model.Entry)
+		for i := 0; i < len(generated); i++ {
+			generated[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, nil, nil)
+			if i%2 == 1 {
+				blockTime, err := ptypes.TimestampProto(base.Add(time.Duration(i) * time.Hour))
+				require.NoError(t, err)
+				generated[i].GetSolana().BlockTime = blockTime
+			} else {
+				txID, err := generated[i].GetTxID()
+				require.NoError(t, err)
+				unindexed[string(txID)] = generated[i]
+			}
+
+			require.NoError(t, rw.Write(ctx, generated[i]))
+		}
+
+		scan := func(t *testing.T) map[string]*model.Entry {
+			scanned := make(map[string]*model.Entry)
+
+			var cursor []byte
+			for {
+				entries, next, err := indexer.GetUnindexed(ctx, cursor, 2)
+				require.NoError(t, err)
+				for _, e := range entries {
+					txID, err := e.GetTxID()
+					require.NoError(t, err)
+					scanned[string(txID)] = e
+				}
+
+				if next == nil {
+					return scanned
+				}
+				cursor = next
+			}
+		}
+
+		scanned := scan(t)
+		require.Len(t, scanned, len(unindexed))
+		for txID, e := range unindexed {
+			assert.True(t, proto.Equal(e, scanned[txID]))
+		}
+
+		// Once the block times are known, repairing the entries adds them
+		// to the index.
+		for _, e := range scanned {
+			blockTime, err := ptypes.TimestampProto(base.Add(time.Duration(e.GetSolana().Slot-1) * time.Hour))
+			require.NoError(t, err)
+			e.GetSolana().BlockTime = blockTime
+			require.NoError(t, repairer.Repair(ctx, e))
+		}
+		assert.Empty(t, scan(t))
+
+		entries, err := rw.GetAccountTransactions(ctx, senderAddr, &history.ReadOptions{
+			StartTime: base,
+			EndTime:   base.Add(3 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		for i, e := range entries {
+			assert.EqualValues(t, i+1, e.GetSolana().Slot)
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
+	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+const (
+	// windowSize is the number of blocks that are compared at a time.
+	windowSize = 100
+
+	// pageSize is the number of history entries loaded at a time.
+	pageSize = 1000
+)
+
+// IssueType is the type of inconsistency found between history and the chain.
+type IssueType int
+
+const (
+	// IssueMissing indicates that a transaction on chain is not in history.
+	IssueMissing IssueType = iota
+
+	// IssueExtra indicates that a transaction in history is not on chain.
+	IssueExtra
+
+	// IssueStale indicates that a transaction exists in history, but differs
+	// from the chain. For example, it is unconfirmed or missing a block time.
+	IssueStale
+)
+
+func (t IssueType) String() string {
+	switch t {
+	case IssueMissing:
+		return "missing"
+	case IssueExtra:
+		return "extra"
+	case IssueStale:
+		return "stale"
+	default:
+		return "unknown"
+	}
+}
+
+// Issue is an inconsistency between history and the chain.
+type Issue struct {
+	Type   IssueType
+	Block  uint64
+	TxID   []byte
+	Reason string
+
+	// Repaired indicates whether or not the issue was repaired. Extra
+	// entries are never repaired, as they may belong to a fork that has
+	// yet to be resolved.
+	Repaired bool
+
+	// RepairErr is the error that occurred while repairing the issue, if any.
+	RepairErr error
+}
+
+func (i *Issue) String() string {
+	s := fmt.Sprintf("%s: block=%d tx=%s", i.Type, i.Block, base58.Encode(i.TxID))
+	if i.Reason != "" {
+		s += fmt.Sprintf(" (%s)", i.Reason)
+	}
+	if i.Repaired {
+		s += " [repaired]"
+	} else if i.RepairErr != nil {
+		s += fmt.Sprintf(" [repair failed: %v]", i.RepairErr)
+	}
+	return s
+}
+
+// Report is the result of a verification.
+type Report struct {
+	// Blocks is the number of blocks (or ledgers) that were verified.
+	Blocks int
+
+	// Entries is the number of entries found on chain.
+	Entries int
+
+	Issues []*Issue
+}
+
+// Verifier verifies the completeness of history against the chain.
+type Verifier struct {
+	log      *logrus.Entry
+	reader   history.Reader
+	repairer history.Repairer
+}
+
+// New returns a new Verifier.
+//
+// If repairer is non-nil, missing and stale entries are repaired by replacing
+// them with the entry loaded from the chain.
+func New(reader history.Reader, repairer history.Repairer) *Verifier {
+	return &Verifier{
+		log:      logrus.StandardLogger().WithField("type", "transaction/history/verify"),
+		reader:   reader,
+		repairer: repairer,
+	}
+}
+
+// VerifySolana verifies the history of the (inclusive) slot range [from, to].
+//
+// Since the ordering of Solana history is indexed by slot, entries that exist
+// in history but not on chain are also reported.
+func (v *Verifier) VerifySolana(ctx context.Context, client solana.Client, loader solanaingestor.BlockLoader, from, to uint64) (*Report, error) {
+	report := &Report{}
+
+	for start := from; start <= to; start += windowSize {
+		end := start + windowSize - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		if err := v.verifySolanaWindow(ctx, client, loader, start, end, report); err != nil {
+			return report, err
+		}
+
+		v.log.WithFields(logrus.Fields{
+			"start":  start,
+			"end":    end,
+			"issues": len(report.Issues),
+		}).Debug("verified window")
+
+		if end == to {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func (v *Verifier) verifySolanaWindow(ctx context.Context, client solana.Client, loader solanaingestor.BlockLoader, start, end uint64, report *Report) error {
+	slots, err := client.GetConfirmedBlocksWithLimit(start, end-start+1)
+	if err != nil {
+		return errors.Wrap(err, "failed to get confirmed blocks")
+	}
+
+	expected := make(map[string]*model.Entry)
+	for _, slot := range slots {
+		if slot > end {
+			break
+		}
+
+		entries, err := loader.LoadBlock(slot)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load block %d", slot)
+		}
+
+		report.Blocks++
+		report.Entries += len(entries)
+		for _, e := range entries {
+			txID, err := e.GetTxID()
+			if err != nil {
+				return errors.Wrap(err, "failed to get tx id")
+			}
+			expected[string(txID)] = e
+		}
+	}
+
+	actual, err := v.getTransactions(ctx, start, end)
+	if err != nil {
+		return err
+	}
+
+	var issues []*Issue
+	for id, e := range actual {
+		if _, ok := expected[id]; !ok {
+			issues = append(issues, &Issue{
+				Type:  IssueExtra,
+				Block: e.GetSolana().Slot,
+				TxID:  []byte(id),
+			})
+		}
+	}
+
+	for id, e := range expected {
+		stored, ok := actual[id]
+		if !ok {
+			// GetTransactions() only contains confirmed entries, so the entry
+			// may exist, but not yet be marked as confirmed.
+			stored, err = v.reader.GetTransaction(ctx, []byte(id))
+			if err != nil && err != history.ErrNotFound {
+				return errors.Wrap(err, "failed to get transaction")
+			}
+		}
+
+		if issue := compareSolana(e, stored); issue != nil {
+			issue.TxID = []byte(id)
+			v.repair(ctx, issue, e)
+			issues = append(issues, issue)
+		}
+	}
+
+	// Maps are unordered, so we sort the issues to keep reports stable.
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Block != issues[j].Block {
+			return issues[i].Block < issues[j].Block
+		}
+		return bytes.Compare(issues[i].TxID, issues[j].TxID) < 0
+	})
+	report.Issues = append(report.Issues, issues...)
+
+	return nil
+}
+
+// getTransactions returns the (confirmed) entries in [start, end], keyed
+// by transaction id.
+func (v *Verifier) getTransactions(ctx context.Context, start, end uint64) (map[string]*model.Entry, error) {
+	actual := make(map[string]*model.Entry)
+
+	limit := pageSize
+	for start <= end {
+		entries, err := v.reader.GetTransactions(ctx, start, end, limit)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transactions")
+		}
+
+		var added int
+		for _, e := range entries {
+			txID, err := e.GetTxID()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get tx id")
+			}
+
+			if _, ok := actual[string(txID)]; !ok {
+				actual[string(txID)] = e
+				added++
+			}
+		}
+
+		if len(entries) < limit {
+			break
+		}
+
+		// Pages start at a block, so a block with more entries than the
+		// limit requires a larger page to make progress.
+		if added == 0 {
+			limit *= 2
+			continue
+		}
+
+		start = entries[len(entries)-1].GetSolana().Slot
+		limit = pageSize
+	}
+
+	return actual, nil
+}
+
+func compareSolana(expected, stored *model.Entry) *Issue {
+	slot := expected.GetSolana().Slot
+	if stored == nil {
+		return &Issue{Type: IssueMissing, Block: slot}
+	}
+
+	s := stored.GetSolana()
+	switch {
+	case s == nil:
+		return &Issue{Type: IssueStale, Block: slot, Reason: "not a solana entry"}
+	case !s.Confirmed:
+		return &Issue{Type: IssueStale, Block: slot, Reason: "unconfirmed"}
+	case s.Slot != slot:
+		return &Issue{Type: IssueStale, Block: slot, Reason: fmt.Sprintf("slot mismatch (stored: %d)", s.Slot)}
+	case s.BlockTime == nil:
+		return &Issue{Type: IssueStale, Block: slot, Reason: "missing block time"}
+	case !bytes.Equal(s.TransactionError, expected.GetSolana().TransactionError):
+		return &Issue{Type: IssueStale, Block: slot, Reason: "transaction error mismatch"}
+	}
+
+	return nil
+}
+
+// VerifyStellar verifies the history of the (inclusive) ledger range [from, to].
+//
+// Stellar history is not indexed by ledger, so only missing and stale
+// entries are reported.
+func (v *Verifier) VerifyStellar(ctx context.Context, loader stellaringestor.LedgerLoader, from, to uint32) (*Report, error) {
+	report := &Report{}
+
+	for seq := from; seq <= to; seq++ {
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		default:
+		}
+
+		entries, err := loader.LoadLedger(seq)
+		if err != nil {
+			return report, errors.Wrapf(err, "failed to load ledger %d", seq)
+		}
+
+		report.Blocks++
+		report.Entries += len(entries)
+
+		for _, e := range entries {
+			txID, err := e.GetTxID()
+			if err != nil {
+				return report, errors.Wrap(err, "failed to get tx id")
+			}
+
+			stored, err := v.reader.GetTransaction(ctx, txID)
+			if err != nil && err != history.ErrNotFound {
+				return report, errors.Wrap(err, "failed to get transaction")
+			}
+
+			if issue := compareStellar(e, stored); issue != nil {
+				issue.TxID = txID
+				v.repair(ctx, issue, e)
+				report.Issues = append(report.Issues, issue)
+			}
+		}
+
+		// Guard against overflow when to is the maximum ledger.
+		if seq == to {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func compareStellar(expected, stored *model.Entry) *Issue {
+	ledger := expected.GetStellar().Ledger
+	if stored == nil {
+		return &Issue{Type: IssueMissing, Block: ledger}
+	}
+
+	s := stored.GetStellar()
+	switch {
+	case s == nil:
+		return &Issue{Type: IssueStale, Block: ledger, Reason: "not a stellar entry"}
+	case s.Ledger != ledger:
+		return &Issue{Type: IssueStale, Block: ledger, Reason: fmt.Sprintf("ledger mismatch (stored: %d)", s.Ledger)}
+	case s.PagingToken != expected.GetStellar().PagingToken:
+		return &Issue{Type: IssueStale, Block: ledger, Reason: "paging token mismatch"}
+	case s.LedgerCloseTime == nil:
+		return &Issue{Type: IssueStale, Block: ledger, Reason: "missing ledger close time"}
+	case !bytes.Equal(s.ResultXdr, expected.GetStellar().ResultXdr):
+		return &Issue{Type: IssueStale, Block: ledger, Reason: "result mismatch"}
+	}
+
+	return nil
+}
+
+func (v *Verifier) repair(ctx context.Context, issue *Issue, expected *model.Entry) {
+	if v.repairer == nil || issue.Type == IssueExtra {
+		return
+	}
+
+	if err := v.repairer.Repair(ctx, expected); err != nil {
+		v.log.WithError(err).WithField("tx", base58.Encode(issue.TxID)).Warn("failed to repair entry")
+		issue.RepairErr = err
+		return
+	}
+
+	issue.Repaired = true
+}
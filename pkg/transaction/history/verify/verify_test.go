@@ -0,0 +1,234 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+)
+
+type blockLoader map[uint64][]*model.Entry
+
+func (l blockLoader) LoadBlock(slot uint64) ([]*model.Entry, error) {
+	return l[slot], nil
+}
+
+type ledgerLoader map[uint32][]*model.Entry
+
+func (l ledgerLoader) LoadLedger(sequence uint32) ([]*model.Entry, error) {
+	return l[sequence], nil
+}
+
+func generateSolanaEntries(t *testing.T, slot uint64, n int) []*model.Entry {
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+
+	var entries []*model.Entry
+	for i := 0; i < n; i++ {
+		e, _ := historytestutil.GenerateSolanaEntry(t, slot, true, testutil.GenerateSolanaKeypair(t), receivers, nil, nil)
+		e.GetSolana().BlockTime = ptypes.TimestampNow()
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func TestVerifySolana(t *testing.T) {
+	rw := historymemory.New()
+	client := solana.NewMockClient()
+
+	loader := blockLoader{
+		1: generateSolanaEntries(t, 1, 2),
+		3: generateSolanaEntries(t, 3, 3),
+		4: generateSolanaEntries(t, 4, 1),
+	}
+	client.On("GetConfirmedBlocksWithLimit", uint64(1), uint64(5)).Return([]uint64{1, 3, 4}, nil)
+
+	// Block 1 is complete.
+	for _, e := range loader[1] {
+		require.NoError(t, rw.Write(context.Background(), e))
+	}
+
+	// Block 3 contains an unconfirmed entry, an entry without a block time,
+	// and a missing entry.
+	unconfirmed := proto.Clone(loader[3][0]).(*model.Entry)
+	unconfirmed.GetSolana().Confirmed = false
+	unconfirmed.GetSolana().BlockTime = nil
+	require.NoError(t, rw.Write(context.Background(), unconfirmed))
+
+	noBlockTime := proto.Clone(loader[3][1]).(*model.Entry)
+	noBlockTime.GetSolana().BlockTime = nil
+	require.NoError(t, rw.Write(context.Background(), noBlockTime))
+
+	// Block 4 is missing, and block 5 contains an entry not on chain.
+	extra := generateSolanaEntries(t, 5, 1)[0]
+	require.NoError(t, rw.Write(context.Background(), extra))
+
+	report, err := New(rw, nil).VerifySolana(context.Background(), client, loader, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blocks)
+	assert.Equal(t, 6, report.Entries)
+
+	type issue struct {
+		t      IssueType
+		block  uint64
+		entry  *model.Entry
+		reason string
+	}
+	expected := []issue{
+		{t: IssueStale, block: 3, entry: unconfirmed, reason: "unconfirmed"},
+		{t: IssueStale, block: 3, entry: noBlockTime, reason: "missing block time"},
+		{t: IssueMissing, block: 3, entry: loader[3][2]},
+		{t: IssueMissing, block: 4, entry: loader[4][0]},
+		{t: IssueExtra, block: 5, entry: extra},
+	}
+
+	require.Len(t, report.Issues, len(expected))
+	for _, e := range expected {
+		txID, err := e.entry.GetTxID()
+		require.NoError(t, err)
+
+		var found bool
+		for _, actual := range report.Issues {
+			if string(actual.TxID) != string(txID) {
+				continue
+			}
+
+			found = true
+			assert.Equal(t, e.t, actual.Type)
+			assert.Equal(t, e.block, actual.Block)
+			assert.Equal(t, e.reason, actual.Reason)
+			assert.False(t, actual.Repaired)
+		}
+		assert.True(t, found)
+	}
+
+	// Issues are ordered by block.
+	for i := 1; i < len(report.Issues); i++ {
+		assert.True(t, report.Issues[i-1].Block <= report.Issues[i].Block)
+	}
+
+	// Repairing should write the chain entries for all but the extra entry.
+	writes := len(rw.Writes)
+	report, err = New(rw, rw).VerifySolana(context.Background(), client, loader, 1, 5)
+	require.NoError(t, err)
+
+	var repaired int
+	for _, issue := range report.Issues {
+		if issue.Type == IssueExtra {
+			assert.False(t, issue.Repaired)
+			continue
+		}
+
+		assert.True(t, issue.Repaired)
+		assert.NoError(t, issue.RepairErr)
+		repaired++
+	}
+	assert.Equal(t, 4, repaired)
+	assert.Len(t, rw.Writes, writes+repaired)
+
+	for _, e := range []*model.Entry{loader[3][0], loader[3][1], loader[3][2], loader[4][0]} {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+		stored, err := rw.GetTransaction(context.Background(), txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(e, stored))
+	}
+
+	// Only the extra entry remains.
+	report, err = New(rw, nil).VerifySolana(context.Background(), client, loader, 1, 5)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, IssueExtra, report.Issues[0].Type)
+}
+
+func TestVerifySolana_Windows(t *testing.T) {
+	rw := historymemory.New()
+	client := solana.NewMockClient()
+
+	loader := blockLoader{
+		50:  generateSolanaEntries(t, 50, 1),
+		150: generateSolanaEntries(t, 150, 1),
+	}
+	client.On("GetConfirmedBlocksWithLimit", uint64(0), uint64(100)).Return([]uint64{50}, nil)
+	client.On("GetConfirmedBlocksWithLimit", uint64(100), uint64(51)).Return([]uint64{150}, nil)
+
+	for _, entries := range loader {
+		for _, e := range entries {
+			require.NoError(t, rw.Write(context.Background(), e))
+		}
+	}
+
+	report, err := New(rw, nil).VerifySolana(context.Background(), client, loader, 0, 150)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Blocks)
+	assert.Equal(t, 2, report.Entries)
+	assert.Empty(t, report.Issues)
+	client.AssertNumberOfCalls(t, "GetConfirmedBlocksWithLimit", 2)
+}
+
+func TestVerifyStellar(t *testing.T) {
+	rw := historymemory.New()
+
+	_, sender := testutil.GenerateAccountID(t)
+	receivers := testutil.GenerateAccountIDs(t, 1)
+
+	var entries []*model.Entry
+	for i := 0; i < 3; i++ {
+		e, _ := historytestutil.GenerateStellarEntry(t, 10, i, sender, receivers, nil, nil)
+		e.GetStellar().LedgerCloseTime = ptypes.TimestampNow()
+		entries = append(entries, e)
+	}
+	loader := ledgerLoader{10: entries}
+
+	require.NoError(t, rw.Write(context.Background(), entries[0]))
+
+	noCloseTime := proto.Clone(entries[1]).(*model.Entry)
+	noCloseTime.GetStellar().LedgerCloseTime = nil
+	require.NoError(t, rw.Write(context.Background(), noCloseTime))
+
+	report, err := New(rw, nil).VerifyStellar(context.Background(), loader, 9, 11)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blocks)
+	assert.Equal(t, 3, report.Entries)
+	require.Len(t, report.Issues, 2)
+
+	assert.Equal(t, IssueStale, report.Issues[0].Type)
+	assert.Equal(t, "missing ledger close time", report.Issues[0].Reason)
+	assert.Equal(t, IssueMissing, report.Issues[1].Type)
+	assert.EqualValues(t, 10, report.Issues[1].Block)
+
+	// Stellar entries cannot be replaced by a write, so the stale entry
+	// must be repaired.
+	assert.True(t, errors.Is(rw.Write(context.Background(), entries[1]), history.ErrInvalidUpdate))
+
+	report, err = New(rw, rw).VerifyStellar(context.Background(), loader, 10, 10)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	for _, issue := range report.Issues {
+		assert.True(t, issue.Repaired)
+		assert.NoError(t, issue.RepairErr)
+	}
+
+	for _, e := range entries[1:] {
+		txID, err := e.GetTxID()
+		require.NoError(t, err)
+		stored, err := rw.GetTransaction(context.Background(), txID)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(e, stored))
+	}
+
+	report, err = New(rw, nil).VerifyStellar(context.Background(), loader, 10, 10)
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+}
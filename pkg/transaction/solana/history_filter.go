@@ -0,0 +1,106 @@
+package solana
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historypb "github.com/kinecosystem/agora/pkg/transaction/history/proto"
+)
+
+// HistoryQueryHeader is the binary metadata key of a serialized
+// historypb.HistoryQuery, which restricts the history returned by GetHistory.
+//
+// The v4 GetHistoryRequest does not contain any query fields, so the query
+// is specified alongside it as request metadata.
+const HistoryQueryHeader = "agora-history-query-bin"
+
+// getHistoryQuery returns the read options specified by the history query
+// in the request metadata, if any.
+//
+// Only the time range and filter of the returned options are set.
+func getHistoryQuery(ctx context.Context) (*history.ReadOptions, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(HistoryQueryHeader)
+	if len(values) == 0 {
+		return nil, nil
+	} else if len(values) > 1 {
+		return nil, errors.New("multiple history queries specified")
+	}
+
+	var q historypb.HistoryQuery
+	if err := proto.Unmarshal([]byte(values[0]), &q); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal history query")
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	opts := &history.ReadOptions{}
+	if q.StartTime != nil {
+		if opts.StartTime, err = ptypes.Timestamp(q.StartTime); err != nil {
+			return nil, errors.Wrap(err, "invalid start time")
+		}
+	}
+	if q.EndTime != nil {
+		if opts.EndTime, err = ptypes.Timestamp(q.EndTime); err != nil {
+			return nil, errors.Wrap(err, "invalid end time")
+		}
+	}
+	if !opts.StartTime.IsZero() && !opts.EndTime.IsZero() && !opts.StartTime.Before(opts.EndTime) {
+		return nil, errors.New("start time must be before end time")
+	}
+
+	if q.Filter != nil {
+		if opts.Filter, err = newHistoryFilter(q.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+func newHistoryFilter(f *historypb.Filter) (*history.Filter, error) {
+	if f.MaxAmount > 0 && f.MinAmount > f.MaxAmount {
+		return nil, errors.New("min amount must be <= max amount")
+	}
+
+	filter := &history.Filter{
+		AppIndex:  uint16(f.AppIndex),
+		MinAmount: f.MinAmount,
+		MaxAmount: f.MaxAmount,
+	}
+
+	switch f.Direction {
+	case historypb.Filter_ANY:
+	case historypb.Filter_INCOMING:
+		filter.Direction = history.DirectionIncoming
+	case historypb.Filter_OUTGOING:
+		filter.Direction = history.DirectionOutgoing
+	default:
+		return nil, errors.Errorf("invalid direction: %s", f.Direction)
+	}
+
+	for _, t := range f.MemoTypes {
+		switch t {
+		case historypb.Filter_NONE:
+			filter.TransactionTypes = append(filter.TransactionTypes, kin.TransactionTypeNone)
+		case historypb.Filter_EARN:
+			filter.TransactionTypes = append(filter.TransactionTypes, kin.TransactionTypeEarn)
+		case historypb.Filter_SPEND:
+			filter.TransactionTypes = append(filter.TransactionTypes, kin.TransactionTypeSpend)
+		case historypb.Filter_P2P:
+			filter.TransactionTypes = append(filter.TransactionTypes, kin.TransactionTypeP2P)
+		default:
+			return nil, errors.Errorf("invalid memo type: %s", t)
+		}
+	}
+
+	return filter, nil
+}
@@ -32,6 +32,9 @@ import (
 	"github.com/kinecosystem/agora/pkg/version"
 )
 
+// historyPageSize is the number of history items returned per request.
+const historyPageSize = 100
+
 type loader struct {
 	log          *logrus.Entry
 	sc           solana.Client
@@ -210,8 +213,12 @@ func (l *loader) loadTransaction(ctx context.Context, id []byte) (*transactionpb
 	return resp, nil
 }
 
-func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transactionpb.Cursor, order transactionpb.GetHistoryRequest_Direction) ([]*transactionpb.HistoryItem, error) {
-	log := l.log.WithField("method", "getTransactions")
+// getItems returns the history items for the account.
+//
+// If specified, the time range and filter of query are applied to the
+// returned items. All other fields of query are ignored.
+func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transactionpb.Cursor, order transactionpb.GetHistoryRequest_Direction, query *history.ReadOptions) ([]*transactionpb.HistoryItem, error) {
+	log := l.log.WithField("method", "getItems")
 
 	// Unfortunately, we stored the account keys based on stellar strings, so we need
 	// to transform the accountID -> string representation.
@@ -222,13 +229,16 @@ func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transac
 	log = log.WithField("account", account)
 
 	opts := &history.ReadOptions{
-		Limit:      100,
+		Limit:      historyPageSize,
 		Descending: order == transactionpb.GetHistoryRequest_DESC,
+		StartTime:  query.GetStartTime(),
+		EndTime:    query.GetEndTime(),
 	}
 	if cursor != nil {
 		start, err := startFromCursor(ctx, cursor)
 		if err != nil {
 			log.WithError(err).Warn("failed to get start from cursor, ignoring")
+			cursor = nil
 		} else {
 			opts.Start = start
 		}
@@ -247,7 +257,20 @@ func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transac
 		}
 	}
 
-	entries, err := l.getEntriesForAccount(ctx, accountID, opts)
+	addresses, err := l.getAddresses(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payment filters are evaluated relative to the owner, as well as all of
+	// its token accounts.
+	if filter := query.GetFilter(); filter != nil {
+		copied := *filter
+		copied.Addresses = addresses
+		opts.Filter = &copied
+	}
+
+	entries, err := l.getEntriesForAddresses(ctx, addresses, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get account transactions")
 	}
@@ -255,26 +278,9 @@ func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transac
 		return nil, nil
 	}
 
-	// It is possible that there are no commits yet for a given blockchain version. If this is the case,
-	// Latest() will return nil, which will result in no history being returned. However, it is possible that
-	// there are entries for older blockchain versions, that _do_ have commits. In that case, we should be filtering
-	// based on those.
-	var highestVersion model.KinVersion
-	if opts.Descending {
-		highestVersion = entries[0].Version
-	} else {
-		highestVersion = entries[len(entries)-1].Version
-	}
-
-	var latestCommit ingestion.Pointer
-	for v := highestVersion; v >= model.KinVersion_KIN2; v-- {
-		latestCommit, err = l.committer.Latest(ctx, ingestion.GetHistoryIngestorName(v))
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get latest pointer for %s", v.String())
-		}
-		if latestCommit != nil {
-			break
-		}
+	latestCommit, err := l.getLatestCommit(ctx, entries, opts.Descending)
+	if err != nil {
+		return nil, err
 	}
 
 	commitCheckRequired := true
@@ -323,26 +329,61 @@ func (l *loader) getItems(ctx context.Context, accountID []byte, cursor *transac
 			continue
 		}
 
-		id, err := e.GetTxID()
+		item, err := l.getItem(ctx, e)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to get tx hash for entry")
+			return nil, err
 		}
+		items = append(items, item)
+	}
 
-		il, err := l.invoiceStore.Get(ctx, id)
-		if err != nil && err != invoice.ErrNotFound {
-			return nil, errors.Wrap(err, "failed to get invoice list for entry")
-		}
+	return items, nil
+}
+
+// getLatestCommit returns the latest commit for the highest version of the
+// (ordered) entries.
+func (l *loader) getLatestCommit(ctx context.Context, entries []*model.Entry, descending bool) (ingestion.Pointer, error) {
+	// It is possible that there are no commits yet for a given blockchain version. If this is the case,
+	// Latest() will return nil, which will result in no history being returned. However, it is possible that
+	// there are entries for older blockchain versions, that _do_ have commits. In that case, we should be filtering
+	// based on those.
+	var highestVersion model.KinVersion
+	if descending {
+		highestVersion = entries[0].Version
+	} else {
+		highestVersion = entries[len(entries)-1].Version
+	}
 
-		item, err := historyItemFromEntry(e)
+	for v := highestVersion; v >= model.KinVersion_KIN2; v-- {
+		latestCommit, err := l.committer.Latest(ctx, ingestion.GetHistoryIngestorName(v))
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to get history item from entry")
+			return nil, errors.Wrapf(err, "failed to get latest pointer for %s", v.String())
 		}
-		item.InvoiceList = il
+		if latestCommit != nil {
+			return latestCommit, nil
+		}
+	}
 
-		items = append(items, item)
+	return nil, nil
+}
+
+func (l *loader) getItem(ctx context.Context, e *model.Entry) (*transactionpb.HistoryItem, error) {
+	id, err := e.GetTxID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tx hash for entry")
 	}
 
-	return items, nil
+	il, err := l.invoiceStore.Get(ctx, id)
+	if err != nil && err != invoice.ErrNotFound {
+		return nil, errors.Wrap(err, "failed to get invoice list for entry")
+	}
+
+	item, err := historyItemFromEntry(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get history item from entry")
+	}
+	item.InvoiceList = il
+
+	return item, nil
 }
 
 func (l *loader) getStellarResponse(ctx context.Context, id []byte, invoiceList *commonpbv3.InvoiceList) (*transactionpb.GetTransactionResponse, error) {
@@ -371,12 +412,25 @@ func (l *loader) getStellarResponse(ctx context.Context, id []byte, invoiceList
 }
 
 func (l *loader) getEntriesForAccount(ctx context.Context, owner []byte, opts *history.ReadOptions) ([]*model.Entry, error) {
+	addresses, err := l.getAddresses(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.getEntriesForAddresses(ctx, addresses, opts)
+}
+
+// getAddresses returns the owner, and the token accounts it owns.
+func (l *loader) getAddresses(owner []byte) ([]ed25519.PublicKey, error) {
 	addresses, err := l.sc.GetTokenAccountsByOwner(owner, l.token)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to resolve token accounts")
 	}
-	addresses = append(addresses, owner)
 
+	return append(addresses, owner), nil
+}
+
+func (l *loader) getEntriesForAddresses(ctx context.Context, addresses []ed25519.PublicKey, opts *history.ReadOptions) ([]*model.Entry, error) {
 	var mu sync.Mutex
 	var entries []*model.Entry
 
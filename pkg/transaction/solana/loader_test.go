@@ -309,18 +309,18 @@ func TestGetItems_Query(t *testing.T) {
 	}
 
 	// Request history from beginning, but without any committed entries.
-	items, err := env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC)
+	items, err := env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC, nil)
 	assert.NoError(t, err)
 	assert.Empty(t, items)
 
 	// Advance to the 5th entry
 	require.NoError(t, env.committer.Commit(context.Background(), ingestion.GetHistoryIngestorName(model.KinVersion_KIN3), nil, historytestutil.GetOrderingKey(t, generated[4])))
-	items, err = env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC)
+	items, err = env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC, nil)
 	assert.NoError(t, err)
 	assert.Len(t, items, 5)
 
 	// Request in reverse order to ensure we're trimming correctly.
-	items, err = env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_DESC)
+	items, err = env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_DESC, nil)
 	assert.NoError(t, err)
 	assert.Len(t, items, 5)
 
@@ -376,7 +376,7 @@ func TestGetItems_Query(t *testing.T) {
 			}
 		}
 
-		items, err = env.loader.getItems(context.Background(), senderPubKey[:], cursor, tc.direction)
+		items, err = env.loader.getItems(context.Background(), senderPubKey[:], cursor, tc.direction, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, len(tc.expected), len(items), "case: %d", i)
 
@@ -453,7 +453,7 @@ func TestGetItems_Invoices(t *testing.T) {
 	latest := historytestutil.GetOrderingKey(t, generated[len(generated)-1])
 	require.NoError(t, env.committer.Commit(context.Background(), ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), nil, latest))
 
-	entries, err := env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC)
+	entries, err := env.loader.getItems(context.Background(), senderPubKey[:], nil, transactionpb.GetHistoryRequest_ASC, nil)
 	assert.NoError(t, err)
 	assert.Len(t, entries, 40)
 
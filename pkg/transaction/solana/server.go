@@ -14,10 +14,10 @@ import (
 	"github.com/kinecosystem/agora-common/solana/memo"
 	"github.com/kinecosystem/agora-common/solana/token"
 	"github.com/kinecosystem/go/clients/horizon"
-	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -26,14 +26,17 @@ import (
 	transactionpb "github.com/kinecosystem/agora-api/genproto/transaction/v4"
 
 	"github.com/kinecosystem/agora/pkg/account/solana/accountinfo"
+	"github.com/kinecosystem/agora/pkg/analytics"
 	"github.com/kinecosystem/agora/pkg/invoice"
 	"github.com/kinecosystem/agora/pkg/migration"
 	"github.com/kinecosystem/agora/pkg/solanautil"
+	"github.com/kinecosystem/agora/pkg/tracing"
 	"github.com/kinecosystem/agora/pkg/transaction"
 	"github.com/kinecosystem/agora/pkg/transaction/dedupe"
 	"github.com/kinecosystem/agora/pkg/transaction/history"
 	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
 	"github.com/kinecosystem/agora/pkg/version"
 	"github.com/kinecosystem/agora/pkg/webhook/events"
 	"github.com/kinecosystem/agora/pkg/webhook/signtransaction"
@@ -66,11 +69,6 @@ var (
 		Namespace: "agora",
 		Name:      "submit_transactions_cancelled",
 	})
-	transferByDest = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Namespace: "agora",
-		Name:      "transfer_by_dest",
-		Help:      "Number of transfers by destination (whitelisted)",
-	}, []string{"dest"})
 	dedupesByType = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "agora",
 		Name:      "transfer_dedupes",
@@ -83,17 +81,6 @@ var (
 	}, []string{"op"})
 )
 
-var destWhitelist = map[string]struct{}{
-	"Fapzahf3E91zAvr1yvNw3mYLv7t2DR6EnT8xjY74fT7C": {}, // Rave
-	"4bRrapNAChmZMLugZacanKfqW5KyHygcWEReBVNiMTUU": {}, // Rave (owner)
-	"CncYnFygz323VNY6okoiv6ycByLumgHzXSBFzXDDFNEZ": {}, // Peerbet
-	"BUS5SyrVLhgakivdRcmZE5F69HdR8xJBjHTiF2mqdKpt": {}, // Peerbet (owner)
-	"2K8XpTqVAheX9cF2niwkTQQBajEwr84TeP34wiYUCoLy": {}, // PauseFor
-	"3rad7aFPdJS3CkYPSphtDAWCNB8BYpV2yc7o5ZjFQbDb": {}, // PauseFor (owner)
-	"7cqCpmzfZphbhzctXLJVabxQecFtYp6Bg4vQXo11SiNM": {}, // Poppin
-	"ejsuFLdZo3YBu4qeuSw9ozbFPwPaUd3Xc2PPuDRpPdS":  {}, // Poppin (owner)
-}
-
 type server struct {
 	log             *logrus.Entry
 	sc              solana.Client
@@ -107,6 +94,8 @@ type server struct {
 	infoCache       accountinfo.Cache
 	eventsSubmitter events.Submitter
 	deduper         dedupe.Deduper
+	flagStore       velocity.FlagStore
+	tracker         *analytics.Tracker
 
 	token      ed25519.PublicKey
 	subsidizer ed25519.PrivateKey
@@ -129,6 +118,8 @@ func New(
 	infoCache accountinfo.Cache,
 	eventsSubmitter events.Submitter,
 	deduper dedupe.Deduper,
+	flagStore velocity.FlagStore,
+	tracker *analytics.Tracker,
 	tokenAccount ed25519.PublicKey,
 	subsidizer ed25519.PrivateKey,
 	hc horizon.ClientInterface,
@@ -152,6 +143,8 @@ func New(
 		infoCache:       infoCache,
 		eventsSubmitter: eventsSubmitter,
 		deduper:         deduper,
+		flagStore:       flagStore,
+		tracker:         tracker,
 		token:           tokenAccount,
 		subsidizer:      subsidizer,
 		hc:              hc,
@@ -250,7 +243,19 @@ func (s *server) GetMinimumBalanceForRentExemption(_ context.Context, req *trans
 // SubmitTransaction submits a transaction.
 //
 // See: https://github.com/kinecosystem/agora-api/blob/master/spec/memo.md
-func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.SubmitTransactionRequest) (*transactionpb.SubmitTransactionResponse, error) {
+func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.SubmitTransactionRequest) (resp *transactionpb.SubmitTransactionResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "TransactionServer/SubmitTransaction")
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.String("result", resp.Result.String()))
+		}
+		tracing.EndSpan(span, err)
+	}()
+
+	return s.submitTransaction(ctx, req)
+}
+
+func (s *server) submitTransaction(ctx context.Context, req *transactionpb.SubmitTransactionRequest) (*transactionpb.SubmitTransactionResponse, error) {
 	log := s.log.WithField("method", "SubmitTransaction")
 
 	submitTxCounter.Inc()
@@ -284,12 +289,6 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 
 		transferStates[string(transfers[0].Source)] -= int64(transfers[0].Amount)
 		transferStates[string(transfers[0].Destination)] += int64(transfers[0].Amount)
-
-		// note: this really should be 'unique', but let's go by transfer for now.
-		destKey := base58.Encode(transfers[0].Destination)
-		if _, ok := destWhitelist[destKey]; ok {
-			transferByDest.WithLabelValues(destKey).Inc()
-		}
 	default:
 		var offset int
 		if m, err := memo.DecompileMemo(txn.Message, 0); err == nil {
@@ -307,12 +306,6 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 
 			transferStates[string(transfers[i].Source)] -= int64(transfers[i].Amount)
 			transferStates[string(transfers[i].Destination)] += int64(transfers[i].Amount)
-
-			// note: this really should be 'unique', but let's go by transfer for now.
-			destKey := base58.Encode(transfers[i].Destination)
-			if _, ok := destWhitelist[destKey]; ok {
-				transferByDest.WithLabelValues(destKey).Inc()
-			}
 		}
 
 		if req.InvoiceList != nil && len(req.InvoiceList.Invoices) != len(transfers) {
@@ -358,6 +351,14 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 		InvoiceList: req.InvoiceList,
 		OpCount:     len(transfers),
 		SignRequest: nil,
+		Transfers:   make([]velocity.Transfer, len(transfers)),
+	}
+	for i, t := range transfers {
+		tx.Transfers[i] = velocity.Transfer{
+			Owner:       t.Owner,
+			Destination: t.Destination,
+			Amount:      t.Amount,
+		}
 	}
 	tx.SignRequest, err = signtransaction.CreateSolanaRequest(txn, req.InvoiceList)
 	if err != nil {
@@ -391,7 +392,9 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 		Signature:      txn.Signature(),
 		SubmissionTime: time.Now(),
 	}
-	prev, err := s.deduper.Dedupe(ctx, req.DedupeId, dedupeInfo)
+	dedupeCtx, dedupeSpan := tracing.StartSpan(ctx, "dedupe.Deduper/Dedupe")
+	prev, err := s.deduper.Dedupe(dedupeCtx, req.DedupeId, dedupeInfo)
+	tracing.EndSpan(dedupeSpan, err)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to check deduper")
 	}
@@ -428,7 +431,10 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 			return
 		}
 
-		if err := s.deduper.Delete(context.Background(), req.DedupeId); err != nil {
+		deleteCtx, deleteSpan := tracing.StartSpan(tracing.Detach(ctx), "dedupe.Deduper/Delete")
+		err := s.deduper.Delete(deleteCtx, req.DedupeId)
+		tracing.EndSpan(deleteSpan, err)
+		if err != nil {
 			dedupeTransitionFailures.WithLabelValues("delete").Inc()
 			log.WithError(err).
 				WithField("id", base64.StdEncoding.EncodeToString(req.DedupeId)).
@@ -468,18 +474,45 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 		return nil, status.Error(codes.Internal, "unhandled authorization error")
 	}
 
+	// Any state recorded during authorization is rolled back unless the
+	// transaction is submitted, so that failed submissions do not count
+	// towards velocity thresholds.
+	var submitted bool
+	defer func() {
+		if submitted {
+			return
+		}
+
+		if err := result.Rollback(tracing.Detach(ctx)); err != nil {
+			log.WithError(err).Warn("failed to rollback authorization")
+		}
+	}()
+
 	//
 	// Submit and record.
 	//
 	if tx.InvoiceList != nil {
 		// todo: do we want to perform garbage collection for failed transactions that are not in the record?
 		log.WithField("tx", base64.StdEncoding.EncodeToString(tx.ID)).Info("Storing invoice")
-		if err := s.invoiceStore.Put(ctx, tx.ID, tx.InvoiceList); err != nil && err != invoice.ErrExists {
+		invoiceCtx, invoiceSpan := tracing.StartSpan(ctx, "invoice.Store/Put")
+		err := s.invoiceStore.Put(invoiceCtx, tx.ID, tx.InvoiceList)
+		if err == invoice.ErrExists {
+			err = nil
+		}
+		tracing.EndSpan(invoiceSpan, err)
+		if err != nil {
 			log.WithError(err).Warn("failed to store invoice list")
 			return nil, status.Errorf(codes.Internal, "failed to store invoice list")
 		}
 	}
 
+	if len(result.Flags) > 0 && s.flagStore != nil {
+		if err := s.flagStore.Put(ctx, tx.ID, result.Flags); err != nil {
+			log.WithError(err).Warn("failed to store velocity flags")
+			return nil, status.Errorf(codes.Internal, "failed to store velocity flags")
+		}
+	}
+
 	// Instead of directly invalidating, we update it to the predicted amount.
 	speculativeStates := s.speculativeLoad(ctx, transferStates, solanautil.CommitmentFromProto(req.Commitment))
 
@@ -491,7 +524,7 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 	}
 
 	var submitResult transactionpb.SubmitTransactionResponse_Result
-	sig, stat, err := s.scSubmit.SubmitTransaction(txn, solanautil.CommitmentFromProto(req.Commitment))
+	sig, stat, err := tracing.SolanaClientWithContext(ctx, s.scSubmit).SubmitTransaction(txn, solanautil.CommitmentFromProto(req.Commitment))
 	if err != nil {
 		log.WithError(err).Warn("unhandled SubmitTransaction")
 		return nil, status.Errorf(codes.Internal, "unhandled error from SubmitTransaction: %v", err)
@@ -500,6 +533,7 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 		// If it's a duplicate signature, we still want to process the Write()
 		// in case that's what failed on an earlier call.
 		if solanautil.IsDuplicateSignature(stat.ErrorResult) {
+			submitted = true
 			submitResult = transactionpb.SubmitTransactionResponse_ALREADY_SUBMITTED
 		} else {
 			// todo: do we want to persist failed transactions at this stage?
@@ -532,11 +566,13 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 			}
 			return resp, nil
 		}
+	} else {
+		submitted = true
 	}
 
 	// We fork the context here, because we want to take action based on the result
 	// of the transaction regardless if the client has cancelled the request.
-	forkedCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	forkedCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 10*time.Second)
 	defer cancel()
 
 	entry := &model.Entry{
@@ -551,9 +587,24 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 
 	if submitResult == transactionpb.SubmitTransactionResponse_OK {
 		s.speculativeWrite(forkedCtx, speculativeStates)
+
+		if s.tracker != nil {
+			tracked := make([]analytics.Transfer, len(transfers))
+			for i, t := range transfers {
+				tracked[i] = analytics.Transfer{
+					Destination: t.Destination,
+					Amount:      t.Amount,
+				}
+			}
+
+			s.tracker.TrackAsync(tracked)
+		}
 	}
 
-	if err := s.history.Write(forkedCtx, entry); err != nil {
+	historyCtx, historySpan := tracing.StartSpan(forkedCtx, "history.Writer/Write")
+	err = s.history.Write(historyCtx, entry)
+	tracing.EndSpan(historySpan, err)
+	if err != nil {
 		// If we're processing an ALREADY_SUBMITTED, then it's possible we've
 		// also already written the entry to history. In this case, we may
 		// receive a history.ErrInvalidUpdate, since we don't have any slot
@@ -583,7 +634,10 @@ func (s *server) SubmitTransaction(ctx context.Context, req *transactionpb.Submi
 	// Since we have a 'success' response, we do not want to clear the dedupe info.
 	noClearDedupe = true
 	dedupeInfo.Response = resp
-	if err := s.deduper.Update(forkedCtx, req.DedupeId, dedupeInfo); err != nil {
+	updateCtx, updateSpan := tracing.StartSpan(forkedCtx, "dedupe.Deduper/Update")
+	err = s.deduper.Update(updateCtx, req.DedupeId, dedupeInfo)
+	tracing.EndSpan(updateSpan, err)
+	if err != nil {
 		dedupeTransitionFailures.WithLabelValues("update").Inc()
 		log.WithError(err).Warn("failed to update dedupe info")
 	}
@@ -623,8 +677,15 @@ func (s *server) GetHistory(ctx context.Context, req *transactionpb.GetHistoryRe
 		return nil, status.Errorf(codes.Internal, "failed to migrate account: %v", err)
 	}
 
-	items, err := s.loader.getItems(ctx, req.AccountId.Value, req.Cursor, req.Direction)
+	query, err := getHistoryQuery(ctx)
 	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid history query: %v", err)
+	}
+
+	items, err := s.loader.getItems(ctx, req.AccountId.Value, req.Cursor, req.Direction, query)
+	if errors.Cause(err) == history.ErrFilterScanExceeded {
+		return nil, status.Error(codes.ResourceExhausted, "history filter is too selective, narrow the query with a time range")
+	} else if err != nil {
 		log.WithError(err).Warn("failed to get history transactions")
 		return nil, status.Error(codes.Internal, "failed to get transactions")
 	}
@@ -787,13 +848,6 @@ func init() {
 			logrus.WithError(err).Error("failed to register submitTransactionsCancelled")
 		}
 	}
-	if err := prometheus.Register(transferByDest); err != nil {
-		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
-			transferByDest = e.ExistingCollector.(*prometheus.CounterVec)
-		} else {
-			logrus.WithError(err).Error("failed to register transferByDest")
-		}
-	}
 	if err := prometheus.Register(dedupesByType); err != nil {
 		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
 			dedupesByType = e.ExistingCollector.(*prometheus.CounterVec)
@@ -5,11 +5,14 @@ import (
 	"context"
 	"crypto/ed25519"
 	"encoding/base64"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/kinecosystem/agora-common/headers"
 	"github.com/kinecosystem/agora-common/kin"
 	"github.com/kinecosystem/agora-common/solana"
@@ -32,21 +35,32 @@ import (
 	commonpb "github.com/kinecosystem/agora-api/genproto/common/v4"
 	transactionpb "github.com/kinecosystem/agora-api/genproto/transaction/v4"
 
+	mappermemory "github.com/kinecosystem/agora/pkg/account/memory"
 	"github.com/kinecosystem/agora/pkg/account/solana/accountinfo"
 	infomemory "github.com/kinecosystem/agora/pkg/account/solana/accountinfo/memory"
+	"github.com/kinecosystem/agora/pkg/analytics"
+	analyticsmemory "github.com/kinecosystem/agora/pkg/analytics/memory"
+	appconfigdb "github.com/kinecosystem/agora/pkg/app/memory"
+	appmapper "github.com/kinecosystem/agora/pkg/app/memory/mapper"
 	"github.com/kinecosystem/agora/pkg/invoice"
 	invoicedb "github.com/kinecosystem/agora/pkg/invoice/memory"
 	"github.com/kinecosystem/agora/pkg/migration"
+	"github.com/kinecosystem/agora/pkg/rate"
 	"github.com/kinecosystem/agora/pkg/testutil"
 	"github.com/kinecosystem/agora/pkg/transaction"
 	"github.com/kinecosystem/agora/pkg/transaction/dedupe"
 	dedupememory "github.com/kinecosystem/agora/pkg/transaction/dedupe/memory"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
 	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 	ingestionmemory "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/memory"
 	historymemory "github.com/kinecosystem/agora/pkg/transaction/history/memory"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
 	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+	historypb "github.com/kinecosystem/agora/pkg/transaction/history/proto"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+	velocitymemory "github.com/kinecosystem/agora/pkg/transaction/velocity/memory"
 	"github.com/kinecosystem/agora/pkg/version"
+	"github.com/kinecosystem/agora/pkg/webhook"
 )
 
 type serverEnv struct {
@@ -63,6 +77,8 @@ type serverEnv struct {
 	infoCache    accountinfo.Cache
 	submitter    *mockSubmitter
 	deduper      dedupe.Deduper
+	flagStore    velocity.FlagStore
+	destStore    analytics.Store
 
 	hClient *horizon.MockClient
 }
@@ -76,6 +92,20 @@ func (m *mockAuthorizer) Authorize(ctx context.Context, txn transaction.Transact
 	return args.Get(0).(transaction.Authorization), args.Error(1)
 }
 
+type mockVelocityChecker struct {
+	mock.Mock
+}
+
+func (m *mockVelocityChecker) Check(ctx context.Context, appIndex uint16, txID []byte, transfers []velocity.Transfer) (velocity.Result, error) {
+	args := m.Called(ctx, appIndex, txID, transfers)
+	return args.Get(0).(velocity.Result), args.Error(1)
+}
+
+func (m *mockVelocityChecker) Rollback(ctx context.Context, result velocity.Result) error {
+	args := m.Called(ctx, result)
+	return args.Error(0)
+}
+
 type mockSubmitter struct {
 	mock.Mock
 }
@@ -138,6 +168,8 @@ func setupServerEnv(t *testing.T) (env serverEnv, cleanup func()) {
 	require.NoError(t, err)
 	env.submitter = &mockSubmitter{}
 	env.deduper = dedupememory.New()
+	env.flagStore = velocitymemory.NewFlagStore()
+	env.destStore = analyticsmemory.New()
 
 	env.subsidizer = testutil.GenerateSolanaKeypair(t)
 	token := testutil.GenerateSolanaKeypair(t)
@@ -158,6 +190,8 @@ func setupServerEnv(t *testing.T) (env serverEnv, cleanup func()) {
 		env.infoCache,
 		env.submitter,
 		env.deduper,
+		env.flagStore,
+		analytics.NewTracker(env.destStore, mappermemory.New(), 0),
 		env.token,
 		env.subsidizer,
 		env.hClient,
@@ -354,6 +388,252 @@ func TestGetHistory(t *testing.T) {
 	}
 }
 
+func TestGetHistory_Filter(t *testing.T) {
+	env, cleanup := setupServerEnv(t)
+	defer cleanup()
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	senderTokenAccount := testutil.GenerateSolanaKeys(t, 1)[0]
+
+	env.sc.On("GetTokenAccountsByOwner", sender.Public().(ed25519.PublicKey), env.token).Return([]ed25519.PublicKey{senderTokenAccount}, nil)
+	env.sc.On("GetTokenAccountsByOwner", receivers[0], env.token).Return([]ed25519.PublicKey{}, nil)
+
+	// Every even entry contains a (spend) memo for app index 1.
+	//
+	// Entry i transfers i+2 quarks from sender to the receiver.
+	entries := make([]*model.Entry, 10)
+	for i := 0; i < len(entries); i++ {
+		var invoiceHash []byte
+		if i%2 == 0 {
+			invoiceHash = make([]byte, 28)
+		}
+
+		entries[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, invoiceHash, nil)
+		require.NoError(t, env.rw.Write(context.Background(), entries[i]))
+	}
+
+	latest := historytestutil.GetOrderingKey(t, entries[len(entries)-1])
+	require.NoError(t, env.committer.Commit(context.Background(), ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), nil, latest))
+
+	assertItems := func(t *testing.T, expected []int, items []*transactionpb.HistoryItem) {
+		require.Len(t, items, len(expected))
+		for i, e := range expected {
+			assert.Equal(t, historytestutil.GetOrderingKey(t, entries[e]), items[i].Cursor.Value, "expected: %d, index: %d", e, i)
+		}
+	}
+
+	var evens []int
+	for i := 0; i < len(entries); i += 2 {
+		evens = append(evens, i)
+	}
+
+	req := &transactionpb.GetHistoryRequest{
+		AccountId: &common.SolanaAccountId{
+			Value: sender.Public().(ed25519.PublicKey),
+		},
+	}
+	query := &historypb.HistoryQuery{
+		Filter: &historypb.Filter{},
+	}
+
+	// An empty filter should match everything
+	resp, err := getHistory(env, req, query)
+	require.NoError(t, err)
+	assert.Equal(t, transactionpb.GetHistoryResponse_OK, resp.Result)
+	assert.Len(t, resp.Items, 10)
+
+	for _, f := range []*historypb.Filter{
+		{AppIndex: 1},
+		{MemoTypes: []historypb.Filter_MemoType{historypb.Filter_SPEND}},
+		{AppIndex: 1, MemoTypes: []historypb.Filter_MemoType{historypb.Filter_EARN, historypb.Filter_SPEND}},
+	} {
+		query.Filter = f
+		resp, err = getHistory(env, req, query)
+		require.NoError(t, err)
+		assertItems(t, evens, resp.Items)
+	}
+
+	for _, f := range []*historypb.Filter{
+		{AppIndex: 2},
+		{MemoTypes: []historypb.Filter_MemoType{historypb.Filter_EARN}},
+		{Direction: historypb.Filter_INCOMING},
+	} {
+		query.Filter = f
+		resp, err = getHistory(env, req, query)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Items)
+	}
+
+	// Payments are outgoing if they are authorized by the owner.
+	query.Filter = &historypb.Filter{Direction: historypb.Filter_OUTGOING}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 10)
+
+	// Amounts
+	query.Filter = &historypb.Filter{MinAmount: 8}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assertItems(t, []int{6, 7, 8, 9}, resp.Items)
+
+	query.Filter = &historypb.Filter{MaxAmount: 4}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assertItems(t, []int{0, 1, 2}, resp.Items)
+
+	query.Filter = &historypb.Filter{MinAmount: 5, MaxAmount: 7, Direction: historypb.Filter_OUTGOING}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assertItems(t, []int{3, 4, 5}, resp.Items)
+
+	// The cursor of the last item in a filtered page can be used to resume.
+	query.Filter = &historypb.Filter{AppIndex: 1}
+	req.Cursor = &transactionpb.Cursor{Value: historytestutil.GetOrderingKey(t, entries[2])}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assertItems(t, []int{4, 6, 8}, resp.Items)
+
+	req.Direction = transactionpb.GetHistoryRequest_DESC
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assertItems(t, []int{0}, resp.Items)
+	req.Cursor = nil
+	req.Direction = transactionpb.GetHistoryRequest_ASC
+
+	// Direction is relative to the receiver as well.
+	req.AccountId.Value = receivers[0]
+	query.Filter = &historypb.Filter{Direction: historypb.Filter_INCOMING}
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 10)
+
+	query.Filter = &historypb.Filter{MinAmount: 10, MaxAmount: 5}
+	_, err = getHistory(env, req, query)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+type scanLimitedRW struct {
+	history.ReaderWriter
+}
+
+func (rw *scanLimitedRW) GetAccountTransactions(ctx context.Context, account string, opts *history.ReadOptions) ([]*model.Entry, error) {
+	if opts.GetFilter() != nil {
+		return nil, history.ErrFilterScanExceeded
+	}
+
+	return rw.ReaderWriter.GetAccountTransactions(ctx, account, opts)
+}
+
+func TestGetHistory_FilterScanLimit(t *testing.T) {
+	env, cleanup := setupServerEnv(t)
+	defer cleanup()
+
+	env.server.loader.rw = &scanLimitedRW{ReaderWriter: env.rw}
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+
+	env.sc.On("GetTokenAccountsByOwner", mock.Anything, env.token).Return([]ed25519.PublicKey{}, nil)
+
+	entry, _ := historytestutil.GenerateSolanaEntry(t, 1, true, sender, receivers, nil, nil)
+	require.NoError(t, env.rw.Write(context.Background(), entry))
+	require.NoError(t, env.committer.Commit(context.Background(), ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), nil, historytestutil.GetOrderingKey(t, entry)))
+
+	req := &transactionpb.GetHistoryRequest{
+		AccountId: &common.SolanaAccountId{
+			Value: sender.Public().(ed25519.PublicKey),
+		},
+	}
+
+	// Rather than returning an empty page, filters that exceed the scan
+	// limit of the store fail.
+	_, err := getHistory(env, req, &historypb.HistoryQuery{Filter: &historypb.Filter{AppIndex: 1}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	resp, err := getHistory(env, req, nil)
+	require.NoError(t, err)
+	assert.Len(t, resp.Items, 1)
+}
+
+func TestGetHistory_TimeRange(t *testing.T) {
+	env, cleanup := setupServerEnv(t)
+	defer cleanup()
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+
+	env.sc.On("GetTokenAccountsByOwner", mock.Anything, env.token).Return([]ed25519.PublicKey{}, nil)
+
+	// Entry i has a block time of base + i minutes.
+	base := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]*model.Entry, 10)
+	for i := 0; i < len(entries); i++ {
+		entries[i], _ = historytestutil.GenerateSolanaEntry(t, uint64(i+1), true, sender, receivers, nil, nil)
+
+		blockTime, err := ptypes.TimestampProto(base.Add(time.Duration(i) * time.Minute))
+		require.NoError(t, err)
+		entries[i].GetSolana().BlockTime = blockTime
+		require.NoError(t, env.rw.Write(context.Background(), entries[i]))
+	}
+
+	latest := historytestutil.GetOrderingKey(t, entries[len(entries)-1])
+	require.NoError(t, env.committer.Commit(context.Background(), ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), nil, latest))
+
+	at := func(offset time.Duration) *timestamp.Timestamp {
+		ts, err := ptypes.TimestampProto(base.Add(offset))
+		require.NoError(t, err)
+		return ts
+	}
+
+	req := &transactionpb.GetHistoryRequest{
+		AccountId: &common.SolanaAccountId{
+			Value: sender.Public().(ed25519.PublicKey),
+		},
+	}
+	query := &historypb.HistoryQuery{
+		StartTime: at(3 * time.Minute),
+		EndTime:   at(6 * time.Minute),
+	}
+
+	resp, err := getHistory(env, req, query)
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 3)
+	for i, item := range resp.Items {
+		assert.Equal(t, historytestutil.GetOrderingKey(t, entries[i+3]), item.Cursor.Value)
+	}
+
+	req.Direction = transactionpb.GetHistoryRequest_DESC
+	query.StartTime = nil
+	query.EndTime = at(2 * time.Minute)
+	resp, err = getHistory(env, req, query)
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 2)
+	for i, item := range resp.Items {
+		assert.Equal(t, historytestutil.GetOrderingKey(t, entries[1-i]), item.Cursor.Value)
+	}
+
+	query.StartTime = at(2 * time.Minute)
+	_, err = getHistory(env, req, query)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// getHistory calls GetHistory with the (optional) query in the request
+// metadata.
+func getHistory(env serverEnv, req *transactionpb.GetHistoryRequest, query *historypb.HistoryQuery) (*transactionpb.GetHistoryResponse, error) {
+	ctx := context.Background()
+	if query != nil {
+		b, err := proto.Marshal(query)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, HistoryQueryHeader, string(b))
+	}
+
+	return env.client.GetHistory(ctx, req)
+}
+
 func TestSubmitTransaction_Plain(t *testing.T) {
 	env, cleanup := setupServerEnv(t)
 	defer cleanup()
@@ -434,6 +714,9 @@ func TestSubmitTransaction_Plain(t *testing.T) {
 	assert.Nil(t, authTx.InvoiceList)
 	assert.Nil(t, authTx.Memo.Memo)
 	assert.Nil(t, authTx.Memo.Text)
+	assert.Equal(t, []velocity.Transfer{
+		{Owner: accounts[0], Destination: accounts[1], Amount: 1},
+	}, authTx.Transfers)
 
 	assert.NoError(t, txn.Sign(env.subsidizer))
 
@@ -442,6 +725,11 @@ func TestSubmitTransaction_Plain(t *testing.T) {
 	assert.Nil(t, authTx.SignRequest.InvoiceList)
 	assert.Equal(t, txn.Marshal(), authTx.SignRequest.SolanaTransaction)
 
+	// No flags were raised, so none should be stored.
+	flags, err := env.flagStore.Get(context.Background(), sig[:])
+	assert.NoError(t, err)
+	assert.Nil(t, flags)
+
 	// Ensure speculative updates is working
 	for i, a := range accounts {
 		info, err := env.infoCache.Get(context.Background(), a)
@@ -459,6 +747,71 @@ func TestSubmitTransaction_Plain(t *testing.T) {
 	assert.EqualValues(t, txn.Marshal(), submittedEntry.GetSolana().Transaction)
 }
 
+func TestSubmitTransaction_TrackedDestination(t *testing.T) {
+	env, cleanup := setupServerEnv(t)
+	defer cleanup()
+
+	txn, accounts := generateTransaction(t, env.subsidizer.Public().(ed25519.PublicKey), 3, nil, nil)
+	require.NoError(t, env.destStore.Add(context.Background(), accounts[2], "partner"))
+
+	env.authorizer.On("Authorize", mock.Anything, mock.Anything).Return(transaction.Authorization{}, nil)
+	env.submitter.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	env.sc.On("GetAccountInfo", mock.Anything, mock.Anything).Return(solana.AccountInfo{}, nil)
+
+	var sig solana.Signature
+	copy(sig[:], ed25519.Sign(env.subsidizer, txn.Message.Marshal()))
+	env.sc.On("SubmitTransaction", mock.Anything, solana.CommitmentRoot).Return(sig, &solana.SignatureStatus{}, nil)
+
+	resp, err := env.client.SubmitTransaction(context.Background(), &transactionpb.SubmitTransactionRequest{
+		Transaction: &commonpb.Transaction{
+			Value: txn.Marshal(),
+		},
+		Commitment: common.Commitment_ROOT,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transactionpb.SubmitTransactionResponse_OK, resp.Result)
+
+	// The second receiver was sent 2 quarks. Tracking happens in the
+	// background, so it may not be reflected immediately.
+	assert.Eventually(t, func() bool {
+		d, err := env.destStore.Get(context.Background(), accounts[2])
+		require.NoError(t, err)
+		return d.TransferCount == 1 && d.QuarkVolume == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSubmitTransaction_VelocityFlags(t *testing.T) {
+	env, cleanup := setupServerEnv(t)
+	defer cleanup()
+
+	txn, _ := generateTransaction(t, env.subsidizer.Public().(ed25519.PublicKey), 1, nil, nil)
+
+	auth := transaction.Authorization{
+		Result: transaction.AuthorizationResultOK,
+		Flags:  []string{"destination_count:1h0m0s"},
+	}
+	env.authorizer.On("Authorize", mock.Anything, mock.Anything).Return(auth, nil)
+	env.submitter.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	env.sc.On("GetAccountInfo", mock.Anything, mock.Anything).Return(solana.AccountInfo{}, nil)
+
+	var sig solana.Signature
+	copy(sig[:], ed25519.Sign(env.subsidizer, txn.Message.Marshal()))
+	env.sc.On("SubmitTransaction", mock.Anything, solana.CommitmentRoot).Return(sig, &solana.SignatureStatus{}, nil)
+
+	resp, err := env.client.SubmitTransaction(context.Background(), &transactionpb.SubmitTransactionRequest{
+		Transaction: &commonpb.Transaction{
+			Value: txn.Marshal(),
+		},
+		Commitment: common.Commitment_ROOT,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transactionpb.SubmitTransactionResponse_OK, resp.Result)
+
+	flags, err := env.flagStore.Get(context.Background(), sig[:])
+	require.NoError(t, err)
+	assert.Equal(t, auth.Flags, flags)
+}
+
 func TestSubmitTransaction_DuplicateSignature(t *testing.T) {
 	env, cleanup := setupServerEnv(t)
 	defer cleanup()
@@ -1459,6 +1812,78 @@ func TestSubmitTransaction_SubmitError(t *testing.T) {
 	assert.Equal(t, submitted.Signatures[0][:], resp.Signature.Value)
 }
 
+func TestSubmitTransaction_VelocityRollback(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		status   *solana.SignatureStatus
+		err      error
+		rollback bool
+	}{
+		{name: "ok", status: &solana.SignatureStatus{}},
+		{
+			name: "duplicate",
+			status: &solana.SignatureStatus{
+				ErrorResult: solana.NewTransactionError(solana.TransactionErrorDuplicateSignature),
+			},
+		},
+		{
+			name: "failed",
+			status: &solana.SignatureStatus{
+				ErrorResult: solana.NewTransactionError(solana.TransactionErrorAccountInUse),
+			},
+			rollback: true,
+		},
+		{name: "error", status: &solana.SignatureStatus{}, err: errors.New("unavailable"), rollback: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env, cleanup := setupServerEnv(t)
+			defer cleanup()
+
+			checker := &mockVelocityChecker{}
+			checker.On("Check", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(velocity.Result{}, nil)
+			checker.On("Rollback", mock.Anything, mock.Anything).Return(nil)
+
+			authorizer, err := transaction.NewAuthorizer(
+				appmapper.New(),
+				appconfigdb.New(),
+				webhook.NewClient(http.DefaultClient),
+				transaction.NewLimiter(func(int) rate.Limiter { return &rate.NoLimiter{} }, -1, -1),
+				checker,
+			)
+			require.NoError(t, err)
+			env.server.authorizer = authorizer
+
+			txn, _ := generateTransaction(t, env.subsidizer.Public().(ed25519.PublicKey), 1, nil, nil)
+
+			env.submitter.On("Submit", mock.Anything, mock.Anything).Return(nil)
+			env.sc.On("GetAccountInfo", mock.Anything, mock.Anything).Return(solana.AccountInfo{}, nil)
+
+			var sig solana.Signature
+			copy(sig[:], ed25519.Sign(env.subsidizer, txn.Message.Marshal()))
+			env.sc.On("SubmitTransaction", mock.Anything, solana.CommitmentRoot).Return(sig, tc.status, tc.err)
+
+			_, err = env.client.SubmitTransaction(context.Background(), &transactionpb.SubmitTransactionRequest{
+				Transaction: &commonpb.Transaction{
+					Value: txn.Marshal(),
+				},
+				Commitment: common.Commitment_ROOT,
+			})
+			if tc.err != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			checker.AssertNumberOfCalls(t, "Check", 1)
+			if tc.rollback {
+				checker.AssertNumberOfCalls(t, "Rollback", 1)
+			} else {
+				checker.AssertNotCalled(t, "Rollback", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestSubmitTransaction_BadTransaction(t *testing.T) {
 	env, cleanup := setupServerEnv(t)
 	defer cleanup()
@@ -123,6 +123,7 @@ func setup(t *testing.T, submitTxGlobalRL, submitTxAppRL int) (env testEnv, clea
 			submitTxGlobalRL,
 			submitTxAppRL,
 		),
+		nil,
 	)
 	require.NoError(t, err)
 
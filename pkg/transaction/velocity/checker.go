@@ -0,0 +1,238 @@
+package velocity
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	subjectOwner       = "owner"
+	subjectDestination = "destination"
+
+	dimensionCount  = "count"
+	dimensionAmount = "amount"
+)
+
+var (
+	exceededCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "velocity_threshold_exceeded",
+		Help:      "Number of velocity thresholds exceeded",
+	}, []string{"subject", "dimension", "action"})
+	failureCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "velocity_check_failures",
+		Help:      "Number of velocity checks that failed due to the store being unavailable",
+	}, []string{"action"})
+)
+
+func init() {
+	if err := prometheus.Register(exceededCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			exceededCounter = e.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			logrus.WithError(err).Error("failed to register velocity threshold exceeded counter")
+		}
+	}
+	if err := prometheus.Register(failureCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			failureCounter = e.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			logrus.WithError(err).Error("failed to register velocity check failure counter")
+		}
+	}
+}
+
+// FailedReason is the flag of a transaction that was rejected because the
+// velocity store was unavailable, and the checker fails closed.
+const FailedReason = "velocity_unavailable"
+
+type checker struct {
+	log    *logrus.Entry
+	store  Store
+	config *Config
+	ttl    time.Duration
+}
+
+// NewChecker returns a Checker that evaluates the thresholds in config
+// against the records in the provided store.
+//
+// Records are keyed by owner and destination only, so that activity across
+// all apps counts towards an app's thresholds.
+func NewChecker(store Store, config *Config) Checker {
+	return &checker{
+		log:    logrus.StandardLogger().WithField("type", "transaction/velocity"),
+		store:  store,
+		config: config,
+		ttl:    config.maxWindow(),
+	}
+}
+
+type subject struct {
+	name       string
+	thresholds []Threshold
+}
+
+// Check implements Checker.Check.
+//
+// The transfers are recorded before the thresholds are evaluated, which
+// ensures that concurrent checks observe each other's transfers. If the
+// transaction is rejected, the newly recorded transfers are removed.
+//
+// If the store fails, the newly recorded transfers are removed, and the
+// transaction is either rejected or an error is returned, depending on
+// whether or not the config fails closed.
+func (c *checker) Check(ctx context.Context, appIndex uint16, txID []byte, transfers []Transfer) (result Result, err error) {
+	if c.ttl == 0 || len(transfers) == 0 {
+		return result, nil
+	}
+
+	var owner, destination subject
+	owner.name = subjectOwner
+	destination.name = subjectDestination
+	if rules := c.config.RulesFor(appIndex); rules != nil {
+		owner.thresholds = rules.Owner
+		destination.thresholds = rules.Destination
+	}
+
+	var keys []string
+	subjects := make(map[string]subject)
+	records := make(map[string][]Record)
+	for i, t := range transfers {
+		id := fmt.Sprintf("%s:%d", hex.EncodeToString(txID), i)
+		for _, k := range []struct {
+			key string
+			s   subject
+		}{
+			{ownerKey(t.Owner), owner},
+			{destinationKey(t.Destination), destination},
+		} {
+			if _, ok := subjects[k.key]; !ok {
+				keys = append(keys, k.key)
+				subjects[k.key] = k.s
+			}
+			records[k.key] = append(records[k.key], Record{ID: id, Amount: t.Amount})
+		}
+	}
+
+	now := time.Now()
+	added := make(map[string][]string)
+	flags := make(map[string]struct{})
+	for _, key := range keys {
+		s := subjects[key]
+		since := make([]time.Time, len(s.thresholds))
+		for i, t := range s.thresholds {
+			since[i] = now.Add(-t.Window)
+		}
+
+		totals, ids, err := c.store.Add(ctx, key, records[key], now, c.ttl, since)
+		if err != nil {
+			c.rollback(ctx, added)
+			return c.failed(errors.Wrapf(err, "failed to record transfers for %s", key))
+		}
+		added[key] = ids
+
+		for i, t := range s.thresholds {
+			var exceeded []string
+			if t.MaxCount > 0 && totals[i].Count > t.MaxCount {
+				exceeded = append(exceeded, dimensionCount)
+			}
+			if t.MaxAmount > 0 && totals[i].Amount > t.MaxAmount {
+				exceeded = append(exceeded, dimensionAmount)
+			}
+
+			for _, dimension := range exceeded {
+				exceededCounter.WithLabelValues(s.name, dimension, actionString(t.Action)).Inc()
+				flags[fmt.Sprintf("%s_%s:%s", s.name, dimension, t.Window)] = struct{}{}
+				if t.Action > result.Action {
+					result.Action = t.Action
+				}
+			}
+		}
+	}
+
+	for f := range flags {
+		result.Flags = append(result.Flags, f)
+	}
+	sort.Strings(result.Flags)
+
+	if result.Action == ActionReject {
+		c.rollback(ctx, added)
+	} else {
+		result.recorded = added
+	}
+
+	return result, nil
+}
+
+// Rollback implements Checker.Rollback.
+func (c *checker) Rollback(ctx context.Context, result Result) error {
+	for key, ids := range result.recorded {
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := c.store.Remove(ctx, key, ids); err != nil {
+			return errors.Wrapf(err, "failed to remove transfers for %s", key)
+		}
+	}
+
+	return nil
+}
+
+// failed returns the result of a check that failed with the provided error.
+func (c *checker) failed(err error) (Result, error) {
+	if !c.config.FailClosed {
+		failureCounter.WithLabelValues(actionString(ActionNone)).Inc()
+		return Result{}, err
+	}
+
+	failureCounter.WithLabelValues(actionString(ActionReject)).Inc()
+	c.log.WithError(err).Warn("rejecting transaction due to velocity check failure")
+	return Result{
+		Action: ActionReject,
+		Flags:  []string{FailedReason},
+	}, nil
+}
+
+// rollback removes newly added records. Records that existed prior to the
+// check (i.e. from a previous check of the same transaction) are retained.
+func (c *checker) rollback(ctx context.Context, added map[string][]string) {
+	for key, ids := range added {
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := c.store.Remove(ctx, key, ids); err != nil {
+			c.log.WithError(err).WithField("key", key).Warn("failed to remove rejected transfers")
+		}
+	}
+}
+
+func ownerKey(owner []byte) string {
+	return strings.Join([]string{subjectOwner, base58.Encode(owner)}, ":")
+}
+
+func destinationKey(dest []byte) string {
+	return strings.Join([]string{subjectDestination, base58.Encode(dest)}, ":")
+}
+
+func actionString(a Action) string {
+	switch a {
+	case ActionFlag:
+		return "flag"
+	case ActionReject:
+		return "reject"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,278 @@
+package velocity_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity/memory"
+)
+
+func TestChecker_NoRules(t *testing.T) {
+	checker := velocity.NewChecker(memory.New(), &velocity.Config{})
+
+	result, err := checker.Check(context.Background(), 1, []byte("tx"), []velocity.Transfer{
+		{Owner: testutil.GenerateSolanaKeys(t, 1)[0], Destination: testutil.GenerateSolanaKeys(t, 1)[0], Amount: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionNone, result.Action)
+	assert.Empty(t, result.Flags)
+}
+
+func TestChecker_Destination(t *testing.T) {
+	store := memory.New()
+	checker := velocity.NewChecker(store, &velocity.Config{
+		Default: &velocity.Rules{
+			Destination: []velocity.Threshold{
+				{Window: time.Hour, MaxCount: 3, Action: velocity.ActionFlag},
+				{Window: time.Hour, MaxAmount: 50 * quarksPerKin, Action: velocity.ActionReject},
+			},
+		},
+	})
+
+	dest := testutil.GenerateSolanaKeys(t, 1)[0]
+	owners := testutil.GenerateSolanaKeys(t, 5)
+
+	// Drain multiple accounts into a single destination.
+	for i := 0; i < 3; i++ {
+		result, err := checker.Check(context.Background(), 1, []byte{byte(i)}, []velocity.Transfer{
+			{Owner: owners[i], Destination: dest, Amount: 10 * quarksPerKin},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, velocity.ActionNone, result.Action)
+	}
+
+	result, err := checker.Check(context.Background(), 1, []byte{3}, []velocity.Transfer{
+		{Owner: owners[3], Destination: dest, Amount: 10 * quarksPerKin},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionFlag, result.Action)
+	assert.Equal(t, []string{"destination_count:1h0m0s"}, result.Flags)
+
+	result, err = checker.Check(context.Background(), 1, []byte{4}, []velocity.Transfer{
+		{Owner: owners[4], Destination: dest, Amount: 20 * quarksPerKin},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionReject, result.Action)
+	assert.Equal(t, []string{"destination_amount:1h0m0s", "destination_count:1h0m0s"}, result.Flags)
+
+	// Rejected transfers should not be recorded.
+	count, amount, err := store.Window(context.Background(), "destination:"+base58.Encode(dest), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	assert.EqualValues(t, 40*quarksPerKin, amount)
+}
+
+func TestChecker_Owner(t *testing.T) {
+	checker := velocity.NewChecker(memory.New(), &velocity.Config{
+		Apps: map[uint16]*velocity.Rules{
+			1: {
+				Owner: []velocity.Threshold{
+					{Window: time.Minute, MaxCount: 2, Action: velocity.ActionReject},
+				},
+			},
+		},
+	})
+
+	owner := testutil.GenerateSolanaKeys(t, 1)[0]
+	dests := testutil.GenerateSolanaKeys(t, 3)
+
+	// Multiple transfers within a single transaction are counted individually.
+	transfers := []velocity.Transfer{
+		{Owner: owner, Destination: dests[0], Amount: 1},
+		{Owner: owner, Destination: dests[1], Amount: 1},
+	}
+	result, err := checker.Check(context.Background(), 1, []byte("tx1"), transfers)
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionNone, result.Action)
+
+	// Other apps are not subject to app 1's rules, but still count towards them.
+	result, err = checker.Check(context.Background(), 2, []byte("tx2"), []velocity.Transfer{
+		{Owner: owner, Destination: dests[2], Amount: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionNone, result.Action)
+
+	result, err = checker.Check(context.Background(), 1, []byte("tx3"), []velocity.Transfer{
+		{Owner: owner, Destination: dests[2], Amount: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionReject, result.Action)
+	assert.Equal(t, []string{"owner_count:1m0s"}, result.Flags)
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := velocity.LoadConfig(strings.NewReader(`{
+		"default": {
+			"destination": [{"window": "1h", "max_count": 1000, "action": "flag"}]
+		},
+		"apps": {
+			"1": {
+				"owner": [{"window": "10m", "max_amount": 100000000, "action": "reject"}]
+			}
+		},
+		"fail_closed": true
+	}`))
+	require.NoError(t, err)
+	assert.True(t, config.FailClosed)
+
+	assert.Equal(t, &velocity.Rules{
+		Destination: []velocity.Threshold{
+			{Window: time.Hour, MaxCount: 1000, Action: velocity.ActionFlag},
+		},
+	}, config.RulesFor(2))
+	assert.Equal(t, &velocity.Rules{
+		Owner: []velocity.Threshold{
+			{Window: 10 * time.Minute, MaxAmount: 100000000, Action: velocity.ActionReject},
+		},
+	}, config.RulesFor(1))
+
+	for _, invalid := range []string{
+		`{"default": {"owner": [{"window": "abc", "max_count": 1, "action": "flag"}]}}`,
+		`{"default": {"owner": [{"window": "-1h", "max_count": 1, "action": "flag"}]}}`,
+		`{"default": {"owner": [{"window": "1h", "max_count": 1, "action": "drop"}]}}`,
+		`{"default": {"owner": [{"window": "1h", "action": "flag"}]}}`,
+		`{"apps": {"abc": {}}}`,
+	} {
+		_, err := velocity.LoadConfig(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestChecker_Idempotent(t *testing.T) {
+	store := memory.New()
+	checker := velocity.NewChecker(store, &velocity.Config{
+		Default: &velocity.Rules{
+			Owner: []velocity.Threshold{{Window: time.Hour, MaxCount: 10, Action: velocity.ActionFlag}},
+		},
+	})
+
+	owner := testutil.GenerateSolanaKeys(t, 1)[0]
+	transfers := []velocity.Transfer{{Owner: owner, Destination: ed25519.PublicKey(make([]byte, 32)), Amount: 5}}
+	for i := 0; i < 3; i++ {
+		_, err := checker.Check(context.Background(), 1, []byte("tx"), transfers)
+		require.NoError(t, err)
+	}
+
+	count, amount, err := store.Window(context.Background(), "owner:"+base58.Encode(owner), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.EqualValues(t, 5, amount)
+}
+
+const quarksPerKin = 1e5
+
+func TestChecker_Retry(t *testing.T) {
+	store := memory.New()
+	checker := velocity.NewChecker(store, &velocity.Config{
+		Default: &velocity.Rules{
+			Owner: []velocity.Threshold{{Window: time.Hour, MaxCount: 1, Action: velocity.ActionReject}},
+		},
+	})
+
+	owner := testutil.GenerateSolanaKeys(t, 1)[0]
+	dests := testutil.GenerateSolanaKeys(t, 2)
+
+	// Resubmitting a transaction should not count its transfers twice.
+	for i := 0; i < 2; i++ {
+		result, err := checker.Check(context.Background(), 1, []byte("tx1"), []velocity.Transfer{
+			{Owner: owner, Destination: dests[0], Amount: 5},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, velocity.ActionNone, result.Action)
+	}
+
+	result, err := checker.Check(context.Background(), 1, []byte("tx2"), []velocity.Transfer{
+		{Owner: owner, Destination: dests[1], Amount: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionReject, result.Action)
+
+	// Rejecting a different transaction should not remove the transfers of
+	// the accepted one.
+	count, amount, err := store.Window(context.Background(), "owner:"+base58.Encode(owner), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.EqualValues(t, 5, amount)
+
+	count, _, err = store.Window(context.Background(), "destination:"+base58.Encode(dests[1]), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestChecker_Rollback(t *testing.T) {
+	store := memory.New()
+	checker := velocity.NewChecker(store, &velocity.Config{
+		Default: &velocity.Rules{
+			Owner: []velocity.Threshold{{Window: time.Hour, MaxCount: 10, Action: velocity.ActionFlag}},
+		},
+	})
+
+	owner := testutil.GenerateSolanaKeys(t, 1)[0]
+	key := "owner:" + base58.Encode(owner)
+
+	_, err := checker.Check(context.Background(), 1, []byte("tx1"), []velocity.Transfer{
+		{Owner: owner, Destination: testutil.GenerateSolanaKeys(t, 1)[0], Amount: 5},
+	})
+	require.NoError(t, err)
+	rolledBack, err := checker.Check(context.Background(), 1, []byte("tx2"), []velocity.Transfer{
+		{Owner: owner, Destination: testutil.GenerateSolanaKeys(t, 1)[0], Amount: 7},
+	})
+	require.NoError(t, err)
+
+	count, amount, err := store.Window(context.Background(), key, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.EqualValues(t, 12, amount)
+
+	require.NoError(t, checker.Rollback(context.Background(), rolledBack))
+
+	count, amount, err = store.Window(context.Background(), key, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.EqualValues(t, 5, amount)
+
+	// Rolling back an empty result, or the same result twice, is a no-op.
+	require.NoError(t, checker.Rollback(context.Background(), velocity.Result{}))
+	require.NoError(t, checker.Rollback(context.Background(), rolledBack))
+
+	count, _, err = store.Window(context.Background(), key, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
+type failingStore struct {
+	velocity.Store
+}
+
+func (s *failingStore) Add(context.Context, string, []velocity.Record, time.Time, time.Duration, []time.Time) ([]velocity.Totals, []string, error) {
+	return nil, nil, errors.New("unavailable")
+}
+
+func TestChecker_Failure(t *testing.T) {
+	rules := &velocity.Rules{
+		Owner: []velocity.Threshold{{Window: time.Hour, MaxCount: 10, Action: velocity.ActionFlag}},
+	}
+	transfers := []velocity.Transfer{
+		{Owner: testutil.GenerateSolanaKeys(t, 1)[0], Destination: testutil.GenerateSolanaKeys(t, 1)[0], Amount: 5},
+	}
+
+	checker := velocity.NewChecker(&failingStore{memory.New()}, &velocity.Config{Default: rules})
+	_, err := checker.Check(context.Background(), 1, []byte("tx"), transfers)
+	assert.Error(t, err)
+
+	checker = velocity.NewChecker(&failingStore{memory.New()}, &velocity.Config{Default: rules, FailClosed: true})
+	result, err := checker.Check(context.Background(), 1, []byte("tx"), transfers)
+	require.NoError(t, err)
+	assert.Equal(t, velocity.ActionReject, result.Action)
+	assert.Equal(t, []string{velocity.FailedReason}, result.Flags)
+}
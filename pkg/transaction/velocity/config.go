@@ -0,0 +1,188 @@
+package velocity
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Threshold is a limit on the number, or total amount, of transfers within
+// a sliding window.
+//
+// A zero MaxCount or MaxAmount indicates no limit for that dimension.
+type Threshold struct {
+	Window    time.Duration
+	MaxCount  int64
+	MaxAmount uint64
+	Action    Action
+}
+
+// Rules are the thresholds that apply to the owners and destinations of
+// transfers.
+type Rules struct {
+	Owner       []Threshold
+	Destination []Threshold
+}
+
+// Config contains the velocity rules for every app.
+//
+// Apps that do not have a specific set of rules use the default rules,
+// if any.
+//
+// If FailClosed is set, transactions are rejected when the velocity store is
+// unavailable. Otherwise, they are not subject to velocity checks.
+type Config struct {
+	Default    *Rules
+	Apps       map[uint16]*Rules
+	FailClosed bool
+}
+
+// RulesFor returns the rules for the specified app index.
+func (c *Config) RulesFor(appIndex uint16) *Rules {
+	if r, ok := c.Apps[appIndex]; ok {
+		return r
+	}
+
+	return c.Default
+}
+
+// maxWindow returns the largest window across all thresholds.
+func (c *Config) maxWindow() (max time.Duration) {
+	rules := []*Rules{c.Default}
+	for _, r := range c.Apps {
+		rules = append(rules, r)
+	}
+
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+
+		for _, t := range append(append([]Threshold{}, r.Owner...), r.Destination...) {
+			if t.Window > max {
+				max = t.Window
+			}
+		}
+	}
+
+	return max
+}
+
+type jsonThreshold struct {
+	Window    string `json:"window"`
+	MaxCount  int64  `json:"max_count"`
+	MaxAmount uint64 `json:"max_amount"`
+	Action    string `json:"action"`
+}
+
+type jsonRules struct {
+	Owner       []jsonThreshold `json:"owner"`
+	Destination []jsonThreshold `json:"destination"`
+}
+
+type jsonConfig struct {
+	Default    *jsonRules            `json:"default"`
+	Apps       map[uint16]*jsonRules `json:"apps"`
+	FailClosed bool                  `json:"fail_closed"`
+}
+
+// LoadConfig loads a JSON encoded Config.
+//
+// Example:
+//
+//	{
+//	  "default": {
+//	    "destination": [{"window": "1h", "max_count": 1000, "action": "flag"}]
+//	  },
+//	  "apps": {
+//	    "1": {
+//	      "owner": [{"window": "10m", "max_amount": 100000000, "action": "reject"}]
+//	    }
+//	  },
+//	  "fail_closed": false
+//	}
+//
+// Amounts are specified in quarks.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var raw jsonConfig
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode config")
+	}
+
+	var err error
+	config := &Config{
+		Apps:       make(map[uint16]*Rules),
+		FailClosed: raw.FailClosed,
+	}
+	if raw.Default != nil {
+		if config.Default, err = raw.Default.toRules(); err != nil {
+			return nil, errors.Wrap(err, "invalid default rules")
+		}
+	}
+	for appIndex, r := range raw.Apps {
+		if r == nil {
+			continue
+		}
+
+		if config.Apps[appIndex], err = r.toRules(); err != nil {
+			return nil, errors.Wrapf(err, "invalid rules for app %d", appIndex)
+		}
+	}
+
+	return config, nil
+}
+
+func (r *jsonRules) toRules() (*Rules, error) {
+	var err error
+	rules := &Rules{}
+	if rules.Owner, err = toThresholds(r.Owner); err != nil {
+		return nil, errors.Wrap(err, "invalid owner threshold")
+	}
+	if rules.Destination, err = toThresholds(r.Destination); err != nil {
+		return nil, errors.Wrap(err, "invalid destination threshold")
+	}
+
+	return rules, nil
+}
+
+func toThresholds(raw []jsonThreshold) ([]Threshold, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	thresholds := make([]Threshold, len(raw))
+	for i, t := range raw {
+		window, err := time.ParseDuration(t.Window)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid window: %s", t.Window)
+		}
+		if window <= 0 {
+			return nil, errors.Errorf("window must be positive: %s", t.Window)
+		}
+
+		var action Action
+		switch t.Action {
+		case "flag":
+			action = ActionFlag
+		case "reject":
+			action = ActionReject
+		default:
+			return nil, errors.Errorf("invalid action: %s", t.Action)
+		}
+
+		if t.MaxCount <= 0 && t.MaxAmount == 0 {
+			return nil, errors.New("at least one of max_count or max_amount must be set")
+		}
+
+		thresholds[i] = Threshold{
+			Window:    window,
+			MaxCount:  t.MaxCount,
+			MaxAmount: t.MaxAmount,
+			Action:    action,
+		}
+	}
+
+	return thresholds, nil
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+)
+
+type record struct {
+	amount uint64
+	at     time.Time
+}
+
+type store struct {
+	mu      sync.Mutex
+	records map[string]map[string]record
+}
+
+// New returns an in memory velocity.Store.
+func New() velocity.Store {
+	return &store{
+		records: make(map[string]map[string]record),
+	}
+}
+
+// Window implements velocity.Store.Window.
+func (s *store) Window(_ context.Context, key string, since time.Time) (count int64, amount uint64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.records[key] {
+		if r.at.Before(since) {
+			continue
+		}
+
+		count++
+		amount += r.amount
+	}
+
+	return count, amount, nil
+}
+
+// Add implements velocity.Store.Add.
+func (s *store) Add(_ context.Context, key string, records []velocity.Record, at time.Time, ttl time.Duration, since []time.Time) (totals []velocity.Totals, added []string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[key]
+	if !ok {
+		existing = make(map[string]record)
+		s.records[key] = existing
+	}
+
+	for _, r := range records {
+		if _, ok := existing[r.ID]; !ok {
+			added = append(added, r.ID)
+		}
+		existing[r.ID] = record{amount: r.Amount, at: at}
+	}
+	for id, r := range existing {
+		if r.at.Before(at.Add(-ttl)) {
+			delete(existing, id)
+		}
+	}
+
+	totals = make([]velocity.Totals, len(since))
+	for i, t := range since {
+		for _, r := range existing {
+			if r.at.Before(t) {
+				continue
+			}
+
+			totals[i].Count++
+			totals[i].Amount += r.amount
+		}
+	}
+
+	return totals, added, nil
+}
+
+// Remove implements velocity.Store.Remove.
+func (s *store) Remove(_ context.Context, key string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.records[key], id)
+	}
+
+	return nil
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make(map[string]map[string]record)
+	s.mu.Unlock()
+}
+
+type flagStore struct {
+	mu    sync.Mutex
+	flags map[string][]string
+}
+
+// NewFlagStore returns an in memory velocity.FlagStore.
+func NewFlagStore() velocity.FlagStore {
+	return &flagStore{
+		flags: make(map[string][]string),
+	}
+}
+
+// Put implements velocity.FlagStore.Put.
+func (s *flagStore) Put(_ context.Context, txID []byte, flags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags[string(txID)] = append([]string(nil), flags...)
+	return nil
+}
+
+// Get implements velocity.FlagStore.Get.
+func (s *flagStore) Get(_ context.Context, txID []byte) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags, ok := s.flags[string(txID)]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]string(nil), flags...), nil
+}
+
+func (s *flagStore) reset() {
+	s.mu.Lock()
+	s.flags = make(map[string][]string)
+	s.mu.Unlock()
+}
@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/kinecosystem/agora/pkg/transaction/velocity/tests"
+)
+
+func TestStore(t *testing.T) {
+	s := New()
+	tests.RunStoreTests(t, s, s.(*store).reset)
+}
+
+func TestFlagStore(t *testing.T) {
+	s := NewFlagStore()
+	tests.RunFlagStoreTests(t, s, s.(*flagStore).reset)
+}
@@ -0,0 +1,258 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+)
+
+const (
+	flagTTL = 24 * time.Hour
+)
+
+type store struct {
+	client redis.Cmdable
+}
+
+// New returns a redis backed velocity.Store.
+//
+// Each key is stored as a sorted set of record ids (scored by time), along
+// with a hash of record amounts. Both share a hash tag so that they reside
+// on the same shard.
+func New(client redis.Cmdable) velocity.Store {
+	return &store{
+		client: client,
+	}
+}
+
+// Window implements velocity.Store.Window.
+func (s *store) Window(_ context.Context, key string, since time.Time) (count int64, amount uint64, err error) {
+	ids, err := s.client.ZRangeByScore(timesKey(key), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixNano(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get records")
+	}
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	amounts, err := s.client.HMGet(amountsKey(key), ids...).Result()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get record amounts")
+	}
+
+	for _, a := range amounts {
+		// The amount may have been removed by a concurrent Add if the record
+		// expired, in which case it's no longer part of the window.
+		str, ok := a.(string)
+		if !ok {
+			continue
+		}
+
+		v, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "invalid record amount: %s", str)
+		}
+
+		count++
+		amount += v
+	}
+
+	return count, amount, nil
+}
+
+// addScript prunes expired records, adds the new records, and returns the
+// ids of the records that did not previously exist, along with the scores
+// and amounts of all records at, or after, the minimum score.
+//
+// KEYS: times, amounts
+// ARGV: score, cutoff, ttl (ms), min score, [id, amount]...
+//
+// Amounts are summed by the caller, since lua numbers cannot represent
+// every uint64.
+var addScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[2])
+if #expired > 0 then
+	redis.call('ZREM', KEYS[1], unpack(expired))
+	redis.call('HDEL', KEYS[2], unpack(expired))
+end
+
+local added = {}
+for i = 5, #ARGV, 2 do
+	if redis.call('ZADD', KEYS[1], ARGV[1], ARGV[i]) == 1 then
+		table.insert(added, ARGV[i])
+	end
+	redis.call('HSET', KEYS[2], ARGV[i], ARGV[i + 1])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+
+local ids = {}
+local scores = {}
+local window = redis.call('ZRANGEBYSCORE', KEYS[1], ARGV[4], '+inf', 'WITHSCORES')
+for i = 1, #window, 2 do
+	table.insert(ids, window[i])
+	table.insert(scores, window[i + 1])
+end
+
+local amounts = {}
+if #ids > 0 then
+	amounts = redis.call('HMGET', KEYS[2], unpack(ids))
+end
+
+return {added, scores, amounts}
+`)
+
+// Add implements velocity.Store.Add.
+func (s *store) Add(_ context.Context, key string, records []velocity.Record, at time.Time, ttl time.Duration, since []time.Time) (totals []velocity.Totals, added []string, err error) {
+	minScore := at.UnixNano()
+	for _, t := range since {
+		if t.UnixNano() < minScore {
+			minScore = t.UnixNano()
+		}
+	}
+
+	args := []interface{}{
+		strconv.FormatInt(at.UnixNano(), 10),
+		strconv.FormatInt(at.Add(-ttl).UnixNano(), 10),
+		strconv.FormatInt(ttl.Milliseconds(), 10),
+		strconv.FormatInt(minScore, 10),
+	}
+	for _, r := range records {
+		args = append(args, r.ID, strconv.FormatUint(r.Amount, 10))
+	}
+
+	res, err := addScript.Run(s.client, []string{timesKey(key), amountsKey(key)}, args...).Result()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to add records")
+	}
+
+	parts, ok := res.([]interface{})
+	if !ok || len(parts) != 3 {
+		return nil, nil, errors.Errorf("unexpected script result: %v", res)
+	}
+	addedIDs, _ := parts[0].([]interface{})
+	scores, _ := parts[1].([]interface{})
+	amounts, _ := parts[2].([]interface{})
+	if len(scores) != len(amounts) {
+		return nil, nil, errors.Errorf("mismatched scores and amounts: %d != %d", len(scores), len(amounts))
+	}
+
+	for _, id := range addedIDs {
+		added = append(added, id.(string))
+	}
+
+	totals = make([]velocity.Totals, len(since))
+	for i := range scores {
+		score, err := strconv.ParseFloat(scores[i].(string), 64)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid record score: %v", scores[i])
+		}
+
+		// Amounts are always set alongside scores within the script, but
+		// the hash may have been expired independently.
+		str, ok := amounts[i].(string)
+		if !ok {
+			continue
+		}
+		amount, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid record amount: %s", str)
+		}
+
+		for j, t := range since {
+			if score >= float64(t.UnixNano()) {
+				totals[j].Count++
+				totals[j].Amount += amount
+			}
+		}
+	}
+
+	return totals, added, nil
+}
+
+// Remove implements velocity.Store.Remove.
+func (s *store) Remove(_ context.Context, key string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := s.client.TxPipelined(func(p redis.Pipeliner) error {
+		p.ZRem(timesKey(key), toInterfaces(ids)...)
+		p.HDel(amountsKey(key), ids...)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to remove records")
+	}
+
+	return nil
+}
+
+func timesKey(key string) string {
+	return fmt.Sprintf("velocity:{%s}:times", key)
+}
+
+func amountsKey(key string) string {
+	return fmt.Sprintf("velocity:{%s}:amounts", key)
+}
+
+func toInterfaces(strs []string) []interface{} {
+	out := make([]interface{}, len(strs))
+	for i, s := range strs {
+		out[i] = s
+	}
+	return out
+}
+
+type flagStore struct {
+	client redis.Cmdable
+}
+
+// NewFlagStore returns a redis backed velocity.FlagStore.
+//
+// Flags expire after a day, which is more than enough time for them to be
+// picked up by event processing.
+func NewFlagStore(client redis.Cmdable) velocity.FlagStore {
+	return &flagStore{
+		client: client,
+	}
+}
+
+// Put implements velocity.FlagStore.Put.
+func (s *flagStore) Put(_ context.Context, txID []byte, flags []string) error {
+	if err := s.client.Set(flagKey(txID), strings.Join(flags, ","), flagTTL).Err(); err != nil {
+		return errors.Wrap(err, "failed to store flags")
+	}
+
+	return nil
+}
+
+// Get implements velocity.FlagStore.Get.
+func (s *flagStore) Get(_ context.Context, txID []byte) ([]string, error) {
+	val, err := s.client.Get(flagKey(txID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get flags")
+	}
+
+	if val == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(val, ","), nil
+}
+
+func flagKey(txID []byte) string {
+	return fmt.Sprintf("velocity:flags:%x", txID)
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-redis/redis/v7"
+	redistest "github.com/kinecosystem/agora-common/redis/test"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/transaction/velocity/tests"
+)
+
+var (
+	redisConnString string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	redisConnString, cleanUpFunc, err = redistest.StartRedis(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting redis connection")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func newClient() *redis.Ring {
+	return redis.NewRing(&redis.RingOptions{
+		Addrs: map[string]string{
+			"server1": redisConnString,
+		},
+	})
+}
+
+func TestStore(t *testing.T) {
+	client := newClient()
+	tests.RunStoreTests(t, New(client), func() {
+		client.FlushAll()
+	})
+}
+
+func TestFlagStore(t *testing.T) {
+	client := newClient()
+	tests.RunFlagStoreTests(t, NewFlagStore(client), func() {
+		client.FlushAll()
+	})
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+)
+
+func RunStoreTests(t *testing.T, s velocity.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s velocity.Store){
+		testWindow,
+		testAddTotals,
+		testOverwrite,
+		testRemove,
+		testExpiry,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func RunFlagStoreTests(t *testing.T, s velocity.FlagStore, teardown func()) {
+	for _, tf := range []func(t *testing.T, s velocity.FlagStore){
+		testFlagRoundTrip,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testWindow(t *testing.T, s velocity.Store) {
+	t.Run("testWindow", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+
+		count, amount, err := s.Window(ctx, "a", now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Zero(t, amount)
+
+		for i, id := range []string{"1", "2", "3"} {
+			_, _, err := s.Add(ctx, "a", []velocity.Record{{ID: id, Amount: 10}}, now.Add(-time.Duration(i)*time.Minute), time.Hour, nil)
+			require.NoError(t, err)
+		}
+		_, _, err = s.Add(ctx, "b", []velocity.Record{{ID: "1", Amount: 100}}, now, time.Hour, nil)
+		require.NoError(t, err)
+
+		count, amount, err = s.Window(ctx, "a", now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+		assert.EqualValues(t, 30, amount)
+
+		count, amount, err = s.Window(ctx, "a", now.Add(-90*time.Second))
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+		assert.EqualValues(t, 20, amount)
+
+		count, amount, err = s.Window(ctx, "b", now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		assert.EqualValues(t, 100, amount)
+	})
+}
+
+func testAddTotals(t *testing.T, s velocity.Store) {
+	t.Run("testAddTotals", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+
+		_, added, err := s.Add(ctx, "a", []velocity.Record{{ID: "1", Amount: 10}}, now.Add(-2*time.Minute), time.Hour, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1"}, added)
+
+		totals, added, err := s.Add(
+			ctx,
+			"a",
+			[]velocity.Record{{ID: "2", Amount: 20}, {ID: "3", Amount: 30}},
+			now,
+			time.Hour,
+			[]time.Time{now.Add(-time.Hour), now.Add(-time.Minute)},
+		)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"2", "3"}, added)
+		assert.Equal(t, []velocity.Totals{{Count: 3, Amount: 60}, {Count: 2, Amount: 50}}, totals)
+	})
+}
+
+func testOverwrite(t *testing.T, s velocity.Store) {
+	t.Run("testOverwrite", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+
+		_, added, err := s.Add(ctx, "a", []velocity.Record{{ID: "1", Amount: 10}}, now, time.Hour, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1"}, added)
+
+		totals, added, err := s.Add(ctx, "a", []velocity.Record{{ID: "1", Amount: 20}}, now, time.Hour, []time.Time{now.Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, added)
+		assert.Equal(t, []velocity.Totals{{Count: 1, Amount: 20}}, totals)
+
+		count, amount, err := s.Window(ctx, "a", now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		assert.EqualValues(t, 20, amount)
+	})
+}
+
+func testRemove(t *testing.T, s velocity.Store) {
+	t.Run("testRemove", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+
+		_, _, err := s.Add(ctx, "a", []velocity.Record{{ID: "1", Amount: 10}, {ID: "2", Amount: 20}}, now, time.Hour, nil)
+		require.NoError(t, err)
+		require.NoError(t, s.Remove(ctx, "a", []string{"2"}))
+		require.NoError(t, s.Remove(ctx, "b", []string{"1"}))
+
+		count, amount, err := s.Window(ctx, "a", now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		assert.EqualValues(t, 10, amount)
+	})
+}
+
+func testExpiry(t *testing.T, s velocity.Store) {
+	t.Run("testExpiry", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now()
+
+		_, _, err := s.Add(ctx, "a", []velocity.Record{{ID: "1", Amount: 10}}, now.Add(-2*time.Hour), time.Hour, nil)
+		require.NoError(t, err)
+		_, _, err = s.Add(ctx, "a", []velocity.Record{{ID: "2", Amount: 20}}, now, time.Hour, nil)
+		require.NoError(t, err)
+
+		// Even though the window is larger than the ttl, the older
+		// record should have been discarded.
+		count, amount, err := s.Window(ctx, "a", now.Add(-3*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		assert.EqualValues(t, 20, amount)
+	})
+}
+
+func testFlagRoundTrip(t *testing.T, s velocity.FlagStore) {
+	t.Run("testFlagRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		flags, err := s.Get(ctx, []byte("tx"))
+		require.NoError(t, err)
+		assert.Nil(t, flags)
+
+		expected := []string{"owner_count:1h0m0s", "destination_amount:10m0s"}
+		require.NoError(t, s.Put(ctx, []byte("tx"), expected))
+
+		flags, err = s.Get(ctx, []byte("tx"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, flags)
+
+		flags, err = s.Get(ctx, []byte("tx2"))
+		require.NoError(t, err)
+		assert.Nil(t, flags)
+	})
+}
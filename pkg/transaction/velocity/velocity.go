@@ -0,0 +1,105 @@
+package velocity
+
+import (
+	"context"
+	"crypto/ed25519"
+	"time"
+)
+
+// Action is the action to take when a threshold is exceeded.
+type Action int
+
+const (
+	// ActionNone indicates that no thresholds were exceeded.
+	ActionNone Action = iota
+	// ActionFlag indicates that the transaction should proceed, but be
+	// flagged for further review.
+	ActionFlag
+	// ActionReject indicates that the transaction should be rejected.
+	ActionReject
+)
+
+// Transfer is a single transfer within a transaction.
+type Transfer struct {
+	// Owner is the owner (authority) of the source account.
+	Owner       ed25519.PublicKey
+	Destination ed25519.PublicKey
+	Amount      uint64
+}
+
+// Result is the outcome of a velocity check.
+type Result struct {
+	// Action is the most severe action of all the exceeded thresholds.
+	Action Action
+
+	// Flags contains a description of every exceeded threshold.
+	Flags []string
+
+	// recorded contains the ids of the records added by the check, by key.
+	recorded map[string][]string
+}
+
+// Checker checks the velocity of transfers from owners and to destinations.
+type Checker interface {
+	// Check checks the transfers in a transaction against the thresholds
+	// configured for the app index.
+	//
+	// Unless the transaction is rejected, the transfers are recorded, and
+	// count towards future checks. Checking the same transaction multiple
+	// times will only record the transfers once.
+	//
+	// If the velocity store is unavailable, an error is returned, unless the
+	// checker is configured to fail closed, in which case the transaction is
+	// rejected.
+	Check(ctx context.Context, appIndex uint16, txID []byte, transfers []Transfer) (Result, error)
+
+	// Rollback removes the transfers recorded by the check that produced the
+	// result. It should be called if the transaction is not submitted, so
+	// that it does not count towards future checks.
+	Rollback(ctx context.Context, result Result) error
+}
+
+// Record is a single transfer record within a Store.
+type Record struct {
+	ID     string
+	Amount uint64
+}
+
+// Totals are the number of records, and the sum of their amounts, within
+// a window.
+type Totals struct {
+	Count  int64
+	Amount uint64
+}
+
+// Store stores transfer records within sliding windows.
+type Store interface {
+	// Window returns the number of records, and the sum of their amounts,
+	// for the specified key that were added at, or after, since.
+	Window(ctx context.Context, key string, since time.Time) (count int64, amount uint64, err error)
+
+	// Add atomically adds the records to the specified key, and returns the
+	// totals of the windows starting at each of the provided times. The
+	// totals include the added records.
+	//
+	// Adding a record with an id that already exists under the key overwrites
+	// the existing record, so that it is only counted once. The ids of the
+	// records that did not already exist are returned, so that they may be
+	// removed if the transfers are rejected. Records older than ttl may be
+	// discarded.
+	Add(ctx context.Context, key string, records []Record, at time.Time, ttl time.Duration, since []time.Time) (totals []Totals, added []string, err error)
+
+	// Remove removes the records with the specified ids from the key.
+	Remove(ctx context.Context, key string, ids []string) error
+}
+
+// FlagStore stores the flags raised for a transaction.
+type FlagStore interface {
+	// Put stores the flags for the specified transaction.
+	Put(ctx context.Context, txID []byte, flags []string) error
+
+	// Get returns the flags for the specified transaction.
+	//
+	// If no flags were stored, nil is returned.
+	Get(ctx context.Context, txID []byte) ([]string, error)
+}
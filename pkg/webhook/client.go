@@ -17,8 +17,10 @@ import (
 	"github.com/kinecosystem/agora-common/retry/backoff"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/kinecosystem/agora/pkg/app"
+	"github.com/kinecosystem/agora/pkg/tracing"
 	"github.com/kinecosystem/agora/pkg/webhook/signtransaction"
 )
 
@@ -56,6 +58,9 @@ func NewClient(httpClient *http.Client) *Client {
 
 // SignTransaction submits a sign transaction request to an app webhook
 func (c *Client) SignTransaction(ctx context.Context, signURL url.URL, webhookSecret string, req *signtransaction.RequestBody) (result *signtransaction.SuccessResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "webhook.Client/SignTransaction", attribute.String("url", signURL.String()))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	signTxJSON, err := json.Marshal(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal sign transaction request body")
@@ -66,6 +71,7 @@ func (c *Client) SignTransaction(ctx context.Context, signURL url.URL, webhookSe
 		return nil, errors.Wrap(err, "failed to create sign transaction http request")
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	tracing.InjectHTTPHeaders(ctx, httpReq.Header)
 	if err := sign(httpReq, webhookSecret, signTxJSON); err != nil {
 		return nil, err
 	}
@@ -123,7 +129,10 @@ func (c *Client) SignTransaction(ctx context.Context, signURL url.URL, webhookSe
 	return nil, &SignTransactionError{Message: "failed to sign transaction", StatusCode: resp.StatusCode}
 }
 
-func (c *Client) Events(ctx context.Context, eventsURL url.URL, webhookSecret string, body []byte) error {
+func (c *Client) Events(ctx context.Context, eventsURL url.URL, webhookSecret string, body []byte) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "webhook.Client/Events", attribute.String("url", eventsURL.String()))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	log := c.log.WithFields(logrus.Fields{
 		"method": "Events",
 		"url":    eventsURL.String(),
@@ -134,6 +143,7 @@ func (c *Client) Events(ctx context.Context, eventsURL url.URL, webhookSecret st
 		return errors.Wrap(err, "failed to create sign transaction http request")
 	}
 	req.Header.Set("Content-Type", "application/json")
+	tracing.InjectHTTPHeaders(ctx, req.Header)
 	if err := sign(req, webhookSecret, body); err != nil {
 		return err
 	}
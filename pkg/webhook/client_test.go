@@ -16,8 +16,11 @@ import (
 	"github.com/kinecosystem/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/kinecosystem/agora-common/headers"
+	"github.com/kinecosystem/agora/pkg/tracing"
 	"github.com/kinecosystem/agora/pkg/webhook/signtransaction"
 )
 
@@ -347,3 +350,33 @@ func newTestServerWithJSONResponse(t *testing.T, env testEnv, statusCode int, re
 	}))
 	return testServer
 }
+
+func TestSendEventsRequest_TracePropagation(t *testing.T) {
+	env := setup(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := tracing.Register("test", sdktrace.WithSyncer(exporter))
+	defer func() { _ = shutdown(context.Background()) }()
+
+	ctx, span := tracing.StartSpan(context.Background(), "parent")
+
+	var traceParent string
+	testServer := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		traceParent = req.Header.Get("traceparent")
+		resp.WriteHeader(200)
+	}))
+	defer testServer.Close()
+
+	eventsURL, err := url.Parse(testServer.URL)
+	require.NoError(t, err)
+	require.NoError(t, env.client.Events(ctx, *eventsURL, env.secretKey, []byte("{}")))
+	span.End()
+
+	assert.NotEmpty(t, traceParent)
+	assert.Contains(t, traceParent, span.SpanContext().TraceID().String())
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "webhook.Client/Events", spans[0].Name)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent.SpanID())
+}
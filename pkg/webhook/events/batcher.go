@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kinecosystem/agora/pkg/app"
+)
+
+// deliverFunc delivers a batch of events for an app.
+type deliverFunc func(ctx context.Context, appIndex uint16, conf *app.Config, events []Event) error
+
+type pendingEvent struct {
+	event    Event
+	conf     *app.Config
+	queuedAt time.Time
+	result   chan error
+}
+
+type appQueue struct {
+	pending []*pendingEvent
+	wakeCh  chan struct{}
+}
+
+// batcher groups events into per app batches, which are delivered once
+// either maxSize events have been queued, or the oldest queued event has
+// waited for maxLatency.
+//
+// Batches for an app are delivered one at a time, in the order the events
+// were queued.
+type batcher struct {
+	maxSize    int
+	maxLatency time.Duration
+	deliver    deliverFunc
+
+	sync.Mutex
+	queues map[uint16]*appQueue
+}
+
+func newBatcher(maxSize int, maxLatency time.Duration, deliver deliverFunc) *batcher {
+	return &batcher{
+		maxSize:    maxSize,
+		maxLatency: maxLatency,
+		deliver:    deliver,
+		queues:     make(map[uint16]*appQueue),
+	}
+}
+
+// add queues the event, and blocks until the batch containing the event has
+// been delivered, returning the result of the delivery.
+//
+// If the context is cancelled before the batch is delivered, the event will
+// still be delivered, but the caller is not notified of the result.
+func (b *batcher) add(ctx context.Context, appIndex uint16, conf *app.Config, event Event) error {
+	e := &pendingEvent{
+		event:    event,
+		conf:     conf,
+		queuedAt: time.Now(),
+		result:   make(chan error, 1),
+	}
+
+	b.Lock()
+	q, ok := b.queues[appIndex]
+	if !ok {
+		q = &appQueue{
+			wakeCh: make(chan struct{}, 1),
+		}
+		b.queues[appIndex] = q
+		go b.run(appIndex, q)
+	}
+	q.pending = append(q.pending, e)
+	if len(q.pending) >= b.maxSize {
+		select {
+		case q.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+	b.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-e.result:
+		return err
+	}
+}
+
+// run delivers the batches for an app until its queue is empty, at which
+// point the queue is removed.
+func (b *batcher) run(appIndex uint16, q *appQueue) {
+	for {
+		b.Lock()
+		if len(q.pending) == 0 {
+			delete(b.queues, appIndex)
+			b.Unlock()
+			return
+		}
+
+		wait := time.Until(q.pending[0].queuedAt.Add(b.maxLatency))
+		if len(q.pending) < b.maxSize && wait > 0 {
+			b.Unlock()
+
+			select {
+			case <-q.wakeCh:
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		size := len(q.pending)
+		if size > b.maxSize {
+			size = b.maxSize
+		}
+		batch := q.pending[:size:size]
+		q.pending = q.pending[size:]
+		b.Unlock()
+
+		events := make([]Event, len(batch))
+		for i, e := range batch {
+			events[i] = e.event
+		}
+
+		// The most recently queued config is used, since it's the closest to
+		// the app's current config.
+		err := b.deliver(context.Background(), appIndex, batch[len(batch)-1].conf, events)
+		for _, e := range batch {
+			e.result <- err
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/app"
+)
+
+type testDeliverer struct {
+	sync.Mutex
+	err     error
+	started chan struct{}
+	block   chan struct{}
+	batches map[uint16][][]Event
+}
+
+func newTestDeliverer() *testDeliverer {
+	return &testDeliverer{
+		batches: make(map[uint16][][]Event),
+	}
+}
+
+func (d *testDeliverer) deliver(_ context.Context, appIndex uint16, _ *app.Config, events []Event) error {
+	if d.started != nil {
+		select {
+		case d.started <- struct{}{}:
+		default:
+		}
+	}
+	if d.block != nil {
+		<-d.block
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.batches[appIndex] = append(d.batches[appIndex], events)
+	return d.err
+}
+
+func (d *testDeliverer) getBatches(appIndex uint16) [][]Event {
+	d.Lock()
+	defer d.Unlock()
+	return append([][]Event(nil), d.batches[appIndex]...)
+}
+
+func TestBatcher_MaxSize(t *testing.T) {
+	d := newTestDeliverer()
+	b := newBatcher(3, time.Hour, d.deliver)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, b.add(context.Background(), 1, &app.Config{}, testEvent(i)))
+		}(i)
+	}
+	wg.Wait()
+
+	batches := d.getBatches(1)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 3)
+	assert.Len(t, batches[1], 3)
+
+	// Once all batches are delivered, the app's queue is removed.
+	require.Eventually(t, func() bool {
+		b.Lock()
+		defer b.Unlock()
+		return len(b.queues) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBatcher_MaxLatency(t *testing.T) {
+	d := newTestDeliverer()
+	b := newBatcher(10, 50*time.Millisecond, d.deliver)
+
+	start := time.Now()
+	require.NoError(t, b.add(context.Background(), 1, &app.Config{}, testEvent(0)))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	batches := d.getBatches(1)
+	require.Len(t, batches, 1)
+	assert.Equal(t, []Event{testEvent(0)}, batches[0])
+}
+
+func TestBatcher_Ordering(t *testing.T) {
+	d := newTestDeliverer()
+	d.started = make(chan struct{}, 1)
+	d.block = make(chan struct{})
+	b := newBatcher(2, time.Millisecond, d.deliver)
+
+	results := make([]chan error, 7)
+	add := func(i int) {
+		results[i] = make(chan error, 1)
+		go func() {
+			results[i] <- b.add(context.Background(), 1, &app.Config{}, testEvent(i))
+		}()
+	}
+
+	// Block the delivery of the first event, so that the remaining events
+	// accumulate behind it. They are queued one at a time, so that their
+	// order is deterministic.
+	add(0)
+	<-d.started
+	for i := 1; i < len(results); i++ {
+		add(i)
+		require.Eventually(t, func() bool {
+			b.Lock()
+			defer b.Unlock()
+			return len(b.queues[1].pending) == i
+		}, time.Second, time.Millisecond)
+	}
+	close(d.block)
+
+	for _, r := range results {
+		require.NoError(t, <-r)
+	}
+
+	expected := [][]Event{
+		{testEvent(0)},
+		{testEvent(1), testEvent(2)},
+		{testEvent(3), testEvent(4)},
+		{testEvent(5), testEvent(6)},
+	}
+	assert.Equal(t, expected, d.getBatches(1))
+}
+
+func TestBatcher_Apps(t *testing.T) {
+	d := newTestDeliverer()
+	d.err = errors.New("failed")
+	b := newBatcher(2, time.Hour, d.deliver)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Every event in a failed batch receives the error.
+			assert.Equal(t, d.err, b.add(context.Background(), uint16(i%2), &app.Config{}, testEvent(i)))
+		}(i)
+	}
+	wg.Wait()
+
+	for appIndex := uint16(0); appIndex < 2; appIndex++ {
+		batches := d.getBatches(appIndex)
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0], 2)
+		for _, e := range batches[0] {
+			assert.EqualValues(t, appIndex, e.TransactionEvent.KinVersion%2)
+		}
+	}
+}
+
+func TestBatcher_Cancelled(t *testing.T) {
+	d := newTestDeliverer()
+	b := newBatcher(10, 50*time.Millisecond, d.deliver)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, b.add(ctx, 1, &app.Config{}, testEvent(0)))
+
+	// The event should still be delivered.
+	require.Eventually(t, func() bool {
+		return len(d.getBatches(1)) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+// testEvent returns a distinguishable event, using the kin version as the
+// identifier.
+func testEvent(i int) Event {
+	return Event{
+		TransactionEvent: &TransactionEvent{
+			KinVersion: i,
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound = errors.New("dead letter not found")
+)
+
+// DefaultSchedule is the default redelivery schedule of dead letters.
+//
+// Each duration is the delay, relative to the previous failed delivery,
+// before the next redelivery. Once the schedule is exhausted, the letter is
+// retained until it is replayed or deleted.
+var DefaultSchedule = []time.Duration{
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	48 * time.Hour,
+	72 * time.Hour,
+}
+
+// Letter is an events webhook delivery that failed.
+type Letter struct {
+	ID       string
+	AppIndex uint16
+
+	// Body is the original webhook payload.
+	Body []byte
+
+	// Error is the error of the last failed delivery.
+	Error string
+
+	// Attempts is the number of failed deliveries, including the original
+	// delivery.
+	Attempts int
+
+	CreatedAt     time.Time
+	LastAttemptAt time.Time
+
+	// NextAttemptAt is the time of the next scheduled redelivery. It is zero
+	// if the schedule has been exhausted.
+	NextAttemptAt time.Time
+}
+
+// New returns a new Letter for a payload whose delivery failed at the
+// specified time, with the first redelivery scheduled according to schedule.
+func New(appIndex uint16, body []byte, deliveryErr error, at time.Time, schedule []time.Duration) *Letter {
+	l := &Letter{
+		ID:        uuid.New().String(),
+		AppIndex:  appIndex,
+		Body:      body,
+		CreatedAt: at,
+	}
+	l.Fail(deliveryErr, at, schedule)
+	return l
+}
+
+// Fail records a failed delivery of the letter at the specified time, and
+// schedules the next redelivery, if any, according to schedule.
+func (l *Letter) Fail(deliveryErr error, at time.Time, schedule []time.Duration) {
+	l.Attempts++
+	l.LastAttemptAt = at
+	if deliveryErr != nil {
+		l.Error = deliveryErr.Error()
+	}
+
+	// The original delivery is not part of the schedule.
+	if l.Attempts-1 < len(schedule) {
+		l.NextAttemptAt = at.Add(schedule[l.Attempts-1])
+	} else {
+		l.NextAttemptAt = time.Time{}
+	}
+}
+
+// Exhausted returns whether or not the redelivery schedule of the letter has
+// been exhausted.
+func (l *Letter) Exhausted() bool {
+	return l.NextAttemptAt.IsZero()
+}
+
+// Store stores dead letters.
+type Store interface {
+	// Put creates or replaces a letter.
+	Put(ctx context.Context, l *Letter) error
+
+	// Get returns the letter with the specified id.
+	//
+	// ErrNotFound is returned if no such letter exists.
+	Get(ctx context.Context, appIndex uint16, id string) (*Letter, error)
+
+	// List returns the letters of the app, ordered by creation time.
+	List(ctx context.Context, appIndex uint16) ([]*Letter, error)
+
+	// GetDue returns up to limit letters (of any app) whose next redelivery
+	// is at or before the specified time, ordered by next redelivery time.
+	// Exhausted letters are never due.
+	GetDue(ctx context.Context, at time.Time, limit int) ([]*Letter, error)
+
+	// Delete deletes the letter with the specified id, if it exists.
+	Delete(ctx context.Context, appIndex uint16, id string) error
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+)
+
+type key struct {
+	appIndex uint16
+	id       string
+}
+
+type memory struct {
+	sync.Mutex
+	letters map[key]*deadletter.Letter
+}
+
+// New returns an in-memory deadletter.Store.
+func New() deadletter.Store {
+	return &memory{
+		letters: make(map[key]*deadletter.Letter),
+	}
+}
+
+func (m *memory) reset() {
+	m.Lock()
+	m.letters = make(map[key]*deadletter.Letter)
+	m.Unlock()
+}
+
+// Put implements deadletter.Store.Put.
+func (m *memory) Put(_ context.Context, l *deadletter.Letter) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.letters[key{appIndex: l.AppIndex, id: l.ID}] = clone(l)
+	return nil
+}
+
+// Get implements deadletter.Store.Get.
+func (m *memory) Get(_ context.Context, appIndex uint16, id string) (*deadletter.Letter, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	l, ok := m.letters[key{appIndex: appIndex, id: id}]
+	if !ok {
+		return nil, deadletter.ErrNotFound
+	}
+
+	return clone(l), nil
+}
+
+// List implements deadletter.Store.List.
+func (m *memory) List(_ context.Context, appIndex uint16) ([]*deadletter.Letter, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var letters []*deadletter.Letter
+	for k, l := range m.letters {
+		if k.appIndex == appIndex {
+			letters = append(letters, clone(l))
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].CreatedAt.Before(letters[j].CreatedAt)
+	})
+	return letters, nil
+}
+
+// GetDue implements deadletter.Store.GetDue.
+func (m *memory) GetDue(_ context.Context, at time.Time, limit int) ([]*deadletter.Letter, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var letters []*deadletter.Letter
+	for _, l := range m.letters {
+		if !l.Exhausted() && !l.NextAttemptAt.After(at) {
+			letters = append(letters, clone(l))
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].NextAttemptAt.Before(letters[j].NextAttemptAt)
+	})
+	if len(letters) > limit {
+		letters = letters[:limit]
+	}
+	return letters, nil
+}
+
+// Delete implements deadletter.Store.Delete.
+func (m *memory) Delete(_ context.Context, appIndex uint16, id string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.letters, key{appIndex: appIndex, id: id})
+	return nil
+}
+
+func clone(l *deadletter.Letter) *deadletter.Letter {
+	cloned := *l
+	cloned.Body = append([]byte(nil), l.Body...)
+	return &cloned
+}
@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter/tests"
+)
+
+func TestStore(t *testing.T) {
+	testStore := New()
+	teardown := func() {
+		testStore.(*memory).reset()
+	}
+
+	tests.RunTests(t, testStore, teardown)
+}
@@ -0,0 +1,218 @@
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/kinecosystem/agora-common/retry"
+	"github.com/kinecosystem/agora-common/retry/backoff"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kinecosystem/agora/pkg/app"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
+	"github.com/kinecosystem/agora/pkg/webhook"
+)
+
+const defaultRedeliveryBatchSize = 100
+
+var (
+	redeliveredCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "webhook_dead_letters_redelivered",
+		Help:      "Number of dead letters that were successfully redelivered",
+	})
+	redeliveryFailedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "webhook_dead_letters_redelivery_failed",
+		Help:      "Number of failed dead letter redeliveries",
+	})
+	exhaustedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agora",
+		Name:      "webhook_dead_letters_exhausted",
+		Help:      "Number of dead letters whose redelivery schedule was exhausted",
+	})
+)
+
+func init() {
+	if err := registerMetrics(); err != nil {
+		logrus.WithError(err).Error("failed to register dead letter metrics")
+	}
+}
+
+// Redeliverer redelivers dead letters according to their schedule.
+type Redeliverer struct {
+	log         *logrus.Entry
+	store       Store
+	configStore app.ConfigStore
+	client      *webhook.Client
+	lock        ingestion.DistributedLock
+	schedule    []time.Duration
+}
+
+// NewRedeliverer returns a new Redeliverer, which redelivers letters to the
+// events URL currently configured for their app. Scheduled redeliveries are
+// only performed while holding lock.
+//
+// If schedule is nil, DefaultSchedule is used.
+func NewRedeliverer(store Store, configStore app.ConfigStore, client *webhook.Client, lock ingestion.DistributedLock, schedule []time.Duration) *Redeliverer {
+	if schedule == nil {
+		schedule = DefaultSchedule
+	}
+
+	return &Redeliverer{
+		log:         logrus.StandardLogger().WithField("type", "webhook/events/deadletter"),
+		store:       store,
+		configStore: configStore,
+		client:      client,
+		lock:        lock,
+		schedule:    schedule,
+	}
+}
+
+// Run redelivers due letters every interval, until the context is cancelled.
+//
+// Run blocks until the lock is acquired, so that letters are only redelivered
+// by a single node at a time.
+func (r *Redeliverer) Run(ctx context.Context, interval time.Duration) error {
+	log := r.log.WithField("method", "Run")
+
+	_, err := retry.Retry(
+		func() error {
+			return r.lock.Lock(ctx)
+		},
+		retry.NonRetriableErrors(context.Canceled),
+		retry.BackoffWithJitter(backoff.Constant(5*time.Second), 5*time.Second, 0.1),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.lock.Unlock(); err != nil {
+			log.WithError(err).Warn("Failed to release lock")
+		}
+	}()
+
+	for {
+		if err := r.redeliverDue(ctx); err == context.Canceled {
+			return err
+		} else if err != nil {
+			log.WithError(err).Warn("failed to redeliver dead letters")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (r *Redeliverer) redeliverDue(ctx context.Context) error {
+	for {
+		letters, err := r.store.GetDue(ctx, time.Now(), defaultRedeliveryBatchSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to get due letters")
+		}
+
+		for _, l := range letters {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if _, err := r.Redeliver(ctx, l); err != nil {
+				return err
+			}
+		}
+
+		// Letters that fail are rescheduled, so they're no longer due.
+		if len(letters) < defaultRedeliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+// Redeliver immediately attempts to deliver the letter. If the delivery
+// succeeds, the letter is deleted. Otherwise, the failure is recorded, and
+// the next redelivery is scheduled.
+//
+// An error is only returned if the store could not be updated.
+func (r *Redeliverer) Redeliver(ctx context.Context, l *Letter) (delivered bool, err error) {
+	log := r.log.WithFields(logrus.Fields{
+		"method":    "Redeliver",
+		"app_index": l.AppIndex,
+		"id":        l.ID,
+		"attempts":  l.Attempts,
+	})
+
+	deliveryErr := r.deliver(ctx, l)
+	if deliveryErr == nil {
+		if err := r.store.Delete(ctx, l.AppIndex, l.ID); err != nil {
+			return true, errors.Wrap(err, "failed to delete redelivered letter")
+		}
+
+		redeliveredCounter.Inc()
+		log.Debug("Redelivered dead letter")
+		return true, nil
+	}
+
+	redeliveryFailedCounter.Inc()
+	log.WithError(deliveryErr).Info("Failed to redeliver dead letter")
+
+	l.Fail(deliveryErr, time.Now(), r.schedule)
+	if l.Exhausted() {
+		exhaustedCounter.Inc()
+		log.Warn("Dead letter redelivery schedule exhausted")
+	}
+
+	if err := r.store.Put(ctx, l); err != nil {
+		return false, errors.Wrap(err, "failed to update letter")
+	}
+
+	return false, nil
+}
+
+func (r *Redeliverer) deliver(ctx context.Context, l *Letter) error {
+	conf, err := r.configStore.Get(ctx, l.AppIndex)
+	if err == app.ErrNotFound {
+		return errors.New("app not found")
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to load config for %d", l.AppIndex)
+	}
+	if conf.EventsURL == nil {
+		return errors.New("app has no events url configured")
+	}
+
+	return r.client.Events(ctx, *conf.EventsURL, conf.WebhookSecret, l.Body)
+}
+
+func registerMetrics() error {
+	if err := prometheus.Register(redeliveredCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			redeliveredCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			return errors.Wrap(err, "failed to register redelivered counter")
+		}
+	}
+
+	if err := prometheus.Register(redeliveryFailedCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			redeliveryFailedCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			return errors.Wrap(err, "failed to register redelivery failed counter")
+		}
+	}
+
+	if err := prometheus.Register(exhaustedCounter); err != nil {
+		if e, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			exhaustedCounter = e.ExistingCollector.(prometheus.Counter)
+		} else {
+			return errors.Wrap(err, "failed to register exhausted counter")
+		}
+	}
+
+	return nil
+}
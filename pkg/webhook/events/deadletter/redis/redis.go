@@ -0,0 +1,293 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+)
+
+const (
+	dueKey = "deadletter:{events}:due"
+
+	// The redelivery schedule is exhausted after about a week, so by default
+	// letters are retained for a week after their last delivery attempt.
+	defaultTTL              = 7 * 24 * time.Hour
+	defaultMaxLettersPerApp = 10000
+)
+
+type store struct {
+	client    redis.Cmdable
+	ttl       time.Duration
+	maxPerApp int64
+}
+
+// Option configures a store.
+type Option func(s *store)
+
+// WithTTL specifies how long letters are retained after they were last put.
+//
+// If none is provided, a week is used.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *store) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// WithMaxLettersPerApp specifies the maximum number of letters retained per
+// app. Once exceeded, the oldest letters of the app are deleted.
+//
+// If none is provided, 10000 is used.
+func WithMaxLettersPerApp(n int) Option {
+	return func(s *store) {
+		if n > 0 {
+			s.maxPerApp = int64(n)
+		}
+	}
+}
+
+// New returns a redis backed deadletter.Store.
+//
+// Each letter is stored in its own key, which expires once the letter has
+// not been put for the configured TTL. Letters are indexed by a sorted set of
+// letter ids per app (scored by creation time), and a sorted set of letters
+// scored by their next redelivery time. Index entries of expired letters are
+// removed as they are encountered. All keys share a hash tag so that they can
+// be updated atomically in a cluster.
+func New(client redis.Cmdable, opts ...Option) deadletter.Store {
+	s := &store{
+		client:    client,
+		ttl:       defaultTTL,
+		maxPerApp: defaultMaxLettersPerApp,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+type model struct {
+	ID            string    `json:"id"`
+	AppIndex      uint16    `json:"app_index"`
+	Body          []byte    `json:"body"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// Put implements deadletter.Store.Put.
+func (s *store) Put(ctx context.Context, l *deadletter.Letter) error {
+	b, err := json.Marshal(&model{
+		ID:            l.ID,
+		AppIndex:      l.AppIndex,
+		Body:          l.Body,
+		Error:         l.Error,
+		Attempts:      l.Attempts,
+		CreatedAt:     l.CreatedAt,
+		LastAttemptAt: l.LastAttemptAt,
+		NextAttemptAt: l.NextAttemptAt,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal letter")
+	}
+
+	field := letterField(l.AppIndex, l.ID)
+	_, err = s.client.TxPipelined(func(p redis.Pipeliner) error {
+		p.Set(letterKey(field), b, s.ttl)
+		p.ZAdd(appKey(l.AppIndex), &redis.Z{Score: float64(l.CreatedAt.UnixNano()), Member: l.ID})
+		p.Expire(appKey(l.AppIndex), s.ttl)
+		if l.Exhausted() {
+			p.ZRem(dueKey, field)
+		} else {
+			p.ZAdd(dueKey, &redis.Z{Score: float64(l.NextAttemptAt.UnixNano()), Member: field})
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to put letter")
+	}
+
+	return s.evict(ctx, l.AppIndex)
+}
+
+// evict deletes the oldest letters of the app, if it has more than the
+// maximum number of letters.
+func (s *store) evict(ctx context.Context, appIndex uint16) error {
+	n, err := s.client.ZCard(appKey(appIndex)).Result()
+	if err != nil {
+		return errors.Wrap(err, "failed to count letters")
+	}
+	if n <= s.maxPerApp {
+		return nil
+	}
+
+	ids, err := s.client.ZRange(appKey(appIndex), 0, n-s.maxPerApp-1).Result()
+	if err != nil {
+		return errors.Wrap(err, "failed to get oldest letters")
+	}
+	for _, id := range ids {
+		if err := s.Delete(ctx, appIndex, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get implements deadletter.Store.Get.
+func (s *store) Get(_ context.Context, appIndex uint16, id string) (*deadletter.Letter, error) {
+	b, err := s.client.Get(letterKey(letterField(appIndex, id))).Bytes()
+	if err == redis.Nil {
+		return nil, deadletter.ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get letter")
+	}
+
+	return fromJSON(b)
+}
+
+// List implements deadletter.Store.List.
+func (s *store) List(_ context.Context, appIndex uint16) ([]*deadletter.Letter, error) {
+	ids, err := s.client.ZRange(appKey(appIndex), 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list letters")
+	}
+
+	fields := make([]string, len(ids))
+	for i, id := range ids {
+		fields[i] = letterField(appIndex, id)
+	}
+
+	return s.getLetters(fields)
+}
+
+// GetDue implements deadletter.Store.GetDue.
+func (s *store) GetDue(_ context.Context, at time.Time, limit int) ([]*deadletter.Letter, error) {
+	fields, err := s.client.ZRangeByScore(dueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(at.UnixNano(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get due letters")
+	}
+
+	return s.getLetters(fields)
+}
+
+// Delete implements deadletter.Store.Delete.
+func (s *store) Delete(_ context.Context, appIndex uint16, id string) error {
+	field := letterField(appIndex, id)
+	_, err := s.client.TxPipelined(func(p redis.Pipeliner) error {
+		p.Del(letterKey(field))
+		p.ZRem(appKey(appIndex), id)
+		p.ZRem(dueKey, field)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete letter")
+	}
+
+	return nil
+}
+
+func (s *store) getLetters(fields []string) ([]*deadletter.Letter, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = letterKey(field)
+	}
+
+	values, err := s.client.MGet(keys...).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get letters")
+	}
+
+	letters := make([]*deadletter.Letter, 0, len(values))
+	for i, v := range values {
+		// The letter may have expired, or been deleted concurrently, in which
+		// case it is removed from the indexes.
+		str, ok := v.(string)
+		if !ok {
+			if err := s.removeIndexes(fields[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		l, err := fromJSON([]byte(str))
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, l)
+	}
+
+	return letters, nil
+}
+
+func (s *store) removeIndexes(field string) error {
+	parts := strings.SplitN(field, "/", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid letter field: %s", field)
+	}
+	appIndex, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return errors.Wrapf(err, "invalid letter field: %s", field)
+	}
+
+	_, err = s.client.TxPipelined(func(p redis.Pipeliner) error {
+		p.ZRem(appKey(uint16(appIndex)), parts[1])
+		p.ZRem(dueKey, field)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to remove expired letter from indexes")
+	}
+
+	return nil
+}
+
+func fromJSON(b []byte) (*deadletter.Letter, error) {
+	var m model
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal letter")
+	}
+
+	return &deadletter.Letter{
+		ID:            m.ID,
+		AppIndex:      m.AppIndex,
+		Body:          m.Body,
+		Error:         m.Error,
+		Attempts:      m.Attempts,
+		CreatedAt:     m.CreatedAt,
+		LastAttemptAt: m.LastAttemptAt,
+		NextAttemptAt: m.NextAttemptAt,
+	}, nil
+}
+
+func appKey(appIndex uint16) string {
+	return fmt.Sprintf("deadletter:{events}:app:%d", appIndex)
+}
+
+func letterKey(field string) string {
+	return fmt.Sprintf("deadletter:{events}:letter:%s", field)
+}
+
+func letterField(appIndex uint16, id string) string {
+	return strings.Join([]string{strconv.Itoa(int(appIndex)), id}, "/")
+}
@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	redistest "github.com/kinecosystem/agora-common/redis/test"
+	"github.com/ory/dockertest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter/tests"
+)
+
+var (
+	redisConnString string
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	redisConnString, cleanUpFunc, err = redistest.StartRedis(context.Background(), pool)
+	if err != nil {
+		log.WithError(err).Error("Error starting redis connection")
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestStore(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: redisConnString,
+	})
+	tests.RunTests(t, New(client), func() {
+		client.FlushAll()
+	})
+}
+
+func TestStore_TTL(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: redisConnString,
+	})
+	defer client.FlushAll()
+
+	s := New(client, WithTTL(time.Second))
+
+	l := deadletter.New(1, []byte("body"), errors.New("error"), time.Now().Add(-time.Hour), []time.Duration{time.Minute})
+	require.NoError(t, s.Put(context.Background(), l))
+
+	ttl, err := client.TTL(letterKey(letterField(1, l.ID))).Result()
+	require.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Second)
+
+	// Once the letter expires, it should be removed from the indexes.
+	require.Eventually(t, func() bool {
+		_, err := s.Get(context.Background(), 1, l.ID)
+		return err == deadletter.ErrNotFound
+	}, 5*time.Second, 100*time.Millisecond)
+
+	due, err := s.GetDue(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	n, err := client.ZCard(dueKey).Result()
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestStore_MaxLettersPerApp(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr: redisConnString,
+	})
+	defer client.FlushAll()
+
+	s := New(client, WithMaxLettersPerApp(2))
+
+	now := time.Now()
+	var letters []*deadletter.Letter
+	for i := 0; i < 3; i++ {
+		l := deadletter.New(1, []byte("body"), errors.New("error"), now.Add(time.Duration(i)*time.Second), []time.Duration{time.Minute})
+		require.NoError(t, s.Put(context.Background(), l))
+		letters = append(letters, l)
+	}
+	require.NoError(t, s.Put(context.Background(), deadletter.New(2, []byte("body"), errors.New("error"), now, []time.Duration{time.Minute})))
+
+	// The oldest letter of the app should have been evicted.
+	listed, err := s.List(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, letters[1].ID, listed[0].ID)
+	assert.Equal(t, letters[2].ID, listed[1].ID)
+
+	_, err = s.Get(context.Background(), 1, letters[0].ID)
+	assert.Equal(t, deadletter.ErrNotFound, err)
+
+	listed, err = s.List(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
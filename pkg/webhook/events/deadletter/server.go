@@ -0,0 +1,141 @@
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	deadletterpb "github.com/kinecosystem/agora/pkg/webhook/events/deadletter/proto"
+)
+
+type server struct {
+	log         *logrus.Entry
+	store       Store
+	redeliverer *Redeliverer
+}
+
+// NewServer returns a deadletterpb.AdminServer backed by the provided store.
+func NewServer(store Store, redeliverer *Redeliverer) deadletterpb.AdminServer {
+	return &server{
+		log:         logrus.StandardLogger().WithField("type", "webhook/events/deadletter/server"),
+		store:       store,
+		redeliverer: redeliverer,
+	}
+}
+
+// ListDeadLetters implements deadletterpb.AdminServer.ListDeadLetters.
+func (s *server) ListDeadLetters(ctx context.Context, req *deadletterpb.ListDeadLettersRequest) (*deadletterpb.ListDeadLettersResponse, error) {
+	letters, err := s.store.List(ctx, uint16(req.AppIndex))
+	if err != nil {
+		s.log.WithError(err).Warn("failed to list dead letters")
+		return nil, status.Error(codes.Internal, "failed to list dead letters")
+	}
+
+	resp := &deadletterpb.ListDeadLettersResponse{
+		DeadLetters: make([]*deadletterpb.DeadLetter, len(letters)),
+	}
+	for i, l := range letters {
+		if resp.DeadLetters[i], err = toProto(l); err != nil {
+			s.log.WithError(err).Warn("failed to marshal dead letter")
+			return nil, status.Error(codes.Internal, "failed to marshal dead letter")
+		}
+	}
+
+	return resp, nil
+}
+
+// Replay implements deadletterpb.AdminServer.Replay.
+func (s *server) Replay(ctx context.Context, req *deadletterpb.ReplayRequest) (*deadletterpb.ReplayResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"app_index": req.AppIndex,
+		"id":        req.Id,
+	})
+
+	l, err := s.store.Get(ctx, uint16(req.AppIndex), req.Id)
+	if err == ErrNotFound {
+		return &deadletterpb.ReplayResponse{Result: deadletterpb.ReplayResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failed to get dead letter")
+		return nil, status.Error(codes.Internal, "failed to get dead letter")
+	}
+
+	delivered, err := s.redeliverer.Redeliver(ctx, l)
+	if err != nil {
+		log.WithError(err).Warn("failed to replay dead letter")
+		return nil, status.Error(codes.Internal, "failed to replay dead letter")
+	}
+	if delivered {
+		log.Info("replayed dead letter")
+		return &deadletterpb.ReplayResponse{}, nil
+	}
+
+	letter, err := toProto(l)
+	if err != nil {
+		log.WithError(err).Warn("failed to marshal dead letter")
+		return nil, status.Error(codes.Internal, "failed to marshal dead letter")
+	}
+
+	return &deadletterpb.ReplayResponse{
+		Result:     deadletterpb.ReplayResponse_FAILED,
+		DeadLetter: letter,
+	}, nil
+}
+
+// Delete implements deadletterpb.AdminServer.Delete.
+func (s *server) Delete(ctx context.Context, req *deadletterpb.DeleteRequest) (*deadletterpb.DeleteResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"app_index": req.AppIndex,
+		"id":        req.Id,
+	})
+
+	if _, err := s.store.Get(ctx, uint16(req.AppIndex), req.Id); err == ErrNotFound {
+		return &deadletterpb.DeleteResponse{Result: deadletterpb.DeleteResponse_NOT_FOUND}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failed to get dead letter")
+		return nil, status.Error(codes.Internal, "failed to get dead letter")
+	}
+
+	if err := s.store.Delete(ctx, uint16(req.AppIndex), req.Id); err != nil {
+		log.WithError(err).Warn("failed to delete dead letter")
+		return nil, status.Error(codes.Internal, "failed to delete dead letter")
+	}
+
+	log.Info("deleted dead letter")
+	return &deadletterpb.DeleteResponse{}, nil
+}
+
+func toProto(l *Letter) (*deadletterpb.DeadLetter, error) {
+	letter := &deadletterpb.DeadLetter{
+		Id:       l.ID,
+		AppIndex: uint32(l.AppIndex),
+		Body:     l.Body,
+		Error:    l.Error,
+		Attempts: uint32(l.Attempts),
+	}
+
+	var err error
+	if letter.CreatedAt, err = timestampProto(l.CreatedAt); err != nil {
+		return nil, err
+	}
+	if letter.LastAttemptAt, err = timestampProto(l.LastAttemptAt); err != nil {
+		return nil, err
+	}
+	if letter.NextAttemptAt, err = timestampProto(l.NextAttemptAt); err != nil {
+		return nil, err
+	}
+
+	return letter, nil
+}
+
+func timestampProto(t time.Time) (*timestamp.Timestamp, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return ptypes.TimestampProto(t)
+}
@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/app"
+	appmemory "github.com/kinecosystem/agora/pkg/app/memory"
+	"github.com/kinecosystem/agora/pkg/webhook"
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter/memory"
+	deadletterpb "github.com/kinecosystem/agora/pkg/webhook/events/deadletter/proto"
+)
+
+type testWebhook struct {
+	sync.Mutex
+	status   int
+	received [][]byte
+}
+
+func (w *testWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.Lock()
+	defer w.Unlock()
+
+	b, _ := ioutil.ReadAll(r.Body)
+	w.received = append(w.received, b)
+	rw.WriteHeader(w.status)
+}
+
+func (w *testWebhook) setStatus(status int) {
+	w.Lock()
+	w.status = status
+	w.Unlock()
+}
+
+func (w *testWebhook) getReceived() [][]byte {
+	w.Lock()
+	defer w.Unlock()
+	return append([][]byte(nil), w.received...)
+}
+
+// testLock is a DistributedLock that is shared by redeliverers of the same
+// test.
+type testLock struct {
+	ch chan struct{}
+}
+
+func newTestLock() *testLock {
+	return &testLock{ch: make(chan struct{}, 1)}
+}
+
+func (l *testLock) Lock(ctx context.Context) error {
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *testLock) Unlock() error {
+	select {
+	case <-l.ch:
+	default:
+	}
+	return nil
+}
+
+type testEnv struct {
+	store       deadletter.Store
+	configStore app.ConfigStore
+	webhook     *testWebhook
+	lock        *testLock
+	redeliverer *deadletter.Redeliverer
+}
+
+func setup(t *testing.T) (env testEnv, teardown func()) {
+	env.store = memory.New()
+	env.configStore = appmemory.New()
+	env.webhook = &testWebhook{status: http.StatusOK}
+	env.lock = newTestLock()
+
+	server := httptest.NewServer(env.webhook)
+	eventsURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, env.configStore.Add(context.Background(), 1, &app.Config{
+		AppName:       "app1",
+		EventsURL:     eventsURL,
+		WebhookSecret: "secret",
+	}))
+	require.NoError(t, env.configStore.Add(context.Background(), 2, &app.Config{
+		AppName: "app2",
+	}))
+
+	env.redeliverer = deadletter.NewRedeliverer(env.store, env.configStore, webhook.NewClient(http.DefaultClient), env.lock, []time.Duration{time.Hour, time.Hour})
+	return env, server.Close
+}
+
+func TestRedeliverer(t *testing.T) {
+	env, teardown := setup(t)
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	due := deadletter.New(1, []byte("due"), errors.New("error"), now.Add(-2*time.Hour), []time.Duration{time.Hour})
+	notDue := deadletter.New(1, []byte("notdue"), errors.New("error"), now, []time.Duration{time.Hour})
+	noURL := deadletter.New(2, []byte("nourl"), errors.New("error"), now.Add(-2*time.Hour), []time.Duration{time.Hour, time.Hour})
+	for _, l := range []*deadletter.Letter{due, notDue, noURL} {
+		require.NoError(t, env.store.Put(ctx, l))
+	}
+
+	go func() {
+		_ = env.redeliverer.Run(ctx, 10*time.Millisecond)
+	}()
+
+	// The due letter should be delivered, and removed.
+	require.Eventually(t, func() bool {
+		_, err := env.store.Get(ctx, 1, due.ID)
+		return err == deadletter.ErrNotFound
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]byte{[]byte("due")}, env.webhook.getReceived())
+
+	_, err := env.store.Get(ctx, 1, notDue.ID)
+	assert.NoError(t, err)
+
+	// The letter for the app without an events url should be rescheduled.
+	require.Eventually(t, func() bool {
+		l, err := env.store.Get(ctx, 2, noURL.ID)
+		require.NoError(t, err)
+		return l.Attempts == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	l, err := env.store.Get(ctx, 2, noURL.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "app has no events url configured", l.Error)
+	assert.True(t, l.NextAttemptAt.After(now))
+}
+
+func TestRedeliverer_Lock(t *testing.T) {
+	env, teardown := setup(t)
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	due := deadletter.New(1, []byte("due"), errors.New("error"), time.Now().Add(-2*time.Hour), []time.Duration{time.Hour})
+	require.NoError(t, env.store.Put(ctx, due))
+
+	// Letters should not be redelivered while another node holds the lock.
+	require.NoError(t, env.lock.Lock(ctx))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- env.redeliverer.Run(ctx, 10*time.Millisecond)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, env.webhook.getReceived())
+
+	require.NoError(t, env.lock.Unlock())
+	require.Eventually(t, func() bool {
+		_, err := env.store.Get(ctx, 1, due.ID)
+		return err == deadletter.ErrNotFound
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]byte{[]byte("due")}, env.webhook.getReceived())
+
+	// The lock should be released once Run returns.
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.NoError(t, env.lock.Lock(context.Background()))
+}
+
+func TestRedeliver_Exhausted(t *testing.T) {
+	env, teardown := setup(t)
+	defer teardown()
+
+	env.webhook.setStatus(http.StatusServiceUnavailable)
+
+	l := deadletter.New(1, []byte("body"), errors.New("error"), time.Now(), []time.Duration{time.Hour})
+	l.Fail(errors.New("error"), time.Now(), []time.Duration{time.Hour, time.Hour})
+	require.NoError(t, env.store.Put(context.Background(), l))
+
+	delivered, err := env.redeliverer.Redeliver(context.Background(), l)
+	require.NoError(t, err)
+	assert.False(t, delivered)
+
+	// The schedule has been exhausted, so the letter is retained, but is no
+	// longer due.
+	stored, err := env.store.Get(context.Background(), 1, l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Attempts)
+	assert.True(t, stored.Exhausted())
+	assert.Equal(t, "webhook error: 503", stored.Error)
+
+	due, err := env.store.GetDue(context.Background(), time.Now().Add(time.Hour*24*365), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestServer(t *testing.T) {
+	env, teardown := setup(t)
+	defer teardown()
+
+	s := deadletter.NewServer(env.store, env.redeliverer)
+	ctx := context.Background()
+
+	listResp, err := s.ListDeadLetters(ctx, &deadletterpb.ListDeadLettersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Empty(t, listResp.DeadLetters)
+
+	now := time.Now()
+	letters := []*deadletter.Letter{
+		deadletter.New(1, []byte("first"), errors.New("error"), now.Add(-time.Minute), deadletter.DefaultSchedule),
+		deadletter.New(1, []byte("second"), errors.New("error"), now, nil),
+	}
+	for _, l := range letters {
+		require.NoError(t, env.store.Put(ctx, l))
+	}
+
+	listResp, err = s.ListDeadLetters(ctx, &deadletterpb.ListDeadLettersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	require.Len(t, listResp.DeadLetters, 2)
+	for i, l := range listResp.DeadLetters {
+		assert.Equal(t, letters[i].ID, l.Id)
+		assert.EqualValues(t, 1, l.AppIndex)
+		assert.Equal(t, letters[i].Body, l.Body)
+		assert.Equal(t, "error", l.Error)
+		assert.EqualValues(t, 1, l.Attempts)
+		assert.NotNil(t, l.CreatedAt)
+		assert.NotNil(t, l.LastAttemptAt)
+	}
+	assert.NotNil(t, listResp.DeadLetters[0].NextAttemptAt)
+	assert.Nil(t, listResp.DeadLetters[1].NextAttemptAt)
+
+	// Failed replays should be rescheduled.
+	env.webhook.setStatus(http.StatusInternalServerError)
+	replayResp, err := s.Replay(ctx, &deadletterpb.ReplayRequest{AppIndex: 1, Id: letters[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, deadletterpb.ReplayResponse_FAILED, replayResp.Result)
+	assert.EqualValues(t, 2, replayResp.DeadLetter.Attempts)
+	assert.Equal(t, "webhook error: 500", replayResp.DeadLetter.Error)
+
+	// Successful replays remove the letter.
+	env.webhook.setStatus(http.StatusOK)
+	replayResp, err = s.Replay(ctx, &deadletterpb.ReplayRequest{AppIndex: 1, Id: letters[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, deadletterpb.ReplayResponse_OK, replayResp.Result)
+	assert.Nil(t, replayResp.DeadLetter)
+	assert.Equal(t, [][]byte{[]byte("second"), []byte("second")}, env.webhook.getReceived())
+
+	replayResp, err = s.Replay(ctx, &deadletterpb.ReplayRequest{AppIndex: 1, Id: letters[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, deadletterpb.ReplayResponse_NOT_FOUND, replayResp.Result)
+
+	// Letters are scoped by app.
+	deleteResp, err := s.Delete(ctx, &deadletterpb.DeleteRequest{AppIndex: 2, Id: letters[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, deadletterpb.DeleteResponse_NOT_FOUND, deleteResp.Result)
+
+	deleteResp, err = s.Delete(ctx, &deadletterpb.DeleteRequest{AppIndex: 1, Id: letters[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, deadletterpb.DeleteResponse_OK, deleteResp.Result)
+
+	listResp, err = s.ListDeadLetters(ctx, &deadletterpb.ListDeadLettersRequest{AppIndex: 1})
+	require.NoError(t, err)
+	assert.Empty(t, listResp.DeadLetters)
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+)
+
+func RunTests(t *testing.T, store deadletter.Store, teardown func()) {
+	for _, tf := range []func(*testing.T, deadletter.Store){testRoundTrip, testList, testGetDue} {
+		tf(t, store)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, store deadletter.Store) {
+	t.Run("TestRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now().Truncate(time.Millisecond)
+
+		l := deadletter.New(1, []byte(`[{"transaction_event":{}}]`), errors.New("webhook error: 503"), now, deadletter.DefaultSchedule)
+
+		_, err := store.Get(ctx, 1, l.ID)
+		assert.Equal(t, deadletter.ErrNotFound, err)
+
+		require.NoError(t, store.Put(ctx, l))
+
+		actual, err := store.Get(ctx, 1, l.ID)
+		require.NoError(t, err)
+		assertLetter(t, l, actual)
+
+		// Letters are scoped by app.
+		_, err = store.Get(ctx, 2, l.ID)
+		assert.Equal(t, deadletter.ErrNotFound, err)
+
+		// Put should replace the letter.
+		l.Fail(errors.New("webhook error: 500"), now.Add(time.Hour), deadletter.DefaultSchedule)
+		require.NoError(t, store.Put(ctx, l))
+
+		actual, err = store.Get(ctx, 1, l.ID)
+		require.NoError(t, err)
+		assertLetter(t, l, actual)
+		assert.Equal(t, 2, actual.Attempts)
+		assert.Equal(t, "webhook error: 500", actual.Error)
+
+		require.NoError(t, store.Delete(ctx, 1, l.ID))
+		_, err = store.Get(ctx, 1, l.ID)
+		assert.Equal(t, deadletter.ErrNotFound, err)
+
+		// Deleting a letter that doesn't exist is a no-op.
+		require.NoError(t, store.Delete(ctx, 1, l.ID))
+	})
+}
+
+func testList(t *testing.T, store deadletter.Store) {
+	t.Run("TestList", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now().Truncate(time.Millisecond)
+
+		letters, err := store.List(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+
+		var expected []*deadletter.Letter
+		for i := 0; i < 5; i++ {
+			l := deadletter.New(1, []byte{byte(i)}, errors.New("error"), now.Add(time.Duration(i)*time.Second), deadletter.DefaultSchedule)
+			expected = append(expected, l)
+		}
+		other := deadletter.New(2, []byte("other"), errors.New("error"), now, deadletter.DefaultSchedule)
+
+		// Insert out of order.
+		for _, i := range []int{3, 1, 4, 0, 2} {
+			require.NoError(t, store.Put(ctx, expected[i]))
+		}
+		require.NoError(t, store.Put(ctx, other))
+
+		letters, err = store.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, letters, len(expected))
+		for i := range expected {
+			assertLetter(t, expected[i], letters[i])
+		}
+
+		letters, err = store.List(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assertLetter(t, other, letters[0])
+	})
+}
+
+func testGetDue(t *testing.T, store deadletter.Store) {
+	t.Run("TestGetDue", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now().Truncate(time.Millisecond)
+		schedule := []time.Duration{time.Minute}
+
+		// Due in 1, 2 and 3 minutes respectively.
+		var letters []*deadletter.Letter
+		for i := 0; i < 3; i++ {
+			l := deadletter.New(uint16(i), []byte{byte(i)}, errors.New("error"), now.Add(time.Duration(i)*time.Minute), schedule)
+			letters = append(letters, l)
+			require.NoError(t, store.Put(ctx, l))
+		}
+
+		// Exhausted letters are never due.
+		exhausted := deadletter.New(1, []byte("exhausted"), errors.New("error"), now, nil)
+		require.True(t, exhausted.Exhausted())
+		require.NoError(t, store.Put(ctx, exhausted))
+
+		due, err := store.GetDue(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		due, err = store.GetDue(ctx, now.Add(2*time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assertLetter(t, letters[0], due[0])
+		assertLetter(t, letters[1], due[1])
+
+		due, err = store.GetDue(ctx, now.Add(time.Hour), 2)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assertLetter(t, letters[0], due[0])
+		assertLetter(t, letters[1], due[1])
+
+		// Rescheduling a letter should remove it from the due letters.
+		letters[0].Fail(errors.New("error"), now.Add(time.Hour), []time.Duration{time.Minute, time.Hour})
+		require.NoError(t, store.Put(ctx, letters[0]))
+
+		due, err = store.GetDue(ctx, now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assertLetter(t, letters[1], due[0])
+		assertLetter(t, letters[2], due[1])
+
+		// As should deleting it.
+		require.NoError(t, store.Delete(ctx, letters[1].AppIndex, letters[1].ID))
+
+		due, err = store.GetDue(ctx, now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assertLetter(t, letters[2], due[0])
+	})
+}
+
+func assertLetter(t *testing.T, expected, actual *deadletter.Letter) {
+	assert.Equal(t, expected.ID, actual.ID)
+	assert.Equal(t, expected.AppIndex, actual.AppIndex)
+	assert.Equal(t, expected.Body, actual.Body)
+	assert.Equal(t, expected.Error, actual.Error)
+	assert.Equal(t, expected.Attempts, actual.Attempts)
+	assert.True(t, expected.CreatedAt.Equal(actual.CreatedAt))
+	assert.True(t, expected.LastAttemptAt.Equal(actual.LastAttemptAt))
+	assert.True(t, expected.NextAttemptAt.Equal(actual.NextAttemptAt))
+}
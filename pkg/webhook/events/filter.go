@@ -0,0 +1,202 @@
+package events
+
+import (
+	"bytes"
+	"crypto/ed25519"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/kinecosystem/go/xdr"
+	"github.com/pkg/errors"
+
+	"github.com/kinecosystem/agora/pkg/app"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+// TokenAccountResolver resolves Solana token accounts. It is implemented by
+// token.Client.
+type TokenAccountResolver interface {
+	GetAccount(accountID ed25519.PublicKey, commitment solana.Commitment) (*token.Account, error)
+}
+
+// matchesFilters returns whether or not the entry matches any of the app's
+// event filters. If there are no filters, every entry matches.
+//
+// The owners of Solana destination accounts are only resolved if accounts
+// is set, and an address filter is not matched otherwise.
+func matchesFilters(filters []app.EventFilter, appIndex uint16, entry *model.Entry, accounts TokenAccountResolver) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+
+	payments, memos, err := entry.GetPaymentsAndMemos()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get payments and memos")
+	}
+
+	successful, err := isSuccessful(entry)
+	if err != nil {
+		return false, err
+	}
+
+	// Transactions attributed to the app by a text memo have no Kin memo for
+	// the app, and so have no type.
+	txTypes := make([]kin.TransactionType, 0, len(memos))
+	for _, m := range memos {
+		if m.AppIndex() == appIndex {
+			txTypes = append(txTypes, m.TransactionType())
+		}
+	}
+	if len(txTypes) == 0 {
+		txTypes = append(txTypes, kin.TransactionTypeNone)
+	}
+
+	owners := &ownerResolver{
+		accounts: accounts,
+		owners:   make(map[string]ed25519.PublicKey),
+	}
+	for i := range filters {
+		matched, err := matchesFilter(&filters[i], successful, txTypes, payments, owners)
+		if err != nil {
+			return false, err
+		} else if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchesFilter(f *app.EventFilter, successful bool, txTypes []kin.TransactionType, payments []model.Payment, owners *ownerResolver) (bool, error) {
+	switch f.Status {
+	case app.EventStatusSuccessful:
+		if !successful {
+			return false, nil
+		}
+	case app.EventStatusFailed:
+		if successful {
+			return false, nil
+		}
+	}
+
+	if len(f.TransactionTypes) > 0 {
+		var matched bool
+		for _, t := range txTypes {
+			if containsType(f.TransactionTypes, t) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if f.MinAmount == 0 && len(f.Addresses) == 0 {
+		return true, nil
+	}
+
+	for _, p := range payments {
+		if p.Amount < f.MinAmount {
+			continue
+		}
+		if len(f.Addresses) > 0 {
+			matched, err := involves(f.Addresses, p, owners)
+			if err != nil {
+				return false, err
+			} else if !matched {
+				continue
+			}
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func isSuccessful(entry *model.Entry) (bool, error) {
+	switch k := entry.Kind.(type) {
+	case *model.Entry_Stellar:
+		var result xdr.TransactionResult
+		if err := result.UnmarshalBinary(k.Stellar.ResultXdr); err != nil {
+			return false, errors.Wrap(err, "failed to unmarshal result")
+		}
+
+		return result.Result.Code == xdr.TransactionResultCodeTxSuccess, nil
+	case *model.Entry_Solana:
+		return len(k.Solana.TransactionError) == 0, nil
+	default:
+		return false, errors.New("unsupported entry type")
+	}
+}
+
+func containsType(types []kin.TransactionType, t kin.TransactionType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// involves returns whether or not the payment was sent to, sent from, or
+// authorized by one of the addresses. The destination of a Solana payment is
+// a token account, so its owner is resolved if no address matched otherwise.
+func involves(addresses []ed25519.PublicKey, p model.Payment, owners *ownerResolver) (bool, error) {
+	for _, a := range addresses {
+		if bytes.Equal(a, p.Source) || bytes.Equal(a, p.Destination) || bytes.Equal(a, p.Owner) {
+			return true, nil
+		}
+	}
+
+	// Only Solana payments have an owner.
+	if len(p.Owner) == 0 {
+		return false, nil
+	}
+
+	owner, err := owners.owner(p.Destination)
+	if err != nil || owner == nil {
+		return false, err
+	}
+	for _, a := range addresses {
+		if bytes.Equal(a, owner) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ownerResolver resolves the owners of token accounts, at most once per
+// account.
+type ownerResolver struct {
+	accounts TokenAccountResolver
+	owners   map[string]ed25519.PublicKey
+}
+
+// owner returns the owner of the token account, or nil if it is unknown.
+func (r *ownerResolver) owner(account ed25519.PublicKey) (ed25519.PublicKey, error) {
+	if r.accounts == nil {
+		return nil, nil
+	}
+	if owner, ok := r.owners[string(account)]; ok {
+		return owner, nil
+	}
+
+	var owner ed25519.PublicKey
+	info, err := r.accounts.GetAccount(account, solana.CommitmentSingle)
+	switch err {
+	case nil:
+		owner = info.Owner
+	case token.ErrAccountNotFound, token.ErrInvalidTokenAccount:
+	default:
+		return nil, errors.Wrap(err, "failed to get destination account")
+	}
+
+	r.owners[string(account)] = owner
+	return owner, nil
+}
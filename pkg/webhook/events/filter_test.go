@@ -0,0 +1,187 @@
+package events
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/solana"
+	"github.com/kinecosystem/agora-common/solana/memo"
+	"github.com/kinecosystem/agora-common/solana/token"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kinecosystem/agora/pkg/app"
+	"github.com/kinecosystem/agora/pkg/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+)
+
+func TestMatchesFilters(t *testing.T) {
+	sender := testutil.GenerateSolanaKeypair(t)
+	receiver := testutil.GenerateSolanaKeys(t, 1)[0]
+	other := testutil.GenerateSolanaKeys(t, 1)[0]
+
+	earn := generateFilterEntry(t, sender, receiver, kinMemo(t, kin.TransactionTypeEarn), 10, false)
+	failedSpend := generateFilterEntry(t, sender, receiver, kinMemo(t, kin.TransactionTypeSpend), 20, true)
+
+	for _, tc := range []struct {
+		filters []app.EventFilter
+		earn    bool
+		spend   bool
+	}{
+		{filters: nil, earn: true, spend: true},
+		{filters: []app.EventFilter{{}}, earn: true, spend: true},
+		{filters: []app.EventFilter{{Status: app.EventStatusSuccessful}}, earn: true},
+		{filters: []app.EventFilter{{Status: app.EventStatusFailed}}, spend: true},
+		{filters: []app.EventFilter{{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}}}, earn: true},
+		{filters: []app.EventFilter{{TransactionTypes: []kin.TransactionType{kin.TransactionTypeP2P}}}},
+		{filters: []app.EventFilter{{MinAmount: 15}}, spend: true},
+		{filters: []app.EventFilter{{MinAmount: 25}}},
+		{filters: []app.EventFilter{{Addresses: []ed25519.PublicKey{receiver}}}, earn: true, spend: true},
+		{filters: []app.EventFilter{{Addresses: []ed25519.PublicKey{sender.Public().(ed25519.PublicKey)}}}, earn: true, spend: true},
+		{filters: []app.EventFilter{{Addresses: []ed25519.PublicKey{other}}}},
+		{filters: []app.EventFilter{{Addresses: []ed25519.PublicKey{other, receiver}, MinAmount: 15}}, spend: true},
+		{
+			// Successful spends or any earn.
+			filters: []app.EventFilter{
+				{TransactionTypes: []kin.TransactionType{kin.TransactionTypeSpend}, Status: app.EventStatusSuccessful},
+				{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}},
+			},
+			earn: true,
+		},
+	} {
+		matched, err := matchesFilters(tc.filters, 1, earn, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.earn, matched, "%+v", tc.filters)
+
+		matched, err = matchesFilters(tc.filters, 1, failedSpend, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.spend, matched, "%+v", tc.filters)
+	}
+
+	// Memo types are only matched for the app's own memos.
+	matched, err := matchesFilters([]app.EventFilter{{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}}}, 2, earn, nil)
+	require.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestMatchesFilters_TextMemo(t *testing.T) {
+	sender := testutil.GenerateSolanaKeypair(t)
+	receiver := testutil.GenerateSolanaKeys(t, 1)[0]
+
+	// Transactions attributed to the app by a text memo have no type.
+	entry := generateFilterEntry(t, sender, receiver, "1-test-text", 10, false)
+
+	for _, tc := range []struct {
+		types   []kin.TransactionType
+		matched bool
+	}{
+		{types: []kin.TransactionType{kin.TransactionTypeNone}, matched: true},
+		{types: []kin.TransactionType{kin.TransactionTypeEarn, kin.TransactionTypeNone}, matched: true},
+		{types: []kin.TransactionType{kin.TransactionTypeEarn}},
+	} {
+		matched, err := matchesFilters([]app.EventFilter{{TransactionTypes: tc.types}}, 1, entry, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.matched, matched, "%v", tc.types)
+	}
+}
+
+func TestMatchesFilters_DestinationOwner(t *testing.T) {
+	sender := testutil.GenerateSolanaKeypair(t)
+	keys := testutil.GenerateSolanaKeys(t, 4)
+	receiver, owner, other, closed := keys[0], keys[1], keys[2], keys[3]
+
+	accounts := &tokenAccounts{
+		owners: map[string]ed25519.PublicKey{
+			string(receiver): owner,
+		},
+	}
+
+	entry := generateFilterEntry(t, sender, receiver, kinMemo(t, kin.TransactionTypeEarn), 10, false)
+	toClosed := generateFilterEntry(t, sender, closed, kinMemo(t, kin.TransactionTypeEarn), 10, false)
+
+	for _, tc := range []struct {
+		addresses []ed25519.PublicKey
+		accounts  TokenAccountResolver
+		entry     *model.Entry
+		matched   bool
+	}{
+		{addresses: []ed25519.PublicKey{owner}, accounts: accounts, entry: entry, matched: true},
+		{addresses: []ed25519.PublicKey{other, owner}, accounts: accounts, entry: entry, matched: true},
+		{addresses: []ed25519.PublicKey{other}, accounts: accounts, entry: entry},
+		{addresses: []ed25519.PublicKey{owner}, accounts: accounts, entry: toClosed},
+		// Without a resolver, only the token account is matched.
+		{addresses: []ed25519.PublicKey{owner}, entry: entry},
+		{addresses: []ed25519.PublicKey{receiver}, entry: entry, matched: true},
+	} {
+		matched, err := matchesFilters([]app.EventFilter{{Addresses: tc.addresses}}, 1, tc.entry, tc.accounts)
+		require.NoError(t, err)
+		assert.Equal(t, tc.matched, matched)
+	}
+
+	// Owners are resolved at most once per entry.
+	accounts.calls = 0
+	filters := []app.EventFilter{{Addresses: []ed25519.PublicKey{other}}, {Addresses: []ed25519.PublicKey{owner}}}
+	matched, err := matchesFilters(filters, 1, entry, accounts)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, 1, accounts.calls)
+
+	// Resolution failures are returned, so that the entry can be retried.
+	accounts.err = errors.New("unavailable")
+	_, err = matchesFilters([]app.EventFilter{{Addresses: []ed25519.PublicKey{owner}}}, 1, entry, accounts)
+	assert.Error(t, err)
+}
+
+type tokenAccounts struct {
+	owners map[string]ed25519.PublicKey
+	err    error
+	calls  int
+}
+
+func (a *tokenAccounts) GetAccount(accountID ed25519.PublicKey, _ solana.Commitment) (*token.Account, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	owner, ok := a.owners[string(accountID)]
+	if !ok {
+		return nil, token.ErrAccountNotFound
+	}
+	return &token.Account{Owner: owner}, nil
+}
+
+func kinMemo(t *testing.T, txType kin.TransactionType) string {
+	m, err := kin.NewMemo(1, txType, 1, make([]byte, 29))
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(m[:])
+}
+
+func generateFilterEntry(t *testing.T, sender ed25519.PrivateKey, receiver ed25519.PublicKey, memoData string, amount uint64, failed bool) *model.Entry {
+	txn := solana.NewTransaction(
+		sender.Public().(ed25519.PublicKey),
+		memo.Instruction(memoData),
+		token.Transfer(sender.Public().(ed25519.PublicKey), receiver, sender.Public().(ed25519.PublicKey), amount),
+	)
+	require.NoError(t, txn.Sign(sender))
+
+	entry := &model.Entry{
+		Version: model.KinVersion_KIN4,
+		Kind: &model.Entry_Solana{
+			Solana: &model.SolanaEntry{
+				Slot:        1,
+				Confirmed:   true,
+				Transaction: txn.Marshal(),
+			},
+		},
+	}
+	if failed {
+		entry.GetSolana().TransactionError = []byte(`{"InstructionError":[1,{"Custom":1}]}`)
+	}
+
+	return entry
+}
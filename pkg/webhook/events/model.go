@@ -16,6 +16,10 @@ type TransactionEvent struct {
 	TxID        []byte                `json:"tx_id"`
 	InvoiceList *commonpb.InvoiceList `json:"invoice_list"`
 
+	// Flags contains the velocity thresholds, if any, that the
+	// transaction exceeded at submission time.
+	Flags []string `json:"flags,omitempty"`
+
 	StellarEvent *StellarEvent `json:"stellar_event"`
 	SolanaEvent  *SolanaEvent  `json:"solana_event"`
 }
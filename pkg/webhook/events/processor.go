@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/kinecosystem/agora-common/kin"
@@ -22,7 +23,9 @@ import (
 	"github.com/kinecosystem/agora/pkg/invoice"
 	"github.com/kinecosystem/agora/pkg/transaction"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
 	"github.com/kinecosystem/agora/pkg/webhook"
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
 )
 
 const (
@@ -45,14 +48,65 @@ type Processor struct {
 	configStore   app.ConfigStore
 	appMapper     app.Mapper
 	webhookClient *webhook.Client
+	flagStore     velocity.FlagStore
+	deadLetters   deadletter.Store
+	tokenAccounts TokenAccountResolver
+
+	batchSize    int
+	batchLatency time.Duration
+	batcher      *batcher
+}
+
+// Option configures a Processor.
+type Option func(*Processor)
+
+// WithBatching enables per app batching of events, where up to maxSize
+// events for an app are delivered in a single webhook call. A batch is
+// delivered once it is full, or once its oldest event has waited for
+// maxLatency. If maxLatency is zero, batches only contain the events that
+// were queued while the previous batch for the app was being delivered.
+//
+// Tasks are only completed once the batch containing their event has been
+// delivered, so the task queue should process at least maxSize tasks
+// concurrently for batches to fill up.
+//
+// If none is provided, each event is delivered individually.
+func WithBatching(maxSize int, maxLatency time.Duration) Option {
+	return func(p *Processor) {
+		if maxSize > 0 {
+			p.batchSize = maxSize
+		}
+		if maxLatency > 0 {
+			p.batchLatency = maxLatency
+		}
+	}
 }
 
+// WithTokenAccountResolver enables the resolution of the owners of Solana
+// destination accounts, so that event filters on addresses match payments
+// sent to a token account owned by one of the addresses.
+//
+// If none is provided, address filters only match Solana payments by their
+// token accounts, and the owner of the source account.
+func WithTokenAccountResolver(resolver TokenAccountResolver) Option {
+	return func(p *Processor) {
+		p.tokenAccounts = resolver
+	}
+}
+
+// NewProcessor returns a new Processor.
+//
+// If flagStore is nil, velocity flags are not included in events. If
+// deadLetters is nil, events whose delivery failed are dropped.
 func NewProcessor(
 	eventsQueueCtor taskqueue.ProcessorCtor,
 	invoiceStore invoice.Store,
 	configStore app.ConfigStore,
 	appMapper app.Mapper,
 	webhookClient *webhook.Client,
+	flagStore velocity.FlagStore,
+	deadLetters deadletter.Store,
+	opts ...Option,
 ) (p *Processor, err error) {
 	p = &Processor{
 		log:           logrus.StandardLogger().WithField("type", "events/Processor"),
@@ -60,6 +114,17 @@ func NewProcessor(
 		configStore:   configStore,
 		appMapper:     appMapper,
 		webhookClient: webhookClient,
+		flagStore:     flagStore,
+		deadLetters:   deadLetters,
+		batchSize:     1,
+	}
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	if p.batchSize > 1 {
+		p.batcher = newBatcher(p.batchSize, p.batchLatency, p.deliver)
 	}
 
 	p.submitter, err = eventsQueueCtor(p.queueHandler)
@@ -252,18 +317,61 @@ func (p *Processor) queueHandler(ctx context.Context, task *task.Message) error
 		return nil
 	}
 
-	events := []Event{event}
+	matched, err := matchesFilters(conf.EventFilters, uint16(appIndex), entry, p.tokenAccounts)
+	if err != nil {
+		log.WithError(err).Warn("Failed to apply event filters")
+		return errors.Wrap(err, "failed to apply event filters")
+	}
+	if !matched {
+		log.Trace("transaction does not match app's event filters; dropping")
+		return nil
+	}
+
+	if p.flagStore != nil {
+		event.TransactionEvent.Flags, err = p.flagStore.Get(ctx, txID)
+		if err != nil {
+			log.WithError(err).Warn("Failed to get velocity flags")
+			return errors.Wrapf(err, "failed to get velocity flags for tx: %x", txID)
+		}
+	}
+
+	if p.batcher != nil {
+		return p.batcher.add(ctx, uint16(appIndex), conf, event)
+	}
+
+	return p.deliver(ctx, uint16(appIndex), conf, []Event{event})
+}
+
+// deliver calls the app's events webhook with the provided events.
+//
+// An error is not returned for failed deliveries, so that the processor will
+// clear the tasks. Instead, the events are dead lettered as a unit, and
+// redelivered over a longer horizon.
+func (p *Processor) deliver(ctx context.Context, appIndex uint16, conf *app.Config, events []Event) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":    "deliver",
+		"app_index": appIndex,
+		"events":    len(events),
+	})
+
 	body, err := json.Marshal(&events)
 	if err != nil {
 		log.WithError(err).Warn("Failed to marshal events body")
 		return errors.Wrap(err, "failed to marshal events body")
 	}
 
-	// note: we don't return an error so the processor will clear the task.
-	//       ideally we can send it to a DQL, or use some other shuffle technique
-	//       to allow for longer retries.
 	if err := p.webhookClient.Events(ctx, *conf.EventsURL, conf.WebhookSecret, body); err != nil {
 		log.WithError(err).Warn("Failed to call events webhook")
+
+		if p.deadLetters != nil {
+			l := deadletter.New(appIndex, body, err, time.Now(), deadletter.DefaultSchedule)
+			if err := p.deadLetters.Put(ctx, l); err != nil {
+				log.WithError(err).Warn("Failed to dead letter events")
+				return errors.Wrap(err, "failed to dead letter events")
+			}
+
+			log.WithField("dead_letter_id", l.ID).Info("Dead lettered events")
+		}
 	}
 
 	return nil
@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sqs/sqsiface"
 	"github.com/golang/protobuf/proto"
 	sqstest "github.com/kinecosystem/agora-common/aws/sqs/test"
+	"github.com/kinecosystem/agora-common/kin"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/taskqueue/model/task"
 	sqstasks "github.com/kinecosystem/agora-common/taskqueue/sqs"
@@ -36,6 +37,8 @@ import (
 	"github.com/kinecosystem/agora/pkg/testutil"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
 	historytestutil "github.com/kinecosystem/agora/pkg/transaction/history/model/testutil"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+	velocitymemory "github.com/kinecosystem/agora/pkg/transaction/velocity/memory"
 	"github.com/kinecosystem/agora/pkg/webhook"
 )
 
@@ -62,6 +65,7 @@ type testEnv struct {
 	invoiceStore   invoice.Store
 	appConfigStore app.ConfigStore
 	appMapper      app.Mapper
+	flagStore      velocity.FlagStore
 }
 
 func TestMain(m *testing.M) {
@@ -85,6 +89,7 @@ func setup(t *testing.T) (env testEnv, teardown func()) {
 	env.invoiceStore = invoicememory.New()
 	env.appConfigStore = appmemory.New()
 	env.appMapper = appmapper.New()
+	env.flagStore = velocitymemory.NewFlagStore()
 
 	setupQueue(t, IngestionQueueName)
 	teardown = func() { deleteQueue(t, IngestionQueueName) }
@@ -100,6 +105,8 @@ func setup(t *testing.T) (env testEnv, teardown func()) {
 		env.appConfigStore,
 		env.appMapper,
 		webhook.NewClient(http.DefaultClient),
+		env.flagStore,
+		nil,
 	)
 	require.NoError(t, err)
 	env.processor = p
@@ -119,6 +126,7 @@ func TestRoundTrip(t *testing.T) {
 	entry, txHash := historytestutil.GenerateStellarEntry(t, 10, 10, accountIDs[0], accountIDs[1:], ilHash[:], nil)
 
 	require.NoError(t, env.invoiceStore.Put(context.Background(), txHash, il))
+	require.NoError(t, env.flagStore.Put(context.Background(), txHash, []string{"owner_count:1h0m0s"}))
 
 	called := make(chan struct{})
 	testServer := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
@@ -136,6 +144,7 @@ func TestRoundTrip(t *testing.T) {
 		assert.EqualValues(t, model.KinVersion_KIN3, txEvent.KinVersion)
 		assert.EqualValues(t, txHash, txEvent.TxHash)
 		assert.True(t, proto.Equal(il, txEvent.InvoiceList))
+		assert.Equal(t, []string{"owner_count:1h0m0s"}, txEvent.Flags)
 
 		assert.NotNil(t, txEvent.StellarEvent)
 		assert.NotNil(t, entry.Kind.(*model.Entry_Stellar).Stellar.EnvelopeXdr, txEvent.StellarEvent.EnvelopeXDR)
@@ -189,6 +198,7 @@ func TestRoundTrip_WithAppID(t *testing.T) {
 		assert.EqualValues(t, model.KinVersion_KIN3, txEvent.KinVersion)
 		assert.EqualValues(t, txHash, txEvent.TxHash)
 		assert.True(t, proto.Equal(il, txEvent.InvoiceList))
+		assert.Nil(t, txEvent.Flags)
 
 		assert.NotNil(t, txEvent.StellarEvent)
 		assert.NotNil(t, entry.Kind.(*model.Entry_Stellar).Stellar.EnvelopeXdr, txEvent.StellarEvent.EnvelopeXDR)
@@ -532,6 +542,56 @@ func TestWebhook_RetriableError(t *testing.T) {
 	assert.NoError(t, env.processor.queueHandler(context.Background(), msg))
 }
 
+func TestWebhook_Filtered(t *testing.T) {
+	env, teardown := setup(t)
+	defer teardown()
+
+	ilBytes, err := proto.Marshal(il)
+	require.NoError(t, err)
+	ilHash := sha256.Sum224(ilBytes)
+
+	sender := testutil.GenerateSolanaKeypair(t)
+	receivers := testutil.GenerateSolanaKeys(t, 1)
+	entry, _ := historytestutil.GenerateSolanaEntry(t, 10, true, sender, receivers, ilHash[:], nil)
+
+	called := false
+	testServer := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+		called = true
+	}))
+
+	eventsURL, err := url.Parse(testServer.URL)
+	require.NoError(t, err)
+
+	// The entry is a successful spend, so only earns should be filtered out.
+	appConfig := &app.Config{
+		AppName:       "kin",
+		EventsURL:     eventsURL,
+		WebhookSecret: "secret",
+		EventFilters: []app.EventFilter{
+			{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}},
+		},
+	}
+	err = env.appConfigStore.Add(context.Background(), 1, appConfig)
+	require.NoError(t, err)
+
+	b, err := proto.Marshal(entry)
+	require.NoError(t, err)
+	msg := &task.Message{
+		TypeName: proto.MessageName(entry),
+		RawValue: b,
+	}
+	require.NoError(t, env.processor.queueHandler(context.Background(), msg))
+	assert.False(t, called)
+
+	require.NoError(t, env.appConfigStore.SetEventFilters(context.Background(), 1, []app.EventFilter{
+		{TransactionTypes: []kin.TransactionType{kin.TransactionTypeEarn}},
+		{Status: app.EventStatusSuccessful},
+	}))
+	require.NoError(t, env.processor.queueHandler(context.Background(), msg))
+	assert.True(t, called)
+}
+
 func setupQueue(t *testing.T, queueName string) string {
 	resp, err := sqsClient.GetQueueUrlRequest(&sqs.GetQueueUrlInput{
 		QueueName: aws.String(queueName),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -13,15 +14,18 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws/external"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go/aws/session"
 	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/go-redis/redis/v7"
 	"github.com/go-redis/redis_rate/v8"
+	"github.com/jackc/pgx/v4/pgxpool"
 	agoraapp "github.com/kinecosystem/agora-common/app"
 	"github.com/kinecosystem/agora-common/headers"
 	"github.com/kinecosystem/agora-common/httpgateway"
 	"github.com/kinecosystem/agora-common/kin"
+	"github.com/kinecosystem/agora-common/protobuf/validation"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/token"
 	sqstasks "github.com/kinecosystem/agora-common/taskqueue/sqs"
@@ -46,8 +50,13 @@ import (
 	accountcache "github.com/kinecosystem/agora/pkg/account/solana/tokenaccount/dynamodb"
 	accountstellar "github.com/kinecosystem/agora/pkg/account/stellar"
 	airdropserver "github.com/kinecosystem/agora/pkg/airdrop/server"
+	"github.com/kinecosystem/agora/pkg/analytics"
+	analyticsdb "github.com/kinecosystem/agora/pkg/analytics/dynamodb"
+	analyticspb "github.com/kinecosystem/agora/pkg/analytics/proto"
+	appconfig "github.com/kinecosystem/agora/pkg/app"
 	appconfigdb "github.com/kinecosystem/agora/pkg/app/dynamodb"
 	appmapper "github.com/kinecosystem/agora/pkg/app/dynamodb/mapper"
+	apppb "github.com/kinecosystem/agora/pkg/app/proto"
 	"github.com/kinecosystem/agora/pkg/channel"
 	channelpool "github.com/kinecosystem/agora/pkg/channel/dynamodb"
 	invoicedb "github.com/kinecosystem/agora/pkg/invoice/dynamodb"
@@ -56,20 +65,38 @@ import (
 	migrationstore "github.com/kinecosystem/agora/pkg/migration/dynamodb"
 	kin3migrator "github.com/kinecosystem/agora/pkg/migration/kin3"
 	"github.com/kinecosystem/agora/pkg/rate"
+	"github.com/kinecosystem/agora/pkg/redisutil"
+	"github.com/kinecosystem/agora/pkg/tracing"
 	"github.com/kinecosystem/agora/pkg/transaction"
 	deduper "github.com/kinecosystem/agora/pkg/transaction/dedupe/dynamodb"
+	"github.com/kinecosystem/agora/pkg/transaction/history"
+	historyarchive "github.com/kinecosystem/agora/pkg/transaction/history/archive"
+	"github.com/kinecosystem/agora/pkg/transaction/history/bus"
+	kafkabus "github.com/kinecosystem/agora/pkg/transaction/history/bus/kafka"
+	redisbus "github.com/kinecosystem/agora/pkg/transaction/history/bus/redis"
+	historycache "github.com/kinecosystem/agora/pkg/transaction/history/cache"
 	historyrw "github.com/kinecosystem/agora/pkg/transaction/history/dynamodb"
 	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 	ingestioncommitter "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/committer"
 	ingestionlock "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/locker"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/fanout"
+	ingestionpb "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/proto"
+	redisingestioncommitter "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/redis/committer"
+	redisingestionlock "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/redis/locker"
 	solanaingestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/solana"
 	stellaringestor "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/stellar"
 	"github.com/kinecosystem/agora/pkg/transaction/history/model"
+	historypg "github.com/kinecosystem/agora/pkg/transaction/history/postgres"
 	transactionsolana "github.com/kinecosystem/agora/pkg/transaction/solana"
 	transactionstellar "github.com/kinecosystem/agora/pkg/transaction/stellar"
+	"github.com/kinecosystem/agora/pkg/transaction/velocity"
+	velocityredis "github.com/kinecosystem/agora/pkg/transaction/velocity/redis"
 	"github.com/kinecosystem/agora/pkg/version"
 	"github.com/kinecosystem/agora/pkg/webhook"
 	"github.com/kinecosystem/agora/pkg/webhook/events"
+	"github.com/kinecosystem/agora/pkg/webhook/events/deadletter"
+	deadletterpb "github.com/kinecosystem/agora/pkg/webhook/events/deadletter/proto"
+	deadletterredis "github.com/kinecosystem/agora/pkg/webhook/events/deadletter/redis"
 
 	// Configurable keystore options:
 	_ "github.com/kinecosystem/agora/pkg/keypair/dynamodb"
@@ -87,6 +114,7 @@ const (
 	solanaResolveEndpointEnv  = "SOLANA_RESOLVE_ENDPOINT"
 	solanaSubmitEndpointEnv   = "SOLANA_SUBMIT_ENDPOINT"
 	solanaMigratorEndpointEnv = "SOLANA_MIGRATOR_ENDPOINT"
+	solanaFallbackEndpointEnv = "SOLANA_FALLBACK_ENDPOINT"
 	kinTokenEnv               = "KIN_TOKEN"
 	airdropSourceEnv          = "AIRDROP_SOURCE"
 	subsidizerKeypairIDEnv    = "SUBSIDIZER_KEYPAIR_ID"
@@ -118,19 +146,65 @@ const (
 	tokenAccountTTLEnv      = "TOKEN_ACCOUNT_TTL"
 	consistencyCheckProbEnv = "TOKEN_ACCOUNT_CONSISTENCY_CHECK_PROBABILITY"
 
+	// Velocity Configs
+	velocityConfigEnv          = "VELOCITY_CONFIG"
+	velocityRedisConnStringEnv = "VELOCITY_REDIS_CONN_STRING"
+
+	// History Configs
+	historyPostgresConnStringEnv = "HISTORY_POSTGRES_CONN_STRING"
+	historyArchiveBucketEnv      = "HISTORY_ARCHIVE_BUCKET"
+	historyIngestionWorkersEnv   = "HISTORY_INGESTION_WORKERS"
+
+	// Ingestion configs
+	ingestionRedisConnStringEnv = "INGESTION_REDIS_CONN_STRING"
+
+	// History bus configs
+	busRedisConnStringEnv = "HISTORY_BUS_REDIS_CONN_STRING"
+	busStreamEnv          = "HISTORY_BUS_STREAM"
+	busStreamMaxLenEnv    = "HISTORY_BUS_STREAM_MAX_LEN"
+	busKafkaBrokersEnv    = "HISTORY_BUS_KAFKA_BROKERS"
+	busKafkaTopicEnv      = "HISTORY_BUS_KAFKA_TOPIC"
+
+	// Events webhook configs
+	eventsDeadLetterRedisConnStringEnv = "EVENTS_DEAD_LETTER_REDIS_CONN_STRING"
+	kin4IngestionEventsEnabledEnv      = "KIN4_INGESTION_EVENTS_ENABLED"
+	eventsBatchMaxSizeEnv              = "EVENTS_BATCH_MAX_SIZE"
+	eventsBatchMaxLatencyEnv           = "EVENTS_BATCH_MAX_LATENCY"
+
+	// Tracing Configs
+	tracingEndpointEnv = "TRACING_OTLP_ENDPOINT"
+
+	// Admin Configs
+	adminListenAddressEnv = "ADMIN_LISTEN_ADDRESS"
+
 	accountInfoTTL         = 30 * time.Second
 	negativeAccountInfoTTL = 15 * time.Second
 	dedupeTTL              = 24 * time.Hour
+	historyCacheSize       = 50_000
+	historyCacheTTL        = 10 * time.Minute
+	trackedDestRefresh     = time.Minute
+	redeliveryInterval     = 5 * time.Minute
+	defaultBusStream       = "kin-history"
+	defaultBusStreamMaxLen = 1_000_000
 )
 
 type app struct {
-	accountStellar accountpbv3.AccountServer
-	accountSolana  accountpbv4.AccountServer
-	txnStellar     transactionpbv3.TransactionServer
-	txnSolana      transactionpbv4.TransactionServer
-	airdropServer  airdroppb.AirdropServer
+	accountStellar  accountpbv3.AccountServer
+	accountSolana   accountpbv4.AccountServer
+	txnStellar      transactionpbv3.TransactionServer
+	txnSolana       transactionpbv4.TransactionServer
+	airdropServer   airdroppb.AirdropServer
+	analyticsAdmin  analyticspb.AdminServer
+	ingestionAdmin  ingestionpb.AdminServer
+	appAdmin        apppb.AdminServer
+	deadLetterAdmin deadletterpb.AdminServer
+
+	adminServer *grpc.Server
 
 	streamCancelFunc context.CancelFunc
+	tracingShutdown  func(context.Context) error
+	historyPool      *pgxpool.Pool
+	kafkaPublisher   *kafkabus.Publisher
 
 	shutdown   sync.Once
 	shutdownCh chan struct{}
@@ -140,6 +214,14 @@ type app struct {
 func (a *app) Init(_ agoraapp.Config) error {
 	a.shutdownCh = make(chan struct{})
 
+	if endpoint := os.Getenv(tracingEndpointEnv); endpoint != "" {
+		shutdown, err := tracing.Init(context.Background(), "agora", endpoint)
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize tracing")
+		}
+		a.tracingShutdown = shutdown
+	}
+
 	keystoreType := os.Getenv(keystoreTypeEnv)
 	keystore, err := keypairdb.CreateStore(keystoreType)
 	if err != nil {
@@ -191,6 +273,7 @@ func (a *app) Init(_ agoraapp.Config) error {
 
 	dynamoClient := dynamodb.New(cfg)
 	appConfigStore := appconfigdb.New(dynamoClient)
+	a.appAdmin = appconfig.NewServer(appConfigStore)
 	appMapper := appmapper.New(dynamoClient)
 	invoiceStore := invoicedb.New(dynamoClient)
 	webhookClient := webhook.NewClient(&http.Client{Timeout: 10 * time.Second})
@@ -229,10 +312,35 @@ func (a *app) Init(_ agoraapp.Config) error {
 		}
 
 		limiter = redis_rate.NewLimiter(redis.NewRing(&redis.RingOptions{
-			Addrs: parseAddrsFromConnString(rlRedisConnString),
+			Addrs: redisutil.ParseAddrs(rlRedisConnString),
 		}))
 	}
 
+	var velocityChecker velocity.Checker
+	var velocityFlagStore velocity.FlagStore
+	if velocityConfigPath := os.Getenv(velocityConfigEnv); velocityConfigPath != "" {
+		velocityRedisConnString := os.Getenv(velocityRedisConnStringEnv)
+		if velocityRedisConnString == "" {
+			return errors.Errorf("%s must be set if %s is set", velocityRedisConnStringEnv, velocityConfigEnv)
+		}
+
+		f, err := os.Open(velocityConfigPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to open velocity config")
+		}
+		velocityConfig, err := velocity.LoadConfig(f)
+		f.Close()
+		if err != nil {
+			return errors.Wrap(err, "failed to load velocity config")
+		}
+
+		velocityClient := redis.NewRing(&redis.RingOptions{
+			Addrs: redisutil.ParseAddrs(velocityRedisConnString),
+		})
+		velocityChecker = velocity.NewChecker(velocityredis.New(velocityClient), velocityConfig)
+		velocityFlagStore = velocityredis.NewFlagStore(velocityClient)
+	}
+
 	var channelPool channel.Pool
 	var kin2ChannelPool channel.Pool
 
@@ -290,15 +398,63 @@ func (a *app) Init(_ agoraapp.Config) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	a.streamCancelFunc = cancel
 
+	// Failed events webhooks are dropped, unless a dead letter store is
+	// configured.
+	var deadLetterStore deadletter.Store
+	if connString := os.Getenv(eventsDeadLetterRedisConnStringEnv); connString != "" {
+		deadLetterStore = deadletterredis.New(redisutil.NewClient(connString))
+	}
+
+	// Events are delivered individually, unless a max batch size is
+	// configured. Since a task is only completed once its batch has been
+	// delivered, the queue must process at least a full batch of tasks
+	// concurrently.
+	var eventsQueueOpts []sqstasks.Option
+	var eventsOpts []events.Option
+	if maxSizeStr := os.Getenv(eventsBatchMaxSizeEnv); maxSizeStr != "" {
+		maxSize, err := strconv.Atoi(maxSizeStr)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse events batch max size")
+		}
+
+		var maxLatency time.Duration
+		if maxLatencyStr := os.Getenv(eventsBatchMaxLatencyEnv); maxLatencyStr != "" {
+			if maxLatency, err = time.ParseDuration(maxLatencyStr); err != nil {
+				return errors.Wrap(err, "failed to parse events batch max latency")
+			}
+		}
+
+		if maxSize > 1 {
+			eventsQueueOpts = append(eventsQueueOpts, sqstasks.WithTaskConcurrency(maxSize))
+			eventsOpts = append(eventsOpts, events.WithBatching(maxSize, maxLatency))
+		}
+	}
+
+	// Event filters on addresses match Solana payments by the owner of their
+	// destination account, which is resolved via the Solana endpoint.
+	if endpoint := os.Getenv(solanaEndpointEnv); endpoint != "" {
+		kinToken, err := base58.Decode(os.Getenv(kinTokenEnv))
+		if err != nil {
+			return errors.Wrap(err, "failed to parse kin token address")
+		}
+
+		tokenClient := token.NewClient(tracing.NewSolanaClient(solana.New(endpoint)), kinToken)
+		eventsOpts = append(eventsOpts, events.WithTokenAccountResolver(tokenClient))
+	}
+
 	eventsProcessor, err := events.NewProcessor(
 		sqstasks.NewProcessorCtor(
 			events.IngestionQueueName,
 			sqs.New(cfg),
+			eventsQueueOpts...,
 		),
 		invoiceStore,
 		appConfigStore,
 		appMapper,
 		webhookClient,
+		velocityFlagStore,
+		deadLetterStore,
+		eventsOpts...,
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to init events processor")
@@ -313,30 +469,85 @@ func (a *app) Init(_ agoraapp.Config) error {
 		return errors.Wrap(err, "failed to get kin 2 network")
 	}
 
+	// Ingestion locks and commits are stored in dynamodb, unless a redis
+	// connection string is provided.
 	committer := ingestioncommitter.New(dynamoClient)
+	newIngestionLock := func(lockKey string) (ingestion.DistributedLock, error) {
+		return ingestionlock.New(dynamodbv1.New(sess), lockKey, 10*time.Second)
+	}
+	if connString := os.Getenv(ingestionRedisConnStringEnv); connString != "" {
+		ingestionClient := redisutil.NewClient(connString)
+		committer = redisingestioncommitter.New(ingestionClient)
+		newIngestionLock = func(lockKey string) (ingestion.DistributedLock, error) {
+			return redisingestionlock.New(ingestionClient, lockKey, 10*time.Second), nil
+		}
+	}
+	ingestionController := ingestion.NewController()
+	a.ingestionAdmin = ingestion.NewServer(ingestionController)
+
+	// Dead letters are only redelivered by the node holding the redelivery
+	// lock.
+	if deadLetterStore != nil {
+		redeliveryLock, err := newIngestionLock("events_dead_letter_redelivery")
+		if err != nil {
+			return errors.Wrap(err, "failed to init dead letter redelivery lock")
+		}
+
+		redeliverer := deadletter.NewRedeliverer(deadLetterStore, appConfigStore, webhookClient, redeliveryLock, nil)
+		a.deadLetterAdmin = deadletter.NewServer(deadLetterStore, redeliverer)
+
+		go func() {
+			err := redeliverer.Run(ctx, redeliveryInterval)
+			if err != nil && err != context.Canceled {
+				log.WithError(err).Warn("dead letter redelivery loop terminated")
+			} else {
+				log.WithError(err).Info("dead letter redelivery loop terminated")
+			}
+		}()
+	}
+
 	historyIngestor := stellaringestor.New(ingestion.GetHistoryIngestorName(model.KinVersion_KIN3), model.KinVersion_KIN3, clientV2, network.Passphrase)
-	eventsIngestor := stellaringestor.New(ingestion.GetEventsIngestorName(model.KinVersion_KIN3), model.KinVersion_KIN3, clientV2, network.Passphrase)
-	historyLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_history_kin3", 10*time.Second)
+	historyLock, err := newIngestionLock("ingestor_history_kin3")
 	if err != nil {
 		return errors.Wrap(err, "failed to init history ingestion lock")
 	}
-	eventsLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_events_kin3", 10*time.Second)
+	eventsLock, err := newIngestionLock("ingestor_events_kin3")
 	if err != nil {
 		return errors.Wrap(err, "failed to init events ingestion lock")
 	}
 
 	kin2HistoryIngestor := stellaringestor.New(ingestion.GetHistoryIngestorName(model.KinVersion_KIN2), model.KinVersion_KIN2, kin2ClientV2, kin2Network.Passphrase)
-	kin2EventsIngestor := stellaringestor.New(ingestion.GetEventsIngestorName(model.KinVersion_KIN2), model.KinVersion_KIN2, kin2ClientV2, kin2Network.Passphrase)
-	kin2HistoryLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_history_kin2", 10*time.Second)
+	kin2HistoryLock, err := newIngestionLock("ingestor_history_kin2")
 	if err != nil {
 		return errors.Wrap(err, "failed to init kin 2 history ingestion lock")
 	}
-	kin2EventsLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_events_kin2", 10*time.Second)
+	kin2EventsLock, err := newIngestionLock("ingestor_events_kin2")
 	if err != nil {
 		return errors.Wrap(err, "failed to init kin 2 events ingestion lock")
 	}
 
-	historyRW := historyrw.New(dynamoClient)
+	var historyRW history.ReaderWriter
+	if connString := os.Getenv(historyPostgresConnStringEnv); connString != "" {
+		a.historyPool, err = pgxpool.Connect(context.Background(), connString)
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to history postgres")
+		}
+		historyRW = historypg.New(a.historyPool)
+	} else {
+		historyRW = historyrw.New(dynamoClient)
+	}
+	if archiveBucket := os.Getenv(historyArchiveBucketEnv); archiveBucket != "" {
+		historyRW = historyarchive.NewReader(historyRW, historyarchive.NewS3Store(s3.New(cfg), archiveBucket, ""))
+	}
+
+	// Ingestion in this process writes through the cache, invalidating cached
+	// results. Writes from other processes (i.e. ingestion in other instances
+	// and repairs) do not, so only finalized entries are cached, and results
+	// (including not found results) expire after a bounded time.
+	historyRW, err = historycache.New(historyRW, historyCacheSize, historyCacheTTL)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize history cache")
+	}
 	txLimiter := transaction.NewLimiter(
 		func(limit int) rate.Limiter {
 			return rate.NewRedisRateLimiter(limiter, redis_rate.PerSecond(limit))
@@ -349,6 +560,7 @@ func (a *app) Init(_ agoraapp.Config) error {
 		appConfigStore,
 		webhookClient,
 		txLimiter,
+		velocityChecker,
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize authorizer")
@@ -371,11 +583,35 @@ func (a *app) Init(_ agoraapp.Config) error {
 	//
 	// Kin3 Ingestion and Streams
 	//
+	// Events are fed from the history ingestion, rather than a separate pass
+	// over the chain. The events sink uses the name of the (former) events
+	// ingestor, so that it resumes from the events ingestor's last commit.
+	//
+	// Committed history is also published to the configured message buses
+	// (if any). The bus sinks are fed by the same fan out loop as the events
+	// sink.
+	//
+	busPublishers, err := a.newBusPublishers()
+	if err != nil {
+		return err
+	}
+
+	historyWriter := fanout.New(
+		historyRW,
+		fanout.NewStellarSource(model.KinVersion_KIN3, stellaringestor.NewLedgerLoader(model.KinVersion_KIN3, clientV2, network.Passphrase)),
+		committer,
+		historyIngestor.Name(),
+		append(
+			[]fanout.Sink{{Name: ingestion.GetEventsIngestorName(model.KinVersion_KIN3), Writer: eventsProcessor}},
+			busPublishers.sinks(model.KinVersion_KIN3)...,
+		),
+		fanout.WithController(ingestionController),
+	)
 	go func() {
 		transactionstellar.StreamTransactions(ctx, clientV2, accountNotifier)
 	}()
 	go func() {
-		err := ingestion.Run(ctx, historyLock, committer, historyRW, historyIngestor)
+		err := ingestionController.Run(ctx, historyLock, committer, historyWriter, historyIngestor)
 		if err != nil && err != context.Canceled {
 			log.WithError(err).Warn("history ingestion loop terminated")
 		} else {
@@ -383,21 +619,32 @@ func (a *app) Init(_ agoraapp.Config) error {
 		}
 	}()
 	go func() {
-		err := ingestion.Run(ctx, eventsLock, committer, eventsProcessor, eventsIngestor)
+		err := historyWriter.Run(ctx, eventsLock)
 		if err != nil && err != context.Canceled {
-			log.WithError(err).Warn("events ingestion loop terminated")
+			log.WithError(err).Warn("events fan out loop terminated")
 		} else {
-			log.WithError(err).Info("events ingestion loop terminated")
+			log.WithError(err).Info("events fan out loop terminated")
 		}
 	}()
 	//
 	// Kin2 Ingestion and Streams
 	//
+	kin2HistoryWriter := fanout.New(
+		historyRW,
+		fanout.NewStellarSource(model.KinVersion_KIN2, stellaringestor.NewLedgerLoader(model.KinVersion_KIN2, kin2ClientV2, kin2Network.Passphrase)),
+		committer,
+		kin2HistoryIngestor.Name(),
+		append(
+			[]fanout.Sink{{Name: ingestion.GetEventsIngestorName(model.KinVersion_KIN2), Writer: eventsProcessor}},
+			busPublishers.sinks(model.KinVersion_KIN2)...,
+		),
+		fanout.WithController(ingestionController),
+	)
 	go func() {
 		transactionstellar.StreamTransactions(ctx, kin2ClientV2, kin2AccountNotifier)
 	}()
 	go func() {
-		err := ingestion.Run(ctx, kin2HistoryLock, committer, historyRW, kin2HistoryIngestor)
+		err := ingestionController.Run(ctx, kin2HistoryLock, committer, kin2HistoryWriter, kin2HistoryIngestor)
 		if err != nil && err != context.Canceled {
 			log.WithError(err).Warn("kin 2 history ingestion loop terminated")
 		} else {
@@ -405,11 +652,11 @@ func (a *app) Init(_ agoraapp.Config) error {
 		}
 	}()
 	go func() {
-		err := ingestion.Run(ctx, kin2EventsLock, committer, eventsProcessor, kin2EventsIngestor)
+		err := kin2HistoryWriter.Run(ctx, kin2EventsLock)
 		if err != nil && err != context.Canceled {
-			log.WithError(err).Warn("kin 2 events ingestion loop terminated")
+			log.WithError(err).Warn("kin 2 events fan out loop terminated")
 		} else {
-			log.WithError(err).Info("kin 2 events ingestion loop terminated")
+			log.WithError(err).Info("kin 2 events fan out loop terminated")
 		}
 	}()
 
@@ -421,22 +668,22 @@ func (a *app) Init(_ agoraapp.Config) error {
 			solanaMigratorClient solana.Client
 		)
 
-		solanaClient = solana.New(os.Getenv(solanaEndpointEnv))
+		solanaClient = tracing.NewSolanaClient(solana.New(os.Getenv(solanaEndpointEnv)))
 
 		if os.Getenv(solanaResolveEndpointEnv) != "" {
-			solanaResolveClient = solana.New(os.Getenv(solanaResolveEndpointEnv))
+			solanaResolveClient = tracing.NewSolanaClient(solana.New(os.Getenv(solanaResolveEndpointEnv)))
 		} else {
 			solanaResolveClient = solanaClient
 		}
 
 		if os.Getenv(solanaSubmitEndpointEnv) != "" {
-			solanaSubmitClient = solana.New(os.Getenv(solanaSubmitEndpointEnv))
+			solanaSubmitClient = tracing.NewSolanaClient(solana.New(os.Getenv(solanaSubmitEndpointEnv)))
 		} else {
 			solanaSubmitClient = solanaClient
 		}
 
 		if os.Getenv(solanaMigratorEndpointEnv) != "" {
-			solanaMigratorClient = solana.New(os.Getenv(solanaMigratorEndpointEnv))
+			solanaMigratorClient = tracing.NewSolanaClient(solana.New(os.Getenv(solanaMigratorEndpointEnv)))
 		} else {
 			solanaMigratorClient = solanaClient
 		}
@@ -569,6 +816,11 @@ func (a *app) Init(_ agoraapp.Config) error {
 		mapper := account.NewMapper(token.NewClient(solanaClient, kinToken), mapperStore)
 		infoCache := infodb.NewCache(dynamoClient, accountInfoTTL, negativeAccountInfoTTL)
 		deduper := deduper.New(dynamoClient, dedupeTTL)
+		analyticsStore := analyticsdb.New(dynamoClient)
+		if err := analytics.Seed(context.Background(), analyticsStore, analytics.DefaultDestinations); err != nil {
+			return errors.Wrap(err, "failed to seed tracked destinations")
+		}
+		a.analyticsAdmin = analytics.NewServer(analyticsStore)
 
 		a.accountSolana, err = accountsolana.New(
 			solanaClient,
@@ -602,32 +854,61 @@ func (a *app) Init(_ agoraapp.Config) error {
 			infoCache,
 			eventsProcessor,
 			deduper,
+			velocityFlagStore,
+			analytics.NewTracker(analyticsStore, mapper, trackedDestRefresh),
 			kinToken,
 			subsidizer,
 			migratorHorizonClient,
 		)
+		var ingestorOpts []solanaingestor.Option
+		if workersStr := os.Getenv(historyIngestionWorkersEnv); workersStr != "" {
+			workers, err := strconv.Atoi(workersStr)
+			if err != nil || workers <= 0 {
+				return errors.Errorf("%s must be set to an integer > 0", historyIngestionWorkersEnv)
+			}
+			ingestorOpts = append(ingestorOpts, solanaingestor.WithConcurrency(workers))
+		}
+		if os.Getenv(solanaFallbackEndpointEnv) != "" {
+			fallbackClient := tracing.NewSolanaClient(solana.New(os.Getenv(solanaFallbackEndpointEnv)))
+			ingestorOpts = append(ingestorOpts, solanaingestor.WithFallbackClient(fallbackClient))
+		}
 
-		kin4HistoryIngestor := solanaingestor.New(ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), solanaClient, kinToken)
-
-		/*
-			Currently supported by "insta events" (i.e. triggered from Submit()).
-
-			kin4EventsIngestor := solanaingestor.New(ingestion.GetEventsIngestorName(model.KinVersion_KIN4), solanaClient, kinToken)
-			kin4EventsLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_events_kin4", 10*time.Second)
+		kin4HistoryIngestor := solanaingestor.New(ingestion.GetHistoryIngestorName(model.KinVersion_KIN4), solanaClient, kinToken, ingestorOpts...)
+
+		// Kin 4 events are currently delivered by "insta events" (i.e. triggered
+		// from Submit()), so the events sink is only fed from history ingestion
+		// if explicitly enabled.
+		var kin4Sinks []fanout.Sink
+		if os.Getenv(kin4IngestionEventsEnabledEnv) == "true" {
+			kin4Sinks = append(kin4Sinks, fanout.Sink{
+				Name:   ingestion.GetEventsIngestorName(model.KinVersion_KIN4),
+				Writer: eventsProcessor,
+			})
+		}
+		kin4HistoryWriter := fanout.New(
+			historyRW,
+			fanout.NewSolanaSource(historyRW),
+			committer,
+			kin4HistoryIngestor.Name(),
+			kin4Sinks,
+			fanout.WithController(ingestionController),
+		)
+		if len(kin4Sinks) > 0 {
+			kin4EventsLock, err := newIngestionLock("ingestor_events_kin4")
 			if err != nil {
 				return errors.Wrap(err, "failed to init kin 4 events ingestion lock")
 			}
 			go func() {
-				err := ingestion.Run(ctx, kin4EventsLock, committer, eventsProcessor, kin4EventsIngestor)
+				err := kin4HistoryWriter.Run(ctx, kin4EventsLock)
 				if err != nil && err != context.Canceled {
-					log.WithError(err).Warn("kin 4 events ingestion loop terminated")
+					log.WithError(err).Warn("kin 4 events fan out loop terminated")
 				} else {
-					log.WithError(err).Info("kin 4 events ingestion loop terminated")
+					log.WithError(err).Info("kin 4 events fan out loop terminated")
 				}
 			}()
-		*/
+		}
 
-		kin4HistoryLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_history_kin4", 10*time.Second)
+		kin4HistoryLock, err := newIngestionLock("ingestor_history_kin4")
 		if err != nil {
 			return errors.Wrap(err, "failed to init kin 4 history ingestion lock")
 		}
@@ -644,7 +925,7 @@ func (a *app) Init(_ agoraapp.Config) error {
 				return
 			}
 
-			err := ingestion.Run(ctx, kin4HistoryLock, committer, historyRW, kin4HistoryIngestor)
+			err := ingestionController.Run(ctx, kin4HistoryLock, committer, kin4HistoryWriter, kin4HistoryIngestor)
 			if err != nil && err != context.Canceled {
 				log.WithError(err).Warn("kin 4 history ingestion loop terminated")
 			} else {
@@ -655,6 +936,101 @@ func (a *app) Init(_ agoraapp.Config) error {
 		a.txnSolana = transactionsolana.NewNoopServer()
 	}
 
+	// Admin services are unauthenticated, so they are only served on the
+	// internal admin listener, if configured.
+	if addr := os.Getenv(adminListenAddressEnv); addr != "" {
+		if err := a.serveAdmin(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// busPublishers are the publishers of the configured message buses.
+type busPublishers map[string]bus.Publisher
+
+// newBusPublishers returns the publishers of the message buses configured
+// in the environment.
+func (a *app) newBusPublishers() (busPublishers, error) {
+	publishers := make(busPublishers)
+	if connString := os.Getenv(busRedisConnStringEnv); connString != "" {
+		maxLen := int64(defaultBusStreamMaxLen)
+		if maxLenStr := os.Getenv(busStreamMaxLenEnv); maxLenStr != "" {
+			var err error
+			maxLen, err = strconv.ParseInt(maxLenStr, 10, 64)
+			if err != nil || maxLen <= 0 {
+				return nil, errors.Errorf("%s must be set to an integer > 0", busStreamMaxLenEnv)
+			}
+		}
+		stream := os.Getenv(busStreamEnv)
+		if stream == "" {
+			stream = defaultBusStream
+		}
+
+		publishers["redis"] = redisbus.New(redisutil.NewClient(connString), stream, maxLen)
+	}
+	if brokers := os.Getenv(busKafkaBrokersEnv); brokers != "" {
+		topic := os.Getenv(busKafkaTopicEnv)
+		if topic == "" {
+			topic = defaultBusStream
+		}
+
+		a.kafkaPublisher = kafkabus.New(strings.Split(brokers, ","), topic)
+		publishers["kafka"] = a.kafkaPublisher
+	}
+
+	return publishers, nil
+}
+
+// sinks returns the fan out sinks that publish the history of the version
+// to the buses. Buses that have not been published to start from the latest
+// committed block, rather than the start of history.
+func (p busPublishers) sinks(version model.KinVersion) []fanout.Sink {
+	var sinks []fanout.Sink
+	for _, name := range []string{"redis", "kafka"} {
+		publisher, ok := p[name]
+		if !ok {
+			continue
+		}
+
+		sinks = append(sinks, fanout.Sink{
+			Name:            fmt.Sprintf("history_bus_%s_kin%d", name, version),
+			Writer:          bus.NewWriter(publisher),
+			StartFromLatest: true,
+		})
+	}
+
+	return sinks
+}
+
+func (a *app) serveAdmin(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", addr)
+	}
+
+	a.adminServer = grpc.NewServer(
+		grpc.UnaryInterceptor(validation.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(validation.StreamServerInterceptor()),
+	)
+	if a.analyticsAdmin != nil {
+		analyticspb.RegisterAdminServer(a.adminServer, a.analyticsAdmin)
+	}
+	ingestionpb.RegisterAdminServer(a.adminServer, a.ingestionAdmin)
+	apppb.RegisterAdminServer(a.adminServer, a.appAdmin)
+	if a.deadLetterAdmin != nil {
+		deadletterpb.RegisterAdminServer(a.adminServer, a.deadLetterAdmin)
+	}
+
+	go func() {
+		if err := a.adminServer.Serve(lis); err != nil {
+			log.WithError(err).Warn("admin grpc server stopped")
+		} else {
+			log.Info("admin grpc server stopped")
+		}
+	}()
+
 	return nil
 }
 
@@ -684,6 +1060,29 @@ func (a *app) Stop() {
 		close(a.shutdownCh)
 
 		a.streamCancelFunc()
+
+		if a.adminServer != nil {
+			a.adminServer.GracefulStop()
+		}
+
+		if a.historyPool != nil {
+			a.historyPool.Close()
+		}
+
+		if a.kafkaPublisher != nil {
+			if err := a.kafkaPublisher.Close(); err != nil {
+				log.WithError(err).Warn("failed to close kafka publisher")
+			}
+		}
+
+		if a.tracingShutdown != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := a.tracingShutdown(ctx); err != nil {
+				log.WithError(err).Warn("failed to shutdown tracing")
+			}
+		}
 	})
 }
 
@@ -700,15 +1099,6 @@ func main() {
 	}
 }
 
-func parseAddrsFromConnString(redisConnString string) map[string]string {
-	hosts := strings.Split(redisConnString, ",")
-	addrs := make(map[string]string)
-	for i, host := range hosts {
-		addrs[fmt.Sprintf("server%d", i)] = host
-	}
-	return addrs
-}
-
 func parseRateLimit(env string) (int, error) {
 	rlStr := os.Getenv(env)
 	if rlStr == "" {
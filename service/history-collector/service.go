@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go/aws/session"
 	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/jackc/pgx/v4/pgxpool"
 	agoraapp "github.com/kinecosystem/agora-common/app"
 	"github.com/kinecosystem/agora-common/solana"
 	"github.com/kinecosystem/agora-common/solana/token"
@@ -22,26 +26,62 @@ import (
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 
+	"github.com/kinecosystem/agora/pkg/redisutil"
+	"github.com/kinecosystem/agora/pkg/transaction/history/bus"
+	kafkabus "github.com/kinecosystem/agora/pkg/transaction/history/bus/kafka"
+	redisbus "github.com/kinecosystem/agora/pkg/transaction/history/bus/redis"
 	historyreader "github.com/kinecosystem/agora/pkg/transaction/history/dynamodb"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion"
 	ingestioncommitter "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/committer"
 	ingestionlock "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/dynamodb/locker"
+	"github.com/kinecosystem/agora/pkg/transaction/history/ingestion/fanout"
+	redisingestioncommitter "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/redis/committer"
+	redisingestionlock "github.com/kinecosystem/agora/pkg/transaction/history/ingestion/redis/locker"
 	"github.com/kinecosystem/agora/pkg/transaction/history/kre"
 	bqsubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/bigquery"
+	filesubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/file"
+	pgsubmitter "github.com/kinecosystem/agora/pkg/transaction/history/kre/postgres"
+	"github.com/kinecosystem/agora/pkg/transaction/history/model"
 )
 
 const (
 	solanaEndpointEnv = "SOLANA_ENDPOINT"
 	kinTokenEnv       = "KIN_TOKEN"
 
+	kreSubmitterEnv = "KRE_SUBMITTER"
+
 	bqCredentialsEnv    = "BQ_CREDENTIALS"
 	bqCreationsTableEnv = "BQ_CREATIONS_TABLE"
 	bqPaymentsTableEnv  = "BQ_PAYMENTS_TABLE"
+
+	bqOwnershipChangesTableEnv = "BQ_OWNERSHIP_CHANGES_TABLE"
+	bqClosuresTableEnv         = "BQ_CLOSURES_TABLE"
+	bqRollupsTableEnv          = "BQ_ROLLUPS_TABLE"
+
+	kreFileDirEnv    = "KRE_FILE_DIR"
+	kreFileFormatEnv = "KRE_FILE_FORMAT"
+
+	krePostgresConnStringEnv = "KRE_POSTGRES_CONN_STRING"
+
+	ingestionRedisConnStringEnv = "INGESTION_REDIS_CONN_STRING"
+
+	busRedisConnStringEnv = "HISTORY_BUS_REDIS_CONN_STRING"
+	busStreamEnv          = "HISTORY_BUS_STREAM"
+	busStreamMaxLenEnv    = "HISTORY_BUS_STREAM_MAX_LEN"
+	busKafkaBrokersEnv    = "HISTORY_BUS_KAFKA_BROKERS"
+	busKafkaTopicEnv      = "HISTORY_BUS_KAFKA_TOPIC"
+
+	defaultBusStream       = "kin-history"
+	defaultBusStreamMaxLen = 1_000_000
 )
 
 type app struct {
 	shutdown         sync.Once
 	loaderCancelFunc context.CancelFunc
 	shutdownCh       chan struct{}
+
+	krePool        *pgxpool.Pool
+	kafkaPublisher *kafkabus.Publisher
 }
 
 // Init implements agorapp.App.Init.
@@ -69,43 +109,38 @@ func (a *app) Init(_ agoraapp.Config) error {
 	}
 	dynamoClient := dynamodb.New(cfg)
 	hist := historyreader.New(dynamoClient)
-	committer := ingestioncommitter.New(dynamoClient)
 	accountStore := infodb.NewStore(dynamoClient)
 
-	sess, err := session.NewSession()
-	if err != nil {
-		return errors.Wrap(err, "failed to init v1 aws sdk")
+	var (
+		committer ingestion.Committer
+		newLock   func(lockKey string) (ingestion.DistributedLock, error)
+	)
+	if connString := os.Getenv(ingestionRedisConnStringEnv); connString != "" {
+		ingestionClient := redisutil.NewClient(connString)
+		committer = redisingestioncommitter.New(ingestionClient)
+		newLock = func(lockKey string) (ingestion.DistributedLock, error) {
+			return redisingestionlock.New(ingestionClient, lockKey, 10*time.Second), nil
+		}
+	} else {
+		sess, err := session.NewSession()
+		if err != nil {
+			return errors.Wrap(err, "failed to init v1 aws sdk")
+		}
+
+		committer = ingestioncommitter.New(dynamoClient)
+		newLock = func(lockKey string) (ingestion.DistributedLock, error) {
+			return ingestionlock.New(dynamodbv1.New(sess), lockKey, 10*time.Second)
+		}
 	}
-	historyLock, err := ingestionlock.New(dynamodbv1.New(sess), "ingestor_kre", 10*time.Second)
+
+	historyLock, err := newLock("ingestor_kre")
 	if err != nil {
 		return errors.Wrap(err, "failed to create history locker")
 	}
 
-	if os.Getenv(bqCreationsTableEnv) == "" {
-		return errors.Errorf("missing %s", bqCreationsTableEnv)
-	}
-	if os.Getenv(bqPaymentsTableEnv) == "" {
-		return errors.Errorf("missing %s", bqPaymentsTableEnv)
-	}
-
-	bqCredentials := os.Getenv(bqCredentialsEnv)
-	if bqCredentials == "" {
-		return errors.Errorf("missing %s", bqCredentialsEnv)
-	}
-	authOption := option.WithAPIKey(bqCredentials)
-	if _, err := url.Parse(bqCredentials); err == nil {
-		creds, err := agoraapp.LoadFile(bqCredentials)
-		if err == nil {
-			authOption = option.WithCredentialsJSON(creds)
-		}
-	}
-	bqClient, err := bigquery.NewClient(
-		context.Background(),
-		"kin-bi",
-		authOption,
-	)
+	kreSubmitters, err := a.newSubmitters()
 	if err != nil {
-		return errors.Wrap(err, "failed to intiialize bigquery client")
+		return err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -117,8 +152,10 @@ func (a *app) Init(_ agoraapp.Config) error {
 		historyLock,
 		solanaClient,
 		token.NewClient(solanaClient, kinToken),
-		bqsubmitter.New(bqClient, os.Getenv(bqCreationsTableEnv)),
-		bqsubmitter.New(bqClient, os.Getenv(bqPaymentsTableEnv)),
+		kreSubmitters.creations,
+		kreSubmitters.payments,
+		kreSubmitters.ownershipChanges,
+		kreSubmitters.closures,
 		accountStore,
 	)
 	go func() {
@@ -130,9 +167,202 @@ func (a *app) Init(_ agoraapp.Config) error {
 		}
 	}()
 
+	if kreSubmitters.rollups != nil {
+		rollupLock, err := newLock("ingestor_kre_rollups")
+		if err != nil {
+			return errors.Wrap(err, "failed to create rollup locker")
+		}
+
+		aggregator := kre.NewAggregator(
+			hist,
+			committer,
+			rollupLock,
+			solanaClient,
+			token.NewClient(solanaClient, kinToken),
+			kreSubmitters.rollups,
+		)
+		go func() {
+			err := aggregator.Process(ctx, 15*time.Minute)
+			if err != nil && err != context.Canceled {
+				log.WithError(err).Warn("rollup loop terminated")
+			} else {
+				log.WithError(err).Info("rollup loop terminated")
+			}
+		}()
+	}
+
+	// Publish committed history to the configured message buses. Buses that
+	// have not been published to start from the latest committed block, rather
+	// than the start of history.
+	var busSinks []fanout.Sink
+	if connString := os.Getenv(busRedisConnStringEnv); connString != "" {
+		maxLen := int64(defaultBusStreamMaxLen)
+		if maxLenStr := os.Getenv(busStreamMaxLenEnv); maxLenStr != "" {
+			maxLen, err = strconv.ParseInt(maxLenStr, 10, 64)
+			if err != nil || maxLen <= 0 {
+				return errors.Errorf("%s must be set to an integer > 0", busStreamMaxLenEnv)
+			}
+		}
+		stream := os.Getenv(busStreamEnv)
+		if stream == "" {
+			stream = defaultBusStream
+		}
+
+		busSinks = append(busSinks, fanout.Sink{
+			Name:            "history_bus_redis",
+			Writer:          bus.NewWriter(redisbus.New(redisutil.NewClient(connString), stream, maxLen)),
+			StartFromLatest: true,
+		})
+	}
+	if brokers := os.Getenv(busKafkaBrokersEnv); brokers != "" {
+		topic := os.Getenv(busKafkaTopicEnv)
+		if topic == "" {
+			topic = defaultBusStream
+		}
+
+		a.kafkaPublisher = kafkabus.New(strings.Split(brokers, ","), topic)
+		busSinks = append(busSinks, fanout.Sink{
+			Name:            "history_bus_kafka",
+			Writer:          bus.NewWriter(a.kafkaPublisher),
+			StartFromLatest: true,
+		})
+	}
+	if len(busSinks) > 0 {
+		busLock, err := newLock("ingestor_history_bus")
+		if err != nil {
+			return errors.Wrap(err, "failed to create bus locker")
+		}
+
+		publisher := fanout.New(
+			hist,
+			fanout.NewSolanaSource(hist),
+			committer,
+			ingestion.GetHistoryIngestorName(model.KinVersion_KIN4),
+			busSinks,
+		)
+		go func() {
+			err := publisher.Run(ctx, busLock)
+			if err != nil && err != context.Canceled {
+				log.WithError(err).Warn("bus publisher terminated")
+			} else {
+				log.WithError(err).Info("bus publisher terminated")
+			}
+		}()
+	}
+
 	return nil
 }
 
+// submitters contains the submitters for each of the KRE tables.
+//
+// ownershipChanges, closures and rollups may be nil, in which case they are
+// not submitted.
+type submitters struct {
+	creations        kre.Submitter
+	payments         kre.Submitter
+	ownershipChanges kre.Submitter
+	closures         kre.Submitter
+	rollups          kre.Submitter
+}
+
+// newSubmitters returns the submitters for the configured KRE backend.
+func (a *app) newSubmitters() (*submitters, error) {
+	switch backend := os.Getenv(kreSubmitterEnv); backend {
+	case "", "bigquery":
+		if os.Getenv(bqCreationsTableEnv) == "" {
+			return nil, errors.Errorf("missing %s", bqCreationsTableEnv)
+		}
+		if os.Getenv(bqPaymentsTableEnv) == "" {
+			return nil, errors.Errorf("missing %s", bqPaymentsTableEnv)
+		}
+
+		bqCredentials := os.Getenv(bqCredentialsEnv)
+		if bqCredentials == "" {
+			return nil, errors.Errorf("missing %s", bqCredentialsEnv)
+		}
+		authOption := option.WithAPIKey(bqCredentials)
+		if _, err := url.Parse(bqCredentials); err == nil {
+			creds, err := agoraapp.LoadFile(bqCredentials)
+			if err == nil {
+				authOption = option.WithCredentialsJSON(creds)
+			}
+		}
+		bqClient, err := bigquery.NewClient(
+			context.Background(),
+			"kin-bi",
+			authOption,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to intiialize bigquery client")
+		}
+
+		s := &submitters{
+			creations: bqsubmitter.New(bqClient, os.Getenv(bqCreationsTableEnv)),
+			payments:  bqsubmitter.New(bqClient, os.Getenv(bqPaymentsTableEnv)),
+		}
+
+		// The ownership changes and closures tables are optional, since they
+		// may not exist in existing deployments.
+		if table := os.Getenv(bqOwnershipChangesTableEnv); table != "" {
+			s.ownershipChanges = bqsubmitter.New(bqClient, table)
+		}
+		if table := os.Getenv(bqClosuresTableEnv); table != "" {
+			s.closures = bqsubmitter.New(bqClient, table)
+		}
+
+		// Rollups may be recomputed, so they replace the partition of their
+		// date, rather than relying on BigQuery's best effort deduplication.
+		if table := os.Getenv(bqRollupsTableEnv); table != "" {
+			s.rollups = bqsubmitter.New(bqClient, table, bqsubmitter.WithReplacePartitions())
+		}
+
+		return s, nil
+	case "file":
+		dir := os.Getenv(kreFileDirEnv)
+		if dir == "" {
+			return nil, errors.Errorf("missing %s", kreFileDirEnv)
+		}
+
+		format := filesubmitter.FormatJSONL
+		if formatStr := os.Getenv(kreFileFormatEnv); formatStr != "" {
+			var err error
+			format, err = filesubmitter.ParseFormat(formatStr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", kreFileFormatEnv)
+			}
+		}
+
+		return &submitters{
+			creations:        filesubmitter.New(filepath.Join(dir, "creations"), kre.CreationsSchema, format),
+			payments:         filesubmitter.New(filepath.Join(dir, "payments"), kre.PaymentsSchema, format),
+			ownershipChanges: filesubmitter.New(filepath.Join(dir, "ownership_changes"), kre.OwnershipChangesSchema, format),
+			closures:         filesubmitter.New(filepath.Join(dir, "closures"), kre.ClosuresSchema, format),
+			rollups:          filesubmitter.New(filepath.Join(dir, "rollups"), kre.RollupsSchema, format, filesubmitter.WithReplacePartitions()),
+		}, nil
+	case "postgres":
+		connString := os.Getenv(krePostgresConnStringEnv)
+		if connString == "" {
+			return nil, errors.Errorf("missing %s", krePostgresConnStringEnv)
+		}
+
+		var err error
+		a.krePool, err = pgxpool.Connect(context.Background(), connString)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to connect to kre postgres")
+		}
+
+		return &submitters{
+			creations:        pgsubmitter.New(a.krePool, pgsubmitter.CreationsTable, kre.CreationsSchema),
+			payments:         pgsubmitter.New(a.krePool, pgsubmitter.PaymentsTable, kre.PaymentsSchema),
+			ownershipChanges: pgsubmitter.New(a.krePool, pgsubmitter.OwnershipChangesTable, kre.OwnershipChangesSchema),
+			closures:         pgsubmitter.New(a.krePool, pgsubmitter.ClosuresTable, kre.ClosuresSchema),
+			rollups:          pgsubmitter.New(a.krePool, pgsubmitter.RollupsTable, kre.RollupsSchema, pgsubmitter.WithUpsert()),
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported %s: %s", kreSubmitterEnv, backend)
+	}
+}
+
 // RegisterWithGRPC implements agorapp.App.RegisterWithGRPC.
 func (a *app) RegisterWithGRPC(server *grpc.Server) {
 }
@@ -148,6 +378,14 @@ func (a *app) Stop() {
 		close(a.shutdownCh)
 
 		a.loaderCancelFunc()
+		if a.krePool != nil {
+			a.krePool.Close()
+		}
+		if a.kafkaPublisher != nil {
+			if err := a.kafkaPublisher.Close(); err != nil {
+				log.WithError(err).Warn("failed to close kafka publisher")
+			}
+		}
 	})
 }
 